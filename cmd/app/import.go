@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/GlebMoskalev/chat-golang/internal/importer"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+// runImport выполняет команду "app import [-format slack|json] <архив>": переносит архив
+// в Postgres и печатает отчёт. Без -format формат определяется по расширению (.zip — Slack).
+// Прерванный импорт можно запустить повторно, уже перенесённое будет пропущено.
+func runImport(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", "", "archive format: slack or json (default: by file extension)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: app import [-format slack|json] <archive>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		*format = importer.FormatJSON
+		if filepath.Ext(path) == ".zip" {
+			*format = importer.FormatSlack
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Failed to open archive:", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		log.Fatal("Failed to open archive:", err)
+	}
+
+	archive, err := importer.Parse(*format, file, info.Size())
+	if err != nil {
+		log.Fatal("Failed to read archive:", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db := connectPostgres()
+	importService := service.NewImportService(
+		repository.NewChatRepository(db),
+		repository.NewMessageRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewChatMemberRepository(db),
+		repository.NewTxManager(db),
+		repository.NewAuditRepository(db),
+	)
+
+	log.Printf("Importing %d chats from %s...", len(archive.Chats), path)
+	report, err := importService.Import(ctx, archive)
+	if err != nil {
+		log.Fatal("Import failed, run the same command again to resume: ", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+}
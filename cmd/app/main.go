@@ -1,18 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/gorilla/mux"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/grpcapi"
 	"github.com/GlebMoskalev/chat-golang/internal/handler"
+	"github.com/GlebMoskalev/chat-golang/internal/linkpreview"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/moderation"
+	"github.com/GlebMoskalev/chat-golang/internal/netutil"
+	"github.com/GlebMoskalev/chat-golang/internal/outbox"
+	"github.com/GlebMoskalev/chat-golang/internal/presence"
 	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/memory"
 	"github.com/GlebMoskalev/chat-golang/internal/service"
+	"github.com/GlebMoskalev/chat-golang/internal/thumbnail"
+	"github.com/GlebMoskalev/chat-golang/internal/webhook"
 )
 
 func getEnv(key, defaultValue string) string {
@@ -23,6 +42,316 @@ func getEnv(key, defaultValue string) string {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		runImport(os.Args[2:])
+		return
+	}
+
+	storage := flag.String("storage", getEnv("STORAGE", "postgres"), "storage backend: postgres or memory")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var (
+		chatRepo    repository.ChatRepository
+		messageRepo repository.MessageRepository
+		outboxRepo  repository.OutboxRepository
+		txManager   repository.TxManager
+
+		webhookRepo  repository.WebhookRepository
+		deliveryRepo repository.WebhookDeliveryRepository
+
+		userRepo     repository.UserRepository
+		incomingRepo repository.IncomingWebhookRepository
+
+		attachmentRepo  repository.AttachmentRepository
+		linkPreviewRepo repository.LinkPreviewRepository
+		memberRepo      repository.ChatMemberRepository
+		mentionRepo     repository.MentionRepository
+		pinRepo         repository.PinRepository
+		retentionRepo   repository.RetentionRepository
+		scheduledRepo   repository.ScheduledMessageRepository
+		moderationRepo  repository.ModerationRepository
+		blockRepo       repository.BlockRepository
+		reportRepo      repository.ReportRepository
+		auditRepo       repository.AuditRepository
+	)
+
+	switch *storage {
+	case "postgres":
+		db := connectPostgres()
+		chatRepo = repository.NewChatRepository(db)
+		messageRepo = repository.NewMessageRepository(db)
+		outboxRepo = repository.NewOutboxRepository(db)
+		txManager = repository.NewTxManager(db)
+		webhookRepo = repository.NewWebhookRepository(db)
+		deliveryRepo = repository.NewWebhookDeliveryRepository(db)
+		userRepo = repository.NewUserRepository(db)
+		incomingRepo = repository.NewIncomingWebhookRepository(db)
+		attachmentRepo = repository.NewAttachmentRepository(db)
+		linkPreviewRepo = repository.NewLinkPreviewRepository(db)
+		memberRepo = repository.NewChatMemberRepository(db)
+		mentionRepo = repository.NewMentionRepository(db)
+		pinRepo = repository.NewPinRepository(db)
+		retentionRepo = repository.NewRetentionRepository(db)
+		scheduledRepo = repository.NewScheduledMessageRepository(db)
+		moderationRepo = repository.NewModerationRepository(db)
+		blockRepo = repository.NewBlockRepository(db)
+		reportRepo = repository.NewReportRepository(db)
+		auditRepo = repository.NewAuditRepository(db)
+	case "memory":
+		log.Println("Using in-memory storage, data will be lost on restart")
+		store := memory.NewStore()
+		chatRepo = memory.NewChatRepository(store)
+		messageRepo = memory.NewMessageRepository(store)
+		outboxRepo = memory.NewOutboxRepository(store)
+		txManager = memory.NewTxManager(store)
+		webhookRepo = memory.NewWebhookRepository(store)
+		deliveryRepo = memory.NewWebhookDeliveryRepository(store)
+		userRepo = memory.NewUserRepository(store)
+		incomingRepo = memory.NewIncomingWebhookRepository(store)
+		attachmentRepo = memory.NewAttachmentRepository(store)
+		linkPreviewRepo = memory.NewLinkPreviewRepository(store)
+		memberRepo = memory.NewChatMemberRepository(store)
+		mentionRepo = memory.NewMentionRepository(store)
+		pinRepo = memory.NewPinRepository(store)
+		retentionRepo = memory.NewRetentionRepository(store)
+		scheduledRepo = memory.NewScheduledMessageRepository(store)
+		moderationRepo = memory.NewModerationRepository(store)
+		blockRepo = memory.NewBlockRepository(store)
+		reportRepo = memory.NewReportRepository(store)
+		auditRepo = memory.NewAuditRepository(store)
+	default:
+		log.Fatalf("Unknown storage %q, expected postgres or memory", *storage)
+	}
+
+	unfurler := linkpreview.NewUnfurler(linkPreviewRepo, linkpreview.NewFetcher(netutil.NewSafeClient(5*time.Second)), 4, 100, linkpreview.DefaultTTL)
+
+	bus := outbox.NewBus()
+	// Ключи — имена курсоров приёмников в outbox, менять их нельзя
+	sinks := map[string]outbox.Sink{"webhooks": webhook.NewSink(webhookRepo, deliveryRepo)}
+	if getEnv("LINK_PREVIEWS", "true") == "true" {
+		sinks["link_previews"] = unfurler
+	}
+	if getEnv("OUTBOX_LOG_EVENTS", "false") == "true" {
+		sinks["log"] = outbox.LogSink{}
+	}
+	if url := os.Getenv("OUTBOX_WEBHOOK_URL"); url != "" {
+		sinks["http"] = outbox.NewHTTPSink(url, nil)
+	}
+
+	pollInterval, err := time.ParseDuration(getEnv("OUTBOX_POLL_INTERVAL", "1s"))
+	if err != nil {
+		log.Fatal("Invalid OUTBOX_POLL_INTERVAL:", err)
+	}
+	blobs, err := blob.NewLocalStore(getEnv("ATTACHMENTS_DIR", "data/attachments"))
+	if err != nil {
+		log.Fatal("Failed to open attachments storage:", err)
+	}
+	maxAttachmentSize, err := strconv.ParseInt(getEnv("ATTACHMENT_MAX_SIZE", "26214400"), 10, 64)
+	if err != nil || maxAttachmentSize <= 0 {
+		log.Fatal("Invalid ATTACHMENT_MAX_SIZE:", getEnv("ATTACHMENT_MAX_SIZE", ""))
+	}
+	thumbnailWorkers, err := strconv.Atoi(getEnv("THUMBNAIL_WORKERS", "2"))
+	if err != nil || thumbnailWorkers <= 0 {
+		log.Fatal("Invalid THUMBNAIL_WORKERS:", getEnv("THUMBNAIL_WORKERS", ""))
+	}
+	maxPins, err := strconv.ParseInt(getEnv("MAX_PINS_PER_CHAT", "50"), 10, 64)
+	if err != nil || maxPins <= 0 {
+		log.Fatal("Invalid MAX_PINS_PER_CHAT:", getEnv("MAX_PINS_PER_CHAT", ""))
+	}
+	maxImportSize, err := strconv.ParseInt(getEnv("IMPORT_MAX_SIZE", "536870912"), 10, 64)
+	if err != nil || maxImportSize <= 0 {
+		log.Fatal("Invalid IMPORT_MAX_SIZE:", getEnv("IMPORT_MAX_SIZE", ""))
+	}
+	retentionMaxAge, err := time.ParseDuration(getEnv("RETENTION_MAX_AGE", "0s"))
+	if err != nil || retentionMaxAge < 0 {
+		log.Fatal("Invalid RETENTION_MAX_AGE:", getEnv("RETENTION_MAX_AGE", ""))
+	}
+	retentionMaxMessages, err := strconv.ParseInt(getEnv("RETENTION_MAX_MESSAGES", "0"), 10, 64)
+	if err != nil || retentionMaxMessages < 0 {
+		log.Fatal("Invalid RETENTION_MAX_MESSAGES:", getEnv("RETENTION_MAX_MESSAGES", ""))
+	}
+	retentionInterval, err := time.ParseDuration(getEnv("RETENTION_INTERVAL", "1h"))
+	if err != nil || retentionInterval <= 0 {
+		log.Fatal("Invalid RETENTION_INTERVAL:", getEnv("RETENTION_INTERVAL", ""))
+	}
+	retentionBatchSize, err := strconv.Atoi(getEnv("RETENTION_BATCH_SIZE", "500"))
+	if err != nil || retentionBatchSize <= 0 {
+		log.Fatal("Invalid RETENTION_BATCH_SIZE:", getEnv("RETENTION_BATCH_SIZE", ""))
+	}
+	scheduleInterval, err := time.ParseDuration(getEnv("SCHEDULER_INTERVAL", "1s"))
+	if err != nil || scheduleInterval <= 0 {
+		log.Fatal("Invalid SCHEDULER_INTERVAL:", getEnv("SCHEDULER_INTERVAL", ""))
+	}
+	sweepInterval, err := time.ParseDuration(getEnv("MESSAGE_SWEEP_INTERVAL", "10s"))
+	if err != nil || sweepInterval <= 0 {
+		log.Fatal("Invalid MESSAGE_SWEEP_INTERVAL:", getEnv("MESSAGE_SWEEP_INTERVAL", ""))
+	}
+	retentionMaxAgeSeconds := int64(retentionMaxAge / time.Second)
+	globalRetention := models.RetentionPolicy{MaxAgeSeconds: &retentionMaxAgeSeconds, MaxMessages: &retentionMaxMessages}
+	moderationChain := moderation.Chain{
+		moderation.NewWordList(moderation.ParseWords(os.Getenv("MODERATION_REJECT_WORDS")), models.ModerationActionReject),
+		moderation.NewWordList(moderation.ParseWords(os.Getenv("MODERATION_MASK_WORDS")), models.ModerationActionMask),
+		moderation.NewWordList(moderation.ParseWords(os.Getenv("MODERATION_FLAG_WORDS")), models.ModerationActionFlag),
+		moderation.NewRules(moderationRepo),
+	}
+	if url := os.Getenv("MODERATION_CLASSIFIER_URL"); url != "" {
+		failOpen := getEnv("MODERATION_CLASSIFIER_FAIL_OPEN", "true") == "true"
+		moderationChain = append(moderationChain, moderation.NewClassifierHook(moderation.NewHTTPClassifier(url, nil), failOpen))
+	}
+	thumbnails := thumbnail.NewGenerator(attachmentRepo, blobs, thumbnailWorkers, 100)
+
+	dispatcher := outbox.NewDispatcher(outboxRepo, sinks, pollInterval)
+	// Подписчики шины живут в этом процессе, поэтому и курсор у неё свой на каждой реплике
+	dispatcher.AddLocalSink("bus", bus)
+	deliverer := webhook.NewDeliverer(webhookRepo, deliveryRepo, txManager, nil, pollInterval)
+
+	moderationService := service.NewModerationService(moderationChain, moderationRepo, chatRepo, messageRepo, txManager, outboxRepo, auditRepo, attachmentRepo, blobs)
+	moderationHandler := handler.NewModerationHandler(moderationService)
+	chatService := service.NewChatService(chatRepo, messageRepo, txManager, outboxRepo, linkPreviewRepo, memberRepo, mentionRepo, pinRepo, auditRepo, moderationService, attachmentRepo, blobs)
+	scheduleService := service.NewScheduleService(scheduledRepo, chatRepo, memberRepo, txManager, chatService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+	chatHandler := handler.NewChatHandler(chatService, scheduleService)
+	webhookService := service.NewWebhookService(webhookRepo, deliveryRepo, chatRepo, txManager, auditRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	userService := service.NewUserService(userRepo)
+	userHandler := handler.NewUserHandler(userService)
+	incomingService := service.NewIncomingWebhookService(incomingRepo, userRepo, chatRepo, txManager, auditRepo, chatService)
+	incomingHandler := handler.NewIncomingWebhookHandler(incomingService)
+	attachmentService := service.NewAttachmentService(attachmentRepo, memberRepo, chatRepo, blobs, chatService, moderationService, thumbnails, maxAttachmentSize)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, maxAttachmentSize)
+	memberService := service.NewMemberService(memberRepo, userRepo, chatRepo, txManager, auditRepo)
+	memberHandler := handler.NewMemberHandler(memberService)
+	dmService := service.NewDMService(chatRepo, userRepo, memberRepo, txManager, outboxRepo)
+	dmHandler := handler.NewDMHandler(dmService)
+	exportService := service.NewExportService(chatRepo, messageRepo, memberRepo)
+	exportHandler := handler.NewExportHandler(exportService)
+	importService := service.NewImportService(chatRepo, messageRepo, userRepo, memberRepo, txManager, auditRepo)
+	importHandler := handler.NewImportHandler(importService, maxImportSize)
+	mentionService := service.NewMentionService(mentionRepo)
+	mentionHandler := handler.NewMentionHandler(mentionService)
+	presenceService := service.NewPresenceService(presence.NewTracker(presence.DefaultTypingTTL, presence.DefaultTimeout), memberRepo, chatRepo, bus)
+	presenceHandler := handler.NewPresenceHandler(presenceService)
+	dispatcher.AddLocalSink("presence", presenceService)
+	pinService := service.NewPinService(pinRepo, messageRepo, memberRepo, chatRepo, txManager, maxPins)
+	pinHandler := handler.NewPinHandler(pinService)
+	retentionService := service.NewRetentionService(retentionRepo, chatRepo, memberRepo, messageRepo, txManager, outboxRepo, auditRepo, blobs, globalRetention, retentionBatchSize)
+	retentionHandler := handler.NewRetentionHandler(retentionService)
+	blockService := service.NewBlockService(blockRepo, userRepo)
+	blockHandler := handler.NewBlockHandler(blockService)
+	reportService := service.NewReportService(reportRepo, messageRepo, memberRepo, chatRepo, txManager, outboxRepo, auditRepo, attachmentRepo, blobs)
+	reportHandler := handler.NewReportHandler(reportService)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+	expiryService := service.NewExpiryService(messageRepo, outboxRepo, txManager, blobs)
+	streamService := service.NewStreamService(bus, memberRepo, chatRepo, 64)
+
+	// X-Forwarded-For учитывается только за доверенным прокси, иначе клиент подделает свой IP в журнале аудита
+	trustProxy := getEnv("TRUST_PROXY", "false") == "true"
+	r := newRouter(handlers{
+		user:       userHandler,
+		block:      blockHandler,
+		mention:    mentionHandler,
+		presence:   presenceHandler,
+		dm:         dmHandler,
+		chat:       chatHandler,
+		schedule:   scheduleHandler,
+		report:     reportHandler,
+		pin:        pinHandler,
+		retention:  retentionHandler,
+		moderation: moderationHandler,
+		member:     memberHandler,
+		attachment: attachmentHandler,
+		incoming:   incomingHandler,
+		webhook:    webhookHandler,
+		export:     exportHandler,
+		imports:    importHandler,
+		audit:      auditHandler,
+	}, userRepo, os.Getenv("ADMIN_TOKEN"), trustProxy)
+
+	grpcAddr := getEnv("GRPC_ADDR", ":9090")
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		log.Fatal("Failed to listen for gRPC:", err)
+	}
+	grpcServer := grpcapi.NewGRPCServer(grpcapi.NewServer(chatService, streamService), userRepo)
+
+	var workers sync.WaitGroup
+	workers.Add(9)
+	go func() {
+		defer workers.Done()
+		log.Println("gRPC server started on", grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Println("gRPC server error:", err)
+		}
+	}()
+	go func() {
+		defer workers.Done()
+		dispatcher.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		deliverer.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		thumbnails.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		unfurler.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		presenceService.Run(ctx, time.Second)
+	}()
+	go func() {
+		defer workers.Done()
+		retentionService.Run(ctx, retentionInterval)
+	}()
+	go func() {
+		defer workers.Done()
+		expiryService.Run(ctx, sweepInterval)
+	}()
+	go func() {
+		defer workers.Done()
+		scheduleService.Run(ctx, scheduleInterval)
+	}()
+
+	server := &http.Server{Addr: ":8080", Handler: r}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Println("Server shutdown error:", err)
+		}
+
+		// Подписки Subscribe длятся, пока клиент не отменит вызов, поэтому ждём их не дольше таймаута
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+	}()
+
+	log.Println("Server started on :8080")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+
+	workers.Wait()
+	log.Println("Server stopped")
+}
+
+func connectPostgres() *gorm.DB {
 	host := getEnv("DB_HOST", "localhost")
 	user := getEnv("DB_USER", "postgres")
 	password := getEnv("DB_PASSWORD", "postgres")
@@ -38,17 +367,5 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	chatRepo := repository.NewChatRepository(db)
-	messageRepo := repository.NewMessageRepository(db)
-	chatService := service.NewChatService(chatRepo, messageRepo)
-	chatHandler := handler.NewChatHandler(chatService)
-
-	r := mux.NewRouter()
-	r.HandleFunc("/chats/", chatHandler.CreateChat).Methods("POST")
-	r.HandleFunc("/chats/{id}", chatHandler.GetChat).Methods("GET")
-	r.HandleFunc("/chats/{id}", chatHandler.DeleteChat).Methods("DELETE")
-	r.HandleFunc("/chats/{id}/messages/", chatHandler.CreateMessage).Methods("POST")
-
-	log.Println("Server started on :8080")
-	log.Fatal(http.ListenAndServe(":8080", r))
+	return db
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"expvar"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/audit"
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/handler"
+	"github.com/GlebMoskalev/chat-golang/internal/openapi"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// handlers — обработчики HTTP API, собранные в main
+type handlers struct {
+	user       *handler.UserHandler
+	block      *handler.BlockHandler
+	mention    *handler.MentionHandler
+	presence   *handler.PresenceHandler
+	dm         *handler.DMHandler
+	chat       *handler.ChatHandler
+	schedule   *handler.ScheduleHandler
+	report     *handler.ReportHandler
+	pin        *handler.PinHandler
+	retention  *handler.RetentionHandler
+	moderation *handler.ModerationHandler
+	member     *handler.MemberHandler
+	attachment *handler.AttachmentHandler
+	incoming   *handler.IncomingWebhookHandler
+	webhook    *handler.WebhookHandler
+	export     *handler.ExportHandler
+	imports    *handler.ImportHandler
+	audit      *handler.AuditHandler
+}
+
+// newRouter регистрирует маршруты HTTP API. Каждый маршрут должен быть описан
+// в internal/openapi/openapi.json — это проверяет TestRoutesDocumented.
+// С trustProxy IP клиента для журнала аудита берётся из X-Forwarded-For.
+func newRouter(h handlers, userRepo repository.UserRepository, adminToken string, trustProxy bool) *mux.Router {
+	r := mux.NewRouter()
+	r.Use(audit.Middleware(trustProxy))
+	r.Use(auth.Middleware(userRepo))
+	r.HandleFunc("/openapi.json", openapi.Spec).Methods("GET")
+	r.PathPrefix("/docs/").Handler(openapi.UI("/docs/")).Methods("GET")
+	r.HandleFunc("/users", h.user.CreateUser).Methods("POST")
+	r.HandleFunc("/users/{id}", h.user.GetUser).Methods("GET")
+	r.HandleFunc("/users/{id}/block", h.block.Block).Methods("POST")
+	r.HandleFunc("/users/{id}/block", h.block.Unblock).Methods("DELETE")
+	r.HandleFunc("/me/blocks", h.block.ListBlocked).Methods("GET")
+	r.HandleFunc("/me/mentions", h.mention.ListMentions).Methods("GET")
+	r.HandleFunc("/me/mentions/read", h.mention.MarkRead).Methods("POST")
+	r.HandleFunc("/me/presence", h.presence.Heartbeat).Methods("POST")
+	r.HandleFunc("/dms", h.dm.OpenDM).Methods("POST")
+	r.HandleFunc("/chats/", h.chat.CreateChat).Methods("POST")
+	r.HandleFunc("/chats/", h.chat.ListChats).Methods("GET")
+	r.HandleFunc("/chats/{id}", h.chat.GetChat).Methods("GET")
+	r.HandleFunc("/chats/{id}", h.chat.DeleteChat).Methods("DELETE")
+	r.HandleFunc("/chats/{id}/messages/", h.chat.CreateMessage).Methods("POST")
+	r.HandleFunc("/chats/{id}/messages/batch", h.chat.CreateMessages).Methods("POST")
+	r.HandleFunc("/chats/{id}/messages/{msgID}/report", h.report.ReportMessage).Methods("POST")
+	r.HandleFunc("/chats/{id}/scheduled", h.schedule.ListScheduled).Methods("GET")
+	r.HandleFunc("/chats/{id}/scheduled/{schedID}", h.schedule.UpdateScheduled).Methods("PATCH")
+	r.HandleFunc("/chats/{id}/scheduled/{schedID}", h.schedule.CancelScheduled).Methods("DELETE")
+	r.HandleFunc("/chats/{id}/read", h.chat.MarkRead).Methods("POST")
+	r.HandleFunc("/chats/{id}/export", h.export.ExportChat).Methods("GET")
+	r.HandleFunc("/chats/{id}/typing", h.presence.Typing).Methods("POST")
+	r.HandleFunc("/chats/{id}/presence", h.presence.GetPresence).Methods("GET")
+	r.HandleFunc("/chats/{id}/pins", h.pin.ListPins).Methods("GET")
+	r.HandleFunc("/chats/{id}/pins/{msgID}", h.pin.PinMessage).Methods("POST")
+	r.HandleFunc("/chats/{id}/pins/{msgID}", h.pin.UnpinMessage).Methods("DELETE")
+	r.HandleFunc("/chats/{id}/retention", h.retention.GetPolicy).Methods("GET")
+	r.HandleFunc("/chats/{id}/retention", h.retention.SetPolicy).Methods("PUT")
+	r.HandleFunc("/chats/{id}/retention", h.retention.DeletePolicy).Methods("DELETE")
+	r.HandleFunc("/chats/{id}/moderation/rules", h.moderation.ListRules).Methods("GET")
+	r.HandleFunc("/chats/{id}/moderation/rules", h.moderation.CreateRule).Methods("POST")
+	r.HandleFunc("/chats/{id}/moderation/rules/{ruleID}", h.moderation.DeleteRule).Methods("DELETE")
+	r.HandleFunc("/moderation/queue", h.moderation.Queue).Methods("GET")
+	r.HandleFunc("/moderation/queue/{msgID}/approve", h.moderation.Approve).Methods("POST")
+	r.HandleFunc("/moderation/queue/{msgID}/reject", h.moderation.Reject).Methods("POST")
+	r.HandleFunc("/chats/{id}/members", h.member.AddMember).Methods("POST")
+	r.HandleFunc("/chats/{id}/members", h.member.ListMembers).Methods("GET")
+	r.HandleFunc("/chats/{id}/attachments", h.attachment.Upload).Methods("POST")
+	r.HandleFunc("/attachments/{id}", h.attachment.Download).Methods("GET")
+	r.HandleFunc("/attachments/{id}/thumbnail", h.attachment.Thumbnail).Methods("GET")
+	r.HandleFunc("/chats/{id}/hooks", h.incoming.CreateHook).Methods("POST")
+	r.HandleFunc("/chats/{id}/hooks", h.incoming.ListHooks).Methods("GET")
+	r.HandleFunc("/chats/{id}/hooks/{hookID}", h.incoming.RevokeHook).Methods("DELETE")
+	r.HandleFunc("/hooks/{token}", h.incoming.PostMessage).Methods("POST")
+
+	admin := r.PathPrefix("/admin").Subrouter()
+	admin.Use(auth.AdminMiddleware(adminToken))
+	admin.HandleFunc("/import", h.imports.Import).Methods("POST")
+	admin.HandleFunc("/retention/report", h.retention.Report).Methods("GET")
+	// Комнатами без владельца (анонимными и импортированными) управляет администратор
+	admin.HandleFunc("/chats/{id}", h.chat.DeleteChat).Methods("DELETE")
+	admin.HandleFunc("/chats/{id}/retention", h.retention.SetPolicy).Methods("PUT")
+	admin.HandleFunc("/chats/{id}/retention", h.retention.DeletePolicy).Methods("DELETE")
+	admin.HandleFunc("/moderation/queue", h.moderation.Queue).Methods("GET")
+	admin.HandleFunc("/moderation/queue/{msgID}/approve", h.moderation.Approve).Methods("POST")
+	admin.HandleFunc("/moderation/queue/{msgID}/reject", h.moderation.Reject).Methods("POST")
+	admin.HandleFunc("/reports", h.report.ListReports).Methods("GET")
+	admin.HandleFunc("/reports/{id}/resolve", h.report.ResolveReport).Methods("POST")
+	admin.HandleFunc("/audit", h.audit.List).Methods("GET")
+	admin.Handle("/metrics", expvar.Handler()).Methods("GET")
+	// Исходящий вебхук может подписаться на события любого чата, поэтому управляет ими только администратор
+	admin.HandleFunc("/webhooks", h.webhook.CreateWebhook).Methods("POST")
+	admin.HandleFunc("/webhooks", h.webhook.ListWebhooks).Methods("GET")
+	admin.HandleFunc("/webhooks/{id}", h.webhook.DeleteWebhook).Methods("DELETE")
+	admin.HandleFunc("/webhooks/{id}/enable", h.webhook.EnableWebhook).Methods("POST")
+	admin.HandleFunc("/webhooks/{id}/deliveries", h.webhook.ListDeliveries).Methods("GET")
+
+	return r
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/openapi"
+)
+
+func TestRoutesDocumented(t *testing.T) {
+	var spec struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openapi.Document(), &spec); err != nil {
+		t.Fatalf("спецификация не разбирается: %v", err)
+	}
+	if spec.OpenAPI != "3.1.0" {
+		t.Errorf("ожидалась версия 3.1.0, получена %q", spec.OpenAPI)
+	}
+
+	registered := make(map[string]bool)
+	err := newRouter(handlers{}, nil, "", false).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		// Префиксы подроутеров вроде /admin сами запросы не обслуживают
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			t.Errorf("у маршрута %s не указаны методы", path)
+			return nil
+		}
+		for _, method := range methods {
+			method = strings.ToLower(method)
+			registered[method+" "+path] = true
+			if _, ok := spec.Paths[path][method]; !ok {
+				t.Errorf("маршрут %s %s не описан в openapi.json", strings.ToUpper(method), path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if !registered[method+" "+path] {
+				t.Errorf("в openapi.json описан несуществующий маршрут %s %s", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestDocsServed(t *testing.T) {
+	router := newRouter(handlers{}, nil, "", false)
+
+	for _, tt := range []struct {
+		path        string
+		contentType string
+	}{
+		{"/openapi.json", "application/json"},
+		{"/docs/", "text/html"},
+		{"/docs/swagger-ui-bundle.js", "javascript"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: ожидался статус 200, получен %d", tt.path, w.Code)
+		}
+		if !strings.Contains(w.Header().Get("Content-Type"), tt.contentType) {
+			t.Errorf("%s: ожидался Content-Type %s, получен %q", tt.path, tt.contentType, w.Header().Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package audit собирает метаданные запроса для журнала аудита.
+//
+// Middleware присваивает каждому запросу ID (берёт из X-Request-ID или генерирует),
+// возвращает его клиенту в том же заголовке и вместе с IP клиента кладёт в контекст.
+// Сервисы читают их через FromContext при записи в журнал.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader — заголовок с ID запроса
+const RequestIDHeader = "X-Request-ID"
+
+// MaxRequestID — ID длиннее этого от клиента не принимается и генерируется заново
+// (столбец request_id в audit_entries — VARCHAR(64))
+const MaxRequestID = 64
+
+// Request — метаданные запроса, попадающие в журнал аудита
+type Request struct {
+	ID string
+	IP string
+}
+
+type requestKey struct{}
+
+// WithRequest возвращает контекст с метаданными запроса
+func WithRequest(ctx context.Context, request Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// FromContext возвращает метаданные запроса; для фоновых задач — пустые
+func FromContext(ctx context.Context) Request {
+	request, _ := ctx.Value(requestKey{}).(Request)
+	return request
+}
+
+// Middleware кладёт в контекст ID запроса и IP клиента. С trustProxy IP берётся
+// из первого адреса X-Forwarded-For — включать только за доверенным прокси.
+// Некорректный адрес в заголовке игнорируется.
+func Middleware(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > MaxRequestID {
+				id = NewRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			request := Request{ID: id, IP: clientIP(r, trustProxy)}
+			next.ServeHTTP(w, r.WithContext(WithRequest(r.Context(), request)))
+		})
+	}
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := net.ParseIP(strings.TrimSpace(first)); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// NewRequestID генерирует случайный ID запроса
+func NewRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
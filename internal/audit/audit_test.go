@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		requestID  string
+		forwarded  string
+		wantID     string
+		wantIP     string
+	}{
+		{
+			name:   "generated request ID",
+			wantIP: "192.0.2.1",
+		},
+		{
+			name:      "client request ID",
+			requestID: "abc-123",
+			wantID:    "abc-123",
+			wantIP:    "192.0.2.1",
+		},
+		{
+			name:      "too long request ID is replaced",
+			requestID: strings.Repeat("a", MaxRequestID+1),
+			wantIP:    "192.0.2.1",
+		},
+		{
+			name:      "forwarded ignored without trusted proxy",
+			forwarded: "203.0.113.7",
+			wantIP:    "192.0.2.1",
+		},
+		{
+			name:       "invalid forwarded address",
+			trustProxy: true,
+			forwarded:  "not-an-ip",
+			wantIP:     "192.0.2.1",
+		},
+		{
+			name:       "forwarded from trusted proxy",
+			trustProxy: true,
+			forwarded:  "203.0.113.7, 10.0.0.1",
+			wantIP:     "203.0.113.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Request
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = FromContext(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			rr := httptest.NewRecorder()
+
+			Middleware(tt.trustProxy)(next).ServeHTTP(rr, req)
+
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, got.ID)
+			} else {
+				assert.Len(t, got.ID, 32)
+			}
+			assert.Equal(t, got.ID, rr.Header().Get(RequestIDHeader))
+			assert.Equal(t, tt.wantIP, got.IP)
+		})
+	}
+}
+
+func TestFromContextEmpty(t *testing.T) {
+	assert.Equal(t, Request{}, FromContext(context.Background()))
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+)
+
+// AdminHeader — заголовок с токеном администратора
+const AdminHeader = "X-Admin-Token"
+
+type adminKey struct{}
+
+// WithAdmin возвращает контекст запроса, выполняемого администратором
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin сообщает, прошёл ли запрос проверку токена администратора
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// AdminMiddleware пропускает только запросы с токеном администратора token (ADMIN_TOKEN).
+// Пустой token отключает административные эндпоинты целиком.
+func AdminMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.Error(w, "Admin API is disabled", http.StatusForbidden)
+				return
+			}
+
+			got := r.Header.Get(AdminHeader)
+			if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "Invalid admin token", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithAdmin(r.Context())))
+		})
+	}
+}
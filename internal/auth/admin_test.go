@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		header     string
+		wantStatus int
+	}{
+		{
+			name:       "valid token",
+			token:      "secret",
+			header:     "secret",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "wrong token",
+			token:      "secret",
+			header:     "guess",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no token",
+			token:      "secret",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "admin API disabled",
+			header:     "",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var admin bool
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				admin = IsAdmin(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set(AdminHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			AdminMiddleware(tt.token)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, admin)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package auth определяет текущего пользователя запроса.
+//
+// Полноценной аутентификации в сервисе пока нет: клиент передаёт свой ID в заголовке
+// X-User-ID, middleware проверяет, что такой пользователь существует, и кладёт ID
+// в контекст. Сервисы читают его через UserID.
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// Header — заголовок, в котором клиент передаёт ID пользователя
+const Header = "X-User-ID"
+
+type userKey struct{}
+
+// WithUserID возвращает контекст, в котором текущим пользователем считается userID
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserID возвращает ID текущего пользователя, false для анонимного запроса
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(userKey{}).(int64)
+	return userID, ok
+}
+
+// Middleware кладёт в контекст пользователя из заголовка X-User-ID.
+// Запрос без заголовка проходит анонимно, с некорректным или неизвестным ID — получает 401.
+func Middleware(userRepo repository.UserRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.Header.Get(Header)
+			if raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				http.Error(w, "Invalid user ID", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := userRepo.GetByID(r.Context(), userID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if user == nil {
+				http.Error(w, "Unknown user", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		setupMock  func(m *mocks.MockUserRepository)
+		wantStatus int
+		wantUserID int64
+		wantAuth   bool
+	}{
+		{
+			name:       "anonymous",
+			setupMock:  func(m *mocks.MockUserRepository) {},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:   "known user",
+			header: "7",
+			setupMock: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&models.User{ID: 7}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantUserID: 7,
+			wantAuth:   true,
+		},
+		{
+			name:   "unknown user",
+			header: "8",
+			setupMock: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), int64(8)).Return(nil, nil)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid id",
+			header:     "abc",
+			setupMock:  func(m *mocks.MockUserRepository) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			tt.setupMock(userRepo)
+
+			var (
+				gotUserID int64
+				gotAuth   bool
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUserID, gotAuth = UserID(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			Middleware(userRepo)(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.Equal(t, tt.wantAuth, gotAuth)
+			assert.Equal(t, tt.wantUserID, gotUserID)
+		})
+	}
+}
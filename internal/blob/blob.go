@@ -0,0 +1,31 @@
+// Package blob хранит содержимое файлов (вложения, миниатюры) отдельно от базы.
+//
+// Store — минимальный интерфейс, который реализуется как локальной файловой системой
+// (LocalStore), так и S3-совместимым хранилищем: Put — PutObject, Open — GetObject
+// с поддержкой Range-запросов для Seek, Delete — DeleteObject.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+//go:generate mockgen -destination=mocks/mock_store.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/blob Store
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Store — хранилище файлов по строковому ключу вида "chats/1/abcdef"
+type Store interface {
+	// Put сохраняет содержимое r под ключом key и возвращает число записанных байт.
+	// Если ключ уже занят, содержимое перезаписывается.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open открывает файл на чтение. ReadSeeker нужен для отдачи Range-запросов.
+	// Если файла нет, возвращает ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete удаляет файл. Удаление несуществующего файла не считается ошибкой.
+	Delete(ctx context.Context, key string) error
+}
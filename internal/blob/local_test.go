@@ -0,0 +1,61 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	written, err := store.Put(ctx, "chats/1/file", strings.NewReader("hello, world"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), written)
+
+	file, err := store.Open(ctx, "chats/1/file")
+	require.NoError(t, err)
+
+	_, err = file.Seek(7, io.SeekStart)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "world", string(rest))
+	require.NoError(t, file.Close())
+
+	require.NoError(t, store.Delete(ctx, "chats/1/file"))
+	require.NoError(t, store.Delete(ctx, "chats/1/file"), "повторное удаление не должно быть ошибкой")
+
+	_, err = store.Open(ctx, "chats/1/file")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "chats/../../outside", "chats//file", "chats/.hidden", `chats\file`} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, ErrInvalidKey, "ключ %q должен быть отклонён", key)
+	}
+}
+
+func TestLocalStore_CancelledPut(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = store.Put(ctx, "file", strings.NewReader("x"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = store.Open(context.Background(), "file")
+	assert.ErrorIs(t, err, ErrNotFound, "недописанный файл не должен появляться")
+}
@@ -0,0 +1,273 @@
+// Package export выгружает историю чата в JSON, CSV или Markdown.
+//
+// Кодировщик получает сообщения порциями и сразу пишет их в поток, ничего не накапливая,
+// поэтому память не зависит от размера чата. Вложения выгружаются ссылками на
+// GET /attachments/{id}, содержимое файлов в выгрузку не попадает.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "md"
+)
+
+// markdownTime — формат времени в заголовках сообщений Markdown-выгрузки
+const markdownTime = "2006-01-02 15:04:05 UTC"
+
+var ErrUnknownFormat = errors.New("format must be json, csv or md")
+
+// Encoder пишет выгрузку одного чата: Begin с участниками чата, затем Write для каждой
+// порции сообщений в хронологическом порядке и End.
+type Encoder interface {
+	ContentType() string
+	Extension() string
+	Begin(chat models.Chat, users []models.User) error
+	Write(messages []models.Message) error
+	End() error
+}
+
+// New возвращает кодировщик формата format, пишущий в w
+func New(format string, w io.Writer) (Encoder, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonEncoder{w: w}, nil
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+	case FormatMarkdown:
+		return &markdownEncoder{w: w}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// attachmentRef — ссылка на вложение вместо его содержимого
+type attachmentRef struct {
+	ID          int64  `json:"id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+}
+
+type messageRecord struct {
+	ID          int64           `json:"id"`
+	AuthorID    *int64          `json:"author_id,omitempty"`
+	Text        string          `json:"text"`
+	Format      string          `json:"format"`
+	CreatedAt   time.Time       `json:"created_at"`
+	EditedAt    *time.Time      `json:"edited_at,omitempty"`
+	Attachments []attachmentRef `json:"attachments,omitempty"`
+}
+
+func newRecord(message models.Message) messageRecord {
+	record := messageRecord{
+		ID:        message.ID,
+		AuthorID:  message.AuthorID,
+		Text:      message.Text,
+		Format:    message.Format,
+		CreatedAt: message.CreatedAt.UTC(),
+	}
+	if record.Format == "" {
+		record.Format = models.MessageFormatPlain
+	}
+	if message.EditedAt != nil {
+		editedAt := message.EditedAt.UTC()
+		record.EditedAt = &editedAt
+	}
+	for _, attachment := range message.Attachments {
+		record.Attachments = append(record.Attachments, attachmentRef{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			URL:         attachmentURL(attachment.ID),
+		})
+	}
+	return record
+}
+
+// usernames индексирует имена участников по ID для подписи сообщений
+func usernames(users []models.User) map[int64]string {
+	names := make(map[int64]string, len(users))
+	for _, user := range users {
+		names[user.ID] = user.Username
+	}
+	return names
+}
+
+func attachmentURL(id int64) string {
+	return "/attachments/" + strconv.FormatInt(id, 10)
+}
+
+// jsonEncoder пишет {"chat": {...}, "users": [...], "messages": [...]}, открывая массив
+// сообщений в Begin и закрывая в End
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) ContentType() string { return "application/json" }
+func (e *jsonEncoder) Extension() string   { return "json" }
+
+func (e *jsonEncoder) Begin(chat models.Chat, users []models.User) error {
+	header, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+	members, err := json.Marshal(users)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(e.w, `{"chat":%s,"users":%s,"messages":[`, header, members)
+	return err
+}
+
+func (e *jsonEncoder) Write(messages []models.Message) error {
+	for _, message := range messages {
+		data, err := json.Marshal(newRecord(message))
+		if err != nil {
+			return err
+		}
+		if e.count > 0 {
+			if _, err := io.WriteString(e.w, ","); err != nil {
+				return err
+			}
+		}
+		if _, err := e.w.Write(data); err != nil {
+			return err
+		}
+		e.count++
+	}
+	return nil
+}
+
+func (e *jsonEncoder) End() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+// csvEncoder пишет по строке на сообщение. Вложения перечисляются в одной ячейке через "; ".
+type csvEncoder struct {
+	w     *csv.Writer
+	names map[int64]string
+}
+
+func (e *csvEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+func (e *csvEncoder) Extension() string   { return "csv" }
+
+func (e *csvEncoder) Begin(chat models.Chat, users []models.User) error {
+	e.names = usernames(users)
+	return e.w.Write([]string{"id", "created_at", "edited_at", "author_id", "author", "format", "text", "attachments"})
+}
+
+func (e *csvEncoder) Write(messages []models.Message) error {
+	for _, message := range messages {
+		record := newRecord(message)
+
+		var editedAt, authorID, author string
+		if record.EditedAt != nil {
+			editedAt = record.EditedAt.Format(time.RFC3339)
+		}
+		if record.AuthorID != nil {
+			authorID = strconv.FormatInt(*record.AuthorID, 10)
+			author = e.names[*record.AuthorID]
+		}
+
+		attachments := make([]string, 0, len(record.Attachments))
+		for _, attachment := range record.Attachments {
+			attachments = append(attachments, fmt.Sprintf("%s (%s)", attachment.FileName, attachment.URL))
+		}
+
+		err := e.w.Write([]string{
+			strconv.FormatInt(record.ID, 10),
+			record.CreatedAt.Format(time.RFC3339),
+			editedAt,
+			authorID,
+			author,
+			record.Format,
+			record.Text,
+			strings.Join(attachments, "; "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// markdownEncoder пишет документ для чтения человеком: заголовок чата и по разделу на сообщение
+type markdownEncoder struct {
+	w     io.Writer
+	names map[int64]string
+}
+
+func (e *markdownEncoder) ContentType() string { return "text/markdown; charset=utf-8" }
+func (e *markdownEncoder) Extension() string   { return "md" }
+
+func (e *markdownEncoder) Begin(chat models.Chat, users []models.User) error {
+	e.names = usernames(users)
+	title := chat.Title
+	if title == "" {
+		title = fmt.Sprintf("Chat %d", chat.ID)
+	}
+	_, err := fmt.Fprintf(e.w, "# %s\n", title)
+	return err
+}
+
+func (e *markdownEncoder) Write(messages []models.Message) error {
+	for _, message := range messages {
+		record := newRecord(message)
+
+		var b strings.Builder
+		author := "unknown"
+		if record.AuthorID != nil {
+			author = e.names[*record.AuthorID]
+			if author == "" {
+				author = "user " + strconv.FormatInt(*record.AuthorID, 10)
+			}
+		}
+		fmt.Fprintf(&b, "\n### %s · %s · #%d\n\n", record.CreatedAt.Format(markdownTime), author, record.ID)
+		b.WriteString(record.Text)
+		b.WriteString("\n")
+		if record.EditedAt != nil {
+			fmt.Fprintf(&b, "\n*edited %s*\n", record.EditedAt.Format(markdownTime))
+		}
+		if len(record.Attachments) > 0 {
+			b.WriteString("\n")
+			for _, attachment := range record.Attachments {
+				fmt.Fprintf(&b, "- [%s](%s) (%s, %d bytes)\n", attachment.FileName, attachment.URL, attachment.ContentType, attachment.Size)
+			}
+		}
+
+		if _, err := io.WriteString(e.w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *markdownEncoder) End() error {
+	return nil
+}
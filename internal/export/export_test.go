@@ -0,0 +1,108 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+func testMessages() [][]models.Message {
+	author := int64(2)
+	createdAt := time.Date(2026, 1, 28, 10, 30, 0, 0, time.UTC)
+	editedAt := createdAt.Add(5 * time.Minute)
+
+	return [][]models.Message{
+		{
+			{ID: 1, ChatID: 1, AuthorID: &author, Text: "привет, \"мир\"", Format: models.MessageFormatPlain, CreatedAt: createdAt},
+			{ID: 2, ChatID: 1, Text: "**отчёт**", Format: models.MessageFormatMarkdown, CreatedAt: createdAt.Add(time.Minute), EditedAt: &editedAt},
+		},
+		{
+			{ID: 3, ChatID: 1, AuthorID: &author, Text: "файл", CreatedAt: createdAt.Add(2 * time.Minute), Attachments: []models.Attachment{
+				{ID: 7, MessageID: 3, FileName: "report.pdf", ContentType: "application/pdf", Size: 1024, StorageKey: "secret"},
+			}},
+		},
+	}
+}
+
+func encode(t *testing.T, format string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc, err := New(format, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, enc.Begin(models.Chat{ID: 1, Title: "General", Type: models.ChatTypeRoom}, []models.User{{ID: 2, Username: "alice"}}))
+	for _, batch := range testMessages() {
+		require.NoError(t, enc.Write(batch))
+	}
+	require.NoError(t, enc.End())
+
+	return buf.String()
+}
+
+func TestJSON(t *testing.T) {
+	out := encode(t, FormatJSON)
+
+	var doc struct {
+		Chat     models.Chat     `json:"chat"`
+		Users    []models.User   `json:"users"`
+		Messages []messageRecord `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+
+	assert.Equal(t, "General", doc.Chat.Title)
+	require.Len(t, doc.Users, 1)
+	assert.Equal(t, "alice", doc.Users[0].Username)
+	require.Len(t, doc.Messages, 3, "порции склеиваются в один массив")
+	assert.Equal(t, models.MessageFormatPlain, doc.Messages[2].Format, "пустой формат выгружается как plain")
+	require.NotNil(t, doc.Messages[1].EditedAt)
+	require.Len(t, doc.Messages[2].Attachments, 1)
+	assert.Equal(t, "/attachments/7", doc.Messages[2].Attachments[0].URL)
+	assert.NotContains(t, out, "secret", "ключ хранилища не выгружается")
+}
+
+func TestJSONEmptyChat(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := New(FormatJSON, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, enc.Begin(models.Chat{ID: 1, Title: "General"}, nil))
+	require.NoError(t, enc.End())
+
+	assert.True(t, json.Valid(buf.Bytes()))
+	assert.Contains(t, buf.String(), `"users":[],"messages":[]`)
+}
+
+func TestCSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(encode(t, FormatCSV))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, rows, 4)
+	assert.Equal(t, []string{"id", "created_at", "edited_at", "author_id", "author", "format", "text", "attachments"}, rows[0])
+	assert.Equal(t, []string{"1", "2026-01-28T10:30:00Z", "", "2", "alice", "plain", "привет, \"мир\"", ""}, rows[1])
+	assert.Equal(t, "2026-01-28T10:35:00Z", rows[2][2])
+	assert.Equal(t, "report.pdf (/attachments/7)", rows[3][7])
+}
+
+func TestMarkdown(t *testing.T) {
+	out := encode(t, FormatMarkdown)
+
+	assert.True(t, strings.HasPrefix(out, "# General\n"))
+	assert.Contains(t, out, "### 2026-01-28 10:30:00 UTC · alice · #1")
+	assert.Contains(t, out, "### 2026-01-28 10:31:00 UTC · unknown · #2")
+	assert.Contains(t, out, "*edited 2026-01-28 10:35:00 UTC*")
+	assert.Contains(t, out, "- [report.pdf](/attachments/7) (application/pdf, 1024 bytes)")
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := New("xml", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
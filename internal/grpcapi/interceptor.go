@@ -0,0 +1,120 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/GlebMoskalev/chat-golang/internal/audit"
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// Ключи метаданных, аналоги заголовков X-User-ID и X-Request-ID
+const (
+	UserIDKey    = "x-user-id"
+	RequestIDKey = "x-request-id"
+)
+
+// withRequest кладёт в контекст ID запроса из x-request-id (или новый) и IP клиента,
+// как audit.Middleware в REST, и возвращает ID клиенту в заголовке ответа
+func withRequest(ctx context.Context) (context.Context, metadata.MD) {
+	id := metadataValue(ctx, RequestIDKey)
+	if id == "" || len(id) > audit.MaxRequestID {
+		id = audit.NewRequestID()
+	}
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+
+	return audit.WithRequest(ctx, audit.Request{ID: id, IP: ip}), metadata.Pairs(RequestIDKey, id)
+}
+
+func requestUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, header := withRequest(ctx)
+	grpc.SetHeader(ctx, header)
+	return handler(ctx, req)
+}
+
+func requestStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, header := withRequest(ss.Context())
+	ss.SetHeader(header)
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate кладёт в контекст пользователя из x-user-id. Вызов без метаданных проходит
+// анонимно, с некорректным или неизвестным ID — получает Unauthenticated.
+func authenticate(ctx context.Context, userRepo repository.UserRepository) (context.Context, error) {
+	raw := metadataValue(ctx, UserIDKey)
+	if raw == "" {
+		return ctx, nil
+	}
+
+	userID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid user ID")
+	}
+
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if user == nil {
+		return nil, status.Error(codes.Unauthenticated, "unknown user")
+	}
+
+	return auth.WithUserID(ctx, userID), nil
+}
+
+func authUnaryInterceptor(userRepo repository.UserRepository) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, userRepo)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(userRepo repository.UserRepository) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), userRepo)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextStream подменяет контекст потока, чтобы перехватчики могли дополнить его
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,182 @@
+// Package grpcapi — gRPC API для внутренних сервисов поверх того же сервисного слоя, что и REST.
+//
+// Контракт описан в api/proto/chat/v1/chat.proto, код в chatv1 сгенерирован protoc.
+// Текущий пользователь передаётся в метаданных x-user-id, ID запроса — в x-request-id.
+// Ошибки сервисов переводятся в коды gRPC так же, как REST переводит их в HTTP-статусы.
+package grpcapi
+
+//go:generate protoc -I ../../api/proto --go_out=../.. --go_opt=module=github.com/GlebMoskalev/chat-golang --go-grpc_out=../.. --go-grpc_opt=module=github.com/GlebMoskalev/chat-golang chat/v1/chat.proto
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/GlebMoskalev/chat-golang/internal/grpcapi/chatv1"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type Server struct {
+	chatv1.UnimplementedChatServiceServer
+
+	chats   service.ChatServiceInterface
+	streams service.StreamServiceInterface
+}
+
+func NewServer(chats service.ChatServiceInterface, streams service.StreamServiceInterface) *Server {
+	return &Server{chats: chats, streams: streams}
+}
+
+// NewGRPCServer создаёт gRPC-сервер с зарегистрированным ChatService и перехватчиками
+// метаданных запроса и текущего пользователя
+func NewGRPCServer(srv *Server, userRepo repository.UserRepository) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(requestUnaryInterceptor, authUnaryInterceptor(userRepo)),
+		grpc.ChainStreamInterceptor(requestStreamInterceptor, authStreamInterceptor(userRepo)),
+	)
+	chatv1.RegisterChatServiceServer(server, srv)
+	return server
+}
+
+func (s *Server) CreateChat(ctx context.Context, req *chatv1.CreateChatRequest) (*chatv1.Chat, error) {
+	chat, err := s.chats.CreateChat(ctx, req.GetTitle())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toChat(chat), nil
+}
+
+func (s *Server) GetChatWithMessages(ctx context.Context, req *chatv1.GetChatWithMessagesRequest) (*chatv1.ChatWithMessages, error) {
+	result, err := s.chats.GetChatWithMessages(ctx, req.GetChatId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	messages := make([]*chatv1.Message, 0, len(result.Messages))
+	for i := range result.Messages {
+		messages = append(messages, toMessage(&result.Messages[i]))
+	}
+	return &chatv1.ChatWithMessages{Chat: toChat(&result.Chat), Messages: messages}, nil
+}
+
+func (s *Server) DeleteChat(ctx context.Context, req *chatv1.DeleteChatRequest) (*emptypb.Empty, error) {
+	if err := s.chats.DeleteChat(ctx, req.GetChatId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) CreateMessage(ctx context.Context, req *chatv1.CreateMessageRequest) (*chatv1.Message, error) {
+	message, err := s.chats.CreateMessage(ctx, req.GetChatId(), models.MessageInput{
+		Text:      req.GetText(),
+		Format:    req.GetFormat(),
+		ExpiresIn: req.GetExpiresIn(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toMessage(message), nil
+}
+
+// Subscribe пересылает события чата, пока клиент не отменит вызов
+func (s *Server) Subscribe(req *chatv1.SubscribeRequest, stream chatv1.ChatService_SubscribeServer) error {
+	events, err := s.streams.Subscribe(stream.Context(), req.GetChatId())
+	if err != nil {
+		return toStatus(err)
+	}
+
+	types := make(map[string]bool, len(req.GetEventTypes()))
+	for _, eventType := range req.GetEventTypes() {
+		types[eventType] = true
+	}
+
+	for event := range events {
+		if len(types) > 0 && !types[event.EventType] {
+			continue
+		}
+		if err := stream.Send(toEvent(event)); err != nil {
+			return err
+		}
+	}
+	return toStatus(stream.Context().Err())
+}
+
+// toStatus переводит ошибку сервиса в статус gRPC. Сервисы возвращают текстовые ошибки,
+// поэтому разбор идёт по тексту, как в xxxErrorStatus обработчиков REST.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return status.Error(codes.Unauthenticated, msg)
+	case msg == "forbidden":
+		return status.Error(codes.PermissionDenied, msg)
+	case strings.HasSuffix(msg, "not found"):
+		return status.Error(codes.NotFound, msg)
+	case strings.HasPrefix(msg, "message rejected"):
+		// Запрос корректен, но модерация не даёт его выполнить — в REST это 422
+		return status.Error(codes.FailedPrecondition, msg)
+	case strings.HasSuffix(msg, "cannot be empty"), strings.Contains(msg, " must "):
+		return status.Error(codes.InvalidArgument, msg)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func toChat(chat *models.Chat) *chatv1.Chat {
+	return &chatv1.Chat{
+		Id:        chat.ID,
+		Title:     chat.Title,
+		Type:      chat.Type,
+		OwnerId:   chat.OwnerID,
+		CreatedAt: timestamppb.New(chat.CreatedAt),
+	}
+}
+
+func toMessage(message *models.Message) *chatv1.Message {
+	return &chatv1.Message{
+		Id:        message.ID,
+		ChatId:    message.ChatID,
+		AuthorId:  message.AuthorID,
+		Text:      message.Text,
+		Format:    message.Format,
+		Html:      message.HTML,
+		CreatedAt: timestamppb.New(message.CreatedAt),
+		EditedAt:  optionalTimestamp(message.EditedAt),
+		ExpiresAt: optionalTimestamp(message.ExpiresAt),
+		Mentions:  message.Mentions,
+		ReadBy:    message.ReadBy,
+		Pinned:    message.Pinned,
+	}
+}
+
+func toEvent(event models.OutboxEvent) *chatv1.Event {
+	return &chatv1.Event{
+		Id:        event.ID,
+		Type:      event.EventType,
+		ChatId:    event.ChatID,
+		Payload:   event.Payload,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,232 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/GlebMoskalev/chat-golang/internal/audit"
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/grpcapi/chatv1"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	repoMocks "github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+)
+
+// newClient поднимает gRPC-сервер в памяти и возвращает клиента к нему
+func newClient(t *testing.T, chats *mocks.MockChatServiceInterface, streams *mocks.MockStreamServiceInterface, users *repoMocks.MockUserRepository) chatv1.ChatServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(NewServer(chats, streams), users)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("не удалось подключиться: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return chatv1.NewChatServiceClient(conn)
+}
+
+func TestCreateChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chats := mocks.NewMockChatServiceInterface(ctrl)
+	users := repoMocks.NewMockUserRepository(ctrl)
+	client := newClient(t, chats, mocks.NewMockStreamServiceInterface(ctrl), users)
+
+	owner := int64(7)
+	users.EXPECT().GetByID(gomock.Any(), owner).Return(&models.User{ID: owner}, nil)
+	chats.EXPECT().
+		CreateChat(gomock.Any(), "General").
+		DoAndReturn(func(ctx context.Context, title string) (*models.Chat, error) {
+			if userID, ok := auth.UserID(ctx); !ok || userID != owner {
+				t.Errorf("ожидался пользователь %d из x-user-id, получен %d", owner, userID)
+			}
+			if audit.FromContext(ctx).ID != "req-1" {
+				t.Errorf("ожидался ID запроса req-1, получен %q", audit.FromContext(ctx).ID)
+			}
+			return &models.Chat{ID: 1, Title: title, Type: models.ChatTypeRoom, OwnerID: &owner, CreatedAt: time.Now()}, nil
+		})
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), UserIDKey, "7", RequestIDKey, "req-1")
+	var header metadata.MD
+	chat, err := client.CreateChat(ctx, &chatv1.CreateChatRequest{Title: "General"}, grpc.Header(&header))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if chat.GetId() != 1 || chat.GetTitle() != "General" || chat.GetOwnerId() != owner {
+		t.Errorf("неожиданный чат: %v", chat)
+	}
+	if got := header.Get(RequestIDKey); len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("ожидался x-request-id req-1 в ответе, получен %v", got)
+	}
+}
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(chatv1.ChatServiceClient) error
+		setup    func(*mocks.MockChatServiceInterface)
+		wantCode codes.Code
+	}{
+		{
+			name: "чат не найден",
+			call: func(c chatv1.ChatServiceClient) error {
+				_, err := c.DeleteChat(context.Background(), &chatv1.DeleteChatRequest{ChatId: 9})
+				return err
+			},
+			setup: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().DeleteChat(gomock.Any(), int64(9)).Return(errors.New("chat not found"))
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "пустой текст",
+			call: func(c chatv1.ChatServiceClient) error {
+				_, err := c.CreateMessage(context.Background(), &chatv1.CreateMessageRequest{ChatId: 1})
+				return err
+			},
+			setup: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().CreateMessage(gomock.Any(), int64(1), models.MessageInput{}).Return(nil, errors.New("text cannot be empty"))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "отклонено модерацией",
+			call: func(c chatv1.ChatServiceClient) error {
+				_, err := c.CreateMessage(context.Background(), &chatv1.CreateMessageRequest{ChatId: 1, Text: "казино"})
+				return err
+			},
+			setup: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().CreateMessage(gomock.Any(), int64(1), models.MessageInput{Text: "казино"}).Return(nil, errors.New("message rejected: word: казино"))
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "не участник",
+			call: func(c chatv1.ChatServiceClient) error {
+				_, err := c.GetChatWithMessages(context.Background(), &chatv1.GetChatWithMessagesRequest{ChatId: 1, Limit: 5})
+				return err
+			},
+			setup: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().GetChatWithMessages(gomock.Any(), int64(1), 5).Return(nil, errors.New("forbidden"))
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "сбой базы",
+			call: func(c chatv1.ChatServiceClient) error {
+				_, err := c.CreateChat(context.Background(), &chatv1.CreateChatRequest{Title: "x"})
+				return err
+			},
+			setup: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().CreateChat(gomock.Any(), "x").Return(nil, errors.New("connection refused"))
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			chats := mocks.NewMockChatServiceInterface(ctrl)
+			tt.setup(chats)
+			client := newClient(t, chats, mocks.NewMockStreamServiceInterface(ctrl), repoMocks.NewMockUserRepository(ctrl))
+
+			if got := status.Code(tt.call(client)); got != tt.wantCode {
+				t.Errorf("ожидался код %s, получен %s", tt.wantCode, got)
+			}
+		})
+	}
+}
+
+func TestUnknownUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := repoMocks.NewMockUserRepository(ctrl)
+	users.EXPECT().GetByID(gomock.Any(), int64(404)).Return(nil, nil)
+	client := newClient(t, mocks.NewMockChatServiceInterface(ctrl), mocks.NewMockStreamServiceInterface(ctrl), users)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), UserIDKey, "404")
+	if _, err := client.CreateChat(ctx, &chatv1.CreateChatRequest{Title: "x"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ожидался Unauthenticated, получено %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), UserIDKey, "abc")
+	if _, err := client.CreateChat(ctx, &chatv1.CreateChatRequest{Title: "x"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ожидался Unauthenticated, получено %v", err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := repoMocks.NewMockUserRepository(ctrl)
+	streams := mocks.NewMockStreamServiceInterface(ctrl)
+	client := newClient(t, mocks.NewMockChatServiceInterface(ctrl), streams, users)
+
+	users.EXPECT().GetByID(gomock.Any(), int64(7)).Return(&models.User{ID: 7}, nil)
+	events := make(chan models.OutboxEvent, 2)
+	events <- models.OutboxEvent{ID: 1, EventType: models.EventTyping, ChatID: 1}
+	events <- models.OutboxEvent{ID: 2, EventType: models.EventMessageCreated, ChatID: 1, Payload: `{"id":5}`}
+	close(events)
+	streams.EXPECT().Subscribe(gomock.Any(), int64(1)).Return(events, nil)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), UserIDKey, "7")
+	stream, err := client.Subscribe(ctx, &chatv1.SubscribeRequest{ChatId: 1, EventTypes: []string{models.EventMessageCreated}})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if event.GetId() != 2 || event.GetPayload() != `{"id":5}` {
+		t.Errorf("ожидалось только событие message.created, получено %v", event)
+	}
+	if _, err := stream.Recv(); err == nil {
+		t.Error("после закрытия подписки поток должен завершиться")
+	}
+}
+
+func TestSubscribeForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	streams := mocks.NewMockStreamServiceInterface(ctrl)
+	streams.EXPECT().Subscribe(gomock.Any(), int64(1)).Return(nil, errors.New("authentication required"))
+	client := newClient(t, mocks.NewMockChatServiceInterface(ctrl), streams, repoMocks.NewMockUserRepository(ctrl))
+
+	stream, err := client.Subscribe(context.Background(), &chatv1.SubscribeRequest{ChatId: 1})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ожидался Unauthenticated, получено %v", err)
+	}
+}
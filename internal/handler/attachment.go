@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+// multipartMemory — сколько данных формы держать в памяти, остальное уходит во временные файлы
+const multipartMemory = 1 << 20
+
+type AttachmentHandler struct {
+	service service.AttachmentServiceInterface
+	maxSize int64
+}
+
+// NewAttachmentHandler создаёт обработчик. maxSize — лимит размера файла, к нему
+// добавляется запас на служебные части multipart-формы.
+func NewAttachmentHandler(service service.AttachmentServiceInterface, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{service: service, maxSize: maxSize}
+}
+
+// Upload принимает multipart/form-data с полем file и необязательной подписью text
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+multipartMemory)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "file too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	message, err := h.service.Upload(r.Context(), chatID, header.Filename, r.FormValue("text"), file)
+	if err != nil {
+		if err.Error() == "file too large" {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, err.Error(), chatErrorStatus(err, http.StatusBadRequest))
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(message)
+}
+
+// Download отдаёт содержимое вложения участнику чата. http.ServeContent поддерживает Range
+// и условные запросы (If-Modified-Since, If-Range).
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, file, err := h.service.Open(r.Context(), id)
+	if err != nil {
+		switch err.Error() {
+		case "attachment not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "authentication required":
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer file.Close()
+
+	// Картинки показываем в браузере, всё остальное (в том числе HTML) только скачиваем
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, attachment.FileName, attachment.CreatedAt, file)
+}
+
+// Thumbnail отдаёт миниатюру картинки. Пока она не сгенерирована, ответ — 404.
+func (h *AttachmentHandler) Thumbnail(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	attachment, file, err := h.service.OpenThumbnail(r.Context(), id)
+	if err != nil {
+		switch err.Error() {
+		case "thumbnail not found":
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case "authentication required":
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", attachment.ThumbnailContentType())
+	w.Header().Set("Content-Disposition", "inline")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", attachment.CreatedAt, file)
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+// nopSeekCloser превращает strings.Reader в io.ReadSeekCloser
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+func multipartBody(t *testing.T, fileName, content, text string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if text != "" {
+		writer.WriteField("text", text)
+	}
+	if fileName != "" {
+		part, err := writer.CreateFormFile("file", fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		fileName       string
+		content        string
+		text           string
+		setupMock      func(*mocks.MockAttachmentServiceInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:     "успешная загрузка",
+			fileName: "report.txt",
+			content:  "hello",
+			text:     "отчёт",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().
+					Upload(gomock.Any(), int64(1), "report.txt", "отчёт", gomock.Any()).
+					DoAndReturn(func(_, _, _, _ any, content io.Reader) (*models.Message, error) {
+						data, _ := io.ReadAll(content)
+						if string(data) != "hello" {
+							t.Errorf("ожидалось содержимое 'hello', получено %q", data)
+						}
+						return &models.Message{ID: 1, ChatID: 1, Attachments: []models.Attachment{{ID: 5}}}, nil
+					})
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"url":"/attachments/5"`,
+		},
+		{
+			name:           "без файла",
+			text:           "отчёт",
+			setupMock:      func(m *mocks.MockAttachmentServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "file is required",
+		},
+		{
+			name:     "слишком большой файл",
+			fileName: "big.bin",
+			content:  "x",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Upload(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("file too large"))
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "чат не найден",
+			fileName: "report.txt",
+			content:  "hello",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Upload(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("chat not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "без пользователя",
+			fileName: "report.txt",
+			content:  "hello",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Upload(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("authentication required"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:     "чужой личный чат",
+			fileName: "report.txt",
+			content:  "hello",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Upload(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:     "подпись отклонена модерацией",
+			fileName: "report.txt",
+			content:  "hello",
+			text:     "запрет",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Upload(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("message rejected: word: запрет"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAttachmentServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewAttachmentHandler(mockService, 1<<20)
+
+			body, contentType := multipartBody(t, tt.fileName, tt.content, tt.text)
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			handler.Upload(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedBody != "" && !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("ожидалось тело ответа содержащее %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	tests := []struct {
+		name                string
+		id                  string
+		rangeHeader         string
+		setupMock           func(*mocks.MockAttachmentServiceInterface)
+		expectedStatus      int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			name: "весь файл",
+			id:   "1",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Open(gomock.Any(), int64(1)).Return(
+					&models.Attachment{ID: 1, FileName: "report.txt", ContentType: "text/plain; charset=utf-8", CreatedAt: time.Now()},
+					nopSeekCloser{strings.NewReader("hello, world")}, nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "hello, world",
+			expectedDisposition: `attachment; filename=report.txt`,
+		},
+		{
+			name:        "Range-запрос",
+			id:          "1",
+			rangeHeader: "bytes=7-11",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Open(gomock.Any(), int64(1)).Return(
+					&models.Attachment{ID: 1, FileName: "photo.png", ContentType: "image/png", CreatedAt: time.Now()},
+					nopSeekCloser{strings.NewReader("hello, world")}, nil)
+			},
+			expectedStatus:      http.StatusPartialContent,
+			expectedBody:        "world",
+			expectedDisposition: `inline; filename=photo.png`,
+		},
+		{
+			name: "вложение не найдено",
+			id:   "999",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Open(gomock.Any(), int64(999)).Return(nil, nil, errors.New("attachment not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "без пользователя",
+			id:   "1",
+			setupMock: func(m *mocks.MockAttachmentServiceInterface) {
+				m.EXPECT().Open(gomock.Any(), int64(1)).Return(nil, nil, errors.New("authentication required"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "невалидный ID",
+			id:             "abc",
+			setupMock:      func(m *mocks.MockAttachmentServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAttachmentServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewAttachmentHandler(mockService, 1<<20)
+
+			req := httptest.NewRequest(http.MethodGet, "/attachments/"+tt.id, nil)
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+
+			handler.Download(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("ожидалось тело %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedDisposition != "" && w.Header().Get("Content-Disposition") != tt.expectedDisposition {
+				t.Errorf("ожидался Content-Disposition %q, получен %q", tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
+
+func TestAttachmentThumbnail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAttachmentServiceInterface(ctrl)
+	mockService.EXPECT().OpenThumbnail(gomock.Any(), int64(1)).Return(
+		&models.Attachment{ID: 1, ContentType: "image/gif", ThumbnailKey: "k", CreatedAt: time.Now()},
+		nopSeekCloser{strings.NewReader("png-bytes")}, nil)
+	mockService.EXPECT().OpenThumbnail(gomock.Any(), int64(2)).Return(nil, nil, errors.New("thumbnail not found"))
+
+	handler := NewAttachmentHandler(mockService, 1<<20)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/attachments/1/thumbnail", nil), map[string]string{"id": "1"})
+	w := httptest.NewRecorder()
+	handler.Thumbnail(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("ожидался статус %d, получен %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("миниатюра GIF должна отдаваться как PNG, получен %q", w.Header().Get("Content-Type"))
+	}
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/attachments/2/thumbnail", nil), map[string]string{"id": "2"})
+	w = httptest.NewRecorder()
+	handler.Thumbnail(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("ожидался статус %d, получен %d", http.StatusNotFound, w.Code)
+	}
+}
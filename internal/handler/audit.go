@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type AuditHandler struct {
+	service service.AuditServiceInterface
+}
+
+func NewAuditHandler(service service.AuditServiceInterface) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// List — журнал аудита для администратора, новые записи первыми:
+// ?actor_id=&action=&target_type=&target_id=&chat_id=&since=&until=&before=<id>&limit=50.
+// since и until — время в RFC 3339, before — ID последней записи предыдущей страницы.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.AuditFilter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		Limit:      50,
+	}
+
+	var err error
+	if filter.ActorID, err = optionalID(query, "actor_id"); err != nil {
+		http.Error(w, "Invalid actor_id", http.StatusBadRequest)
+		return
+	}
+	if filter.TargetID, err = optionalID(query, "target_id"); err != nil {
+		http.Error(w, "Invalid target_id", http.StatusBadRequest)
+		return
+	}
+	if filter.ChatID, err = optionalID(query, "chat_id"); err != nil {
+		http.Error(w, "Invalid chat_id", http.StatusBadRequest)
+		return
+	}
+	if filter.Since, err = optionalTime(query, "since"); err != nil {
+		http.Error(w, "Invalid since", http.StatusBadRequest)
+		return
+	}
+	if filter.Until, err = optionalTime(query, "until"); err != nil {
+		http.Error(w, "Invalid until", http.StatusBadRequest)
+		return
+	}
+	if before := query.Get("before"); before != "" {
+		id, err := strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid before", http.StatusBadRequest)
+			return
+		}
+		filter.BeforeID = id
+	}
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil {
+		filter.Limit = l
+	}
+
+	entries, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), auditErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func optionalID(query url.Values, key string) (*int64, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func optionalTime(query url.Values, key string) (*time.Time, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func auditErrorStatus(err error) int {
+	if strings.HasPrefix(err.Error(), "since must") {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListAudit(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mocks.MockAuditServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:  "фильтр и курсор",
+			query: "?actor_id=7&action=chat.delete&chat_id=3&since=2026-10-01T00:00:00Z&before=100&limit=20",
+			setupMock: func(m *mocks.MockAuditServiceInterface) {
+				m.EXPECT().
+					List(gomock.Any(), gomock.Cond(func(f models.AuditFilter) bool {
+						return *f.ActorID == 7 && f.Action == models.AuditChatDelete && *f.ChatID == 3 &&
+							f.Since.Equal(since) && f.Until == nil && f.TargetID == nil && f.BeforeID == 100 && f.Limit == 20
+					})).
+					Return([]models.AuditEntry{{ID: 99, Action: models.AuditChatDelete}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "без фильтров",
+			query: "",
+			setupMock: func(m *mocks.MockAuditServiceInterface) {
+				m.EXPECT().List(gomock.Any(), models.AuditFilter{Limit: 50}).Return([]models.AuditEntry{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "некорректное время",
+			query:          "?since=yesterday",
+			setupMock:      func(m *mocks.MockAuditServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "некорректный actor_id",
+			query:          "?actor_id=abc",
+			setupMock:      func(m *mocks.MockAuditServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuditServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewAuditHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
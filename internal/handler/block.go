@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type BlockHandler struct {
+	service service.BlockServiceInterface
+}
+
+func NewBlockHandler(service service.BlockServiceInterface) *BlockHandler {
+	return &BlockHandler{service: service}
+}
+
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Block(r.Context(), userID); err != nil {
+		http.Error(w, err.Error(), blockErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Unblock(r.Context(), userID); err != nil {
+		http.Error(w, err.Error(), blockErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *BlockHandler) ListBlocked(w http.ResponseWriter, r *http.Request) {
+	blocks, err := h.service.ListBlocked(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), blockErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(blocks)
+}
+
+func blockErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "user not found":
+		return http.StatusNotFound
+	case "cannot block yourself":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -4,18 +4,22 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/GlebMoskalev/chat-golang/internal/models"
 	"github.com/GlebMoskalev/chat-golang/internal/service"
 )
 
 type ChatHandler struct {
-	service service.ChatServiceInterface
+	service   service.ChatServiceInterface
+	scheduler service.ScheduleServiceInterface
 }
 
-func NewChatHandler(service service.ChatServiceInterface) *ChatHandler {
-	return &ChatHandler{service: service}
+func NewChatHandler(service service.ChatServiceInterface, scheduler service.ScheduleServiceInterface) *ChatHandler {
+	return &ChatHandler{service: service, scheduler: scheduler}
 }
 
 func (h *ChatHandler) CreateChat(w http.ResponseWriter, r *http.Request) {
@@ -57,7 +61,7 @@ func (h *ChatHandler) GetChat(w http.ResponseWriter, r *http.Request) {
 
 	chatWithMessages, err := h.service.GetChatWithMessages(r.Context(), id, limit)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		http.Error(w, err.Error(), chatErrorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -74,7 +78,7 @@ func (h *ChatHandler) DeleteChat(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.DeleteChat(r.Context(), id); err != nil {
-		http.Error(w, "Chat not found", http.StatusNotFound)
+		http.Error(w, err.Error(), chatErrorStatus(err, http.StatusInternalServerError))
 		return
 	}
 
@@ -90,21 +94,31 @@ func (h *ChatHandler) CreateMessage(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		Text string `json:"text"`
+		models.MessageInput
+		SendAt *time.Time `json:"send_at"`
 	}
-
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
 		return
 	}
 
-	message, err := h.service.CreateMessage(r.Context(), chatID, req.Text)
-	if err != nil {
-		if err.Error() == "chat not found" {
-			http.Error(w, err.Error(), http.StatusNotFound)
+	// С send_at сообщение не создаётся, а встаёт в очередь: 202 и отложенное сообщение
+	if req.SendAt != nil {
+		scheduled, err := h.scheduler.ScheduleMessage(r.Context(), chatID, req.MessageInput, *req.SendAt)
+		if err != nil {
+			http.Error(w, err.Error(), scheduleErrorStatus(err))
 			return
 		}
-		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(scheduled)
+		return
+	}
+
+	message, err := h.service.CreateMessage(r.Context(), chatID, req.MessageInput)
+	if err != nil {
+		http.Error(w, err.Error(), chatErrorStatus(err, http.StatusBadRequest))
 		return
 	}
 
@@ -112,3 +126,125 @@ func (h *ChatHandler) CreateMessage(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(message)
 }
+
+// CreateMessages принимает {"messages": [{"text", "format"}, ...], "atomic": false}.
+// 201 — созданы все сообщения, 207 — часть отклонена проверкой, 422 — в режиме atomic
+// пакет отклонён целиком. Во всех трёх случаях в ответе результат по каждому сообщению.
+func (h *ChatHandler) CreateMessages(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Messages []models.MessageInput `json:"messages"`
+		Atomic   bool                  `json:"atomic"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.CreateMessages(r.Context(), chatID, req.Messages, req.Atomic)
+	status := http.StatusCreated
+	switch {
+	case err != nil && err.Error() == "batch rejected":
+		status = http.StatusUnprocessableEntity
+	case err != nil:
+		http.Error(w, err.Error(), chatErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	created, failed := 0, 0
+	for _, result := range results {
+		if result.Message != nil {
+			created++
+		}
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if err == nil && failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"results": results,
+		"created": created,
+		"failed":  failed,
+	})
+}
+
+// ListChats отдаёт чаты текущего пользователя с unread_count
+func (h *ChatHandler) ListChats(w http.ResponseWriter, r *http.Request) {
+	chats, err := h.service.ListChats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), readErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chats)
+}
+
+// MarkRead сдвигает отметку прочтения текущего пользователя на message_id
+func (h *ChatHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		MessageID int64 `json:"message_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), chatID, req.MessageID); err != nil {
+		http.Error(w, err.Error(), readErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// chatErrorStatus переводит ошибки доступа к чату и отказ модерации в статус,
+// остальные — в fallback. Отклонённое модерацией сообщение — корректный запрос,
+// который нельзя сохранить, поэтому 422 на всех путях создания сообщений.
+func chatErrorStatus(err error, fallback int) int {
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return http.StatusUnauthorized
+	case msg == "forbidden":
+		return http.StatusForbidden
+	case msg == "chat not found":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "message rejected"):
+		return http.StatusUnprocessableEntity
+	default:
+		return fallback
+	}
+}
+
+func readErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "forbidden":
+		return http.StatusForbidden
+	case "message not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -188,6 +188,16 @@ func TestDeleteChat(t *testing.T) {
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:   "не владелец",
+			chatID: "1",
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					DeleteChat(gomock.Any(), int64(1)).
+					Return(errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
 	}
 
 	for _, tt := range tests {
@@ -229,7 +239,7 @@ func TestCreateMessage(t *testing.T) {
 			requestBody: `{"text":"Привет!"}`,
 			setupMock: func(m *mocks.MockChatServiceInterface) {
 				m.EXPECT().
-					CreateMessage(gomock.Any(), int64(1), "Привет!").
+					CreateMessage(gomock.Any(), int64(1), models.MessageInput{Text: "Привет!"}).
 					Return(&models.Message{
 						ID:        1,
 						ChatID:    1,
@@ -239,13 +249,24 @@ func TestCreateMessage(t *testing.T) {
 			},
 			expectedStatus: http.StatusCreated,
 		},
+		{
+			name:        "исчезающее сообщение",
+			chatID:      "1",
+			requestBody: `{"text":"Привет!","expires_in":60}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessage(gomock.Any(), int64(1), models.MessageInput{Text: "Привет!", ExpiresIn: 60}).
+					Return(&models.Message{ID: 1, ChatID: 1, Text: "Привет!"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
 		{
 			name:        "чат не найден",
 			chatID:      "999",
 			requestBody: `{"text":"Привет!"}`,
 			setupMock: func(m *mocks.MockChatServiceInterface) {
 				m.EXPECT().
-					CreateMessage(gomock.Any(), int64(999), "Привет!").
+					CreateMessage(gomock.Any(), int64(999), models.MessageInput{Text: "Привет!"}).
 					Return(nil, errors.New("chat not found"))
 			},
 			expectedStatus: http.StatusNotFound,
@@ -257,7 +278,7 @@ func TestCreateMessage(t *testing.T) {
 			requestBody: `{"text":""}`,
 			setupMock: func(m *mocks.MockChatServiceInterface) {
 				m.EXPECT().
-					CreateMessage(gomock.Any(), int64(1), "").
+					CreateMessage(gomock.Any(), int64(1), models.MessageInput{}).
 					Return(nil, errors.New("text must be 1-5000 characters"))
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -293,3 +314,237 @@ func TestCreateMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateScheduledMessage(t *testing.T) {
+	sendAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockScheduleServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:        "сообщение отложено",
+			requestBody: `{"text":"Доброе утро","send_at":"2026-10-19T09:00:00Z"}`,
+			setupMock: func(m *mocks.MockScheduleServiceInterface) {
+				m.EXPECT().
+					ScheduleMessage(gomock.Any(), int64(1), models.MessageInput{Text: "Доброе утро"}, sendAt).
+					Return(&models.ScheduledMessage{ID: 7, ChatID: 1, Text: "Доброе утро", SendAt: sendAt}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:        "время в прошлом",
+			requestBody: `{"text":"Доброе утро","send_at":"2020-01-01T00:00:00Z"}`,
+			setupMock: func(m *mocks.MockScheduleServiceInterface) {
+				m.EXPECT().ScheduleMessage(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(nil, errors.New("send_at must be in the future"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockScheduler := mocks.NewMockScheduleServiceInterface(ctrl)
+			tt.setupMock(mockScheduler)
+
+			handler := NewChatHandler(mocks.NewMockChatServiceInterface(ctrl), mockScheduler)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/messages/", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/messages/", handler.CreateMessage).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCreateMessages(t *testing.T) {
+	created := models.BatchItemResult{Index: 0, Message: &models.Message{ID: 1, ChatID: 1, Text: "первое"}}
+	failed := models.BatchItemResult{Index: 1, Error: "text cannot be empty"}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockChatServiceInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "все сообщения созданы",
+			requestBody: `{"messages":[{"text":"первое"}]}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessages(gomock.Any(), int64(1), []models.MessageInput{{Text: "первое"}}, false).
+					Return([]models.BatchItemResult{created}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"created":1`,
+		},
+		{
+			name:        "частичная вставка",
+			requestBody: `{"messages":[{"text":"первое"},{"text":""}]}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessages(gomock.Any(), int64(1), gomock.Any(), false).
+					Return([]models.BatchItemResult{created, failed}, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			expectedBody:   "text cannot be empty",
+		},
+		{
+			name:        "пакет отклонён",
+			requestBody: `{"messages":[{"text":"первое"},{"text":""}],"atomic":true}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessages(gomock.Any(), int64(1), gomock.Any(), true).
+					Return([]models.BatchItemResult{{Index: 0}, failed}, errors.New("batch rejected"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   `"failed":1`,
+		},
+		{
+			name:        "чат не найден",
+			requestBody: `{"messages":[{"text":"первое"}]}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessages(gomock.Any(), int64(1), gomock.Any(), false).
+					Return(nil, errors.New("chat not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "пустой пакет",
+			requestBody: `{"messages":[]}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().
+					CreateMessages(gomock.Any(), int64(1), gomock.Any(), false).
+					Return(nil, errors.New("messages cannot be empty"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockChatServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := &ChatHandler{service: mockService}
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/messages/batch", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/messages/batch", handler.CreateMessages).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedBody != "" && !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("ожидалось тело ответа содержащее %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockChatServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:        "успешная отметка",
+			requestBody: `{"message_id":10}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().MarkRead(gomock.Any(), int64(1), int64(10)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "сообщение не найдено",
+			requestBody: `{"message_id":999}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().MarkRead(gomock.Any(), int64(1), int64(999)).Return(errors.New("message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "не участник",
+			requestBody: `{"message_id":10}`,
+			setupMock: func(m *mocks.MockChatServiceInterface) {
+				m.EXPECT().MarkRead(gomock.Any(), int64(1), int64(10)).Return(errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "невалидный JSON",
+			requestBody:    `{invalid}`,
+			setupMock:      func(m *mocks.MockChatServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockChatServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewChatHandler(mockService, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/read", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/read", handler.MarkRead).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestListChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockChatServiceInterface(ctrl)
+	mockService.EXPECT().ListChats(gomock.Any()).Return([]models.ChatSummary{
+		{Chat: models.Chat{ID: 1, Title: "Общий"}, UnreadCount: 3},
+	}, nil)
+
+	handler := NewChatHandler(mockService, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/chats/", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListChats(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ожидался статус %d, получен %d", http.StatusOK, w.Code)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"unread_count":3`)) {
+		t.Errorf("ожидался unread_count в ответе, получено %q", w.Body.String())
+	}
+}
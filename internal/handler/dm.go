@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type DMHandler struct {
+	service service.DMServiceInterface
+}
+
+func NewDMHandler(service service.DMServiceInterface) *DMHandler {
+	return &DMHandler{service: service}
+}
+
+// OpenDM возвращает личный чат с пользователем: 201, если чат только что создан, и 200, если он уже был
+func (h *DMHandler) OpenDM(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID int64 `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.UserID <= 0 {
+		http.Error(w, "user_id is required", http.StatusBadRequest)
+		return
+	}
+
+	chat, created, err := h.service.OpenDM(r.Context(), req.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), dmErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(chat)
+}
+
+func dmErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "cannot start a direct message with yourself":
+		return http.StatusBadRequest
+	case "user not found":
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
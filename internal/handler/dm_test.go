@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOpenDM(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockDMServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "новый личный чат",
+			body: `{"user_id": 2}`,
+			setupMock: func(m *mocks.MockDMServiceInterface) {
+				m.EXPECT().OpenDM(gomock.Any(), int64(2)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, true, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "существующий личный чат",
+			body: `{"user_id": 2}`,
+			setupMock: func(m *mocks.MockDMServiceInterface) {
+				m.EXPECT().OpenDM(gomock.Any(), int64(2)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, false, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "чат с самим собой",
+			body: `{"user_id": 1}`,
+			setupMock: func(m *mocks.MockDMServiceInterface) {
+				m.EXPECT().OpenDM(gomock.Any(), int64(1)).Return(nil, false, errors.New("cannot start a direct message with yourself"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "пользователь не найден",
+			body: `{"user_id": 99}`,
+			setupMock: func(m *mocks.MockDMServiceInterface) {
+				m.EXPECT().OpenDM(gomock.Any(), int64(99)).Return(nil, false, errors.New("user not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "без user_id",
+			body:           `{}`,
+			setupMock:      func(m *mocks.MockDMServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockDMServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewDMHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/dms", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.OpenDM(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/export"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type ExportHandler struct {
+	service service.ExportServiceInterface
+}
+
+func NewExportHandler(service service.ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportChat отдаёт историю чата файлом в формате ?format=json|csv|md (по умолчанию json)
+func (h *ExportHandler) ExportChat(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatJSON
+	}
+
+	out := &startedWriter{ResponseWriter: w}
+	enc, err := export.New(format, out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", enc.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="chat-%d.%s"`, chatID, enc.Extension()))
+
+	if err := h.service.ExportChat(r.Context(), chatID, enc); err != nil {
+		if out.started {
+			// Статус уже отправлен, клиент получит оборванный файл
+			log.Printf("export: chat %d: %v", chatID, err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), exportErrorStatus(err))
+	}
+}
+
+// startedWriter запоминает, начался ли ответ, чтобы после ошибки не писать второй статус
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) Write(p []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(p)
+}
+
+func exportErrorStatus(err error) int {
+	return chatErrorStatus(err, http.StatusInternalServerError)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/export"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportChat(t *testing.T) {
+	tests := []struct {
+		name                string
+		path                string
+		setupMock           func(*mocks.MockExportServiceInterface)
+		expectedStatus      int
+		expectedContentType string
+		expectedDisposition string
+	}{
+		{
+			name: "json по умолчанию",
+			path: "/chats/1/export",
+			setupMock: func(m *mocks.MockExportServiceInterface) {
+				m.EXPECT().
+					ExportChat(gomock.Any(), int64(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, chatID int64, enc export.Encoder) error {
+						if err := enc.Begin(models.Chat{ID: 1, Title: "General"}, nil); err != nil {
+							return err
+						}
+						return enc.End()
+					})
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedDisposition: `attachment; filename="chat-1.json"`,
+		},
+		{
+			name: "csv",
+			path: "/chats/1/export?format=csv",
+			setupMock: func(m *mocks.MockExportServiceInterface) {
+				m.EXPECT().ExportChat(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedDisposition: `attachment; filename="chat-1.csv"`,
+		},
+		{
+			name: "чат не найден",
+			path: "/chats/1/export?format=md",
+			setupMock: func(m *mocks.MockExportServiceInterface) {
+				m.EXPECT().ExportChat(gomock.Any(), int64(1), gomock.Any()).Return(errors.New("chat not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "неизвестный формат",
+			path:           "/chats/1/export?format=xml",
+			setupMock:      func(m *mocks.MockExportServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockExportServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewExportHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/export", handler.ExportChat).Methods("GET")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedContentType != "" && w.Header().Get("Content-Type") != tt.expectedContentType {
+				t.Errorf("ожидался Content-Type %q, получен %q", tt.expectedContentType, w.Header().Get("Content-Type"))
+			}
+			if w.Header().Get("Content-Disposition") != tt.expectedDisposition {
+				t.Errorf("ожидался Content-Disposition %q, получен %q", tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/GlebMoskalev/chat-golang/internal/importer"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type ImportHandler struct {
+	service service.ImportServiceInterface
+	maxSize int64
+}
+
+func NewImportHandler(service service.ImportServiceInterface, maxSize int64) *ImportHandler {
+	return &ImportHandler{service: service, maxSize: maxSize}
+}
+
+// Import принимает архив в теле запроса: ?format=slack — zip-выгрузку Slack,
+// ?format=json (по умолчанию) — собственную JSON-выгрузку чата.
+// zip читается с произвольного места, поэтому тело сначала сохраняется во временный файл.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = importer.FormatJSON
+	}
+	if format != importer.FormatSlack && format != importer.FormatJSON {
+		http.Error(w, importer.ErrUnknownFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	size, err := io.Copy(file, http.MaxBytesReader(w, r.Body, h.maxSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "archive too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	archive, err := importer.Parse(format, file, size)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), archive)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/importer"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImport(t *testing.T) {
+	export := `{"chat":{"id":7,"title":"General"},"users":[],"messages":[{"id":1,"text":"привет","created_at":"2026-01-28T10:30:00Z"}]}`
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func(*mocks.MockImportServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "json-выгрузка",
+			path: "/admin/import",
+			body: export,
+			setupMock: func(m *mocks.MockImportServiceInterface) {
+				m.EXPECT().
+					Import(gomock.Any(), gomock.Cond(func(archive *importer.Archive) bool {
+						return len(archive.Chats) == 1 && len(archive.Chats[0].Messages) == 1
+					})).
+					DoAndReturn(func(ctx context.Context, archive *importer.Archive) (*models.ImportReport, error) {
+						return &models.ImportReport{ChatsCreated: 1, MessagesImported: 1}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "битый архив slack",
+			path:           "/admin/import?format=slack",
+			body:           "not a zip",
+			setupMock:      func(m *mocks.MockImportServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "неизвестный формат",
+			path:           "/admin/import?format=mbox",
+			body:           export,
+			setupMock:      func(m *mocks.MockImportServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "слишком большой архив",
+			path:           "/admin/import",
+			body:           export + strings.Repeat(" ", 1024),
+			setupMock:      func(m *mocks.MockImportServiceInterface) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockImportServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewImportHandler(mockService, 1024)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handler.Import(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+// maxIncomingBody ограничивает тело запроса входящего вебхука
+const maxIncomingBody = 64 << 10
+
+type IncomingWebhookHandler struct {
+	service service.IncomingWebhookServiceInterface
+}
+
+func NewIncomingWebhookHandler(service service.IncomingWebhookServiceInterface) *IncomingWebhookHandler {
+	return &IncomingWebhookHandler{service: service}
+}
+
+func (h *IncomingWebhookHandler) CreateHook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.service.CreateHook(r.Context(), chatID, req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), hookErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+func (h *IncomingWebhookHandler) ListHooks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	hooks, err := h.service.ListHooks(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), hookErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hooks)
+}
+
+func (h *IncomingWebhookHandler) RevokeHook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+	hookID, err := strconv.ParseInt(vars["hookID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid hook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RevokeHook(r.Context(), chatID, hookID); err != nil {
+		http.Error(w, err.Error(), hookErrorStatus(err, http.StatusInternalServerError))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PostMessage принимает либо JSON (models.IncomingMessage), либо text/plain —
+// тогда всё тело считается текстом сообщения.
+func (h *IncomingWebhookHandler) PostMessage(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+	body := http.MaxBytesReader(w, r.Body, maxIncomingBody)
+
+	var payload models.IncomingMessage
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/plain" {
+		text, err := io.ReadAll(body)
+		if err != nil {
+			http.Error(w, "Invalid body", http.StatusBadRequest)
+			return
+		}
+		payload.Text = string(text)
+	} else if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	message, err := h.service.PostMessage(r.Context(), token, payload)
+	if err != nil {
+		http.Error(w, err.Error(), hookErrorStatus(err, http.StatusBadRequest))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(message)
+}
+
+// hookErrorStatus — как chatErrorStatus, плюс неизвестный вебхук
+func hookErrorStatus(err error, fallback int) int {
+	if err.Error() == "hook not found" {
+		return http.StatusNotFound
+	}
+	return chatErrorStatus(err, fallback)
+}
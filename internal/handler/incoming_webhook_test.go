@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateHook(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockIncomingWebhookServiceInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "успешное создание",
+			requestBody: `{"name":"CI"}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().
+					CreateHook(gomock.Any(), int64(1), "CI").
+					Return(&models.IncomingWebhook{ID: 1, ChatID: 1, Name: "CI", Token: "tok"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"token":"tok"`,
+		},
+		{
+			name:        "без пользователя",
+			requestBody: `{"name":"CI"}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().CreateHook(gomock.Any(), int64(1), "CI").Return(nil, errors.New("authentication required"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "не владелец",
+			requestBody: `{"name":"CI"}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().CreateHook(gomock.Any(), int64(1), "CI").Return(nil, errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "невалидный JSON",
+			requestBody:    `{invalid}`,
+			setupMock:      func(m *mocks.MockIncomingWebhookServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIncomingWebhookServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewIncomingWebhookHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/hooks", bytes.NewBufferString(tt.requestBody))
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			handler.CreateHook(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedBody != "" && !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("ожидалось тело ответа содержащее %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPostIncomingMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentType    string
+		requestBody    string
+		setupMock      func(*mocks.MockIncomingWebhookServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:        "JSON с полями",
+			contentType: "application/json",
+			requestBody: `{"title":"Deploy","text":"done","fields":[{"title":"Env","value":"prod"}]}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().
+					PostMessage(gomock.Any(), "tok", models.IncomingMessage{
+						Title:  "Deploy",
+						Text:   "done",
+						Fields: []models.IncomingField{{Title: "Env", Value: "prod"}},
+					}).
+					Return(&models.Message{ID: 1, ChatID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "простой текст",
+			contentType: "text/plain; charset=utf-8",
+			requestBody: "disk is 95% full",
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().
+					PostMessage(gomock.Any(), "tok", models.IncomingMessage{Text: "disk is 95% full"}).
+					Return(&models.Message{ID: 1, ChatID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "неизвестный токен",
+			contentType: "application/json",
+			requestBody: `{"text":"hi"}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().PostMessage(gomock.Any(), "tok", gomock.Any()).Return(nil, errors.New("hook not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "пустое сообщение",
+			contentType: "application/json",
+			requestBody: `{}`,
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().PostMessage(gomock.Any(), "tok", gomock.Any()).Return(nil, errors.New("text cannot be empty"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "отклонено модерацией",
+			contentType: "text/plain",
+			requestBody: "спам",
+			setupMock: func(m *mocks.MockIncomingWebhookServiceInterface) {
+				m.EXPECT().PostMessage(gomock.Any(), "tok", gomock.Any()).Return(nil, errors.New("message rejected: спам"))
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockIncomingWebhookServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewIncomingWebhookHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/hooks/tok", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = mux.SetURLVars(req, map[string]string{"token": "tok"})
+			w := httptest.NewRecorder()
+
+			handler.PostMessage(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type MemberHandler struct {
+	service service.MemberServiceInterface
+}
+
+func NewMemberHandler(service service.MemberServiceInterface) *MemberHandler {
+	return &MemberHandler{service: service}
+}
+
+func (h *MemberHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Username string `json:"username"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.service.AddMember(r.Context(), chatID, req.Username)
+	if err != nil {
+		http.Error(w, err.Error(), memberErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *MemberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	members, err := h.service.ListMembers(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), memberErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+func memberErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "forbidden":
+		return http.StatusForbidden
+	case "chat not found", "user not found":
+		return http.StatusNotFound
+	case "cannot add members to a direct message":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type MentionHandler struct {
+	service service.MentionServiceInterface
+}
+
+func NewMentionHandler(service service.MentionServiceInterface) *MentionHandler {
+	return &MentionHandler{service: service}
+}
+
+// ListMentions отдаёт упоминания текущего пользователя.
+// Параметры: limit, before (курсор next_before), unread=true — только непрочитанные.
+func (h *MentionHandler) ListMentions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 20
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	var before int64
+	if beforeStr := query.Get("before"); beforeStr != "" {
+		b, err := strconv.ParseInt(beforeStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid before", http.StatusBadRequest)
+			return
+		}
+		before = b
+	}
+
+	unreadOnly, _ := strconv.ParseBool(query.Get("unread"))
+
+	page, err := h.service.ListMentions(r.Context(), before, unreadOnly, limit)
+	if err != nil {
+		http.Error(w, err.Error(), mentionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// MarkRead отмечает упоминания прочитанными. Без тела или с пустым message_ids
+// отмечаются все упоминания.
+func (h *MentionHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		MessageIDs []int64 `json:"message_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.service.MarkRead(r.Context(), req.MessageIDs)
+	if err != nil {
+		http.Error(w, err.Error(), mentionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"updated": updated})
+}
+
+func mentionErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "at most 1000 message ids per request":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
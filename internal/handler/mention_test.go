@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListMentions(t *testing.T) {
+	next := int64(10)
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(*mocks.MockMentionServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:  "первая страница непрочитанных",
+			query: "?unread=true&limit=5",
+			setupMock: func(m *mocks.MockMentionServiceInterface) {
+				m.EXPECT().
+					ListMentions(gomock.Any(), int64(0), true, 5).
+					Return(&models.MentionPage{Mentions: []models.Mention{{MessageID: 11}}, UnreadCount: 3, NextBefore: &next}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "следующая страница",
+			query: "?before=10",
+			setupMock: func(m *mocks.MockMentionServiceInterface) {
+				m.EXPECT().
+					ListMentions(gomock.Any(), int64(10), false, 20).
+					Return(&models.MentionPage{Mentions: []models.Mention{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный курсор",
+			query:          "?before=abc",
+			setupMock:      func(m *mocks.MockMentionServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "без пользователя",
+			query: "",
+			setupMock: func(m *mocks.MockMentionServiceInterface) {
+				m.EXPECT().
+					ListMentions(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("authentication required"))
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockMentionServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewMentionHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/me/mentions"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.ListMentions(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response models.MentionPage
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Errorf("ошибка парсинга ответа: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestMarkMentionsRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockMentionServiceInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "выбранные упоминания",
+			requestBody: `{"message_ids":[1,2]}`,
+			setupMock: func(m *mocks.MockMentionServiceInterface) {
+				m.EXPECT().MarkRead(gomock.Any(), []int64{1, 2}).Return(int64(2), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"updated":2}`,
+		},
+		{
+			name:        "без тела — все упоминания",
+			requestBody: "",
+			setupMock: func(m *mocks.MockMentionServiceInterface) {
+				m.EXPECT().MarkRead(gomock.Any(), gomock.Nil()).Return(int64(7), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"updated":7}`,
+		},
+		{
+			name:           "невалидный JSON",
+			requestBody:    `{invalid}`,
+			setupMock:      func(m *mocks.MockMentionServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockMentionServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewMentionHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/mentions/read", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			handler.MarkRead(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedBody != "" && !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("ожидалось тело ответа содержащее %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type ModerationHandler struct {
+	service service.ModerationServiceInterface
+}
+
+func NewModerationHandler(service service.ModerationServiceInterface) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// Queue отдаёт отмеченные сообщения из чатов текущего пользователя, старые первыми
+func (h *ModerationHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.service.Queue(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(flags)
+}
+
+func (h *ModerationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	messageID, err := strconv.ParseInt(mux.Vars(r)["msgID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid message ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Approve(r.Context(), messageID); err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ModerationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	messageID, err := strconv.ParseInt(mux.Vars(r)["msgID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid message ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Reject(r.Context(), messageID); err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ModerationHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	rules, err := h.service.ListRules(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreateRule принимает {"pattern": "<регулярное выражение>", "action": "reject|mask|flag"}
+func (h *ModerationHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Pattern string `json:"pattern"`
+		Action  string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.service.CreateRule(r.Context(), chatID, req.Pattern, req.Action)
+	if err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *ModerationHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+	ruleID, err := strconv.ParseInt(vars["ruleID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteRule(r.Context(), chatID, ruleID); err != nil {
+		http.Error(w, err.Error(), moderationErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func moderationErrorStatus(err error) int {
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return http.StatusUnauthorized
+	case msg == "forbidden":
+		return http.StatusForbidden
+	case msg == "chat not found", msg == "flag not found", msg == "rule not found":
+		return http.StatusNotFound
+	case strings.HasPrefix(msg, "pattern"), strings.HasPrefix(msg, "invalid pattern"), strings.HasPrefix(msg, "action"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateModerationRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockModerationServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "успешное создание",
+			body: `{"pattern":"(?i)казино","action":"flag"}`,
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().
+					CreateRule(gomock.Any(), int64(1), "(?i)казино", models.ModerationActionFlag).
+					Return(&models.ModerationRule{ID: 1, ChatID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "невалидное выражение",
+			body: `{"pattern":"(","action":"flag"}`,
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().CreateRule(gomock.Any(), int64(1), "(", "flag").Return(nil, errors.New("invalid pattern: missing closing )"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "не владелец",
+			body: `{"pattern":"x","action":"flag"}`,
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().CreateRule(gomock.Any(), int64(1), "x", "flag").Return(nil, errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "невалидный JSON",
+			body:           `{`,
+			setupMock:      func(m *mocks.MockModerationServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockModerationServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewModerationHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/moderation/rules", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/moderation/rules", handler.CreateRule).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestReviewModerationQueue(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(*mocks.MockModerationServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "одобрение",
+			path: "/moderation/queue/5/approve",
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().Approve(gomock.Any(), int64(5)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "отклонение",
+			path: "/moderation/queue/5/reject",
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().Reject(gomock.Any(), int64(5)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "нет в очереди",
+			path: "/moderation/queue/5/approve",
+			setupMock: func(m *mocks.MockModerationServiceInterface) {
+				m.EXPECT().Approve(gomock.Any(), int64(5)).Return(errors.New("flag not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID",
+			path:           "/moderation/queue/abc/reject",
+			setupMock:      func(m *mocks.MockModerationServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockModerationServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewModerationHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/moderation/queue/{msgID}/approve", handler.Approve).Methods("POST")
+			router.HandleFunc("/moderation/queue/{msgID}/reject", handler.Reject).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
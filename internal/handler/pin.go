@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type PinHandler struct {
+	service service.PinServiceInterface
+}
+
+func NewPinHandler(service service.PinServiceInterface) *PinHandler {
+	return &PinHandler{service: service}
+}
+
+func (h *PinHandler) PinMessage(w http.ResponseWriter, r *http.Request) {
+	chatID, messageID, ok := pinIDs(w, r)
+	if !ok {
+		return
+	}
+
+	pin, err := h.service.PinMessage(r.Context(), chatID, messageID)
+	if err != nil {
+		http.Error(w, err.Error(), pinErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(pin)
+}
+
+func (h *PinHandler) UnpinMessage(w http.ResponseWriter, r *http.Request) {
+	chatID, messageID, ok := pinIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.UnpinMessage(r.Context(), chatID, messageID); err != nil {
+		http.Error(w, err.Error(), pinErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PinHandler) ListPins(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	pins, err := h.service.ListPins(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), pinErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pins)
+}
+
+// pinIDs разбирает {id} и {msgID} из пути, при ошибке сам отвечает 400
+func pinIDs(w http.ResponseWriter, r *http.Request) (chatID, messageID int64, ok bool) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	messageID, err = strconv.ParseInt(vars["msgID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid message ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return chatID, messageID, true
+}
+
+func pinErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "forbidden":
+		return http.StatusForbidden
+	case "chat not found", "message not found", "pin not found":
+		return http.StatusNotFound
+	case "message already pinned", "pin limit reached":
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
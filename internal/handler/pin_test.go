@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPinMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		setupMock      func(*mocks.MockPinServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "успешное закрепление",
+			path: "/chats/1/pins/10",
+			setupMock: func(m *mocks.MockPinServiceInterface) {
+				m.EXPECT().PinMessage(gomock.Any(), int64(1), int64(10)).Return(&models.ChatPin{ChatID: 1, MessageID: 10}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "лимит закрепов",
+			path: "/chats/1/pins/10",
+			setupMock: func(m *mocks.MockPinServiceInterface) {
+				m.EXPECT().PinMessage(gomock.Any(), int64(1), int64(10)).Return(nil, errors.New("pin limit reached"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "сообщение не найдено",
+			path: "/chats/1/pins/10",
+			setupMock: func(m *mocks.MockPinServiceInterface) {
+				m.EXPECT().PinMessage(gomock.Any(), int64(1), int64(10)).Return(nil, errors.New("message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID сообщения",
+			path:           "/chats/1/pins/abc",
+			setupMock:      func(m *mocks.MockPinServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockPinServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewPinHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/pins/{msgID}", handler.PinMessage).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestUnpinMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPinServiceInterface(ctrl)
+	mockService.EXPECT().UnpinMessage(gomock.Any(), int64(1), int64(10)).Return(nil)
+	mockService.EXPECT().UnpinMessage(gomock.Any(), int64(1), int64(11)).Return(errors.New("pin not found"))
+
+	handler := NewPinHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/chats/{id}/pins/{msgID}", handler.UnpinMessage).Methods("DELETE")
+
+	for path, expected := range map[string]int{
+		"/chats/1/pins/10": http.StatusNoContent,
+		"/chats/1/pins/11": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+		if w.Code != expected {
+			t.Errorf("%s: ожидался статус %d, получен %d", path, expected, w.Code)
+		}
+	}
+}
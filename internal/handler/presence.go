@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type PresenceHandler struct {
+	service service.PresenceServiceInterface
+}
+
+func NewPresenceHandler(service service.PresenceServiceInterface) *PresenceHandler {
+	return &PresenceHandler{service: service}
+}
+
+func (h *PresenceHandler) Typing(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Typing(r.Context(), chatID); err != nil {
+		http.Error(w, err.Error(), presenceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Heartbeat принимает {"status":"online"|"away"}; без тела статус — online
+func (h *PresenceHandler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Status string `json:"status"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Heartbeat(r.Context(), req.Status); err != nil {
+		http.Error(w, err.Error(), presenceErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PresenceHandler) GetPresence(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.GetPresence(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), presenceErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func presenceErrorStatus(err error) int {
+	switch err.Error() {
+	case "authentication required":
+		return http.StatusUnauthorized
+	case "forbidden":
+		return http.StatusForbidden
+	case "chat not found":
+		return http.StatusNotFound
+	case "status must be online or away":
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTyping(t *testing.T) {
+	tests := []struct {
+		name           string
+		chatID         string
+		setupMock      func(*mocks.MockPresenceServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:   "успешно",
+			chatID: "1",
+			setupMock: func(m *mocks.MockPresenceServiceInterface) {
+				m.EXPECT().Typing(gomock.Any(), int64(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "не участник",
+			chatID: "1",
+			setupMock: func(m *mocks.MockPresenceServiceInterface) {
+				m.EXPECT().Typing(gomock.Any(), int64(1)).Return(errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "невалидный ID",
+			chatID:         "abc",
+			setupMock:      func(m *mocks.MockPresenceServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockPresenceServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewPresenceHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/"+tt.chatID+"/typing", nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/typing", handler.Typing).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockPresenceServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:        "away",
+			requestBody: `{"status":"away"}`,
+			setupMock: func(m *mocks.MockPresenceServiceInterface) {
+				m.EXPECT().Heartbeat(gomock.Any(), "away").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "без тела",
+			requestBody: "",
+			setupMock: func(m *mocks.MockPresenceServiceInterface) {
+				m.EXPECT().Heartbeat(gomock.Any(), "").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "неизвестный статус",
+			requestBody: `{"status":"busy"}`,
+			setupMock: func(m *mocks.MockPresenceServiceInterface) {
+				m.EXPECT().Heartbeat(gomock.Any(), "busy").Return(errors.New("status must be online or away"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockPresenceServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewPresenceHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/presence", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			handler.Heartbeat(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestGetPresence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockPresenceServiceInterface(ctrl)
+	mockService.EXPECT().GetPresence(gomock.Any(), int64(1)).Return(&models.ChatPresence{
+		ChatID:  1,
+		Members: []models.MemberPresence{{UserID: 1, Username: "alice", Status: "online"}},
+		Typing:  []int64{1},
+	}, nil)
+
+	handler := NewPresenceHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/chats/1/presence", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/chats/{id}/presence", handler.GetPresence).Methods("GET")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ожидался статус %d, получен %d", http.StatusOK, w.Code)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte(`"typing":[1]`)) {
+		t.Errorf("ожидался список печатающих, получено %q", w.Body.String())
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type ReportHandler struct {
+	service service.ReportServiceInterface
+}
+
+func NewReportHandler(service service.ReportServiceInterface) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// ReportMessage принимает {"reason": "spam|abuse|harassment|illegal|other", "comment": "..."}
+func (h *ReportHandler) ReportMessage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+	messageID, err := strconv.ParseInt(vars["msgID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid message ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Reason  string `json:"reason"`
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.ReportMessage(r.Context(), chatID, messageID, req.Reason, req.Comment)
+	if err != nil {
+		http.Error(w, err.Error(), reportErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+// ListReports — список жалоб для администратора: ?status=open&after=<id>&limit=50
+func (h *ReportHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var afterID int64
+	if after := query.Get("after"); after != "" {
+		id, err := strconv.ParseInt(after, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid after", http.StatusBadRequest)
+			return
+		}
+		afterID = id
+	}
+	limit := 50
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil {
+		limit = l
+	}
+
+	reports, err := h.service.ListReports(r.Context(), query.Get("status"), afterID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), reportErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reports)
+}
+
+// ResolveReport принимает {"status": "resolved|dismissed", "resolution": "...", "delete_message": false}
+func (h *ReportHandler) ResolveReport(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid report ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Status        string `json:"status"`
+		Resolution    string `json:"resolution"`
+		DeleteMessage bool   `json:"delete_message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.ResolveReport(r.Context(), id, req.Status, req.Resolution, req.DeleteMessage)
+	if err != nil {
+		http.Error(w, err.Error(), reportErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func reportErrorStatus(err error) int {
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return http.StatusUnauthorized
+	case msg == "message not found", msg == "report not found":
+		return http.StatusNotFound
+	case msg == "message already reported", msg == "report already closed":
+		return http.StatusConflict
+	case msg == "cannot report own message", strings.HasPrefix(msg, "reason must"), strings.HasPrefix(msg, "status must"),
+		strings.HasSuffix(msg, "at most 1000 characters"), strings.HasPrefix(msg, "delete_message"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
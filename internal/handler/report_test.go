@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReportMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockReportServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "успешная жалоба",
+			body: `{"reason":"spam","comment":"реклама"}`,
+			setupMock: func(m *mocks.MockReportServiceInterface) {
+				m.EXPECT().ReportMessage(gomock.Any(), int64(1), int64(5), "spam", "реклама").Return(&models.MessageReport{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "повторная жалоба",
+			body: `{"reason":"spam"}`,
+			setupMock: func(m *mocks.MockReportServiceInterface) {
+				m.EXPECT().ReportMessage(gomock.Any(), int64(1), int64(5), "spam", "").Return(nil, errors.New("message already reported"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "неизвестная причина",
+			body: `{"reason":"boring"}`,
+			setupMock: func(m *mocks.MockReportServiceInterface) {
+				m.EXPECT().ReportMessage(gomock.Any(), int64(1), int64(5), "boring", "").
+					Return(nil, errors.New("reason must be spam, abuse, harassment, illegal or other"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "нет сообщения",
+			body: `{"reason":"spam"}`,
+			setupMock: func(m *mocks.MockReportServiceInterface) {
+				m.EXPECT().ReportMessage(gomock.Any(), int64(1), int64(5), "spam", "").Return(nil, errors.New("message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "сообщение чужого личного чата",
+			body: `{"reason":"abuse"}`,
+			setupMock: func(m *mocks.MockReportServiceInterface) {
+				m.EXPECT().ReportMessage(gomock.Any(), int64(1), int64(5), "abuse", "").Return(nil, errors.New("message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockReportServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewReportHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/chats/1/messages/5/report", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/messages/{msgID}/report", handler.ReportMessage).Methods("POST")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+			if strings.Contains(w.Body.String(), `"text"`) {
+				t.Errorf("ответ жалобщику содержит текст сообщения: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestResolveReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockReportServiceInterface(ctrl)
+	mockService.EXPECT().ResolveReport(gomock.Any(), int64(3), "resolved", "спам", true).
+		Return(&models.MessageReport{ID: 3, Status: models.ReportStatusResolved}, nil)
+	mockService.EXPECT().ResolveReport(gomock.Any(), int64(4), "dismissed", "", false).
+		Return(nil, errors.New("report already closed"))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/reports/{id}/resolve", NewReportHandler(mockService).ResolveReport).Methods("POST")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/reports/3/resolve",
+		strings.NewReader(`{"status":"resolved","resolution":"спам","delete_message":true}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("ожидался статус 200, получен %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/reports/4/resolve", strings.NewReader(`{"status":"dismissed"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("ожидался статус 409, получен %d", w.Code)
+	}
+}
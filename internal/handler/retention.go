@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+type RetentionHandler struct {
+	service service.RetentionServiceInterface
+}
+
+func NewRetentionHandler(service service.RetentionServiceInterface) *RetentionHandler {
+	return &RetentionHandler{service: service}
+}
+
+func (h *RetentionHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.service.GetPolicy(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), retentionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+func (h *RetentionHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		MaxAgeSeconds *int64 `json:"max_age_seconds"`
+		MaxMessages   *int64 `json:"max_messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	policy, err := h.service.SetPolicy(r.Context(), chatID, models.RetentionPolicy{
+		MaxAgeSeconds: req.MaxAgeSeconds,
+		MaxMessages:   req.MaxMessages,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), retentionErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+func (h *RetentionHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeletePolicy(r.Context(), chatID); err != nil {
+		http.Error(w, err.Error(), retentionErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Report — пробный прогон очистки: сколько сообщений удалилось бы в каждом чате
+func (h *RetentionHandler) Report(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.Report(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func retentionErrorStatus(err error) int {
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return http.StatusUnauthorized
+	case msg == "forbidden":
+		return http.StatusForbidden
+	case msg == "chat not found":
+		return http.StatusNotFound
+	case strings.HasSuffix(msg, "must not be negative"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
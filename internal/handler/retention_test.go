@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetRetentionPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockRetentionServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "успешное изменение",
+			body: `{"max_age_seconds":86400}`,
+			setupMock: func(m *mocks.MockRetentionServiceInterface) {
+				m.EXPECT().
+					SetPolicy(gomock.Any(), int64(1), gomock.Cond(func(p models.RetentionPolicy) bool {
+						return p.MaxAgeSeconds != nil && *p.MaxAgeSeconds == 86400 && p.MaxMessages == nil
+					})).
+					Return(&models.RetentionPolicy{ChatID: 1}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "отрицательное значение",
+			body: `{"max_messages":-1}`,
+			setupMock: func(m *mocks.MockRetentionServiceInterface) {
+				m.EXPECT().SetPolicy(gomock.Any(), int64(1), gomock.Any()).Return(nil, errors.New("max_messages must not be negative"))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "не владелец",
+			body: `{}`,
+			setupMock: func(m *mocks.MockRetentionServiceInterface) {
+				m.EXPECT().SetPolicy(gomock.Any(), int64(1), gomock.Any()).Return(nil, errors.New("forbidden"))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "невалидный JSON",
+			body:           `{`,
+			setupMock:      func(m *mocks.MockRetentionServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockRetentionServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewRetentionHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPut, "/chats/1/retention", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/retention", handler.SetPolicy).Methods("PUT")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestRetentionReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockRetentionServiceInterface(ctrl)
+	mockService.EXPECT().Report(gomock.Any()).Return(&models.RetentionReport{
+		Expired: 4,
+		Chats:   []models.RetentionReportItem{{ChatID: 5, Expired: 4}},
+	}, nil)
+
+	handler := NewRetentionHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/retention/report", nil)
+	w := httptest.NewRecorder()
+	handler.Report(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("ожидался статус 200, получен %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"expired":4`) {
+		t.Errorf("в отчёте нет числа сообщений: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service"
+)
+
+// ScheduleHandler управляет отложенными сообщениями. Создаются они через
+// POST /chats/{id}/messages/ с send_at (см. ChatHandler.CreateMessage).
+type ScheduleHandler struct {
+	service service.ScheduleServiceInterface
+}
+
+func NewScheduleHandler(service service.ScheduleServiceInterface) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+func (h *ScheduleHandler) ListScheduled(w http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return
+	}
+
+	messages, err := h.service.ListScheduled(r.Context(), chatID)
+	if err != nil {
+		http.Error(w, err.Error(), scheduleErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+}
+
+func (h *ScheduleHandler) UpdateScheduled(w http.ResponseWriter, r *http.Request) {
+	chatID, id, ok := scheduledIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var patch models.ScheduledMessagePatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	message, err := h.service.UpdateScheduled(r.Context(), chatID, id, patch)
+	if err != nil {
+		http.Error(w, err.Error(), scheduleErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message)
+}
+
+func (h *ScheduleHandler) CancelScheduled(w http.ResponseWriter, r *http.Request) {
+	chatID, id, ok := scheduledIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelScheduled(r.Context(), chatID, id); err != nil {
+		http.Error(w, err.Error(), scheduleErrorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// scheduledIDs разбирает {id} и {schedID} из пути, при ошибке сам отвечает 400
+func scheduledIDs(w http.ResponseWriter, r *http.Request) (chatID, id int64, ok bool) {
+	vars := mux.Vars(r)
+	chatID, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid chat ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	id, err = strconv.ParseInt(vars["schedID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid scheduled message ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return chatID, id, true
+}
+
+func scheduleErrorStatus(err error) int {
+	switch msg := err.Error(); {
+	case msg == "authentication required":
+		return http.StatusUnauthorized
+	case msg == "forbidden":
+		return http.StatusForbidden
+	case msg == "chat not found", msg == "scheduled message not found":
+		return http.StatusNotFound
+	case msg == "scheduled message is being sent":
+		return http.StatusConflict
+	case msg == "send_at must be in the future", msg == "text cannot be empty", msg == "text must be 1-5000 characters",
+		msg == "format must be plain or markdown", strings.HasPrefix(msg, "expires_in must be"):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateScheduled(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		requestBody    string
+		setupMock      func(*mocks.MockScheduleServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:        "изменение текста",
+			path:        "/chats/1/scheduled/7",
+			requestBody: `{"text":"исправлено"}`,
+			setupMock: func(m *mocks.MockScheduleServiceInterface) {
+				m.EXPECT().
+					UpdateScheduled(gomock.Any(), int64(1), int64(7), gomock.Cond(func(p models.ScheduledMessagePatch) bool {
+						return p.Text != nil && *p.Text == "исправлено" && p.SendAt == nil
+					})).
+					Return(&models.ScheduledMessage{ID: 7, ChatID: 1, Text: "исправлено"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "уже отправлено",
+			path:        "/chats/1/scheduled/7",
+			requestBody: `{"text":"исправлено"}`,
+			setupMock: func(m *mocks.MockScheduleServiceInterface) {
+				m.EXPECT().UpdateScheduled(gomock.Any(), int64(1), int64(7), gomock.Any()).Return(nil, errors.New("scheduled message not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "сообщение уже отправляется",
+			path:        "/chats/1/scheduled/7",
+			requestBody: `{"text":"исправлено"}`,
+			setupMock: func(m *mocks.MockScheduleServiceInterface) {
+				m.EXPECT().UpdateScheduled(gomock.Any(), int64(1), int64(7), gomock.Any()).Return(nil, errors.New("scheduled message is being sent"))
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "невалидный ID",
+			path:           "/chats/1/scheduled/abc",
+			requestBody:    `{}`,
+			setupMock:      func(m *mocks.MockScheduleServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockScheduleServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewScheduleHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPatch, tt.path, bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/chats/{id}/scheduled/{schedID}", handler.UpdateScheduled).Methods("PATCH")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestCancelScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockScheduleServiceInterface(ctrl)
+	mockService.EXPECT().CancelScheduled(gomock.Any(), int64(1), int64(7)).Return(nil)
+
+	handler := NewScheduleHandler(mockService)
+
+	req := httptest.NewRequest(http.MethodDelete, "/chats/1/scheduled/7", nil)
+	w := httptest.NewRecorder()
+
+	router := mux.NewRouter()
+	router.HandleFunc("/chats/{id}/scheduled/{schedID}", handler.CancelScheduled).Methods("DELETE")
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("ожидался статус %d, получен %d", http.StatusNoContent, w.Code)
+	}
+}
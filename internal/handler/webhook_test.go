@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*mocks.MockWebhookServiceInterface)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "успешное создание",
+			requestBody: `{"url":"https://example.com/hook","events":["message.created"],"chat_id":5,"secret":"s"}`,
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().
+					CreateWebhook(gomock.Any(), "https://example.com/hook", []string{"message.created"}, gomock.Any(), "s").
+					Return(&models.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "s", Active: true}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"secret":"s"`,
+		},
+		{
+			name:        "невалидный URL",
+			requestBody: `{"url":"nope"}`,
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().
+					CreateWebhook(gomock.Any(), "nope", gomock.Any(), gomock.Any(), "").
+					Return(nil, errors.New("url must be an absolute http or https URL"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "url must be an absolute http or https URL",
+		},
+		{
+			name:        "чат не найден",
+			requestBody: `{"url":"https://example.com/hook","chat_id":999}`,
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("chat not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный JSON",
+			requestBody:    `{invalid}`,
+			setupMock:      func(m *mocks.MockWebhookServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewWebhookHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.requestBody))
+			w := httptest.NewRecorder()
+
+			handler.CreateWebhook(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedBody != "" && !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("ожидалось тело ответа содержащее %q, получено %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		webhookID      string
+		setupMock      func(*mocks.MockWebhookServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name:      "успешное удаление",
+			webhookID: "1",
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().DeleteWebhook(gomock.Any(), int64(1)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:      "вебхук не найден",
+			webhookID: "999",
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().DeleteWebhook(gomock.Any(), int64(999)).Return(errors.New("webhook not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "невалидный ID",
+			webhookID:      "abc",
+			setupMock:      func(m *mocks.MockWebhookServiceInterface) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewWebhookHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.webhookID, nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/webhooks/{id}", handler.DeleteWebhook).Methods("DELETE")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func(*mocks.MockWebhookServiceInterface)
+		expectedStatus int
+	}{
+		{
+			name: "успешное получение журнала",
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().
+					ListDeliveries(gomock.Any(), int64(1), 5).
+					Return([]models.WebhookDelivery{{ID: 1, WebhookID: 1, Status: models.DeliveryDelivered}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "вебхук не найден",
+			setupMock: func(m *mocks.MockWebhookServiceInterface) {
+				m.EXPECT().
+					ListDeliveries(gomock.Any(), int64(1), 5).
+					Return(nil, errors.New("webhook not found"))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookServiceInterface(ctrl)
+			tt.setupMock(mockService)
+
+			handler := NewWebhookHandler(mockService)
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?limit=5", nil)
+			w := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/webhooks/{id}/deliveries", handler.ListDeliveries).Methods("GET")
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ожидался статус %d, получен %d", tt.expectedStatus, w.Code)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response []models.WebhookDelivery
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Errorf("ошибка парсинга ответа: %v", err)
+				}
+			}
+		})
+	}
+}
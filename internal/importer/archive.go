@@ -0,0 +1,110 @@
+// Package importer читает архивы переписки в общее представление Archive.
+//
+// Поддерживаются выгрузка рабочего пространства Slack (zip с users.json, channels.json
+// и папкой сообщений на каждый канал) и собственная JSON-выгрузка чата
+// (GET /chats/{id}/export?format=json). Сохраняет архив service.ImportService.
+package importer
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	FormatSlack = "slack"
+	FormatJSON  = "json"
+)
+
+// maxTextLength — ограничение длины сообщения в базе (VARCHAR(5000)), более длинные обрезаются
+const maxTextLength = 5000
+
+var ErrUnknownFormat = errors.New("format must be slack or json")
+
+// Archive — пользователи и чаты одного архива. Source входит в ключи импорта,
+// по которым повторный импорт пропускает уже перенесённое.
+type Archive struct {
+	Source string
+	Users  []User
+	Chats  []Chat
+}
+
+// User — автор сообщений в архиве. Username уже приведён к правилам имён сервиса.
+type User struct {
+	ExternalID string
+	Username   string
+	IsBot      bool
+}
+
+type Chat struct {
+	ExternalID string
+	Title      string
+	CreatedAt  time.Time
+	// Messages отсортированы от старых к новым
+	Messages []Message
+}
+
+type Message struct {
+	ExternalID       string
+	AuthorExternalID string
+	Text             string
+	Format           string
+	CreatedAt        time.Time
+	EditedAt         *time.Time
+}
+
+// ChatKey — ключ импорта чата
+func (a *Archive) ChatKey(chat Chat) string {
+	return a.Source + ":" + chat.ExternalID
+}
+
+// MessageKey — ключ импорта сообщения, уникальный в пределах архива
+func (a *Archive) MessageKey(chat Chat, message Message) string {
+	return a.Source + ":" + chat.ExternalID + ":" + message.ExternalID
+}
+
+// Parse читает архив формата format
+func Parse(format string, r io.ReaderAt, size int64) (*Archive, error) {
+	switch format {
+	case FormatSlack:
+		return ParseSlack(r, size)
+	case FormatJSON:
+		return ParseExport(io.NewSectionReader(r, 0, size))
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+var invalidUsernameChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// normalizeUsername приводит имя из архива к правилам сервиса (3-32 символа a-z, 0-9, '_', '.', '-').
+// Если от имени ничего не осталось, используется fallback.
+func normalizeUsername(name, fallback string) string {
+	username := invalidUsernameChars.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "_")
+	if len(username) > 32 {
+		username = username[:32]
+	}
+	if len(strings.Trim(username, "_")) == 0 {
+		username = invalidUsernameChars.ReplaceAllString(strings.ToLower(fallback), "_")
+	}
+	for len(username) < 3 {
+		username += "_"
+	}
+	// @channel упоминает весь чат, такое имя занять нельзя
+	if username == "channel" {
+		username = "channel_"
+	}
+	return username
+}
+
+// truncateText обрезает текст до maxTextLength символов
+func truncateText(text string) string {
+	if utf8.RuneCountInString(text) <= maxTextLength {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxTextLength])
+}
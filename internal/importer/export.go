@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// exportDocument — JSON-выгрузка чата из пакета export
+type exportDocument struct {
+	Chat     models.Chat   `json:"chat"`
+	Users    []models.User `json:"users"`
+	Messages []struct {
+		ID          int64      `json:"id"`
+		AuthorID    *int64     `json:"author_id"`
+		Text        string     `json:"text"`
+		Format      string     `json:"format"`
+		CreatedAt   time.Time  `json:"created_at"`
+		EditedAt    *time.Time `json:"edited_at"`
+		Attachments []struct {
+			FileName string `json:"file_name"`
+			URL      string `json:"url"`
+		} `json:"attachments"`
+	} `json:"messages"`
+}
+
+// ParseExport читает собственную JSON-выгрузку чата. Авторы сопоставляются по списку users
+// выгрузки, личный чат переносится комнатой. Вложения переносятся строками со ссылками
+// на исходный сервер.
+func ParseExport(r io.Reader) (*Archive, error) {
+	var doc exportDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid export: %w", err)
+	}
+	if doc.Chat.ID == 0 {
+		return nil, fmt.Errorf("invalid export: no chat")
+	}
+
+	archive := &Archive{Source: FormatJSON}
+	for _, user := range doc.Users {
+		id := strconv.FormatInt(user.ID, 10)
+		archive.Users = append(archive.Users, User{
+			ExternalID: id,
+			Username:   normalizeUsername(user.Username, "user-"+id),
+			IsBot:      user.IsBot,
+		})
+	}
+
+	chat := Chat{
+		ExternalID: strconv.FormatInt(doc.Chat.ID, 10),
+		Title:      doc.Chat.Title,
+		CreatedAt:  doc.Chat.CreatedAt,
+	}
+	if chat.Title == "" {
+		chat.Title = "Chat " + chat.ExternalID
+	}
+
+	for _, message := range doc.Messages {
+		lines := []string{}
+		if text := strings.TrimSpace(message.Text); text != "" {
+			lines = append(lines, text)
+		}
+		for _, attachment := range message.Attachments {
+			lines = append(lines, strings.TrimSpace(attachment.FileName+" "+attachment.URL))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		imported := Message{
+			ExternalID: strconv.FormatInt(message.ID, 10),
+			Text:       truncateText(strings.Join(lines, "\n")),
+			Format:     message.Format,
+			CreatedAt:  message.CreatedAt,
+			EditedAt:   message.EditedAt,
+		}
+		if message.AuthorID != nil {
+			imported.AuthorExternalID = strconv.FormatInt(*message.AuthorID, 10)
+		}
+		if imported.Format != models.MessageFormatMarkdown {
+			imported.Format = models.MessageFormatPlain
+		}
+		chat.Messages = append(chat.Messages, imported)
+	}
+	archive.Chats = []Chat{chat}
+
+	return archive, nil
+}
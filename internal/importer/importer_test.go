@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func slackZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParseSlack(t *testing.T) {
+	r := slackZip(t, map[string]string{
+		"users.json":    `[{"id":"U1","name":"Alice.Smith"},{"id":"U2","name":"deploy bot","is_bot":true},{"id":"U3","name":"x"}]`,
+		"channels.json": `[{"id":"C1","name":"general","created":1577836800}]`,
+		"groups.json":   `[{"id":"G1","name":"secret","created":1577836800}]`,
+		"general/2020-01-02.json": `[
+			{"type":"message","user":"U1","text":"второй день","ts":"1577923200.000200"}
+		]`,
+		"general/2020-01-01.json": `[
+			{"type":"message","subtype":"channel_join","user":"U2","text":"<@U2> has joined the channel","ts":"1577836900.000000"},
+			{"type":"message","user":"U1","text":"hi <@U2>, see <https://example.com|docs> &amp; <!here>","ts":"1577836801.123456","edited":{"user":"U1","ts":"1577836900.000000"}},
+			{"type":"message","subtype":"file_share","user":"U3","text":"","ts":"1577836802.000000","files":[{"name":"plan.pdf","url_private":"https://files.slack.com/plan.pdf"}]}
+		]`,
+		"secret/2020-01-01.json": `[{"type":"message","user":"U2","text":"deployed","ts":"1577836803.000000"}]`,
+	})
+
+	archive, err := Parse(FormatSlack, r, r.Size())
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatSlack, archive.Source)
+	require.Len(t, archive.Users, 3)
+	assert.Equal(t, "alice.smith", archive.Users[0].Username)
+	assert.Equal(t, "deploy_bot", archive.Users[1].Username)
+	assert.True(t, archive.Users[1].IsBot)
+	assert.Equal(t, "x__", archive.Users[2].Username, "короткое имя дополняется до 3 символов")
+
+	require.Len(t, archive.Chats, 2)
+	general := archive.Chats[0]
+	assert.Equal(t, "general", general.Title)
+	assert.Equal(t, "slack:C1", archive.ChatKey(general))
+	assert.True(t, general.CreatedAt.Equal(time.Unix(1577836800, 0)))
+
+	require.Len(t, general.Messages, 3, "служебный channel_join пропущен")
+	first := general.Messages[0]
+	assert.Equal(t, "hi @deploy_bot, see docs (https://example.com) & @channel", first.Text)
+	assert.Equal(t, "U1", first.AuthorExternalID)
+	assert.True(t, first.CreatedAt.Equal(time.Unix(1577836801, 123456000)))
+	require.NotNil(t, first.EditedAt)
+	assert.Equal(t, "slack:C1:1577836801.123456", archive.MessageKey(general, first))
+
+	assert.Equal(t, "plan.pdf https://files.slack.com/plan.pdf", general.Messages[1].Text)
+	assert.Equal(t, "второй день", general.Messages[2].Text, "файлы дней идут по порядку")
+
+	require.Len(t, archive.Chats[1].Messages, 1)
+	assert.Equal(t, "secret", archive.Chats[1].Title)
+}
+
+func TestParseSlackInvalid(t *testing.T) {
+	_, err := ParseSlack(strings.NewReader("not a zip"), 9)
+	assert.Error(t, err)
+
+	r := slackZip(t, map[string]string{"users.json": `[]`})
+	_, err = ParseSlack(r, r.Size())
+	assert.Error(t, err, "архив без каналов")
+}
+
+func TestParseSlackLimits(t *testing.T) {
+	entries, entrySize, totalSize := maxSlackEntries, maxSlackEntrySize, maxSlackTotalSize
+	t.Cleanup(func() {
+		maxSlackEntries, maxSlackEntrySize, maxSlackTotalSize = entries, entrySize, totalSize
+	})
+	maxSlackEntries, maxSlackEntrySize, maxSlackTotalSize = 4, 1<<10, 2<<10
+
+	channels := `[{"id":"C1","name":"general","created":1577836800}]`
+	// Пробелы сжимаются почти в ноль: маленький архив распаковывается в большой файл
+	message := `{"type":"message","user":"U1","text":"hi","ts":"1577836801.000000"}`
+	day := "[" + message + "]"
+	pad := func(n int) string { return "[" + message + strings.Repeat(" ", n) + "]" }
+	padded := pad(900)
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		expectErr string
+	}{
+		{
+			name:  "в пределах лимитов",
+			files: map[string]string{"channels.json": channels, "general/2020-01-01.json": padded, "general/2020-01-02.json": day},
+		},
+		{
+			name:      "один файл больше лимита",
+			files:     map[string]string{"channels.json": channels, "general/2020-01-01.json": pad(1 << 20)},
+			expectErr: "invalid slack archive: general/2020-01-01.json: too large when uncompressed",
+		},
+		{
+			name:      "вместе файлы больше общего лимита",
+			files:     map[string]string{"channels.json": channels, "general/2020-01-01.json": padded, "general/2020-01-02.json": padded, "general/2020-01-03.json": padded},
+			expectErr: "invalid slack archive: general/2020-01-03.json: too large when uncompressed",
+		},
+		{
+			name: "слишком много файлов",
+			files: map[string]string{"channels.json": channels, "general/2020-01-01.json": day, "general/2020-01-02.json": day,
+				"general/2020-01-03.json": day, "general/2020-01-04.json": day},
+			expectErr: "invalid slack archive: more than 4 files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := slackZip(t, tt.files)
+			archive, err := ParseSlack(r, r.Size())
+			if tt.expectErr != "" {
+				assert.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, archive.Chats, 1)
+			assert.Len(t, archive.Chats[0].Messages, 2)
+		})
+	}
+}
+
+func TestParseExport(t *testing.T) {
+	doc := `{
+		"chat": {"id": 7, "title": "", "type": "dm", "created_at": "2026-01-28T10:00:00Z"},
+		"users": [{"id": 2, "username": "alice", "is_bot": false}],
+		"messages": [
+			{"id": 1, "author_id": 2, "text": "**привет**", "format": "markdown", "created_at": "2026-01-28T10:30:00Z", "edited_at": "2026-01-28T10:35:00Z"},
+			{"id": 2, "author_id": 9, "text": "отчёт", "format": "plain", "created_at": "2026-01-28T10:31:00Z",
+			 "attachments": [{"id": 5, "file_name": "report.pdf", "url": "/attachments/5"}]}
+		]
+	}`
+
+	archive, err := Parse(FormatJSON, strings.NewReader(doc), int64(len(doc)))
+	require.NoError(t, err)
+
+	require.Len(t, archive.Users, 1)
+	assert.Equal(t, User{ExternalID: "2", Username: "alice"}, archive.Users[0])
+
+	require.Len(t, archive.Chats, 1)
+	chat := archive.Chats[0]
+	assert.Equal(t, "Chat 7", chat.Title, "у личного чата нет названия")
+	assert.Equal(t, "json:7", archive.ChatKey(chat))
+
+	require.Len(t, chat.Messages, 2)
+	assert.Equal(t, "markdown", chat.Messages[0].Format)
+	require.NotNil(t, chat.Messages[0].EditedAt)
+	assert.Equal(t, "2", chat.Messages[0].AuthorExternalID)
+	assert.Equal(t, "отчёт\nreport.pdf /attachments/5", chat.Messages[1].Text)
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	_, err := Parse("mbox", strings.NewReader(""), 0)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	assert.Equal(t, "john_doe", normalizeUsername("John Doe", "fallback"))
+	assert.Equal(t, "channel_", normalizeUsername("channel", "fallback"))
+	assert.Equal(t, "slack-u1", normalizeUsername("Иван", "slack-U1"))
+	assert.Len(t, normalizeUsername(strings.Repeat("a", 40), "fallback"), 32)
+}
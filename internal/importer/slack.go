@@ -0,0 +1,276 @@
+package importer
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// slackSubtypes — подтипы сообщений Slack, которые переносятся. Остальные (вход
+// в канал, смена темы и т. п.) — служебные и пропускаются.
+var slackSubtypes = map[string]bool{
+	"":                 true,
+	"bot_message":      true,
+	"file_share":       true,
+	"me_message":       true,
+	"thread_broadcast": true,
+}
+
+var (
+	slackUserRef    = regexp.MustCompile(`<@([A-Z0-9]+)(?:\|[^>]*)?>`)
+	slackChannelRef = regexp.MustCompile(`<#[A-Z0-9]+\|([^>]*)>`)
+	slackSpecial    = regexp.MustCompile(`<!(channel|here|everyone)(?:\|[^>]*)?>`)
+	slackLink       = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]*))?>`)
+)
+
+// Ограничения на распакованный архив: сильно сжатый zip (zip-бомба) не должен
+// исчерпать память при разборе. Переменные, а не константы, чтобы их можно было
+// уменьшить в тестах.
+var (
+	// maxSlackEntries — сколько файлов может быть в архиве
+	maxSlackEntries = 100_000
+	// maxSlackEntrySize — сколько байт можно распаковать из одного JSON-файла
+	maxSlackEntrySize int64 = 64 << 20
+	// maxSlackTotalSize — сколько байт можно распаковать из всех файлов архива вместе
+	maxSlackTotalSize int64 = 1 << 30
+)
+
+// slackFiles — файлы архива по очищенным путям и остаток общего лимита распаковки
+type slackFiles struct {
+	files     map[string]*zip.File
+	remaining int64
+}
+
+type slackUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	IsBot bool   `json:"is_bot"`
+}
+
+type slackChannel struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Created int64  `json:"created"`
+}
+
+type slackMessage struct {
+	Type    string `json:"type"`
+	Subtype string `json:"subtype"`
+	User    string `json:"user"`
+	Text    string `json:"text"`
+	TS      string `json:"ts"`
+	Edited  *struct {
+		TS string `json:"ts"`
+	} `json:"edited"`
+	Files []struct {
+		Name       string `json:"name"`
+		URLPrivate string `json:"url_private"`
+	} `json:"files"`
+}
+
+// ParseSlack читает zip-выгрузку рабочего пространства Slack: пользователей из users.json,
+// публичные и приватные каналы из channels.json и groups.json, сообщения из файлов
+// <канал>/<дата>.json. Личные переписки Slack не переносятся.
+func ParseSlack(r io.ReaderAt, size int64) (*Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slack archive: %w", err)
+	}
+
+	if len(zr.File) > maxSlackEntries {
+		return nil, fmt.Errorf("invalid slack archive: more than %d files", maxSlackEntries)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[path.Clean(file.Name)] = file
+	}
+	archiveFiles := &slackFiles{files: files, remaining: maxSlackTotalSize}
+
+	var users []slackUser
+	if err := archiveFiles.readJSON("users.json", &users); err != nil {
+		return nil, err
+	}
+
+	var channels []slackChannel
+	for _, name := range []string{"channels.json", "groups.json"} {
+		var list []slackChannel
+		if err := archiveFiles.readJSON(name, &list); err != nil {
+			return nil, err
+		}
+		channels = append(channels, list...)
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("invalid slack archive: no channels.json")
+	}
+
+	archive := &Archive{Source: FormatSlack}
+	usernames := make(map[string]string, len(users))
+	for _, user := range users {
+		username := normalizeUsername(user.Name, "slack-"+user.ID)
+		usernames[user.ID] = username
+		archive.Users = append(archive.Users, User{
+			ExternalID: user.ID,
+			Username:   username,
+			IsBot:      user.IsBot,
+		})
+	}
+
+	for _, channel := range channels {
+		chat := Chat{
+			ExternalID: channel.ID,
+			Title:      channel.Name,
+			CreatedAt:  time.Unix(channel.Created, 0).UTC(),
+		}
+
+		// Файлы каналов называются по дате, поэтому сортировка по имени хронологическая
+		var days []string
+		for name := range files {
+			if path.Dir(name) == channel.Name && path.Ext(name) == ".json" {
+				days = append(days, name)
+			}
+		}
+		sort.Strings(days)
+
+		for _, day := range days {
+			var messages []slackMessage
+			if err := archiveFiles.readJSON(day, &messages); err != nil {
+				return nil, err
+			}
+			for _, message := range messages {
+				if imported, ok := convertSlackMessage(message, usernames); ok {
+					chat.Messages = append(chat.Messages, imported)
+				}
+			}
+		}
+
+		sort.SliceStable(chat.Messages, func(i, j int) bool {
+			return chat.Messages[i].CreatedAt.Before(chat.Messages[j].CreatedAt)
+		})
+		archive.Chats = append(archive.Chats, chat)
+	}
+
+	return archive, nil
+}
+
+// convertSlackMessage переводит сообщение Slack в общее представление. Служебные
+// и пустые сообщения пропускаются.
+func convertSlackMessage(message slackMessage, usernames map[string]string) (Message, bool) {
+	if message.Type != "message" || !slackSubtypes[message.Subtype] {
+		return Message{}, false
+	}
+
+	createdAt, err := parseSlackTS(message.TS)
+	if err != nil {
+		return Message{}, false
+	}
+
+	lines := []string{}
+	if text := strings.TrimSpace(convertSlackText(message.Text, usernames)); text != "" {
+		lines = append(lines, text)
+	}
+	// Файлы не переносятся, остаются ссылки на них в Slack
+	for _, file := range message.Files {
+		lines = append(lines, strings.TrimSpace(file.Name+" "+file.URLPrivate))
+	}
+	if len(lines) == 0 {
+		return Message{}, false
+	}
+
+	imported := Message{
+		ExternalID:       message.TS,
+		AuthorExternalID: message.User,
+		Text:             truncateText(strings.Join(lines, "\n")),
+		Format:           models.MessageFormatPlain,
+		CreatedAt:        createdAt,
+	}
+	if message.Edited != nil {
+		if editedAt, err := parseSlackTS(message.Edited.TS); err == nil {
+			imported.EditedAt = &editedAt
+		}
+	}
+
+	return imported, true
+}
+
+// convertSlackText заменяет разметку ссылок Slack на текст: <@U123> на @username,
+// <!channel> и <!here> на @channel, <url|текст> на "текст (url)".
+func convertSlackText(text string, usernames map[string]string) string {
+	text = slackUserRef.ReplaceAllStringFunc(text, func(ref string) string {
+		id := slackUserRef.FindStringSubmatch(ref)[1]
+		if username, ok := usernames[id]; ok {
+			return "@" + username
+		}
+		return ref
+	})
+	text = slackChannelRef.ReplaceAllString(text, "#$1")
+	text = slackSpecial.ReplaceAllString(text, "@channel")
+	text = slackLink.ReplaceAllStringFunc(text, func(ref string) string {
+		parts := slackLink.FindStringSubmatch(ref)
+		if parts[2] == "" || parts[2] == parts[1] {
+			return parts[1]
+		}
+		return parts[2] + " (" + parts[1] + ")"
+	})
+	// Slack экранирует в тексте только &, < и >
+	return html.UnescapeString(text)
+}
+
+// parseSlackTS переводит ts вида "1360782804.083113" во время с точностью до микросекунд
+func parseSlackTS(ts string) (time.Time, error) {
+	seconds, fraction, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid slack ts %q", ts)
+	}
+
+	var micros int64
+	if fraction != "" {
+		fraction = (fraction + "000000")[:6]
+		if micros, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("invalid slack ts %q", ts)
+		}
+	}
+
+	return time.Unix(sec, micros*int64(time.Microsecond)).UTC(), nil
+}
+
+// readJSON разбирает JSON-файл архива. Отсутствующий файл не считается ошибкой.
+// Распаковывается не больше maxSlackEntrySize байт и не больше остатка общего лимита:
+// размеру из заголовка zip верить нельзя.
+func (z *slackFiles) readJSON(name string, v any) error {
+	file, ok := z.files[name]
+	if !ok {
+		return nil
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	limit := min(maxSlackEntrySize, z.remaining)
+	// Байт сверх лимита отличает файл ровно limit от слишком большого
+	limited := &io.LimitedReader{R: rc, N: limit + 1}
+	err = json.NewDecoder(limited).Decode(v)
+	read := limit + 1 - limited.N
+	if read > limit {
+		return fmt.Errorf("invalid slack archive: %s: too large when uncompressed", name)
+	}
+	z.remaining -= read
+	if err != nil {
+		return fmt.Errorf("invalid slack archive: %s: %w", name, err)
+	}
+	return nil
+}
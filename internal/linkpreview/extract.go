@@ -0,0 +1,60 @@
+package linkpreview
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxPerMessage — сколько ссылок одного сообщения разворачивается в карточки
+const MaxPerMessage = 3
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+
+// ExtractURLs находит в тексте до limit уникальных http(s)-ссылок в порядке появления.
+// Завершающая пунктуация и непарные скобки отбрасываются, фрагмент (#...) удаляется.
+func ExtractURLs(text string, limit int) []string {
+	var urls []string
+	seen := make(map[string]bool)
+
+	for _, raw := range urlPattern.FindAllString(text, -1) {
+		raw = trimTrailing(raw)
+
+		parsed, err := url.Parse(raw)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		parsed.Fragment = ""
+		normalized := parsed.String()
+
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		urls = append(urls, normalized)
+
+		if len(urls) == limit {
+			break
+		}
+	}
+
+	return urls
+}
+
+// trimTrailing убирает пунктуацию в конце ссылки ("см. https://example.com.")
+// и закрывающие скобки без пары ("(https://example.com)")
+func trimTrailing(raw string) string {
+	for {
+		trimmed := strings.TrimRight(raw, ".,;:!?*_~")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = strings.TrimSuffix(trimmed, ")")
+		}
+		if strings.HasSuffix(trimmed, "]") && strings.Count(trimmed, "[") < strings.Count(trimmed, "]") {
+			trimmed = strings.TrimSuffix(trimmed, "]")
+		}
+		if trimmed == raw {
+			return raw
+		}
+		raw = trimmed
+	}
+}
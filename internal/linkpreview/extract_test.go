@@ -0,0 +1,46 @@
+package linkpreview
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "пунктуация и скобки",
+			text:  "См. https://example.com/a. И (https://example.com/b), а ещё https://en.wikipedia.org/wiki/Go_(language)!",
+			limit: 5,
+			want:  []string{"https://example.com/a", "https://example.com/b", "https://en.wikipedia.org/wiki/Go_(language)"},
+		},
+		{
+			name:  "дубликаты и фрагменты",
+			text:  "http://example.com/page#top и http://example.com/page",
+			limit: 5,
+			want:  []string{"http://example.com/page"},
+		},
+		{
+			name:  "лимит",
+			text:  "https://a.com https://b.com https://c.com",
+			limit: 2,
+			want:  []string{"https://a.com", "https://b.com"},
+		},
+		{
+			name:  "без ссылок",
+			text:  "ftp://example.com и просто текст",
+			limit: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractURLs(tt.text, tt.limit))
+		})
+	}
+}
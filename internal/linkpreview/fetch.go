@@ -0,0 +1,151 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+const (
+	// maxBodySize — сколько байт страницы читается в поисках метатегов
+	maxBodySize = 512 << 10
+	// maxFieldLength — ограничение длины полей карточки
+	maxFieldLength = 500
+	userAgent      = "chat-golang-linkpreview/1.0"
+)
+
+var ErrNotHTML = errors.New("response is not an HTML page")
+
+type Fetcher struct {
+	client *http.Client
+}
+
+// NewFetcher создаёт загрузчик метаданных. В продакшене client должен быть из netutil.NewSafeClient.
+func NewFetcher(client *http.Client) *Fetcher {
+	return &Fetcher{client: client}
+}
+
+// Fetch загружает страницу и собирает карточку из OpenGraph, Twitter Card и <title>
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	meta := parseMeta(io.LimitReader(resp.Body, maxBodySize))
+
+	preview := &models.LinkPreview{
+		URL:         rawURL,
+		Title:       first(meta["og:title"], meta["twitter:title"], meta["title"]),
+		Description: first(meta["og:description"], meta["twitter:description"], meta["description"]),
+		SiteName:    first(meta["og:site_name"], resp.Request.URL.Hostname()),
+		ImageURL:    resolveImage(resp.Request.URL, first(meta["og:image"], meta["og:image:url"], meta["twitter:image"])),
+	}
+
+	return preview, nil
+}
+
+// parseMeta собирает метатеги и <title> из <head>. Разбор останавливается на <body>,
+// поэтому большие страницы не читаются целиком.
+func parseMeta(r io.Reader) map[string]string {
+	meta := make(map[string]string)
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return meta
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				return meta
+			case "title":
+				inTitle = true
+			case "meta":
+				var key, content string
+				for _, attr := range token.Attr {
+					switch attr.Key {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(attr.Val))
+					case "content":
+						content = attr.Val
+					}
+				}
+				if key != "" && meta[key] == "" {
+					meta[key] = clean(content)
+				}
+			}
+		case html.EndTagToken:
+			if tokenizer.Token().Data == "title" {
+				inTitle = false
+			}
+		case html.TextToken:
+			if inTitle && meta["title"] == "" {
+				meta["title"] = clean(string(tokenizer.Text()))
+			}
+		}
+	}
+}
+
+// clean схлопывает пробелы и обрезает строку до maxFieldLength символов
+func clean(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) <= maxFieldLength {
+		return value
+	}
+	return string([]rune(value)[:maxFieldLength])
+}
+
+// resolveImage превращает относительный адрес картинки в абсолютный http(s)
+func resolveImage(base *url.URL, raw string) string {
+	if raw == "" {
+		return ""
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(ref)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package linkpreview
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!doctype html><html><head>
+			<title>Fallback title</title>
+			<meta property="og:title" content="  Статья   про Go ">
+			<meta property="og:description" content="Описание статьи">
+			<meta property="og:image" content="/images/cover.png">
+			<meta property="og:site_name" content="Example">
+			</head><body><meta property="og:title" content="в body не читается"></body></html>`))
+	})
+	mux.HandleFunc("/twitter", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Title tag</title>
+			<meta name="twitter:description" content="Twitter description">
+			<meta name="twitter:image" content="javascript:alert(1)">
+			</head></html>`))
+	})
+	mux.HandleFunc("/huge-title", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>" + strings.Repeat("я", 2000) + "</title></head></html>"))
+	})
+	mux.HandleFunc("/file.pdf", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.4"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/article", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch(t *testing.T) {
+	server := newTestSite(t)
+	fetcher := NewFetcher(server.Client())
+	ctx := context.Background()
+
+	preview, err := fetcher.Fetch(ctx, server.URL+"/article")
+	require.NoError(t, err)
+	assert.Equal(t, "Статья про Go", preview.Title)
+	assert.Equal(t, "Описание статьи", preview.Description)
+	assert.Equal(t, server.URL+"/images/cover.png", preview.ImageURL)
+	assert.Equal(t, "Example", preview.SiteName)
+
+	preview, err = fetcher.Fetch(ctx, server.URL+"/twitter")
+	require.NoError(t, err)
+	assert.Equal(t, "Title tag", preview.Title)
+	assert.Equal(t, "Twitter description", preview.Description)
+	assert.Empty(t, preview.ImageURL, "небезопасные схемы картинок отбрасываются")
+	assert.Equal(t, "127.0.0.1", preview.SiteName)
+
+	preview, err = fetcher.Fetch(ctx, server.URL+"/huge-title")
+	require.NoError(t, err)
+	assert.Equal(t, maxFieldLength, len([]rune(preview.Title)))
+
+	preview, err = fetcher.Fetch(ctx, server.URL+"/redirect")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/redirect", preview.URL, "карточка хранится под исходным URL")
+	assert.Equal(t, "Статья про Go", preview.Title)
+
+	_, err = fetcher.Fetch(ctx, server.URL+"/file.pdf")
+	assert.ErrorIs(t, err, ErrNotHTML)
+
+	_, err = fetcher.Fetch(ctx, server.URL+"/missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+// Package linkpreview разворачивает ссылки из сообщений в карточки (unfurling).
+//
+// Unfurler подключается к outbox как приёмник событий: на каждое message.created он
+// ставит ссылки из текста в очередь, а пул воркеров в фоне загружает страницы через
+// защищённый от SSRF клиент и кеширует карточки в таблице link_previews. Сервис чатов
+// при чтении сообщений подбирает карточки по тем же ссылкам.
+package linkpreview
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// DefaultTTL — как долго карточка считается свежей и не загружается заново
+const DefaultTTL = 24 * time.Hour
+
+type Unfurler struct {
+	repo    repository.LinkPreviewRepository
+	fetcher *Fetcher
+
+	jobs    chan string
+	workers int
+	ttl     time.Duration
+
+	mu       sync.Mutex
+	inflight map[string]bool
+}
+
+func NewUnfurler(repo repository.LinkPreviewRepository, fetcher *Fetcher, workers, queueSize int, ttl time.Duration) *Unfurler {
+	return &Unfurler{
+		repo:     repo,
+		fetcher:  fetcher,
+		jobs:     make(chan string, queueSize),
+		workers:  workers,
+		ttl:      ttl,
+		inflight: make(map[string]bool),
+	}
+}
+
+// Publish реализует outbox.Sink: ставит ссылки нового сообщения в очередь.
+// Никогда не возвращает ошибку, чтобы не задерживать остальные события outbox.
+func (u *Unfurler) Publish(ctx context.Context, event models.OutboxEvent) error {
+	if event.EventType != models.EventMessageCreated {
+		return nil
+	}
+
+	var message models.Message
+	if err := json.Unmarshal([]byte(event.Payload), &message); err != nil {
+		log.Printf("linkpreview: event %d: %v", event.ID, err)
+		return nil
+	}
+
+	for _, url := range ExtractURLs(message.Text, MaxPerMessage) {
+		u.Enqueue(url)
+	}
+
+	return nil
+}
+
+// Enqueue ставит URL в очередь, не блокируясь. Возвращает false, если URL уже
+// обрабатывается или очередь переполнена.
+func (u *Unfurler) Enqueue(url string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.inflight[url] {
+		return false
+	}
+
+	select {
+	case u.jobs <- url:
+		u.inflight[url] = true
+		return true
+	default:
+		log.Printf("linkpreview: queue is full, skipping %s", url)
+		return false
+	}
+}
+
+// Run запускает воркеры и ждёт их завершения после отмены ctx
+func (u *Unfurler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range u.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case url := <-u.jobs:
+					if err := u.Process(ctx, url); err != nil && ctx.Err() == nil {
+						log.Printf("linkpreview: %s: %v", url, err)
+					}
+					u.mu.Lock()
+					delete(u.inflight, url)
+					u.mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Process загружает карточку, если в кеше нет свежей. Ошибка загрузки тоже
+// кешируется, ошибкой Process считается только сбой записи в базу.
+func (u *Unfurler) Process(ctx context.Context, url string) error {
+	cached, err := u.repo.GetByURL(ctx, url)
+	if err != nil {
+		return err
+	}
+	if cached != nil && time.Since(cached.FetchedAt) < u.ttl {
+		return nil
+	}
+
+	preview, err := u.fetcher.Fetch(ctx, url)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		preview = &models.LinkPreview{URL: url, Status: models.LinkPreviewFailed, Error: err.Error()}
+	} else {
+		preview.Status = models.LinkPreviewOK
+	}
+	preview.FetchedAt = time.Now()
+
+	return u.repo.Upsert(ctx, preview)
+}
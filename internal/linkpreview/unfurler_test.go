@@ -0,0 +1,90 @@
+package linkpreview
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/memory"
+)
+
+func messageEvent(t *testing.T, text string) models.OutboxEvent {
+	t.Helper()
+
+	payload, err := json.Marshal(models.Message{ID: 1, ChatID: 1, Text: text})
+	require.NoError(t, err)
+	return models.OutboxEvent{ID: 1, EventType: models.EventMessageCreated, ChatID: 1, Payload: string(payload)}
+}
+
+func TestUnfurler(t *testing.T) {
+	server := newTestSite(t)
+	repo := memory.NewLinkPreviewRepository(memory.NewStore())
+	unfurler := NewUnfurler(repo, NewFetcher(server.Client()), 2, 10, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		unfurler.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.NoError(t, unfurler.Publish(ctx, messageEvent(t, "читайте "+server.URL+"/article и "+server.URL+"/missing")))
+	require.NoError(t, unfurler.Publish(ctx, models.OutboxEvent{EventType: models.EventChatCreated, Payload: `{}`}))
+
+	require.Eventually(t, func() bool {
+		previews, err := repo.GetByURLs(ctx, []string{server.URL + "/article", server.URL + "/missing"})
+		return err == nil && len(previews) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	article, err := repo.GetByURL(ctx, server.URL+"/article")
+	require.NoError(t, err)
+	assert.Equal(t, models.LinkPreviewOK, article.Status)
+	assert.Equal(t, "Статья про Go", article.Title)
+
+	missing, err := repo.GetByURL(ctx, server.URL+"/missing")
+	require.NoError(t, err)
+	assert.Equal(t, models.LinkPreviewFailed, missing.Status)
+	assert.NotEmpty(t, missing.Error)
+}
+
+func TestUnfurler_UsesFreshCache(t *testing.T) {
+	server := newTestSite(t)
+	repo := memory.NewLinkPreviewRepository(memory.NewStore())
+	ctx := context.Background()
+
+	url := server.URL + "/article"
+	require.NoError(t, repo.Upsert(ctx, &models.LinkPreview{URL: url, Title: "Из кеша", Status: models.LinkPreviewOK, FetchedAt: time.Now()}))
+
+	unfurler := NewUnfurler(repo, NewFetcher(server.Client()), 1, 1, time.Hour)
+	require.NoError(t, unfurler.Process(ctx, url))
+
+	cached, err := repo.GetByURL(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, "Из кеша", cached.Title, "свежая карточка не загружается заново")
+
+	// Протухшая карточка обновляется
+	unfurler = NewUnfurler(repo, NewFetcher(server.Client()), 1, 1, 0)
+	require.NoError(t, unfurler.Process(ctx, url))
+
+	cached, err = repo.GetByURL(ctx, url)
+	require.NoError(t, err)
+	assert.Equal(t, "Статья про Go", cached.Title)
+}
+
+func TestUnfurler_EnqueueDeduplicates(t *testing.T) {
+	unfurler := NewUnfurler(memory.NewLinkPreviewRepository(memory.NewStore()), nil, 1, 2, time.Hour)
+
+	assert.True(t, unfurler.Enqueue("https://example.com/a"))
+	assert.False(t, unfurler.Enqueue("https://example.com/a"), "URL уже в очереди")
+	assert.True(t, unfurler.Enqueue("https://example.com/b"))
+	assert.False(t, unfurler.Enqueue("https://example.com/c"), "очередь переполнена")
+}
@@ -0,0 +1,195 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var bareURLPattern = regexp.MustCompile(`^https?://[^\s<>"'` + "`" + `]+`)
+
+// inlineDelimiters — парные разделители в порядке проверки (длинные раньше коротких)
+var inlineDelimiters = []struct {
+	marker string
+	tag    string
+}{
+	{"**", "strong"},
+	{"__", "strong"},
+	{"~~", "del"},
+	{"*", "em"},
+	{"_", "em"},
+}
+
+// renderInline обрабатывает строчную разметку. Весь текст вне тегов экранируется.
+func renderInline(text string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		// Экранирование: \* выводит звёздочку как есть
+		if rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_~[]()#>-+.!", rune(rest[1])) {
+			out.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+		}
+
+		if rest[0] == '`' {
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				out.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		}
+
+		if rest[0] == '[' {
+			if label, href, n, ok := parseLink(rest); ok {
+				out.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">` + renderInline(label) + "</a>")
+				i += n
+				continue
+			}
+		}
+
+		if rest[0] == 'h' && (i == 0 || !isWordByte(text[i-1])) {
+			if match := bareURLPattern.FindString(rest); match != "" {
+				link := trimURL(match)
+				if safeHref(link) {
+					escaped := html.EscapeString(link)
+					out.WriteString(`<a href="` + escaped + `" rel="nofollow noopener noreferrer">` + escaped + "</a>")
+					i += len(link)
+					continue
+				}
+			}
+		}
+
+		if n, ok := renderDelimited(&out, text, i); ok {
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		out.WriteString(html.EscapeString(string(r)))
+		i += size
+	}
+
+	return out.String()
+}
+
+// renderDelimited пробует разобрать **жирный**, *курсив* и т.п. с позиции i
+func renderDelimited(out *strings.Builder, text string, i int) (int, bool) {
+	rest := text[i:]
+	for _, d := range inlineDelimiters {
+		if !strings.HasPrefix(rest, d.marker) {
+			continue
+		}
+		// Подчёркивания внутри слов (snake_case) не считаются разметкой
+		if d.marker[0] == '_' && i > 0 && isWordByte(text[i-1]) {
+			return 0, false
+		}
+
+		inner := rest[len(d.marker):]
+		if inner == "" || unicode.IsSpace(rune(inner[0])) {
+			continue
+		}
+
+		end := closingDelimiter(inner, d.marker)
+		if end <= 0 {
+			continue
+		}
+
+		out.WriteString("<" + d.tag + ">" + renderInline(inner[:end]) + "</" + d.tag + ">")
+		return len(d.marker)*2 + end, true
+	}
+	return 0, false
+}
+
+// closingDelimiter ищет закрывающий разделитель, перед которым нет пробела
+func closingDelimiter(inner, marker string) int {
+	for from := 0; ; {
+		idx := strings.Index(inner[from:], marker)
+		if idx < 0 {
+			return -1
+		}
+		end := from + idx
+		// Для двойного маркера в серии вида "***" закрывающим считается конец серии:
+		// **жирный и *курсив*** — внутренний курсив закрывается первой звёздочкой
+		if len(marker) == 2 {
+			for end+len(marker) < len(inner) && inner[end+len(marker)] == marker[0] {
+				end++
+			}
+		}
+		after := end + len(marker)
+		valid := end > 0 && !unicode.IsSpace(rune(inner[end-1]))
+		// Одиночный маркер не должен быть частью двойного (*a **b** c*)
+		if valid && len(marker) == 1 && after < len(inner) && inner[after] == marker[0] {
+			valid = false
+			end++
+		}
+		if marker[0] == '_' && after < len(inner) && isWordByte(inner[after]) {
+			valid = false
+		}
+		if valid {
+			return end
+		}
+		from = end + 1
+		if from >= len(inner) {
+			return -1
+		}
+	}
+}
+
+// parseLink разбирает [текст](url). Небезопасные схемы превращают ссылку в обычный текст.
+func parseLink(text string) (label, href string, n int, ok bool) {
+	closeLabel := strings.Index(text, "](")
+	if closeLabel <= 1 {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeURL <= 0 {
+		return "", "", 0, false
+	}
+
+	label = text[1:closeLabel]
+	href = strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeURL])
+	if strings.ContainsAny(label, "[]") || !safeHref(href) {
+		return "", "", 0, false
+	}
+
+	return label, href, closeLabel + 3 + closeURL, true
+}
+
+// safeHref пропускает только абсолютные http, https и mailto ссылки
+func safeHref(href string) bool {
+	parsed, err := url.Parse(href)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return parsed.Opaque != ""
+	}
+	return false
+}
+
+// trimURL отбрасывает пунктуацию в конце голой ссылки и непарные закрывающие скобки
+func trimURL(link string) string {
+	for {
+		trimmed := strings.TrimRight(link, ".,;:!?*_~")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = strings.TrimSuffix(trimmed, ")")
+		}
+		if trimmed == link {
+			return link
+		}
+		link = trimmed
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
@@ -0,0 +1,136 @@
+// Package markdown превращает текст сообщений в HTML, безопасный для вставки в страницу.
+//
+// Поддерживается подмножество CommonMark, которого хватает для чата:
+//
+//   - абзацы и переносы строк;
+//   - заголовки (# … ######), цитаты (>), маркированные и нумерованные списки;
+//   - блоки кода (```), `код`, **жирный**, *курсив* / _курсив_, ~~зачёркнутый~~;
+//   - ссылки [текст](url) и голые http(s)-ссылки.
+//
+// Безопасность обеспечивается построением: весь пользовательский текст экранируется,
+// сырой HTML не пропускается, а в href попадают только http, https и mailto.
+// Поэтому результат одинаково и без XSS отображается в вебе и мобильных клиентах.
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletPattern      = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedPattern     = regexp.MustCompile(`^\s{0,3}\d{1,9}[.)]\s+(.*)$`)
+	blockquotePattern  = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	fencePattern       = regexp.MustCompile("^\\s{0,3}```\\s*([\\w+#.-]*)\\s*$")
+	closingFence       = regexp.MustCompile("^\\s{0,3}```\\s*$")
+	languageClassValid = regexp.MustCompile(`^[\w+#.-]{1,32}$`)
+)
+
+// Plain экранирует обычный текст и сохраняет переносы строк
+func Plain(text string) string {
+	return "<p>" + strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n") + "</p>"
+}
+
+// Render превращает markdown в безопасный HTML
+func Render(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var out strings.Builder
+	renderBlocks(&out, strings.Split(text, "\n"))
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func renderBlocks(out *strings.Builder, lines []string) {
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		out.WriteString("<p>")
+		for i, line := range paragraph {
+			if i > 0 {
+				out.WriteString("<br>\n")
+			}
+			out.WriteString(renderInline(strings.TrimSpace(line)))
+		}
+		out.WriteString("</p>\n")
+		paragraph = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if match := fencePattern.FindStringSubmatch(line); match != nil {
+			flush()
+			var code []string
+			for i++; i < len(lines) && !closingFence.MatchString(lines[i]); i++ {
+				code = append(code, lines[i])
+			}
+			out.WriteString("<pre><code")
+			if languageClassValid.MatchString(match[1]) {
+				out.WriteString(` class="language-` + html.EscapeString(match[1]) + `"`)
+			}
+			out.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			flush()
+			level := string(rune('0' + len(match[1])))
+			out.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">\n")
+			continue
+		}
+
+		if blockquotePattern.MatchString(line) {
+			flush()
+			var quoted []string
+			for ; i < len(lines); i++ {
+				match := blockquotePattern.FindStringSubmatch(lines[i])
+				if match == nil {
+					break
+				}
+				quoted = append(quoted, match[1])
+			}
+			i--
+			out.WriteString("<blockquote>\n")
+			renderBlocks(out, quoted)
+			out.WriteString("</blockquote>\n")
+			continue
+		}
+
+		if list, tag := listPattern(line); list != nil {
+			flush()
+			out.WriteString("<" + tag + ">\n")
+			for ; i < len(lines); i++ {
+				match := list.FindStringSubmatch(lines[i])
+				if match == nil {
+					break
+				}
+				out.WriteString("<li>" + renderInline(match[1]) + "</li>\n")
+			}
+			i--
+			out.WriteString("</" + tag + ">\n")
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	flush()
+}
+
+// listPattern определяет, начинается ли со строки список, и возвращает его тег
+func listPattern(line string) (*regexp.Regexp, string) {
+	switch {
+	case bulletPattern.MatchString(line):
+		return bulletPattern, "ul"
+	case orderedPattern.MatchString(line):
+		return orderedPattern, "ol"
+	}
+	return nil, ""
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "абзацы и переносы",
+			in:   "первая строка\nвторая\n\nновый абзац",
+			want: "<p>первая строка<br>\nвторая</p>\n<p>новый абзац</p>",
+		},
+		{
+			name: "выделение",
+			in:   "**жирный**, *курсив*, _курсив_, ~~нет~~ и `код *не* разметка`",
+			want: "<p><strong>жирный</strong>, <em>курсив</em>, <em>курсив</em>, <del>нет</del> и <code>код *не* разметка</code></p>",
+		},
+		{
+			name: "вложенное выделение",
+			in:   "**жирный и *курсив***",
+			want: "<p><strong>жирный и <em>курсив</em></strong></p>",
+		},
+		{
+			name: "snake_case и одиночные звёздочки",
+			in:   "snake_case_name и 2 * 3 * 4",
+			want: "<p>snake_case_name и 2 * 3 * 4</p>",
+		},
+		{
+			name: "экранирование разметки",
+			in:   `\*не курсив\*`,
+			want: "<p>*не курсив*</p>",
+		},
+		{
+			name: "ссылки",
+			in:   "[Go](https://go.dev) и https://example.com/a_b.",
+			want: `<p><a href="https://go.dev" rel="nofollow noopener noreferrer">Go</a> и <a href="https://example.com/a_b" rel="nofollow noopener noreferrer">https://example.com/a_b</a>.</p>`,
+		},
+		{
+			name: "заголовок и списки",
+			in:   "# Релиз\n- один\n- **два**\n1. первый\n2. второй",
+			want: "<h1>Релиз</h1>\n<ul>\n<li>один</li>\n<li><strong>два</strong></li>\n</ul>\n<ol>\n<li>первый</li>\n<li>второй</li>\n</ol>",
+		},
+		{
+			name: "цитата",
+			in:   "> цитата\n> **важно**\nответ",
+			want: "<blockquote>\n<p>цитата<br>\n<strong>важно</strong></p>\n</blockquote>\n<p>ответ</p>",
+		},
+		{
+			name: "блок кода",
+			in:   "```go\nfmt.Println(\"<hi>\")\n```",
+			want: "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.in))
+		})
+	}
+}
+
+func TestRender_XSS(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "сырой HTML",
+			in:   `<script>alert(1)</script><img src=x onerror=alert(1)>`,
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;&lt;img src=x onerror=alert(1)&gt;</p>",
+		},
+		{
+			name: "javascript в ссылке",
+			in:   "[click](javascript:alert(1))",
+			want: "<p>[click](javascript:alert(1))</p>",
+		},
+		{
+			name: "data URL",
+			in:   "[x](data:text/html;base64,PHNjcmlwdD4=)",
+			want: "<p>[x](data:text/html;base64,PHNjcmlwdD4=)</p>",
+		},
+		{
+			name: "кавычки в адресе",
+			in:   `[x](https://example.com/"onmouseover="alert(1))`,
+			want: `<p><a href="https://example.com/&#34;onmouseover=&#34;alert(1" rel="nofollow noopener noreferrer">x</a>)</p>`,
+		},
+		{
+			name: "язык блока кода",
+			in:   "```\"><script>\ncode\n```",
+			want: "<p>```&#34;&gt;&lt;script&gt;<br>\ncode</p>\n<pre><code></code></pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.in))
+		})
+	}
+}
+
+func TestPlain(t *testing.T) {
+	assert.Equal(t, "<p>**не жирный** &lt;b&gt;<br>\nстрока</p>", Plain("**не жирный** <b>\nстрока"))
+}
@@ -1,21 +1,611 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+type User struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username" gorm:"uniqueIndex"`
+	IsBot     bool      `json:"is_bot"`
+	CreatedAt time.Time `json:"created_at"`
+}
 
 type Chat struct {
 	ID        int64     `json:"id"`
 	Title     string    `json:"title"`
+	Type      string    `json:"type" gorm:"default:room"`
+	OwnerID   *int64    `json:"owner_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// DMKey — пара участников личного чата в виде "<меньший ID>:<больший ID>".
+	// Уникальный индекс гарантирует один личный чат на пару. У комнат пустой.
+	DMKey *string `json:"-" gorm:"uniqueIndex"`
+	// ImportKey — идентификатор чата в импортированном архиве ("slack:C024BE91L").
+	// По нему повторный импорт находит уже созданный чат.
+	ImportKey *string `json:"-" gorm:"uniqueIndex"`
+}
+
+// Типы чатов
+const (
+	ChatTypeRoom = "room"
+	ChatTypeDM   = "dm"
+)
+
+// DMKey строит ключ личного чата пары пользователей независимо от порядка
+func DMKey(a, b int64) string {
+	if a > b {
+		a, b = b, a
+	}
+	return strconv.FormatInt(a, 10) + ":" + strconv.FormatInt(b, 10)
 }
 
 type Message struct {
+	ID       int64  `json:"id"`
+	ChatID   int64  `json:"chat_id"`
+	AuthorID *int64 `json:"author_id,omitempty"`
+	Text     string `json:"text"`
+	Format   string `json:"format"`
+	// HTML — безопасный HTML, отрендеренный из Text по Format. Не хранится в базе.
+	HTML      string    `json:"html" gorm:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	// EditedAt — время последней правки текста, nil если сообщение не редактировалось
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+	// ExpiresAt — когда исчезающее сообщение будет удалено, nil у обычных сообщений
+	ExpiresAt *time.Time `json:"expires_at,omitempty" gorm:"index:idx_messages_expires_at,where:expires_at IS NOT NULL"`
+	// ImportKey — идентификатор сообщения в импортированном архиве, у обычных сообщений пустой
+	ImportKey *string `json:"-" gorm:"uniqueIndex"`
+
+	Attachments  []Attachment  `json:"attachments,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	LinkPreviews []LinkPreview `json:"link_previews,omitempty" gorm:"-"`
+	// Mentions — ID упомянутых пользователей. Заполняется только при создании сообщения
+	// (ответ и событие message.created), в базе упоминания лежат в message_mentions.
+	Mentions []int64 `json:"mentions,omitempty" gorm:"-"`
+	// ReadBy — ID участников, прочитавших сообщение (кроме автора). Заполняется в GET /chats/{id}.
+	ReadBy []int64 `json:"read_by,omitempty" gorm:"-"`
+	// Pinned — закреплено ли сообщение в чате. Заполняется в GET /chats/{id}.
+	Pinned bool `json:"pinned" gorm:"-"`
+
+	// Chat нужен только для описания внешнего ключа (ON DELETE CASCADE) в GORM
+	Chat *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Attachment — файл, прикреплённый к сообщению. Содержимое лежит в blob-хранилище
+// под ключом StorageKey, в базе — только метаданные.
+type Attachment struct {
+	ID          int64     `json:"id"`
+	ChatID      int64     `json:"chat_id"`
+	MessageID   int64     `json:"message_id"`
+	UploaderID  *int64    `json:"uploader_id,omitempty"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Размеры картинки и её миниатюры. Заполняются фоновым генератором миниатюр,
+	// для остальных файлов остаются нулевыми.
+	Width           int    `json:"width,omitempty"`
+	Height          int    `json:"height,omitempty"`
+	ThumbnailKey    string `json:"-"`
+	ThumbnailWidth  int    `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight int    `json:"thumbnail_height,omitempty"`
+
+	Message *Message `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// URL возвращает путь, по которому отдаётся содержимое вложения
+func (a Attachment) URL() string {
+	return "/attachments/" + strconv.FormatInt(a.ID, 10)
+}
+
+// ThumbnailURL возвращает путь миниатюры или пустую строку, если её ещё нет
+func (a Attachment) ThumbnailURL() string {
+	if a.ThumbnailKey == "" {
+		return ""
+	}
+	return a.URL() + "/thumbnail"
+}
+
+// ThumbnailContentType — формат миниатюры: JPEG для фотографий, PNG для остального,
+// чтобы не терять прозрачность
+func (a Attachment) ThumbnailContentType() string {
+	if a.ContentType == "image/jpeg" {
+		return "image/jpeg"
+	}
+	return "image/png"
+}
+
+// MarshalJSON добавляет к вложению поля url и thumbnail_url
+func (a Attachment) MarshalJSON() ([]byte, error) {
+	type attachment Attachment
+	return json.Marshal(struct {
+		attachment
+		URL          string `json:"url"`
+		ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	}{attachment(a), a.URL(), a.ThumbnailURL()})
+}
+
+// ChatMember — участник чата. Владелец становится участником при создании чата,
+// автор — при первом сообщении, остальных добавляют участники.
+// LastReadMessageID и LastReadAt — последнее прочитанное сообщение и время его создания:
+// по времени непрочитанные считаются через индекс (chat_id, created_at).
+type ChatMember struct {
+	ChatID            int64      `json:"chat_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID            int64      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	JoinedAt          time.Time  `json:"joined_at"`
+	LastReadMessageID *int64     `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time `json:"last_read_at,omitempty"`
+
+	Chat *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	User *User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// HasRead сообщает, прочитал ли участник сообщение
+func (m ChatMember) HasRead(message Message) bool {
+	if m.LastReadMessageID == nil || m.LastReadAt == nil {
+		return false
+	}
+	if message.CreatedAt.Equal(*m.LastReadAt) {
+		return message.ID <= *m.LastReadMessageID
+	}
+	return message.CreatedAt.Before(*m.LastReadAt)
+}
+
+// ChatSummary — чат в списке чатов пользователя вместе с числом непрочитанных сообщений
+type ChatSummary struct {
+	Chat
+	LastReadMessageID *int64 `json:"last_read_message_id"`
+	UnreadCount       int64  `json:"unread_count"`
+}
+
+// MessageInput — данные нового сообщения, одиночного или из пакета
+type MessageInput struct {
+	Text   string `json:"text"`
+	Format string `json:"format"`
+	// ExpiresIn — через сколько секунд сообщение исчезнет, 0 — хранится как обычно
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// Attachments — уже загруженные файлы, которые сохраняются вместе с сообщением.
+	// Заполняется только сервером, из запроса не читается.
+	Attachments []Attachment `json:"-"`
+}
+
+// BatchItemResult — результат одного сообщения пакета: созданное сообщение или ошибка проверки
+type BatchItemResult struct {
+	Index   int      `json:"index"`
+	Message *Message `json:"message,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// RetentionPolicy — срок хранения сообщений чата: не старше MaxAgeSeconds и не больше
+// MaxMessages последних сообщений. nil-поле наследует глобальную политику, 0 снимает ограничение.
+type RetentionPolicy struct {
+	ChatID        int64     `json:"chat_id" gorm:"primaryKey;autoIncrement:false"`
+	MaxAgeSeconds *int64    `json:"max_age_seconds"`
+	MaxMessages   *int64    `json:"max_messages"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Chat *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (RetentionPolicy) TableName() string {
+	return "chat_retention_policies"
+}
+
+// RetentionReportItem — сколько сообщений чата удалит очистка по действующей политике
+type RetentionReportItem struct {
+	ChatID        int64     `json:"chat_id"`
+	MaxAgeSeconds int64     `json:"max_age_seconds"`
+	MaxMessages   int64     `json:"max_messages"`
+	Expired       int64     `json:"expired"`
+	Cutoff        time.Time `json:"cutoff"`
+}
+
+// RetentionReport — итог пробного прогона очистки: что удалилось бы прямо сейчас
+type RetentionReport struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	Expired     int64                 `json:"expired"`
+	Chats       []RetentionReportItem `json:"chats"`
+}
+
+// ImportReport — итог импорта архива. При повторном импорте уже перенесённые сообщения
+// попадают в MessagesSkipped.
+type ImportReport struct {
+	UsersCreated     int `json:"users_created"`
+	ChatsCreated     int `json:"chats_created"`
+	MessagesImported int `json:"messages_imported"`
+	MessagesSkipped  int `json:"messages_skipped"`
+}
+
+// ChatPin — закреплённое сообщение чата
+type ChatPin struct {
+	MessageID int64     `json:"message_id" gorm:"primaryKey;autoIncrement:false"`
+	ChatID    int64     `json:"chat_id" gorm:"index"`
+	PinnedBy  *int64    `json:"pinned_by,omitempty"`
+	PinnedAt  time.Time `json:"pinned_at"`
+
+	Message *Message `json:"message,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	Chat    *Chat    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ScheduledMessage — сообщение, отложенное до SendAt. Видно только автору, при отправке
+// превращается в обычное сообщение и удаляется из очереди.
+type ScheduledMessage struct {
 	ID        int64     `json:"id"`
 	ChatID    int64     `json:"chat_id"`
+	AuthorID  int64     `json:"author_id" gorm:"index"`
 	Text      string    `json:"text"`
+	Format    string    `json:"format"`
+	ExpiresIn int64     `json:"expires_in,omitempty"`
+	SendAt    time.Time `json:"send_at" gorm:"index"`
+	// Attempts — число неудачных попыток отправки, LastError — причина последней
+	Attempts  int    `json:"attempts,omitempty"`
+	LastError string `json:"last_error,omitempty"`
+	// FailedAt — когда отправку бросили; такое сообщение больше не отправляется,
+	// пока автор его не изменит
+	FailedAt *time.Time `json:"failed_at,omitempty"`
+	// ClaimedUntil — до какого момента сообщение отправляет захватившая его реплика;
+	// остальные его не берут, а если реплика упала, после этого момента оно уйдёт снова
+	ClaimedUntil *time.Time `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Chat   *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Author *User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ScheduledMessagePatch — изменение отложенного сообщения, nil-поля не меняются
+type ScheduledMessagePatch struct {
+	Text      *string    `json:"text"`
+	Format    *string    `json:"format"`
+	ExpiresIn *int64     `json:"expires_in"`
+	SendAt    *time.Time `json:"send_at"`
+}
+
+// Действия модерации: отклонить сообщение, скрыть совпадения звёздочками или отправить на проверку
+const (
+	ModerationActionReject = "reject"
+	ModerationActionMask   = "mask"
+	ModerationActionFlag   = "flag"
+)
+
+// ModerationRule — регулярное выражение модерации, заданное владельцем чата
+type ModerationRule struct {
+	ID        int64     `json:"id"`
+	ChatID    int64     `json:"chat_id" gorm:"index"`
+	Pattern   string    `json:"pattern"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Chat *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// ModerationFlag — сообщение в очереди на проверку. Сообщение уже опубликовано,
+// отклонение при проверке удаляет его.
+type ModerationFlag struct {
+	MessageID int64     `json:"message_id" gorm:"primaryKey;autoIncrement:false"`
+	ChatID    int64     `json:"chat_id" gorm:"index"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Message *Message `json:"message,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	Chat    *Chat    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// UserBlock — BlockerID заблокировал BlockedID и больше не видит его сообщений
+type UserBlock struct {
+	BlockerID int64     `json:"blocker_id" gorm:"primaryKey;autoIncrement:false"`
+	BlockedID int64     `json:"blocked_id" gorm:"primaryKey;autoIncrement:false"`
 	CreatedAt time.Time `json:"created_at"`
+
+	Blocker *User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Blocked *User `json:"blocked,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Коды причин жалобы на сообщение
+const (
+	ReportReasonSpam       = "spam"
+	ReportReasonAbuse      = "abuse"
+	ReportReasonHarassment = "harassment"
+	ReportReasonIllegal    = "illegal"
+	ReportReasonOther      = "other"
+)
+
+// Статусы жалобы: открытую разбирает администратор, закрывая её как resolved
+// (нарушение подтверждено) или dismissed (жалоба отклонена)
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusResolved  = "resolved"
+	ReportStatusDismissed = "dismissed"
+)
+
+// MessageReport — жалоба пользователя на сообщение. Текст сообщения сохраняется
+// на момент жалобы, поэтому она остаётся понятной и после удаления сообщения.
+type MessageReport struct {
+	ID         int64      `json:"id"`
+	MessageID  *int64     `json:"message_id" gorm:"uniqueIndex:idx_message_reports_message_reporter"`
+	ChatID     int64      `json:"chat_id" gorm:"index"`
+	ReporterID int64      `json:"reporter_id" gorm:"uniqueIndex:idx_message_reports_message_reporter"`
+	AuthorID   *int64     `json:"author_id,omitempty"`
+	Reason     string     `json:"reason"`
+	Comment    string     `json:"comment,omitempty"`
+	Text       string     `json:"text,omitempty"`
+	Status     string     `json:"status" gorm:"default:open;index"`
+	Resolution string     `json:"resolution,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	Message  *Message `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Chat     *Chat    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Reporter *User    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Mention — упоминание пользователя в сообщении (@username или @channel)
+type Mention struct {
+	MessageID int64      `json:"message_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID    int64      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	ChatID    int64      `json:"chat_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	Message *Message `json:"message,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	User    *User    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+func (Mention) TableName() string {
+	return "message_mentions"
+}
+
+// MemberPresence — статус участника чата: online, away или offline
+type MemberPresence struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	Status   string `json:"status"`
 }
 
+// ChatPresence — снимок присутствия в чате: статусы участников и кто сейчас печатает
+type ChatPresence struct {
+	ChatID  int64            `json:"chat_id"`
+	Members []MemberPresence `json:"members"`
+	Typing  []int64          `json:"typing"`
+}
+
+// MentionPage — страница упоминаний. NextBefore передаётся в before для следующей страницы
+// и отсутствует на последней.
+type MentionPage struct {
+	Mentions    []Mention `json:"mentions"`
+	UnreadCount int64     `json:"unread_count"`
+	NextBefore  *int64    `json:"next_before,omitempty"`
+}
+
+// Форматы текста сообщения
+const (
+	MessageFormatPlain    = "plain"
+	MessageFormatMarkdown = "markdown"
+)
+
 type ChatWithMessages struct {
 	Chat
 	Messages []Message `json:"messages"`
 }
+
+// Типы доменных событий, которые пишутся в outbox
+const (
+	EventChatCreated    = "chat.created"
+	EventChatDeleted    = "chat.deleted"
+	EventMessageCreated = "message.created"
+	EventMessageDeleted = "message.deleted"
+)
+
+// Эфемерные события: публикуются сразу в шину подписчикам, минуя outbox и вебхуки
+const (
+	EventTyping   = "chat.typing"
+	EventPresence = "user.presence"
+)
+
+// KnownEvent сообщает, является ли eventType одним из доменных событий
+func KnownEvent(eventType string) bool {
+	switch eventType {
+	case EventChatCreated, EventChatDeleted, EventMessageCreated, EventMessageDeleted:
+		return true
+	}
+	return false
+}
+
+// Действия журнала аудита
+const (
+	AuditChatDelete           = "chat.delete"
+	AuditMemberAdd            = "member.add"
+	AuditRetentionUpdate      = "retention.update"
+	AuditRetentionDelete      = "retention.delete"
+	AuditModerationRuleCreate = "moderation_rule.create"
+	AuditModerationRuleDelete = "moderation_rule.delete"
+	AuditModerationApprove    = "moderation.approve"
+	AuditModerationReject     = "moderation.reject"
+	AuditReportResolve        = "report.resolve"
+	AuditHookCreate           = "hook.create"
+	AuditHookRevoke           = "hook.revoke"
+	AuditWebhookCreate        = "webhook.create"
+	AuditWebhookUpdate        = "webhook.update"
+	AuditWebhookDelete        = "webhook.delete"
+	AuditImport               = "import"
+)
+
+// Кто выполнил действие из журнала аудита
+const (
+	AuditActorUser   = "user"
+	AuditActorAdmin  = "admin"
+	AuditActorSystem = "system"
+)
+
+// AuditEntry — запись журнала аудита. Журнал только дополняется: записи не меняются
+// и не удаляются, в том числе вместе с чатом, поэтому у ChatID и ActorID нет внешних ключей.
+// Before и After — JSON-снимки цели до и после действия.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorID    *int64          `json:"actor_id,omitempty" gorm:"index"`
+	Action     string          `json:"action" gorm:"index"`
+	TargetType string          `json:"target_type"`
+	TargetID   int64           `json:"target_id"`
+	ChatID     *int64          `json:"chat_id,omitempty" gorm:"index"`
+	Before     json.RawMessage `json:"before,omitempty" gorm:"type:jsonb"`
+	After      json.RawMessage `json:"after,omitempty" gorm:"type:jsonb"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditFilter — условия выборки журнала аудита. Пустые поля не ограничивают выборку.
+// Записи отдаются новыми первыми, BeforeID — курсор: только записи с ID меньше него.
+type AuditFilter struct {
+	ActorID    *int64
+	Action     string
+	TargetType string
+	TargetID   *int64
+	ChatID     *int64
+	Since      *time.Time
+	Until      *time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+// OutboxEvent — доменное событие, записанное в той же транзакции, что и изменение данных.
+// Payload хранит JSON-представление события.
+type OutboxEvent struct {
+	ID        int64     `json:"id"`
+	EventType string    `json:"event_type"`
+	ChatID    int64     `json:"chat_id"`
+	Payload   string    `json:"payload" gorm:"type:jsonb"`
+	CreatedAt time.Time `json:"created_at"`
+	// TxID — ID транзакции, записавшей событие; его выдаёт база при вставке.
+	// События доставляются в порядке (TxID, ID), то есть в порядке фиксации транзакций.
+	TxID int64 `json:"-" gorm:"->;default:0"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+// OutboxCursor — позиция приёмника событий в outbox: последнее доставленное ему событие
+// и число неудачных попыток доставить следующее. Каждый приёмник продвигается по outbox
+// независимо от остальных.
+type OutboxCursor struct {
+	Sink        string    `json:"sink" gorm:"primaryKey"`
+	LastTxID    int64     `json:"last_tx_id"`
+	LastEventID int64     `json:"last_event_id"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (OutboxCursor) TableName() string {
+	return "outbox_cursors"
+}
+
+// Webhook — подписка внешней системы на события.
+// Пустой Events означает подписку на все события, пустой ChatID — на все чаты.
+type Webhook struct {
+	ID                  int64      `json:"id"`
+	URL                 string     `json:"url"`
+	Events              []string   `json:"events" gorm:"serializer:json"`
+	ChatID              *int64     `json:"chat_id,omitempty"`
+	Secret              string     `json:"secret,omitempty"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// Matches сообщает, подписан ли вебхук на событие eventType в чате chatID
+func (w *Webhook) Matches(eventType string, chatID int64) bool {
+	if w.ChatID != nil && *w.ChatID != chatID {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Статусы доставки вебхука
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery — доставка одного события одному вебхуку вместе с историей попыток
+type WebhookDelivery struct {
+	ID            int64      `json:"id"`
+	WebhookID     int64      `json:"webhook_id" gorm:"uniqueIndex:uq_webhook_deliveries_event"`
+	EventID       int64      `json:"event_id" gorm:"uniqueIndex:uq_webhook_deliveries_event"`
+	EventType     string     `json:"event_type"`
+	Payload       string     `json:"-" gorm:"type:jsonb"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  int        `json:"response_code"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Webhook *Webhook `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// Статусы загрузки карточки ссылки
+const (
+	LinkPreviewOK     = "ok"
+	LinkPreviewFailed = "failed"
+)
+
+// LinkPreview — закешированная карточка ссылки (OpenGraph / Twitter Card).
+// Одна запись на URL, к сообщениям карточки подбираются по ссылкам из текста.
+// Неудачные загрузки тоже кешируются, чтобы не ходить на недоступный сайт снова и снова.
+type LinkPreview struct {
+	ID          int64     `json:"-"`
+	URL         string    `json:"url" gorm:"uniqueIndex"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	SiteName    string    `json:"site_name,omitempty"`
+	Status      string    `json:"-"`
+	Error       string    `json:"-"`
+	FetchedAt   time.Time `json:"-"`
+}
+
+// IncomingWebhook — токен, по которому внешняя система (CI, мониторинг) пишет в чат от имени бота.
+// В базе хранится только хеш токена, сам токен возвращается один раз при создании.
+type IncomingWebhook struct {
+	ID        int64     `json:"id"`
+	ChatID    int64     `json:"chat_id"`
+	Name      string    `json:"name"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	Token     string    `json:"token,omitempty" gorm:"-"`
+	BotUserID int64     `json:"bot_user_id"`
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Chat *Chat `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// IncomingMessage — тело запроса входящего вебхука.
+// Title, Fields и URL превращаются в markdown вокруг основного Text.
+type IncomingMessage struct {
+	Text   string          `json:"text"`
+	Title  string          `json:"title"`
+	URL    string          `json:"url"`
+	Fields []IncomingField `json:"fields"`
+}
+
+type IncomingField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
@@ -0,0 +1,105 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// Classifier — внешний классификатор текста (ML-модель, сторонний сервис).
+// Возвращает Verdict в том же формате, что и хуки.
+type Classifier interface {
+	Classify(ctx context.Context, chatID int64, text string) (Verdict, error)
+}
+
+// ClassifierHook встраивает Classifier в цепочку. При failOpen ошибка классификатора
+// только пишется в лог и сообщение пропускается, иначе отправка сообщения не удаётся.
+type ClassifierHook struct {
+	classifier Classifier
+	failOpen   bool
+}
+
+func NewClassifierHook(classifier Classifier, failOpen bool) *ClassifierHook {
+	return &ClassifierHook{classifier: classifier, failOpen: failOpen}
+}
+
+func (h *ClassifierHook) Check(ctx context.Context, chatID int64, text string) (Verdict, error) {
+	verdict, err := h.classifier.Classify(ctx, chatID, text)
+	if err != nil {
+		if h.failOpen {
+			log.Printf("moderation: classifier: %v", err)
+			return Verdict{}, nil
+		}
+		return Verdict{}, err
+	}
+	if verdict.Action != "" && !ValidAction(verdict.Action) {
+		return Verdict{}, ErrUnknownAction
+	}
+	if verdict.Action == models.ModerationActionMask && verdict.Text == "" {
+		verdict.Text = mask(text)
+	}
+	return verdict, nil
+}
+
+// HTTPClassifier отправляет текст POST-запросом {"chat_id", "text"} и ждёт в ответ
+// {"action", "reason", "text"}; пустой action означает, что сообщение в порядке.
+type HTTPClassifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPClassifier(url string, client *http.Client) *HTTPClassifier {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &HTTPClassifier{url: url, client: client}
+}
+
+type classifyRequest struct {
+	ChatID int64  `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+type classifyResponse struct {
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+func (c *HTTPClassifier) Classify(ctx context.Context, chatID int64, text string) (Verdict, error) {
+	body, err := json.Marshal(classifyRequest{ChatID: chatID, Text: text})
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return Verdict{}, fmt.Errorf("classifier responded with status %d", resp.StatusCode)
+	}
+
+	var out classifyResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil {
+		return Verdict{}, fmt.Errorf("classifier response: %w", err)
+	}
+
+	return Verdict{Action: out.Action, Reason: out.Reason, Text: out.Text}, nil
+}
@@ -0,0 +1,91 @@
+// Package moderation проверяет текст сообщения перед сохранением цепочкой хуков:
+// встроенные списки слов, регулярные выражения чата и внешние классификаторы.
+package moderation
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// Verdict — решение одного хука. Пустой Action пропускает сообщение без изменений.
+// Непустой Text при маскировании или отметке заменяет текст сообщения: так хук,
+// который и скрыл часть текста, и отметил сообщение, возвращает оба решения сразу.
+type Verdict struct {
+	Action string
+	Text   string
+	Reason string
+}
+
+// Hook — одно звено цепочки модерации
+type Hook interface {
+	Check(ctx context.Context, chatID int64, text string) (Verdict, error)
+}
+
+// Result — итог прохода цепочки
+type Result struct {
+	// Text — текст после маскирования, его и нужно сохранять
+	Text     string
+	Rejected bool
+	Flagged  bool
+	// Reasons — причины отклонения или отметки в порядке срабатывания хуков
+	Reasons []string
+}
+
+// Chain — хуки в порядке вызова
+type Chain []Hook
+
+// ErrUnknownAction возвращается, если хук вернул неизвестное действие
+var ErrUnknownAction = errors.New("unknown moderation action")
+
+// Run пропускает текст через все хуки. Каждый следующий хук видит текст после
+// маскирования предыдущими, отклонение останавливает цепочку, отметки накапливаются.
+func (c Chain) Run(ctx context.Context, chatID int64, text string) (Result, error) {
+	result := Result{Text: text}
+
+	for _, hook := range c {
+		verdict, err := hook.Check(ctx, chatID, result.Text)
+		if err != nil {
+			return result, err
+		}
+
+		switch verdict.Action {
+		case "":
+		case models.ModerationActionReject:
+			result.Rejected = true
+			result.Reasons = append(result.Reasons, verdict.Reason)
+			return result, nil
+		case models.ModerationActionMask:
+		case models.ModerationActionFlag:
+			result.Flagged = true
+			result.Reasons = append(result.Reasons, verdict.Reason)
+		default:
+			return result, ErrUnknownAction
+		}
+
+		if verdict.Text != "" {
+			result.Text = verdict.Text
+		}
+	}
+
+	return result, nil
+}
+
+// ValidAction сообщает, является ли action одним из действий модерации
+func ValidAction(action string) bool {
+	switch action {
+	case models.ModerationActionReject, models.ModerationActionMask, models.ModerationActionFlag:
+		return true
+	}
+	return false
+}
+
+// mask заменяет каждую руну s звёздочкой
+func mask(s string) string {
+	masked := make([]rune, 0, len(s))
+	for range s {
+		masked = append(masked, '*')
+	}
+	return string(masked)
+}
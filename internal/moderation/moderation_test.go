@@ -0,0 +1,138 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+type staticRules []models.ModerationRule
+
+func (r staticRules) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	return r, nil
+}
+
+type classifierFunc func(ctx context.Context, chatID int64, text string) (Verdict, error)
+
+func (f classifierFunc) Classify(ctx context.Context, chatID int64, text string) (Verdict, error) {
+	return f(ctx, chatID, text)
+}
+
+func TestWordList(t *testing.T) {
+	words := NewWordList([]string{"Спам", "bad"}, models.ModerationActionMask)
+
+	verdict, err := words.Check(context.Background(), 1, "Это СПАМ, bad-ссылка и спаммер")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationActionMask, verdict.Action)
+	assert.Equal(t, "Это ****, ***-ссылка и спаммер", verdict.Text)
+	assert.Equal(t, "word: спам", verdict.Reason)
+
+	verdict, err = words.Check(context.Background(), 1, "всё чисто")
+	require.NoError(t, err)
+	assert.Empty(t, verdict.Action)
+}
+
+func TestChain(t *testing.T) {
+	chain := Chain{
+		NewWordList([]string{"дурак"}, models.ModerationActionMask),
+		NewRules(staticRules{
+			{ID: 3, Pattern: `(?i)казино`, Action: models.ModerationActionFlag},
+			{ID: 4, Pattern: `\d{4}-\d{4}`, Action: models.ModerationActionMask},
+		}),
+		NewWordList([]string{"запрет"}, models.ModerationActionReject),
+	}
+
+	result, err := chain.Run(context.Background(), 1, "дурак, Казино по номеру 1234-5678")
+	require.NoError(t, err)
+	assert.False(t, result.Rejected)
+	assert.True(t, result.Flagged)
+	assert.Equal(t, []string{"rule #3"}, result.Reasons)
+	assert.Equal(t, "*****, Казино по номеру *********", result.Text)
+
+	result, err = chain.Run(context.Background(), 1, "Запрет, дурак")
+	require.NoError(t, err)
+	assert.True(t, result.Rejected)
+	assert.Equal(t, []string{"word: запрет"}, result.Reasons)
+}
+
+func TestRulesCacheBounded(t *testing.T) {
+	rules := NewRules(staticRules{})
+
+	for i := 0; i < maxCompiled+10; i++ {
+		_, err := rules.regexp("rule" + strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	assert.Len(t, rules.compiled, maxCompiled)
+	assert.Equal(t, maxCompiled, rules.recent.Len())
+	assert.NotContains(t, rules.compiled, "rule0", "давно не встречавшееся выражение вытесняется")
+	assert.Contains(t, rules.compiled, "rule"+strconv.Itoa(maxCompiled+9))
+
+	// Использованное выражение становится самым свежим и не вытесняется следующим
+	_, err := rules.regexp("rule10")
+	require.NoError(t, err)
+	_, err = rules.regexp("new")
+	require.NoError(t, err)
+	assert.Contains(t, rules.compiled, "rule10")
+	assert.NotContains(t, rules.compiled, "rule11")
+}
+
+func TestClassifierHook(t *testing.T) {
+	failing := classifierFunc(func(ctx context.Context, chatID int64, text string) (Verdict, error) {
+		return Verdict{}, errors.New("unavailable")
+	})
+
+	verdict, err := NewClassifierHook(failing, true).Check(context.Background(), 1, "текст")
+	require.NoError(t, err)
+	assert.Empty(t, verdict.Action)
+
+	_, err = NewClassifierHook(failing, false).Check(context.Background(), 1, "текст")
+	assert.Error(t, err)
+
+	unknown := classifierFunc(func(ctx context.Context, chatID int64, text string) (Verdict, error) {
+		return Verdict{Action: "ban"}, nil
+	})
+	_, err = NewClassifierHook(unknown, true).Check(context.Background(), 1, "текст")
+	assert.ErrorIs(t, err, ErrUnknownAction)
+}
+
+func TestHTTPClassifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req classifyRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, int64(9), req.ChatID)
+		assert.Equal(t, "купи слона", req.Text)
+		w.Write([]byte(`{"action":"flag","reason":"spam"}`))
+	}))
+	defer server.Close()
+
+	verdict, err := NewHTTPClassifier(server.URL, server.Client()).Classify(context.Background(), 9, "купи слона")
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Action: models.ModerationActionFlag, Reason: "spam"}, verdict)
+}
+
+func TestCompilePattern(t *testing.T) {
+	_, err := CompilePattern(`(`)
+	assert.Error(t, err)
+
+	_, err = CompilePattern("")
+	assert.Error(t, err)
+
+	_, err = CompilePattern(`\bspam\b`)
+	assert.NoError(t, err)
+}
+
+func TestWordListFlagKeepsText(t *testing.T) {
+	verdict, err := NewWordList([]string{"казино"}, models.ModerationActionFlag).Check(context.Background(), 1, "лучшее казино")
+	require.NoError(t, err)
+	assert.Equal(t, models.ModerationActionFlag, verdict.Action)
+	assert.Empty(t, verdict.Text)
+}
@@ -0,0 +1,130 @@
+package moderation
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// MaxPatternLength — наибольшая длина регулярного выражения правила
+const MaxPatternLength = 500
+
+// maxCompiled — сколько скомпилированных выражений держит кеш Rules
+const maxCompiled = 1024
+
+// RuleSource отдаёт правила чата, например repository.ModerationRepository
+type RuleSource interface {
+	ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error)
+}
+
+// Rules — хук с регулярными выражениями, которые владелец задал для своего чата.
+// Правила применяются по порядку создания. Скомпилированные выражения кешируются:
+// в кеше не больше maxCompiled выражений, давно не встречавшиеся вытесняются, поэтому
+// удалённые правила и чаты не копятся в памяти.
+type Rules struct {
+	source RuleSource
+
+	mu       sync.Mutex
+	compiled map[string]*list.Element // pattern → элемент recent
+	recent   *list.List               // *compiledPattern, недавно использованные первыми
+}
+
+type compiledPattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func NewRules(source RuleSource) *Rules {
+	return &Rules{
+		source:   source,
+		compiled: make(map[string]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// CompilePattern проверяет регулярное выражение правила
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" || len(pattern) > MaxPatternLength {
+		return nil, errors.New("pattern must be 1-500 characters")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.New("invalid pattern: " + err.Error())
+	}
+	return re, nil
+}
+
+func (r *Rules) Check(ctx context.Context, chatID int64, text string) (Verdict, error) {
+	rules, err := r.source.ListRules(ctx, chatID)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	original := text
+	var flagged *Verdict
+	for _, rule := range rules {
+		re, err := r.regexp(rule.Pattern)
+		if err != nil {
+			// Выражения проверяются при создании правила, сюда попадают только испорченные вручную
+			continue
+		}
+		if !re.MatchString(text) {
+			continue
+		}
+
+		reason := "rule #" + strconv.FormatInt(rule.ID, 10)
+		switch rule.Action {
+		case models.ModerationActionReject:
+			return Verdict{Action: rule.Action, Reason: reason}, nil
+		case models.ModerationActionMask:
+			text = re.ReplaceAllStringFunc(text, mask)
+		case models.ModerationActionFlag:
+			if flagged == nil {
+				flagged = &Verdict{Action: rule.Action, Reason: reason}
+			}
+		}
+	}
+
+	if flagged != nil {
+		flagged.Text = text
+		return *flagged, nil
+	}
+	if text != original {
+		return Verdict{Action: models.ModerationActionMask, Text: text}, nil
+	}
+	return Verdict{}, nil
+}
+
+// regexp достаёт скомпилированное выражение из кеша или компилирует его
+func (r *Rules) regexp(pattern string) (*regexp.Regexp, error) {
+	r.mu.Lock()
+	if elem, ok := r.compiled[pattern]; ok {
+		r.recent.MoveToFront(elem)
+		r.mu.Unlock()
+		return elem.Value.(*compiledPattern).re, nil
+	}
+	r.mu.Unlock()
+
+	re, err := CompilePattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Параллельный Check мог уже скомпилировать то же выражение
+	if _, ok := r.compiled[pattern]; !ok {
+		r.compiled[pattern] = r.recent.PushFront(&compiledPattern{pattern: pattern, re: re})
+		if r.recent.Len() > maxCompiled {
+			oldest := r.recent.Back()
+			r.recent.Remove(oldest)
+			delete(r.compiled, oldest.Value.(*compiledPattern).pattern)
+		}
+	}
+	return re, nil
+}
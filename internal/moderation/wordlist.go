@@ -0,0 +1,85 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// WordList — встроенный фильтр по списку слов. Слова сравниваются целиком и без учёта
+// регистра: "спам" совпадает со "СПАМ", но не со "спаммер".
+type WordList struct {
+	words  map[string]struct{}
+	action string
+}
+
+// NewWordList создаёт фильтр, применяющий action к сообщениям с любым из words
+func NewWordList(words []string, action string) *WordList {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			set[word] = struct{}{}
+		}
+	}
+	return &WordList{words: set, action: action}
+}
+
+// ParseWords разбирает список слов через запятую, как в переменных окружения
+func ParseWords(list string) []string {
+	var words []string
+	for _, word := range strings.Split(list, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func (w *WordList) Check(ctx context.Context, chatID int64, text string) (Verdict, error) {
+	var (
+		out     strings.Builder
+		found   string
+		matched bool
+	)
+
+	// Текст режется на слова из букв и цифр, всё остальное переносится как есть
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			out.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if _, ok := w.words[strings.ToLower(word)]; ok {
+			if !matched {
+				found = strings.ToLower(word)
+			}
+			matched = true
+			out.WriteString(mask(word))
+		} else {
+			out.WriteString(word)
+		}
+		i = j
+	}
+
+	if !matched {
+		return Verdict{}, nil
+	}
+	verdict := Verdict{Action: w.action, Reason: "word: " + found}
+	if w.action == models.ModerationActionMask {
+		verdict.Text = out.String()
+	}
+	return verdict, nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,46 @@
+// Package openapi отдаёт спецификацию HTTP API (OpenAPI 3.1) и встроенный Swagger UI.
+//
+// Спецификация пишется вручную в openapi.json. Тест в cmd/app сверяет её с маршрутами
+// роутера, так что новый эндпоинт без описания не пройдёт go test.
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed index.html
+var index []byte
+
+// Document возвращает спецификацию в JSON
+func Document() []byte {
+	return spec
+}
+
+// Spec отдаёт спецификацию, GET /openapi.json
+func Spec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+// UI отдаёт Swagger UI, смонтированный под prefix (например, "/docs/"). Статика UI
+// встроена в бинарник, спецификация берётся с /openapi.json.
+func UI(prefix string) http.Handler {
+	files := http.StripPrefix(prefix, http.FileServer(http.FS(swaggerFiles.FS)))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, prefix) {
+		case "", "index.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(index)
+		default:
+			files.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// Bus — in-process шина: рассылает опубликованные события всем подписчикам внутри приложения.
+// Подписчики получают события в порядке публикации. Если буфер подписчика переполнен,
+// событие для него отбрасывается, чтобы медленный подписчик не блокировал доставку остальным.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[int]chan models.OutboxEvent
+	nextID int
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[int]chan models.OutboxEvent)}
+}
+
+// Subscribe регистрирует подписчика. Возвращённую функцию нужно вызвать для отписки,
+// после неё канал закрывается.
+func (b *Bus) Subscribe(buffer int) (<-chan models.OutboxEvent, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan models.OutboxEvent, buffer)
+	b.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs, id)
+			close(ch)
+		})
+	}
+}
+
+// Publish рассылает событие подписчикам, не блокируясь на медленных
+func (b *Bus) Publish(ctx context.Context, event models.OutboxEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+
+	return nil
+}
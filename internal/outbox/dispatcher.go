@@ -0,0 +1,251 @@
+// Package outbox доставляет события из таблицы outbox во внешние приёмники (sinks).
+//
+// События пишутся сервисом в той же транзакции, что и изменение данных, а Dispatcher
+// в фоне публикует их каждому приёмнику строго по порядку ID. У каждого приёмника свой
+// курсор в outbox и свой цикл доставки: упавший или медленный приёмник задерживает
+// только себя, остальные (in-process шина, вебхуки, карточки ссылок) продолжают получать
+// события. Курсор сдвигается только после успешной доставки, поэтому гарантия —
+// at-least-once для каждого приёмника отдельно: после сбоя приёмник может получить
+// событие ещё раз, но повтор не затрагивает тех, кто его уже получил.
+//
+// ID событий выдаются при вставке, а транзакции фиксируются в другом порядке: событие
+// с меньшим ID может стать видимым позже следующего. Поэтому курсор — пара (ID транзакции,
+// ID события), а репозиторий отдаёт только события уже завершённых транзакций: всё,
+// что станет видимым позже, окажется после курсора. Откаты не оставляют пропусков, которых
+// нужно ждать.
+//
+// Локальные приёмники (in-process шина) держат курсор в памяти процесса и начинают
+// с конца outbox: каждая реплика рассылает события своим подписчикам сама.
+//
+// События, доставленные всем приёмникам, хранятся ещё keep и затем удаляются.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/retry"
+)
+
+// Sink — приёмник событий. Publish должен быть идемпотентным на стороне получателя
+// либо получатель должен уметь отбрасывать дубликаты по ID события.
+type Sink interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// sinkState — приёмник и момент, раньше которого не стоит повторять упавшее событие.
+// У локального приёмника здесь же его курсор. retryAt и cursor меняются только из цикла
+// доставки этого приёмника.
+type sinkState struct {
+	name    string
+	sink    Sink
+	retryAt time.Time
+
+	local  bool
+	cursor *models.OutboxCursor
+}
+
+type Dispatcher struct {
+	repo  repository.OutboxRepository
+	sinks []*sinkState
+
+	interval   time.Duration
+	batchSize  int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	// keep — сколько хранятся события, уже доставленные всем приёмникам
+	keep          time.Duration
+	pruneInterval time.Duration
+	now           func() time.Time
+}
+
+// NewDispatcher создаёт диспетчер. Имя приёмника — ключ его курсора в базе, поэтому
+// оно должно оставаться тем же между перезапусками.
+func NewDispatcher(repo repository.OutboxRepository, sinks map[string]Sink, interval time.Duration) *Dispatcher {
+	states := make([]*sinkState, 0, len(sinks))
+	for name, sink := range sinks {
+		states = append(states, &sinkState{name: name, sink: sink})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].name < states[j].name })
+
+	return &Dispatcher{
+		repo:          repo,
+		sinks:         states,
+		interval:      interval,
+		batchSize:     100,
+		minBackoff:    time.Second,
+		maxBackoff:    time.Minute,
+		keep:          24 * time.Hour,
+		pruneInterval: 10 * time.Minute,
+		now:           time.Now,
+	}
+}
+
+// AddLocalSink добавляет приёмник, курсор которого хранится только в памяти процесса.
+// Он получает события, записанные после запуска, и не задерживает удаление старых.
+// Вызывается до Run.
+func (d *Dispatcher) AddLocalSink(name string, sink Sink) {
+	d.sinks = append(d.sinks, &sinkState{name: name, sink: sink, local: true})
+	sort.Slice(d.sinks, func(i, j int) bool { return d.sinks[i].name < d.sinks[j].name })
+}
+
+// Run доставляет события каждому приёмнику в своей горутине и периодически удаляет
+// доставленные события, пока не будет отменён ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, state := range d.sinks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.runSink(ctx, state)
+		}()
+	}
+
+	ticker := time.NewTicker(d.pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			if _, err := d.Prune(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("outbox: prune: %v", err)
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) runSink(ctx context.Context, state *sinkState) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.dispatch(ctx, state); err != nil && ctx.Err() == nil {
+			log.Printf("outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchPending один раз доставляет накопившиеся события всем приёмникам по очереди
+// и возвращает общее число доставок. Ошибка одного приёмника не мешает остальным.
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	var published int
+	var errs []error
+	for _, state := range d.sinks {
+		n, err := d.dispatch(ctx, state)
+		published += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return published, errors.Join(errs...)
+}
+
+// dispatch публикует приёмнику события после его курсора и возвращает число опубликованных.
+// На первом неудачном событии останавливается, чтобы не нарушить порядок, и откладывает
+// следующую попытку с экспоненциальной задержкой.
+func (d *Dispatcher) dispatch(ctx context.Context, state *sinkState) (int, error) {
+	if d.now().Before(state.retryAt) {
+		return 0, nil
+	}
+
+	cursor, err := d.cursor(ctx, state)
+	if err != nil {
+		return 0, fmt.Errorf("sink %s: get cursor: %w", state.name, err)
+	}
+
+	events, err := d.repo.ListAfter(ctx, cursor.LastTxID, cursor.LastEventID, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("sink %s: list events: %w", state.name, err)
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := state.sink.Publish(ctx, event); err != nil {
+			cursor.Attempts++
+			cursor.LastError = err.Error()
+			if saveErr := d.saveCursor(ctx, state, cursor); saveErr != nil {
+				log.Printf("outbox: save cursor of sink %s: %v", state.name, saveErr)
+			}
+			state.retryAt = d.now().Add(retry.Backoff(cursor.Attempts, d.minBackoff, d.maxBackoff))
+			return published, fmt.Errorf("sink %s: publish event %d (%s): %w", state.name, event.ID, event.EventType, err)
+		}
+
+		cursor.LastTxID = event.TxID
+		cursor.LastEventID = event.ID
+		cursor.Attempts = 0
+		cursor.LastError = ""
+		if err := d.saveCursor(ctx, state, cursor); err != nil {
+			return published, fmt.Errorf("sink %s: save cursor: %w", state.name, err)
+		}
+		published++
+	}
+
+	state.retryAt = time.Time{}
+	return published, nil
+}
+
+// cursor получает курсор приёмника. Локальный приёмник при первом вызове встаёт
+// на последнее записанное событие.
+func (d *Dispatcher) cursor(ctx context.Context, state *sinkState) (*models.OutboxCursor, error) {
+	if !state.local {
+		return d.repo.GetCursor(ctx, state.name)
+	}
+
+	if state.cursor == nil {
+		txID, id, err := d.repo.Head(ctx)
+		if err != nil {
+			return nil, err
+		}
+		state.cursor = &models.OutboxCursor{Sink: state.name, LastTxID: txID, LastEventID: id}
+	}
+
+	cursor := *state.cursor
+	return &cursor, nil
+}
+
+func (d *Dispatcher) saveCursor(ctx context.Context, state *sinkState, cursor *models.OutboxCursor) error {
+	if state.local {
+		saved := *cursor
+		state.cursor = &saved
+		return nil
+	}
+	return d.repo.SaveCursor(ctx, cursor)
+}
+
+// Prune удаляет события старше keep, доставленные всем приёмникам с курсором в базе,
+// и возвращает, сколько удалено
+func (d *Dispatcher) Prune(ctx context.Context) (int64, error) {
+	names := make([]string, 0, len(d.sinks))
+	for _, state := range d.sinks {
+		if !state.local {
+			names = append(names, state.name)
+		}
+	}
+
+	var pruned int64
+	before := d.now().Add(-d.keep)
+	for {
+		deleted, err := d.repo.Prune(ctx, names, before, d.batchSize)
+		pruned += deleted
+		if err != nil || deleted < int64(d.batchSize) {
+			return pruned, err
+		}
+	}
+}
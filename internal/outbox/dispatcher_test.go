@@ -0,0 +1,259 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/memory"
+)
+
+// recordingSink запоминает полученные события и может падать заданное число раз
+type recordingSink struct {
+	mu       sync.Mutex
+	events   []models.OutboxEvent
+	failures int
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) types() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	types := make([]string, 0, len(s.events))
+	for _, event := range s.events {
+		types = append(types, event.EventType)
+	}
+	return types
+}
+
+func addEvents(t *testing.T, store *memory.Store, types ...string) {
+	t.Helper()
+
+	repo := memory.NewOutboxRepository(store)
+	for _, eventType := range types {
+		require.NoError(t, repo.Add(context.Background(), &models.OutboxEvent{
+			EventType: eventType,
+			ChatID:    1,
+			Payload:   `{"id":1}`,
+		}))
+	}
+}
+
+func TestDispatcher_PublishesInOrder(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated, models.EventMessageCreated, models.EventChatDeleted)
+
+	first, second := &recordingSink{}, &recordingSink{}
+	d := NewDispatcher(repo, map[string]Sink{"first": first, "second": second}, time.Second)
+
+	n, err := d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 6, n, "каждое событие доставлено каждому приёмнику")
+
+	expected := []string{models.EventChatCreated, models.EventMessageCreated, models.EventChatDeleted}
+	assert.Equal(t, expected, first.types())
+	assert.Equal(t, expected, second.types())
+
+	for _, name := range []string{"first", "second"} {
+		cursor, err := repo.GetCursor(context.Background(), name)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), cursor.LastEventID)
+	}
+
+	n, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n, "доставленные события не публикуются повторно")
+}
+
+func TestDispatcher_RetriesWithoutBreakingOrder(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated, models.EventMessageCreated)
+
+	sink := &recordingSink{failures: 1}
+	d := NewDispatcher(repo, map[string]Sink{"http": sink}, time.Second)
+	d.minBackoff = time.Hour
+
+	n, err := d.DispatchPending(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, sink.types(), "после ошибки первого события следующие публиковаться не должны")
+
+	cursor, err := repo.GetCursor(context.Background(), "http")
+	require.NoError(t, err)
+	assert.Zero(t, cursor.LastEventID)
+	assert.Equal(t, 1, cursor.Attempts)
+	assert.Equal(t, "sink unavailable", cursor.LastError)
+
+	// Пока не истекла задержка, повторной попытки нет
+	n, err = d.DispatchPending(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	d.sinks[0].retryAt = time.Time{}
+	n, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{models.EventChatCreated, models.EventMessageCreated}, sink.types())
+
+	cursor, err = repo.GetCursor(context.Background(), "http")
+	require.NoError(t, err)
+	assert.Zero(t, cursor.Attempts, "успешная доставка сбрасывает счётчик попыток")
+}
+
+func TestDispatcher_FailingSinkDoesNotBlockOthers(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated, models.EventMessageCreated)
+
+	// Шина получает события, хотя HTTP-приёмник падает, а повтор для HTTP-приёмника
+	// не публикует их в шину ещё раз
+	bus, httpSink := &recordingSink{}, &recordingSink{failures: 1}
+	d := NewDispatcher(repo, map[string]Sink{"bus": bus, "http": httpSink}, time.Second)
+
+	_, err := d.DispatchPending(context.Background())
+	assert.Error(t, err)
+	assert.Len(t, bus.types(), 2)
+	assert.Empty(t, httpSink.types())
+
+	for _, state := range d.sinks {
+		state.retryAt = time.Time{}
+	}
+	_, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, bus.types(), 2)
+	assert.Len(t, httpSink.types(), 2)
+}
+
+func TestDispatcher_SkipsRolledBackEvents(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated)
+
+	// Откаченная транзакция оставляет пропуск в ID, но курсор не ждёт на нём:
+	// репозиторий отдаёт только события завершённых транзакций
+	err := memory.NewTxManager(store).WithinTransaction(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, repo.Add(ctx, &models.OutboxEvent{EventType: models.EventMessageCreated, ChatID: 1, Payload: `{}`}))
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+	addEvents(t, store, models.EventChatDeleted)
+
+	sink := &recordingSink{}
+	d := NewDispatcher(repo, map[string]Sink{"http": sink}, time.Second)
+
+	n, err := d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{models.EventChatCreated, models.EventChatDeleted}, sink.types())
+
+	cursor, err := repo.GetCursor(context.Background(), "http")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), cursor.LastEventID)
+}
+
+func TestDispatcher_LocalSink(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated)
+
+	bus, httpSink := &recordingSink{}, &recordingSink{}
+	d := NewDispatcher(repo, map[string]Sink{"http": httpSink}, time.Second)
+	d.AddLocalSink("bus", bus)
+	d.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+
+	_, err := d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, bus.types(), "локальный приёмник начинает с конца outbox")
+
+	addEvents(t, store, models.EventMessageCreated)
+	_, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.EventMessageCreated}, bus.types())
+	assert.Len(t, httpSink.types(), 2)
+
+	cursor, err := repo.GetCursor(context.Background(), "bus")
+	require.NoError(t, err)
+	assert.Zero(t, cursor.LastEventID, "курсор локального приёмника не пишется в базу")
+
+	pruned, err := d.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned, "локальный приёмник не задерживает удаление")
+}
+
+func TestDispatcher_Prune(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated, models.EventMessageCreated)
+
+	bus, httpSink := &recordingSink{}, &recordingSink{failures: 1}
+	d := NewDispatcher(repo, map[string]Sink{"bus": bus, "http": httpSink}, time.Second)
+	d.minBackoff = time.Hour
+	d.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+
+	_, err := d.DispatchPending(context.Background())
+	assert.Error(t, err)
+
+	pruned, err := d.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "события, не доставленные HTTP-приёмнику, хранятся")
+
+	d.sinks[1].retryAt = time.Time{}
+	_, err = d.DispatchPending(context.Background())
+	require.NoError(t, err)
+
+	pruned, err = d.Prune(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+
+	left, err := repo.ListAfter(context.Background(), 0, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, left)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewOutboxRepository(store)
+	addEvents(t, store, models.EventChatCreated)
+
+	bus := NewBus()
+	events, unsubscribe := bus.Subscribe(1)
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewDispatcher(repo, map[string]Sink{"bus": bus}, 10*time.Millisecond).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case event := <-events:
+		assert.Equal(t, models.EventChatCreated, event.EventType)
+	case <-time.After(time.Second):
+		t.Fatal("событие не было доставлено")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// Envelope — формат события, в котором его получают внешние приёмники
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	ChatID     int64           `json:"chat_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func NewEnvelope(event models.OutboxEvent) Envelope {
+	return Envelope{
+		ID:         event.ID,
+		Type:       event.EventType,
+		ChatID:     event.ChatID,
+		OccurredAt: event.CreatedAt,
+		Data:       json.RawMessage(event.Payload),
+	}
+}
+
+// LogSink пишет события в стандартный лог
+type LogSink struct{}
+
+func (LogSink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	log.Printf("event #%d %s chat=%d: %s", event.ID, event.EventType, event.ChatID, event.Payload)
+	return nil
+}
+
+// HTTPSink отправляет каждое событие POST-запросом с JSON-телом Envelope.
+// Любой ответ кроме 2xx считается ошибкой и приводит к повторной доставке.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSink{url: url, client: client}
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+func TestHTTPSink_Publish(t *testing.T) {
+	var received Envelope
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "7", r.Header.Get("X-Event-ID"))
+		assert.Equal(t, models.EventMessageCreated, r.Header.Get("X-Event-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	err := sink.Publish(context.Background(), models.OutboxEvent{
+		ID:        7,
+		EventType: models.EventMessageCreated,
+		ChatID:    3,
+		Payload:   `{"id":11,"text":"Привет"}`,
+		CreatedAt: time.Date(2026, 1, 28, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), received.ID)
+	assert.Equal(t, models.EventMessageCreated, received.Type)
+	assert.Equal(t, int64(3), received.ChatID)
+	assert.JSONEq(t, `{"id":11,"text":"Привет"}`, string(received.Data))
+}
+
+func TestHTTPSink_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	err := sink.Publish(context.Background(), models.OutboxEvent{ID: 1, EventType: models.EventChatCreated, Payload: `{}`})
+	assert.Error(t, err)
+}
+
+func TestBus_SlowSubscriberDoesNotBlock(t *testing.T) {
+	bus := NewBus()
+	fast, unsubscribeFast := bus.Subscribe(10)
+	defer unsubscribeFast()
+	slow, unsubscribeSlow := bus.Subscribe(1)
+
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(t, bus.Publish(context.Background(), models.OutboxEvent{ID: i}))
+	}
+
+	assert.Len(t, fast, 3)
+	assert.Len(t, slow, 1)
+
+	unsubscribeSlow()
+	_, open := <-slow
+	assert.True(t, open, "буфер должен читаться и после отписки")
+	_, open = <-slow
+	assert.False(t, open, "канал закрывается после отписки")
+}
@@ -0,0 +1,156 @@
+// Package presence хранит эфемерное состояние пользователей: кто печатает в чате и кто
+// онлайн. Данные живут только в памяти процесса и сами истекают по TTL, в базу не пишутся.
+package presence
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status — статус присутствия пользователя
+type Status string
+
+const (
+	Online  Status = "online"
+	Away    Status = "away"
+	Offline Status = "offline"
+)
+
+const (
+	// DefaultTypingTTL — сколько держится индикатор набора без повторного сигнала
+	DefaultTypingTTL = 5 * time.Second
+	// DefaultTimeout — через сколько после последнего heartbeat пользователь считается offline
+	DefaultTimeout = 60 * time.Second
+)
+
+// Change — смена статуса пользователя, обнаруженная при очистке
+type Change struct {
+	UserID int64
+	Status Status
+}
+
+type userState struct {
+	status   Status
+	lastSeen time.Time
+}
+
+// Tracker — потокобезопасное in-memory хранилище с TTL
+type Tracker struct {
+	mu     sync.Mutex
+	typing map[int64]map[int64]time.Time // чат → пользователь → когда истекает
+	users  map[int64]userState
+
+	typingTTL time.Duration
+	timeout   time.Duration
+	now       func() time.Time
+}
+
+func NewTracker(typingTTL, timeout time.Duration) *Tracker {
+	return &Tracker{
+		typing:    make(map[int64]map[int64]time.Time),
+		users:     make(map[int64]userState),
+		typingTTL: typingTTL,
+		timeout:   timeout,
+		now:       time.Now,
+	}
+}
+
+// StartTyping отмечает, что пользователь печатает в чате, и возвращает время,
+// когда индикатор погаснет без повторного сигнала
+func (t *Tracker) StartTyping(chatID, userID int64) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	users, ok := t.typing[chatID]
+	if !ok {
+		users = make(map[int64]time.Time)
+		t.typing[chatID] = users
+	}
+	expiresAt := t.now().Add(t.typingTTL)
+	users[userID] = expiresAt
+	return expiresAt
+}
+
+// StopTyping гасит индикатор, например когда сообщение отправлено
+func (t *Tracker) StopTyping(chatID, userID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.typing[chatID], userID)
+	if len(t.typing[chatID]) == 0 {
+		delete(t.typing, chatID)
+	}
+}
+
+// Typing возвращает ID пользователей, печатающих в чате, по возрастанию
+func (t *Tracker) Typing(chatID int64) []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	userIDs := make([]int64, 0, len(t.typing[chatID]))
+	for userID, expiresAt := range t.typing[chatID] {
+		if now.Before(expiresAt) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs
+}
+
+// Heartbeat продлевает присутствие пользователя со статусом status (Online или Away)
+// и возвращает предыдущий статус
+func (t *Tracker) Heartbeat(userID int64, status Status) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	prev := t.statusLocked(userID)
+	t.users[userID] = userState{status: status, lastSeen: t.now()}
+	return prev
+}
+
+// Status возвращает текущий статус пользователя
+func (t *Tracker) Status(userID int64) Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.statusLocked(userID)
+}
+
+func (t *Tracker) statusLocked(userID int64) Status {
+	state, ok := t.users[userID]
+	if !ok || t.now().Sub(state.lastSeen) >= t.timeout {
+		return Offline
+	}
+	return state.status
+}
+
+// Sweep удаляет истёкшие индикаторы набора и пользователей без heartbeat.
+// Возвращает пользователей, ушедших в offline, чтобы об этом можно было сообщить.
+func (t *Tracker) Sweep() []Change {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	for chatID, users := range t.typing {
+		for userID, expiresAt := range users {
+			if !now.Before(expiresAt) {
+				delete(users, userID)
+			}
+		}
+		if len(users) == 0 {
+			delete(t.typing, chatID)
+		}
+	}
+
+	var changes []Change
+	for userID, state := range t.users {
+		if now.Sub(state.lastSeen) >= t.timeout {
+			delete(t.users, userID)
+			changes = append(changes, Change{UserID: userID, Status: Offline})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].UserID < changes[j].UserID })
+	return changes
+}
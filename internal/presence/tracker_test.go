@@ -0,0 +1,63 @@
+package presence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTracker возвращает трекер с управляемыми часами
+func newTestTracker() (*Tracker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(5*time.Second, time.Minute)
+	tracker.now = func() time.Time { return now }
+	return tracker, &now
+}
+
+func TestTyping(t *testing.T) {
+	tracker, now := newTestTracker()
+
+	expiresAt := tracker.StartTyping(1, 10)
+	assert.Equal(t, now.Add(5*time.Second), expiresAt)
+	tracker.StartTyping(1, 5)
+	tracker.StartTyping(2, 7)
+
+	assert.Equal(t, []int64{5, 10}, tracker.Typing(1))
+	assert.Equal(t, []int64{7}, tracker.Typing(2))
+
+	tracker.StopTyping(1, 5)
+	assert.Equal(t, []int64{10}, tracker.Typing(1))
+
+	*now = now.Add(3 * time.Second)
+	tracker.StartTyping(1, 10)
+	*now = now.Add(3 * time.Second)
+	assert.Equal(t, []int64{10}, tracker.Typing(1), "повторный сигнал продлевает индикатор")
+	assert.Empty(t, tracker.Typing(2), "индикатор истекает без повторного сигнала")
+
+	tracker.Sweep()
+	assert.Empty(t, tracker.typing[2], "очистка удаляет истёкшие индикаторы")
+}
+
+func TestPresence(t *testing.T) {
+	tracker, now := newTestTracker()
+
+	assert.Equal(t, Offline, tracker.Status(1))
+
+	assert.Equal(t, Offline, tracker.Heartbeat(1, Online))
+	assert.Equal(t, Online, tracker.Status(1))
+
+	*now = now.Add(30 * time.Second)
+	assert.Equal(t, Online, tracker.Heartbeat(1, Away))
+	assert.Equal(t, Away, tracker.Status(1))
+	tracker.Heartbeat(2, Online)
+
+	*now = now.Add(45 * time.Second)
+	assert.Equal(t, Away, tracker.Status(1))
+	assert.Empty(t, tracker.Sweep())
+
+	*now = now.Add(20 * time.Second)
+	assert.Equal(t, Offline, tracker.Status(1), "без heartbeat пользователь уходит в offline")
+	assert.Equal(t, []Change{{UserID: 1, Status: Offline}, {UserID: 2, Status: Offline}}, tracker.Sweep())
+	assert.Empty(t, tracker.Sweep(), "об уходе в offline сообщается один раз")
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_attachment_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository AttachmentRepository
+
+var (
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
+
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *models.Attachment) error
+	GetByID(ctx context.Context, id int64) (*models.Attachment, error)
+	ListByMessage(ctx context.Context, messageID int64) ([]models.Attachment, error)
+	ListByChat(ctx context.Context, chatID int64) ([]models.Attachment, error)
+	UpdateThumbnail(ctx context.Context, attachment *models.Attachment) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+// Create сохраняет метаданные вложения. Если сообщения нет, возвращает ErrMessageNotFound.
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	err := conn(ctx, r.db).Create(attachment).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// GetByID получает вложение по ID, nil, nil если его нет
+func (r *attachmentRepository) GetByID(ctx context.Context, id int64) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := conn(ctx, r.db).First(&attachment, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// ListByMessage получает вложения сообщения по порядку ID
+func (r *attachmentRepository) ListByMessage(ctx context.Context, messageID int64) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := conn(ctx, r.db).Where("message_id = ?", messageID).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// ListByChat получает все вложения чата по порядку ID
+func (r *attachmentRepository) ListByChat(ctx context.Context, chatID int64) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := conn(ctx, r.db).Where("chat_id = ?", chatID).Order("id ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// UpdateThumbnail сохраняет размеры картинки и ключ её миниатюры.
+// Если вложение уже удалено, возвращает ErrAttachmentNotFound.
+func (r *attachmentRepository) UpdateThumbnail(ctx context.Context, attachment *models.Attachment) error {
+	result := conn(ctx, r.db).
+		Model(&models.Attachment{ID: attachment.ID}).
+		Select("width", "height", "thumbnail_key", "thumbnail_width", "thumbnail_height").
+		Updates(attachment)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAttachmentNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_block_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository BlockRepository
+
+type BlockRepository interface {
+	Block(ctx context.Context, blockerID, blockedID int64) error
+	Unblock(ctx context.Context, blockerID, blockedID int64) error
+	ListBlocked(ctx context.Context, blockerID int64) ([]models.UserBlock, error)
+}
+
+type blockRepository struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) BlockRepository {
+	return &blockRepository{db: db}
+}
+
+// Block блокирует пользователя. Повторная блокировка ничего не меняет.
+// Если кого-то из пользователей нет, возвращает ErrUserNotFound.
+func (r *blockRepository) Block(ctx context.Context, blockerID, blockedID int64) error {
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserBlock{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: time.Now()}).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// Unblock снимает блокировку. Снятие несуществующей блокировки не ошибка.
+func (r *blockRepository) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	return conn(ctx, r.db).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&models.UserBlock{}).Error
+}
+
+// ListBlocked получает блокировки пользователя вместе с заблокированными, новые первыми
+func (r *blockRepository) ListBlocked(ctx context.Context, blockerID int64) ([]models.UserBlock, error) {
+	blocks := make([]models.UserBlock, 0)
+	err := conn(ctx, r.db).
+		Preload("Blocked").
+		Where("blocker_id = ?", blockerID).
+		Order("created_at DESC, blocked_id DESC").
+		Find(&blocks).Error
+	return blocks, err
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/GlebMoskalev/chat-golang/internal/models"
 )
@@ -13,6 +14,9 @@ import (
 
 var (
 	ErrChatNotFound = errors.New("chat not found")
+	ErrDMExists     = errors.New("direct message already exists")
+	// ErrAlreadyImported — запись с таким ключом импорта уже есть (параллельный импорт того же архива)
+	ErrAlreadyImported = errors.New("record already imported")
 )
 
 type ChatRepository interface {
@@ -20,6 +24,9 @@ type ChatRepository interface {
 	Delete(ctx context.Context, id int64) error
 	Exists(ctx context.Context, id int64) (bool, error)
 	GetByID(ctx context.Context, id int64) (*models.Chat, error)
+	GetByDMKey(ctx context.Context, key string) (*models.Chat, error)
+	GetByImportKey(ctx context.Context, key string) (*models.Chat, error)
+	ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error)
 }
 
 type chatRepository struct {
@@ -30,14 +37,24 @@ func NewChatRepository(db *gorm.DB) ChatRepository {
 	return &chatRepository{db: db}
 }
 
-// Create создаёт новый чат
+// Create создаёт новый чат. Если личный чат этой пары уже есть, возвращает ErrDMExists,
+// если чат с таким ключом импорта уже есть — ErrAlreadyImported.
 func (r *chatRepository) Create(ctx context.Context, chat *models.Chat) error {
-	return r.db.WithContext(ctx).Create(chat).Error
+	err := conn(ctx, r.db).Create(chat).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrDuplicatedKey) {
+		switch {
+		case chat.DMKey != nil:
+			return ErrDMExists
+		case chat.ImportKey != nil:
+			return ErrAlreadyImported
+		}
+	}
+	return err
 }
 
 // Delete удаляет чат (сообщения удалятся каскадом)
 func (r *chatRepository) Delete(ctx context.Context, id int64) error {
-	result := r.db.WithContext(ctx).Delete(&models.Chat{}, id)
+	result := conn(ctx, r.db).Delete(&models.Chat{}, id)
 
 	if result.Error != nil {
 		return result.Error
@@ -53,7 +70,7 @@ func (r *chatRepository) Delete(ctx context.Context, id int64) error {
 // GetByID получает чат по ID
 func (r *chatRepository) GetByID(ctx context.Context, id int64) (*models.Chat, error) {
 	var chat models.Chat
-	err := r.db.WithContext(ctx).First(&chat, id).Error
+	err := conn(ctx, r.db).First(&chat, id).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil // Возвращаем nil, nil для "не найдено"
@@ -66,13 +83,68 @@ func (r *chatRepository) GetByID(ctx context.Context, id int64) (*models.Chat, e
 	return &chat, nil
 }
 
-// Exists проверяет существование чата
+// GetByDMKey ищет личный чат по ключу пары, nil, nil если его нет
+func (r *chatRepository) GetByDMKey(ctx context.Context, key string) (*models.Chat, error) {
+	var chat models.Chat
+	err := conn(ctx, r.db).Where("dm_key = ?", key).First(&chat).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &chat, nil
+}
+
+// GetByImportKey ищет импортированный чат по ключу из архива, nil, nil если его нет
+func (r *chatRepository) GetByImportKey(ctx context.Context, key string) (*models.Chat, error) {
+	var chat models.Chat
+	err := conn(ctx, r.db).Where("import_key = ?", key).First(&chat).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &chat, nil
+}
+
+// ListIDs получает до limit ID чатов больше afterID по возрастанию, для обхода всех чатов порциями
+func (r *chatRepository) ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	var ids []int64
+	err := conn(ctx, r.db).
+		Model(&models.Chat{}).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Exists проверяет существование чата.
+// Внутри транзакции строка чата блокируется (FOR SHARE) до её завершения,
+// чтобы чат нельзя было удалить между проверкой и следующими запросами.
 func (r *chatRepository) Exists(ctx context.Context, id int64) (bool, error) {
-	var count int64
-	err := r.db.
-		WithContext(ctx).
+	query := conn(ctx, r.db).
 		Model(&models.Chat{}).
-		Where("id = ?", id).
-		Count(&count).Error
-	return count > 0, err
+		Where("id = ?", id)
+
+	if !inTransaction(ctx) {
+		var count int64
+		err := query.Count(&count).Error
+		return count > 0, err
+	}
+
+	var ids []int64
+	err := query.
+		Clauses(clause.Locking{Strength: clause.LockingStrengthShare}).
+		Limit(1).
+		Pluck("id", &ids).Error
+	return len(ids) > 0, err
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_chat_member_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ChatMemberRepository
+
+type ChatMemberRepository interface {
+	Add(ctx context.Context, chatID, userID int64) error
+	IsMember(ctx context.Context, chatID, userID int64) (bool, error)
+	ListMembers(ctx context.Context, chatID int64) ([]models.User, error)
+	MarkRead(ctx context.Context, chatID, userID, messageID int64, createdAt time.Time) error
+	ListReadStates(ctx context.Context, chatID int64) ([]models.ChatMember, error)
+	ListChats(ctx context.Context, userID int64) ([]models.ChatSummary, error)
+	ListChatIDs(ctx context.Context, userID int64) ([]int64, error)
+}
+
+type chatMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewChatMemberRepository(db *gorm.DB) ChatMemberRepository {
+	return &chatMemberRepository{db: db}
+}
+
+// Add добавляет участника. Повторное добавление ничего не меняет.
+// Если чата нет, возвращает ErrChatNotFound.
+func (r *chatMemberRepository) Add(ctx context.Context, chatID, userID int64) error {
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ChatMember{ChatID: chatID, UserID: userID, JoinedAt: time.Now()}).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
+}
+
+// IsMember проверяет, состоит ли пользователь в чате
+func (r *chatMemberRepository) IsMember(ctx context.Context, chatID, userID int64) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.ChatMember{}).
+		Where("chat_id = ? AND user_id = ?", chatID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListMembers получает участников чата в порядке вступления
+func (r *chatMemberRepository) ListMembers(ctx context.Context, chatID int64) ([]models.User, error) {
+	var users []models.User
+	err := conn(ctx, r.db).
+		Joins("JOIN chat_members ON chat_members.user_id = users.id").
+		Where("chat_members.chat_id = ?", chatID).
+		Order("chat_members.joined_at ASC, users.id ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// MarkRead сдвигает отметку прочтения участника на сообщение messageID, созданное в createdAt.
+// Отметка двигается только вперёд: более старое сообщение её не меняет. Если пользователь
+// не состоит в чате, ничего не происходит.
+func (r *chatMemberRepository) MarkRead(ctx context.Context, chatID, userID, messageID int64, createdAt time.Time) error {
+	return conn(ctx, r.db).
+		Model(&models.ChatMember{}).
+		Where("chat_id = ? AND user_id = ?", chatID, userID).
+		Where("last_read_at IS NULL OR last_read_at < ? OR (last_read_at = ? AND last_read_message_id < ?)",
+			createdAt, createdAt, messageID).
+		Updates(map[string]any{
+			"last_read_message_id": messageID,
+			"last_read_at":         createdAt,
+		}).Error
+}
+
+// ListReadStates получает участников чата с их отметками прочтения
+func (r *chatMemberRepository) ListReadStates(ctx context.Context, chatID int64) ([]models.ChatMember, error) {
+	var members []models.ChatMember
+	err := conn(ctx, r.db).
+		Where("chat_id = ?", chatID).
+		Order("user_id ASC").
+		Find(&members).Error
+	return members, err
+}
+
+// ListChats получает чаты пользователя с числом непрочитанных сообщений.
+// Непрочитанные — чужие неистёкшие сообщения новее отметки прочтения, кроме сообщений
+// заблокированных пользователем авторов; подсчёт идёт по индексу idx_messages_chat_created,
+// а не по всем сообщениям чата.
+func (r *chatMemberRepository) ListChats(ctx context.Context, userID int64) ([]models.ChatSummary, error) {
+	blocked := conn(ctx, r.db).Model(&models.UserBlock{}).Select("blocked_id").Where("blocker_id = ?", userID)
+	unread := conn(ctx, r.db).
+		Table("messages").
+		Select("COUNT(*)").
+		Where("messages.chat_id = chat_members.chat_id").
+		Where("chat_members.last_read_at IS NULL OR messages.created_at > chat_members.last_read_at OR "+
+			"(messages.created_at = chat_members.last_read_at AND messages.id > chat_members.last_read_message_id)").
+		Where("messages.author_id IS NULL OR (messages.author_id <> ? AND messages.author_id NOT IN (?))", userID, blocked).
+		Where("messages.expires_at IS NULL OR messages.expires_at > ?", time.Now())
+
+	var summaries []models.ChatSummary
+	err := conn(ctx, r.db).
+		Table("chats").
+		Select("chats.*, chat_members.last_read_message_id, (?) AS unread_count", unread).
+		Joins("JOIN chat_members ON chat_members.chat_id = chats.id").
+		Where("chat_members.user_id = ?", userID).
+		Order("chats.id ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+// ListChatIDs получает ID чатов пользователя по возрастанию. В отличие от ListChats
+// не считает непрочитанные и не читает сами чаты.
+func (r *chatMemberRepository) ListChatIDs(ctx context.Context, userID int64) ([]int64, error) {
+	var ids []int64
+	err := conn(ctx, r.db).
+		Model(&models.ChatMember{}).
+		Where("user_id = ?", userID).
+		Order("chat_id ASC").
+		Pluck("chat_id", &ids).Error
+	return ids, err
+}
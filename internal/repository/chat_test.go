@@ -17,7 +17,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Chat{}, &models.Message{})
+	err = db.AutoMigrate(&models.Chat{}, &models.Message{}, &models.Attachment{})
 	require.NoError(t, err)
 
 	return db
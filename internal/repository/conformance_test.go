@@ -0,0 +1,162 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/repotest"
+)
+
+func TestConformance_SQLite(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{
+			Logger: logger.Default.LogMode(logger.Silent),
+		})
+		require.NoError(t, err)
+
+		// Каждое соединение к :memory: — отдельная база, поэтому держим ровно одно
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		sqlDB.SetMaxOpenConns(1)
+		t.Cleanup(func() { sqlDB.Close() })
+
+		require.NoError(t, db.AutoMigrate(&models.Chat{}, &models.Message{}, &models.OutboxEvent{}, &models.OutboxCursor{},
+			&models.Webhook{}, &models.WebhookDelivery{}, &models.User{}, &models.IncomingWebhook{},
+			&models.Attachment{}, &models.LinkPreview{}, &models.ChatMember{}, &models.Mention{}, &models.ChatPin{},
+			&models.RetentionPolicy{}, &models.ScheduledMessage{},
+			&models.ModerationRule{}, &models.ModerationFlag{},
+			&models.UserBlock{}, &models.MessageReport{}, &models.AuditEntry{}))
+
+		return repotest.Repositories{
+			Tx:       repository.NewTxManager(db),
+			Chats:    repository.NewChatRepository(db),
+			Messages: repository.NewMessageRepository(db),
+			Outbox:   repository.NewOutboxRepository(db),
+
+			Webhooks:   repository.NewWebhookRepository(db),
+			Deliveries: repository.NewWebhookDeliveryRepository(db),
+
+			Users:    repository.NewUserRepository(db),
+			Incoming: repository.NewIncomingWebhookRepository(db),
+
+			Attachments:  repository.NewAttachmentRepository(db),
+			LinkPreviews: repository.NewLinkPreviewRepository(db),
+
+			Members:  repository.NewChatMemberRepository(db),
+			Mentions: repository.NewMentionRepository(db),
+			Pins:     repository.NewPinRepository(db),
+
+			Retention:  repository.NewRetentionRepository(db),
+			Scheduled:  repository.NewScheduledMessageRepository(db),
+			Moderation: repository.NewModerationRepository(db),
+
+			Blocks:  repository.NewBlockRepository(db),
+			Reports: repository.NewReportRepository(db),
+			Audit:   repository.NewAuditRepository(db),
+		}
+	})
+}
+
+func TestConformance_Postgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Postgres conformance пропускается в -short режиме")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("chat"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("postgres"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, container)
+	require.NoError(t, err)
+
+	dsn, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	for _, stmt := range migrationsUp(t) {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		truncateAll(t, db)
+
+		return repotest.Repositories{
+			Tx:       repository.NewTxManager(db),
+			Chats:    repository.NewChatRepository(db),
+			Messages: repository.NewMessageRepository(db),
+			Outbox:   repository.NewOutboxRepository(db),
+
+			Webhooks:   repository.NewWebhookRepository(db),
+			Deliveries: repository.NewWebhookDeliveryRepository(db),
+
+			Users:    repository.NewUserRepository(db),
+			Incoming: repository.NewIncomingWebhookRepository(db),
+
+			Attachments:  repository.NewAttachmentRepository(db),
+			LinkPreviews: repository.NewLinkPreviewRepository(db),
+
+			Members:  repository.NewChatMemberRepository(db),
+			Mentions: repository.NewMentionRepository(db),
+			Pins:     repository.NewPinRepository(db),
+
+			Retention:  repository.NewRetentionRepository(db),
+			Scheduled:  repository.NewScheduledMessageRepository(db),
+			Moderation: repository.NewModerationRepository(db),
+
+			Blocks:  repository.NewBlockRepository(db),
+			Reports: repository.NewReportRepository(db),
+			Audit:   repository.NewAuditRepository(db),
+		}
+	})
+}
+
+// truncateAll очищает все таблицы схемы public и сбрасывает последовательности
+func truncateAll(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	var tables []string
+	require.NoError(t, db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = 'public'").Scan(&tables).Error)
+	require.NoError(t, db.Exec("TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE").Error)
+}
+
+// migrationsUp возвращает Up-секции всех goose-миграций в порядке их применения
+func migrationsUp(t *testing.T) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.sql"))
+	require.NoError(t, err)
+	sort.Strings(files)
+
+	var statements []string
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		up, _, _ := strings.Cut(string(content), "-- +goose Down")
+		statements = append(statements, up)
+	}
+
+	return statements
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_incoming_webhook_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository IncomingWebhookRepository
+
+var (
+	ErrIncomingWebhookNotFound = errors.New("incoming webhook not found")
+)
+
+type IncomingWebhookRepository interface {
+	Create(ctx context.Context, hook *models.IncomingWebhook) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error)
+	ListByChat(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error)
+	Delete(ctx context.Context, chatID, id int64) error
+}
+
+type incomingWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewIncomingWebhookRepository(db *gorm.DB) IncomingWebhookRepository {
+	return &incomingWebhookRepository{db: db}
+}
+
+// Create сохраняет входящий вебхук. Если чата нет, возвращает ErrChatNotFound.
+func (r *incomingWebhookRepository) Create(ctx context.Context, hook *models.IncomingWebhook) error {
+	err := conn(ctx, r.db).Create(hook).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
+}
+
+// GetByTokenHash ищет вебхук по хешу токена, nil, nil если его нет
+func (r *incomingWebhookRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error) {
+	var hook models.IncomingWebhook
+	err := conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&hook).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
+
+// ListByChat получает вебхуки чата
+func (r *incomingWebhookRepository) ListByChat(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error) {
+	var hooks []models.IncomingWebhook
+	err := conn(ctx, r.db).Where("chat_id = ?", chatID).Order("id ASC").Find(&hooks).Error
+	return hooks, err
+}
+
+// Delete отзывает вебхук чата
+func (r *incomingWebhookRepository) Delete(ctx context.Context, chatID, id int64) error {
+	result := conn(ctx, r.db).Where("chat_id = ?", chatID).Delete(&models.IncomingWebhook{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrIncomingWebhookNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_link_preview_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository LinkPreviewRepository
+
+type LinkPreviewRepository interface {
+	Upsert(ctx context.Context, preview *models.LinkPreview) error
+	GetByURL(ctx context.Context, url string) (*models.LinkPreview, error)
+	GetByURLs(ctx context.Context, urls []string) ([]models.LinkPreview, error)
+}
+
+type linkPreviewRepository struct {
+	db *gorm.DB
+}
+
+func NewLinkPreviewRepository(db *gorm.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{db: db}
+}
+
+// Upsert сохраняет карточку, перезаписывая прошлую загрузку того же URL
+func (r *linkPreviewRepository) Upsert(ctx context.Context, preview *models.LinkPreview) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "url"}},
+			DoUpdates: clause.AssignmentColumns([]string{"title", "description", "image_url", "site_name", "status", "error", "fetched_at"}),
+		}).
+		Create(preview).Error
+}
+
+// GetByURL получает карточку по URL, nil, nil если её нет
+func (r *linkPreviewRepository) GetByURL(ctx context.Context, url string) (*models.LinkPreview, error) {
+	var preview models.LinkPreview
+	err := conn(ctx, r.db).Where("url = ?", url).First(&preview).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &preview, nil
+}
+
+// GetByURLs получает карточки сразу для нескольких URL (в любом порядке)
+func (r *linkPreviewRepository) GetByURLs(ctx context.Context, urls []string) ([]models.LinkPreview, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	var previews []models.LinkPreview
+	err := conn(ctx, r.db).Where("url IN ?", urls).Find(&previews).Error
+	return previews, err
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type attachmentRepository struct {
+	store *Store
+}
+
+func NewAttachmentRepository(store *Store) repository.AttachmentRepository {
+	return &attachmentRepository{store: store}
+}
+
+// Create сохраняет метаданные вложения. Если сообщения нет, возвращает ErrMessageNotFound.
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.messages.rows[attachment.MessageID]; !ok {
+		return repository.ErrMessageNotFound
+	}
+
+	attachment.ID = r.store.attachments.nextID()
+	if attachment.CreatedAt.IsZero() {
+		attachment.CreatedAt = time.Now()
+	}
+
+	stored := *attachment
+	stored.Message = nil
+	r.store.attachments.put(attachment.ID, stored)
+
+	return nil
+}
+
+// GetByID получает вложение по ID, nil, nil если его нет
+func (r *attachmentRepository) GetByID(ctx context.Context, id int64) (*models.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	attachment, ok := r.store.attachments.rows[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &attachment, nil
+}
+
+// ListByMessage получает вложения сообщения по порядку ID
+func (r *attachmentRepository) ListByMessage(ctx context.Context, messageID int64) ([]models.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	return r.store.attachmentsOf(messageID), nil
+}
+
+// ListByChat получает все вложения чата по порядку ID
+func (r *attachmentRepository) ListByChat(ctx context.Context, chatID int64) ([]models.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	var attachments []models.Attachment
+	for _, attachment := range r.store.attachments.rows {
+		if attachment.ChatID == chatID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+
+	return attachments, nil
+}
+
+// UpdateThumbnail сохраняет размеры картинки и ключ её миниатюры
+func (r *attachmentRepository) UpdateThumbnail(ctx context.Context, attachment *models.Attachment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	stored, ok := r.store.attachments.rows[attachment.ID]
+	if !ok {
+		return repository.ErrAttachmentNotFound
+	}
+
+	stored.Width = attachment.Width
+	stored.Height = attachment.Height
+	stored.ThumbnailKey = attachment.ThumbnailKey
+	stored.ThumbnailWidth = attachment.ThumbnailWidth
+	stored.ThumbnailHeight = attachment.ThumbnailHeight
+	r.store.attachments.put(attachment.ID, stored)
+
+	return nil
+}
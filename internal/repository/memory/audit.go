@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type auditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) repository.AuditRepository {
+	return &auditRepository{store: store}
+}
+
+// Add дописывает запись в журнал
+func (r *auditRepository) Add(ctx context.Context, entry *models.AuditEntry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	entry.ID = r.store.audit.nextID()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	r.store.audit.put(entry.ID, *entry)
+
+	return nil
+}
+
+// List получает записи журнала по фильтру, новые первыми
+func (r *auditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	entries := make([]models.AuditEntry, 0)
+	for _, entry := range r.store.audit.rows {
+		if matchesAudit(entry, filter) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID > entries[j].ID
+	})
+
+	if filter.Limit >= 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, nil
+}
+
+func matchesAudit(entry models.AuditEntry, filter models.AuditFilter) bool {
+	switch {
+	case filter.ActorID != nil && (entry.ActorID == nil || *entry.ActorID != *filter.ActorID):
+		return false
+	case filter.Action != "" && entry.Action != filter.Action:
+		return false
+	case filter.TargetType != "" && entry.TargetType != filter.TargetType:
+		return false
+	case filter.TargetID != nil && entry.TargetID != *filter.TargetID:
+		return false
+	case filter.ChatID != nil && (entry.ChatID == nil || *entry.ChatID != *filter.ChatID):
+		return false
+	case filter.Since != nil && entry.CreatedAt.Before(*filter.Since):
+		return false
+	case filter.Until != nil && !entry.CreatedAt.Before(*filter.Until):
+		return false
+	case filter.BeforeID > 0 && entry.ID >= filter.BeforeID:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type blockRepository struct {
+	store *Store
+}
+
+func NewBlockRepository(store *Store) repository.BlockRepository {
+	return &blockRepository{store: store}
+}
+
+// Block блокирует пользователя. Повторная блокировка ничего не меняет.
+func (r *blockRepository) Block(ctx context.Context, blockerID, blockedID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.users.rows[blockerID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	if _, ok := r.store.users.rows[blockedID]; !ok {
+		return repository.ErrUserNotFound
+	}
+
+	for _, block := range r.store.blocks.rows {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			return nil
+		}
+	}
+
+	r.store.blocks.put(r.store.blocks.nextID(), models.UserBlock{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// Unblock снимает блокировку
+func (r *blockRepository) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	for key, block := range r.store.blocks.rows {
+		if block.BlockerID == blockerID && block.BlockedID == blockedID {
+			r.store.blocks.remove(key)
+		}
+	}
+
+	return nil
+}
+
+// ListBlocked получает блокировки пользователя вместе с заблокированными, новые первыми
+func (r *blockRepository) ListBlocked(ctx context.Context, blockerID int64) ([]models.UserBlock, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	blocks := make([]models.UserBlock, 0)
+	for _, block := range r.store.blocks.rows {
+		if block.BlockerID != blockerID {
+			continue
+		}
+		if user, ok := r.store.users.rows[block.BlockedID]; ok {
+			block.Blocked = &user
+		}
+		blocks = append(blocks, block)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		if !blocks[i].CreatedAt.Equal(blocks[j].CreatedAt) {
+			return blocks[i].CreatedAt.After(blocks[j].CreatedAt)
+		}
+		return blocks[i].BlockedID > blocks[j].BlockedID
+	})
+
+	return blocks, nil
+}
@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type chatRepository struct {
+	store *Store
+}
+
+func NewChatRepository(store *Store) repository.ChatRepository {
+	return &chatRepository{store: store}
+}
+
+// Create создаёт новый чат. Если личный чат этой пары уже есть, возвращает ErrDMExists,
+// если чат с таким ключом импорта уже есть — ErrAlreadyImported.
+func (r *chatRepository) Create(ctx context.Context, chat *models.Chat) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	// Аналог уникального индекса idx_chats_dm_key
+	if chat.DMKey != nil {
+		for _, existing := range r.store.chats.rows {
+			if existing.DMKey != nil && *existing.DMKey == *chat.DMKey {
+				return repository.ErrDMExists
+			}
+		}
+	}
+
+	// Аналог уникального индекса idx_chats_import_key
+	if chat.ImportKey != nil {
+		for _, existing := range r.store.chats.rows {
+			if existing.ImportKey != nil && *existing.ImportKey == *chat.ImportKey {
+				return repository.ErrAlreadyImported
+			}
+		}
+	}
+
+	chat.ID = r.store.chats.nextID()
+	if chat.Type == "" {
+		chat.Type = models.ChatTypeRoom
+	}
+	if chat.CreatedAt.IsZero() {
+		chat.CreatedAt = time.Now()
+	}
+	r.store.chats.put(chat.ID, *chat)
+
+	return nil
+}
+
+// Delete удаляет чат вместе с его сообщениями
+func (r *chatRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[id]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	r.store.chats.remove(id)
+	for msgID, msg := range r.store.messages.rows {
+		if msg.ChatID == id {
+			r.store.messages.remove(msgID)
+		}
+	}
+	for attachmentID, attachment := range r.store.attachments.rows {
+		if attachment.ChatID == id {
+			r.store.attachments.remove(attachmentID)
+		}
+	}
+	for hookID, hook := range r.store.incoming.rows {
+		if hook.ChatID == id {
+			r.store.incoming.remove(hookID)
+		}
+	}
+	for key, member := range r.store.members.rows {
+		if member.ChatID == id {
+			r.store.members.remove(key)
+		}
+	}
+	for key, mention := range r.store.mentions.rows {
+		if mention.ChatID == id {
+			r.store.mentions.remove(key)
+		}
+	}
+	for key, pin := range r.store.pins.rows {
+		if pin.ChatID == id {
+			r.store.pins.remove(key)
+		}
+	}
+	r.store.retention.remove(id)
+	for ruleID, rule := range r.store.moderationRules.rows {
+		if rule.ChatID == id {
+			r.store.moderationRules.remove(ruleID)
+		}
+	}
+	for messageID, flag := range r.store.moderationFlags.rows {
+		if flag.ChatID == id {
+			r.store.moderationFlags.remove(messageID)
+		}
+	}
+	for scheduledID, scheduled := range r.store.scheduled.rows {
+		if scheduled.ChatID == id {
+			r.store.scheduled.remove(scheduledID)
+		}
+	}
+	for reportID, report := range r.store.reports.rows {
+		if report.ChatID == id {
+			r.store.reports.remove(reportID)
+		}
+	}
+
+	return nil
+}
+
+// GetByID получает чат по ID
+func (r *chatRepository) GetByID(ctx context.Context, id int64) (*models.Chat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	chat, ok := r.store.chats.rows[id]
+	if !ok {
+		return nil, nil // Как и в GORM-реализации: nil, nil для "не найдено"
+	}
+
+	return &chat, nil
+}
+
+// Exists проверяет существование чата
+func (r *chatRepository) Exists(ctx context.Context, id int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	_, ok := r.store.chats.rows[id]
+	return ok, nil
+}
+
+// GetByDMKey ищет личный чат по ключу пары, nil, nil если его нет
+func (r *chatRepository) GetByDMKey(ctx context.Context, key string) (*models.Chat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, chat := range r.store.chats.rows {
+		if chat.DMKey != nil && *chat.DMKey == key {
+			return &chat, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetByImportKey ищет импортированный чат по ключу из архива, nil, nil если его нет
+func (r *chatRepository) GetByImportKey(ctx context.Context, key string) (*models.Chat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, chat := range r.store.chats.rows {
+		if chat.ImportKey != nil && *chat.ImportKey == key {
+			return &chat, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListIDs получает до limit ID чатов больше afterID по возрастанию
+func (r *chatRepository) ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	ids := make([]int64, 0)
+	for id := range r.store.chats.rows {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return ids, nil
+}
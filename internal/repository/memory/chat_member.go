@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type chatMemberRepository struct {
+	store *Store
+}
+
+func NewChatMemberRepository(store *Store) repository.ChatMemberRepository {
+	return &chatMemberRepository{store: store}
+}
+
+// Add добавляет участника. Повторное добавление ничего не меняет.
+func (r *chatMemberRepository) Add(ctx context.Context, chatID, userID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[chatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	for _, member := range r.store.members.rows {
+		if member.ChatID == chatID && member.UserID == userID {
+			return nil
+		}
+	}
+
+	r.store.members.put(r.store.members.nextID(), models.ChatMember{
+		ChatID:   chatID,
+		UserID:   userID,
+		JoinedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// IsMember проверяет, состоит ли пользователь в чате
+func (r *chatMemberRepository) IsMember(ctx context.Context, chatID, userID int64) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, member := range r.store.members.rows {
+		if member.ChatID == chatID && member.UserID == userID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ListMembers получает участников чата в порядке вступления
+func (r *chatMemberRepository) ListMembers(ctx context.Context, chatID int64) ([]models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	members := make([]models.ChatMember, 0)
+	for _, member := range r.store.members.rows {
+		if member.ChatID == chatID {
+			members = append(members, member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+
+	users := make([]models.User, 0, len(members))
+	for _, member := range members {
+		if user, ok := r.store.users.rows[member.UserID]; ok {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
+// MarkRead сдвигает отметку прочтения участника вперёд. Если пользователь
+// не состоит в чате, ничего не происходит.
+func (r *chatMemberRepository) MarkRead(ctx context.Context, chatID, userID, messageID int64, createdAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	for key, member := range r.store.members.rows {
+		if member.ChatID != chatID || member.UserID != userID {
+			continue
+		}
+		if member.HasRead(models.Message{ID: messageID, CreatedAt: createdAt}) {
+			return nil
+		}
+		member.LastReadMessageID = &messageID
+		member.LastReadAt = &createdAt
+		r.store.members.put(key, member)
+		return nil
+	}
+
+	return nil
+}
+
+// ListReadStates получает участников чата с их отметками прочтения
+func (r *chatMemberRepository) ListReadStates(ctx context.Context, chatID int64) ([]models.ChatMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	members := make([]models.ChatMember, 0)
+	for _, member := range r.store.members.rows {
+		if member.ChatID == chatID {
+			members = append(members, member)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID < members[j].UserID
+	})
+
+	return members, nil
+}
+
+// ListChats получает чаты пользователя с числом непрочитанных чужих сообщений
+func (r *chatMemberRepository) ListChats(ctx context.Context, userID int64) ([]models.ChatSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	now := time.Now()
+	blocked := r.store.blockedBy(userID)
+	summaries := make([]models.ChatSummary, 0)
+	for _, member := range r.store.members.rows {
+		if member.UserID != userID {
+			continue
+		}
+		chat, ok := r.store.chats.rows[member.ChatID]
+		if !ok {
+			continue
+		}
+
+		summary := models.ChatSummary{Chat: chat, LastReadMessageID: member.LastReadMessageID}
+		for _, msg := range r.store.messages.rows {
+			if msg.ChatID != chat.ID || member.HasRead(msg) || expired(msg, now) {
+				continue
+			}
+			if msg.AuthorID != nil && (*msg.AuthorID == userID || blocked[*msg.AuthorID]) {
+				continue
+			}
+			summary.UnreadCount++
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].ID < summaries[j].ID
+	})
+
+	return summaries, nil
+}
+
+// ListChatIDs получает ID чатов пользователя по возрастанию
+func (r *chatMemberRepository) ListChatIDs(ctx context.Context, userID int64) ([]int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	ids := make([]int64, 0)
+	for _, member := range r.store.members.rows {
+		if member.UserID == userID {
+			ids = append(ids, member.ChatID)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/repository/repotest"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		store := NewStore()
+		return repotest.Repositories{
+			Tx:       NewTxManager(store),
+			Chats:    NewChatRepository(store),
+			Messages: NewMessageRepository(store),
+			Outbox:   NewOutboxRepository(store),
+
+			Webhooks:   NewWebhookRepository(store),
+			Deliveries: NewWebhookDeliveryRepository(store),
+
+			Users:    NewUserRepository(store),
+			Incoming: NewIncomingWebhookRepository(store),
+
+			Attachments:  NewAttachmentRepository(store),
+			LinkPreviews: NewLinkPreviewRepository(store),
+
+			Members:  NewChatMemberRepository(store),
+			Mentions: NewMentionRepository(store),
+			Pins:     NewPinRepository(store),
+
+			Retention:  NewRetentionRepository(store),
+			Scheduled:  NewScheduledMessageRepository(store),
+			Moderation: NewModerationRepository(store),
+
+			Blocks:  NewBlockRepository(store),
+			Reports: NewReportRepository(store),
+			Audit:   NewAuditRepository(store),
+		}
+	})
+}
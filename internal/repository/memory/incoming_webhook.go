@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type incomingWebhookRepository struct {
+	store *Store
+}
+
+func NewIncomingWebhookRepository(store *Store) repository.IncomingWebhookRepository {
+	return &incomingWebhookRepository{store: store}
+}
+
+// Create сохраняет входящий вебхук
+func (r *incomingWebhookRepository) Create(ctx context.Context, hook *models.IncomingWebhook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[hook.ChatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	hook.ID = r.store.incoming.nextID()
+	if hook.CreatedAt.IsZero() {
+		hook.CreatedAt = time.Now()
+	}
+
+	stored := *hook
+	stored.Token = ""
+	stored.Chat = nil
+	r.store.incoming.put(hook.ID, stored)
+
+	return nil
+}
+
+// GetByTokenHash ищет вебхук по хешу токена, nil, nil если его нет
+func (r *incomingWebhookRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, hook := range r.store.incoming.rows {
+		if hook.TokenHash == tokenHash {
+			return &hook, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListByChat получает вебхуки чата
+func (r *incomingWebhookRepository) ListByChat(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	hooks := make([]models.IncomingWebhook, 0)
+	for _, hook := range r.store.incoming.rows {
+		if hook.ChatID == chatID {
+			hooks = append(hooks, hook)
+		}
+	}
+
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].ID < hooks[j].ID
+	})
+
+	return hooks, nil
+}
+
+// Delete отзывает вебхук чата
+func (r *incomingWebhookRepository) Delete(ctx context.Context, chatID, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	hook, ok := r.store.incoming.rows[id]
+	if !ok || hook.ChatID != chatID {
+		return repository.ErrIncomingWebhookNotFound
+	}
+
+	r.store.incoming.remove(id)
+	return nil
+}
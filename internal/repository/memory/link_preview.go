@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type linkPreviewRepository struct {
+	store *Store
+}
+
+func NewLinkPreviewRepository(store *Store) repository.LinkPreviewRepository {
+	return &linkPreviewRepository{store: store}
+}
+
+// Upsert сохраняет карточку, перезаписывая прошлую загрузку того же URL
+func (r *linkPreviewRepository) Upsert(ctx context.Context, preview *models.LinkPreview) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	for id, existing := range r.store.linkPreviews.rows {
+		if existing.URL == preview.URL {
+			preview.ID = id
+			r.store.linkPreviews.put(id, *preview)
+			return nil
+		}
+	}
+
+	preview.ID = r.store.linkPreviews.nextID()
+	r.store.linkPreviews.put(preview.ID, *preview)
+
+	return nil
+}
+
+// GetByURL получает карточку по URL, nil, nil если её нет
+func (r *linkPreviewRepository) GetByURL(ctx context.Context, url string) (*models.LinkPreview, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, preview := range r.store.linkPreviews.rows {
+		if preview.URL == url {
+			return &preview, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetByURLs получает карточки сразу для нескольких URL (в любом порядке)
+func (r *linkPreviewRepository) GetByURLs(ctx context.Context, urls []string) ([]models.LinkPreview, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	var previews []models.LinkPreview
+	for _, preview := range r.store.linkPreviews.rows {
+		if slices.Contains(urls, preview.URL) {
+			previews = append(previews, preview)
+		}
+	}
+
+	return previews, nil
+}
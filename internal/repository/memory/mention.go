@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type mentionRepository struct {
+	store *Store
+}
+
+func NewMentionRepository(store *Store) repository.MentionRepository {
+	return &mentionRepository{store: store}
+}
+
+// Create сохраняет упоминания одного или нескольких сообщений
+func (r *mentionRepository) Create(ctx context.Context, mentions []models.Mention) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	now := time.Now()
+	for _, mention := range mentions {
+		if _, ok := r.store.messages.rows[mention.MessageID]; !ok {
+			return repository.ErrMessageNotFound
+		}
+		if mention.CreatedAt.IsZero() {
+			mention.CreatedAt = now
+		}
+		mention.Message = nil
+		mention.User = nil
+		r.store.mentions.put(r.store.mentions.nextID(), mention)
+	}
+
+	return nil
+}
+
+// ListByUser получает упоминания пользователя вместе с сообщениями, новые первыми
+func (r *mentionRepository) ListByUser(ctx context.Context, userID, beforeMessageID int64, unreadOnly bool, limit int) ([]models.Mention, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	mentions := make([]models.Mention, 0)
+	for _, mention := range r.store.mentions.rows {
+		if mention.UserID != userID {
+			continue
+		}
+		if beforeMessageID > 0 && mention.MessageID >= beforeMessageID {
+			continue
+		}
+		if unreadOnly && mention.ReadAt != nil {
+			continue
+		}
+		mentions = append(mentions, mention)
+	}
+
+	sort.Slice(mentions, func(i, j int) bool {
+		return mentions[i].MessageID > mentions[j].MessageID
+	})
+
+	if limit >= 0 && len(mentions) > limit {
+		mentions = mentions[:limit]
+	}
+
+	for i := range mentions {
+		if msg, ok := r.store.messages.rows[mentions[i].MessageID]; ok {
+			mentions[i].Message = &msg
+		}
+	}
+
+	return mentions, nil
+}
+
+// CountUnread считает непрочитанные упоминания пользователя
+func (r *mentionRepository) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	var count int64
+	for _, mention := range r.store.mentions.rows {
+		if mention.UserID == userID && mention.ReadAt == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// MarkRead отмечает упоминания прочитанными и возвращает их число.
+// Пустой messageIDs означает все непрочитанные упоминания пользователя.
+func (r *mentionRepository) MarkRead(ctx context.Context, userID int64, messageIDs []int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	now := time.Now()
+	var updated int64
+	for key, mention := range r.store.mentions.rows {
+		if mention.UserID != userID || mention.ReadAt != nil {
+			continue
+		}
+		if len(messageIDs) > 0 && !slices.Contains(messageIDs, mention.MessageID) {
+			continue
+		}
+		mention.ReadAt = &now
+		r.store.mentions.put(key, mention)
+		updated++
+	}
+
+	return updated, nil
+}
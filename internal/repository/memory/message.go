@@ -0,0 +1,347 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type messageRepository struct {
+	store *Store
+}
+
+func NewMessageRepository(store *Store) repository.MessageRepository {
+	return &messageRepository{store: store}
+}
+
+// Create создаёт сообщение
+func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	// Аналог внешнего ключа fk_messages_chat
+	if _, ok := r.store.chats.rows[message.ChatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	message.ID = r.store.messages.nextID()
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now()
+	}
+
+	stored := *message
+	stored.Chat = nil
+	stored.Attachments = nil
+	r.store.messages.put(message.ID, stored)
+
+	return nil
+}
+
+// GetByChatID получает последние N сообщений чата (новые первыми) вместе с вложениями,
+// пропуская авторов, заблокированных viewerID
+func (r *messageRepository) GetByChatID(ctx context.Context, chatID, viewerID int64, limit int) ([]models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	blocked := r.store.blockedBy(viewerID)
+	now := time.Now()
+	messages := make([]models.Message, 0)
+	for _, msg := range r.store.messages.rows {
+		if msg.AuthorID != nil && blocked[*msg.AuthorID] {
+			continue
+		}
+		if msg.ChatID == chatID && !expired(msg, now) {
+			messages = append(messages, msg)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.After(messages[j].CreatedAt)
+		}
+		return messages[i].ID > messages[j].ID
+	})
+
+	if limit >= 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	for i := range messages {
+		messages[i].Attachments = r.attachmentsOf(messages[i].ID)
+	}
+
+	return messages, nil
+}
+
+// GetByID получает сообщение без вложений, nil, nil если его нет или оно истекло
+func (r *messageRepository) GetByID(ctx context.Context, id int64) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	message, ok := r.store.messages.rows[id]
+	if !ok || expired(message, time.Now()) {
+		return nil, nil
+	}
+
+	return &message, nil
+}
+
+// ListAfter получает до limit сообщений чата от старых к новым, идущих после сообщения
+// (afterCreatedAt, afterID), вместе с вложениями
+func (r *messageRepository) ListAfter(ctx context.Context, chatID int64, afterCreatedAt time.Time, afterID int64, limit int) ([]models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	now := time.Now()
+	messages := make([]models.Message, 0)
+	for _, msg := range r.store.messages.rows {
+		if msg.ChatID != chatID || expired(msg, now) {
+			continue
+		}
+		if msg.CreatedAt.After(afterCreatedAt) || (msg.CreatedAt.Equal(afterCreatedAt) && msg.ID > afterID) {
+			messages = append(messages, msg)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	if limit >= 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	for i := range messages {
+		messages[i].Attachments = r.attachmentsOf(messages[i].ID)
+	}
+
+	return messages, nil
+}
+
+// CreateBatch вставляет сообщения и заполняет их ID. Либо вставляются все, либо ни одно.
+func (r *messageRepository) CreateBatch(ctx context.Context, messages []models.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	keys := make(map[string]bool)
+	for _, msg := range r.store.messages.rows {
+		if msg.ImportKey != nil {
+			keys[*msg.ImportKey] = true
+		}
+	}
+	for _, message := range messages {
+		if _, ok := r.store.chats.rows[message.ChatID]; !ok {
+			return repository.ErrChatNotFound
+		}
+		// Аналог уникального индекса idx_messages_import_key
+		if message.ImportKey != nil {
+			if keys[*message.ImportKey] {
+				return repository.ErrAlreadyImported
+			}
+			keys[*message.ImportKey] = true
+		}
+	}
+
+	now := time.Now()
+	for i := range messages {
+		messages[i].ID = r.store.messages.nextID()
+		if messages[i].CreatedAt.IsZero() {
+			messages[i].CreatedAt = now
+		}
+
+		stored := messages[i]
+		stored.Chat = nil
+		stored.Attachments = nil
+		r.store.messages.put(stored.ID, stored)
+	}
+
+	return nil
+}
+
+// ImportedKeys возвращает те из ключей импорта, сообщения с которыми уже есть
+func (r *messageRepository) ImportedKeys(ctx context.Context, keys []string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key] = true
+	}
+
+	existing := make([]string, 0)
+	for _, msg := range r.store.messages.rows {
+		if msg.ImportKey != nil && wanted[*msg.ImportKey] {
+			existing = append(existing, *msg.ImportKey)
+		}
+	}
+
+	return existing, nil
+}
+
+// NthNewest получает сообщение чата, перед которым есть ровно n более новых, nil, nil если его нет
+func (r *messageRepository) NthNewest(ctx context.Context, chatID int64, n int) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	messages := r.upTo(chatID, nil)
+	if n >= len(messages) {
+		return nil, nil
+	}
+
+	message := messages[len(messages)-1-n]
+	return &message, nil
+}
+
+// CountUpTo считает сообщения чата не новее сообщения (createdAt, id) включительно
+func (r *messageRepository) CountUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	return int64(len(r.upTo(chatID, &models.Message{ID: id, CreatedAt: createdAt}))), nil
+}
+
+// DeleteUpTo удаляет до limit самых старых сообщений чата не новее (createdAt, id)
+// вместе с их вложениями, упоминаниями и закрепами и возвращает их ID и ChatID с вложениями
+func (r *messageRepository) DeleteUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64, limit int) ([]models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	messages := r.upTo(chatID, &models.Message{ID: id, CreatedAt: createdAt})
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+
+	deleted := make([]models.Message, 0, len(messages))
+	for _, message := range messages {
+		deleted = append(deleted, models.Message{ID: message.ID, ChatID: message.ChatID, Attachments: r.store.attachmentsOf(message.ID)})
+		r.store.deleteMessage(message.ID)
+	}
+
+	return deleted, nil
+}
+
+// DeleteExpired удаляет до limit исчезающих сообщений, истёкших к now, начиная с самых
+// давно истёкших, и возвращает их ID и ChatID с вложениями
+func (r *messageRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	var deleted []models.Message
+	for _, msg := range r.store.messages.rows {
+		if expired(msg, now) {
+			deleted = append(deleted, models.Message{ID: msg.ID, ChatID: msg.ChatID, ExpiresAt: msg.ExpiresAt})
+		}
+	}
+
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i].ExpiresAt.Before(*deleted[j].ExpiresAt)
+	})
+	if len(deleted) > limit {
+		deleted = deleted[:limit]
+	}
+
+	for i := range deleted {
+		deleted[i].Attachments = r.store.attachmentsOf(deleted[i].ID)
+		r.store.deleteMessage(deleted[i].ID)
+		deleted[i].ExpiresAt = nil
+	}
+
+	return deleted, nil
+}
+
+// Delete удаляет сообщение вместе с вложениями, упоминаниями и закрепами
+func (r *messageRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.messages.rows[id]; !ok {
+		return repository.ErrMessageNotFound
+	}
+	r.store.deleteMessage(id)
+
+	return nil
+}
+
+// expired сообщает, истёк ли к now срок исчезающего сообщения
+func expired(msg models.Message, now time.Time) bool {
+	return msg.ExpiresAt != nil && !msg.ExpiresAt.After(now)
+}
+
+// upTo возвращает сообщения чата от старых к новым, не новее last (все, если last == nil).
+// Вызывается под блокировкой.
+func (r *messageRepository) upTo(chatID int64, last *models.Message) []models.Message {
+	var messages []models.Message
+	for _, msg := range r.store.messages.rows {
+		if msg.ChatID != chatID {
+			continue
+		}
+		if last != nil && (msg.CreatedAt.After(last.CreatedAt) || (msg.CreatedAt.Equal(last.CreatedAt) && msg.ID > last.ID)) {
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+
+	return messages
+}
+
+// attachmentsOf возвращает вложения сообщения по возрастанию ID. Вызывается под блокировкой.
+func (r *messageRepository) attachmentsOf(messageID int64) []models.Attachment {
+	var attachments []models.Attachment
+	for _, attachment := range r.store.attachments.rows {
+		if attachment.MessageID == messageID {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].ID < attachments[j].ID
+	})
+
+	return attachments
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type moderationRepository struct {
+	store *Store
+}
+
+func NewModerationRepository(store *Store) repository.ModerationRepository {
+	return &moderationRepository{store: store}
+}
+
+// ListRules получает правила чата в порядке создания
+func (r *moderationRepository) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	rules := make([]models.ModerationRule, 0)
+	for _, rule := range r.store.moderationRules.rows {
+		if rule.ChatID == chatID {
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+
+	return rules, nil
+}
+
+// CreateRule добавляет правило. Если чата нет, возвращает ErrChatNotFound.
+func (r *moderationRepository) CreateRule(ctx context.Context, rule *models.ModerationRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[rule.ChatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	rule.ID = r.store.moderationRules.nextID()
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = time.Now()
+	}
+
+	stored := *rule
+	stored.Chat = nil
+	r.store.moderationRules.put(rule.ID, stored)
+
+	return nil
+}
+
+// DeleteRule удаляет правило чата
+func (r *moderationRepository) DeleteRule(ctx context.Context, chatID, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	rule, ok := r.store.moderationRules.rows[id]
+	if !ok || rule.ChatID != chatID {
+		return repository.ErrModerationRuleNotFound
+	}
+	r.store.moderationRules.remove(id)
+
+	return nil
+}
+
+// AddFlag ставит сообщение в очередь на проверку
+func (r *moderationRepository) AddFlag(ctx context.Context, flag *models.ModerationFlag) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.messages.rows[flag.MessageID]; !ok {
+		return repository.ErrMessageNotFound
+	}
+
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = time.Now()
+	}
+
+	stored := *flag
+	stored.Message, stored.Chat = nil, nil
+	r.store.moderationFlags.put(flag.MessageID, stored)
+
+	return nil
+}
+
+// GetFlag получает отметку сообщения, nil, nil если сообщение не на проверке
+func (r *moderationRepository) GetFlag(ctx context.Context, messageID int64) (*models.ModerationFlag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	flag, ok := r.store.moderationFlags.rows[messageID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &flag, nil
+}
+
+// ListFlagsByOwner получает очередь проверки по чатам владельца вместе с сообщениями
+func (r *moderationRepository) ListFlagsByOwner(ctx context.Context, ownerID int64) ([]models.ModerationFlag, error) {
+	return r.listFlags(ctx, func(flag models.ModerationFlag) bool {
+		chat, ok := r.store.chats.rows[flag.ChatID]
+		return ok && chat.OwnerID != nil && *chat.OwnerID == ownerID
+	})
+}
+
+// ListFlags получает всю очередь проверки вместе с сообщениями
+func (r *moderationRepository) ListFlags(ctx context.Context) ([]models.ModerationFlag, error) {
+	return r.listFlags(ctx, func(models.ModerationFlag) bool { return true })
+}
+
+// listFlags получает отметки, для которых match вернул true, старые первыми
+func (r *moderationRepository) listFlags(ctx context.Context, match func(models.ModerationFlag) bool) ([]models.ModerationFlag, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	flags := make([]models.ModerationFlag, 0)
+	for _, flag := range r.store.moderationFlags.rows {
+		if !match(flag) {
+			continue
+		}
+		if message, ok := r.store.messages.rows[flag.MessageID]; ok {
+			flag.Message = &message
+		}
+		flags = append(flags, flag)
+	}
+
+	sort.Slice(flags, func(i, j int) bool {
+		if !flags[i].CreatedAt.Equal(flags[j].CreatedAt) {
+			return flags[i].CreatedAt.Before(flags[j].CreatedAt)
+		}
+		return flags[i].MessageID < flags[j].MessageID
+	})
+
+	return flags, nil
+}
+
+// DeleteFlag убирает сообщение из очереди
+func (r *moderationRepository) DeleteFlag(ctx context.Context, messageID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.moderationFlags.rows[messageID]; !ok {
+		return repository.ErrFlagNotFound
+	}
+	r.store.moderationFlags.remove(messageID)
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type outboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) repository.OutboxRepository {
+	return &outboxRepository{store: store}
+}
+
+// Add записывает событие в outbox
+func (r *outboxRepository) Add(ctx context.Context, event *models.OutboxEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	event.ID = r.store.outbox.nextID()
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.store.outbox.put(event.ID, *event)
+
+	return nil
+}
+
+// ListAfter получает до limit событий, записанных после позиции (afterTxID, afterID).
+// Транзакции Store сериализованы, поэтому порядок ID совпадает с порядком фиксации,
+// а TxID событий всегда нулевой.
+func (r *outboxRepository) ListAfter(ctx context.Context, afterTxID, afterID int64, limit int) ([]models.OutboxEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	events := make([]models.OutboxEvent, 0)
+	for _, event := range r.store.outbox.rows {
+		if outboxAfter(event.TxID, event.ID, afterTxID, afterID) {
+			events = append(events, event)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	if limit >= 0 && len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+// Head возвращает позицию последнего записанного события
+func (r *outboxRepository) Head(ctx context.Context) (int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	var txID, id int64
+	for _, event := range r.store.outbox.rows {
+		if outboxAfter(event.TxID, event.ID, txID, id) {
+			txID, id = event.TxID, event.ID
+		}
+	}
+
+	return txID, id, nil
+}
+
+// GetCursor получает позицию приёмника sink. У нового приёмника курсора ещё нет:
+// ему доставляются все события, оставшиеся в outbox.
+func (r *outboxRepository) GetCursor(ctx context.Context, sink string) (*models.OutboxCursor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	id, ok := r.cursorID(sink)
+	if !ok {
+		return &models.OutboxCursor{Sink: sink}, nil
+	}
+
+	cursor := r.store.outboxCursors.rows[id]
+	return &cursor, nil
+}
+
+// SaveCursor сохраняет позицию приёмника и состояние его повторов
+func (r *outboxRepository) SaveCursor(ctx context.Context, cursor *models.OutboxCursor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	cursor.UpdatedAt = time.Now()
+	id, ok := r.cursorID(cursor.Sink)
+	if !ok {
+		id = r.store.outboxCursors.nextID()
+	}
+	r.store.outboxCursors.put(id, *cursor)
+
+	return nil
+}
+
+// Prune удаляет до limit событий старше before, которые уже доставлены всем приёмникам sinks,
+// начиная с самых старых, и возвращает, сколько удалено
+func (r *outboxRepository) Prune(ctx context.Context, sinks []string, before time.Time, limit int) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	var last models.OutboxCursor
+	for i, sink := range sinks {
+		id, ok := r.cursorID(sink)
+		if !ok {
+			return 0, nil
+		}
+		if cursor := r.store.outboxCursors.rows[id]; i == 0 || outboxAfter(last.LastTxID, last.LastEventID, cursor.LastTxID, cursor.LastEventID) {
+			last = cursor
+		}
+	}
+
+	var old []int64
+	for id, event := range r.store.outbox.rows {
+		if !outboxAfter(event.TxID, event.ID, last.LastTxID, last.LastEventID) && event.CreatedAt.Before(before) {
+			old = append(old, id)
+		}
+	}
+
+	sort.Slice(old, func(i, j int) bool { return old[i] < old[j] })
+	if len(old) > limit {
+		old = old[:limit]
+	}
+	for _, id := range old {
+		r.store.outbox.remove(id)
+	}
+
+	return int64(len(old)), nil
+}
+
+// cursorID находит суррогатный ID курсора приёмника. Вызывается под блокировкой.
+func (r *outboxRepository) cursorID(sink string) (int64, bool) {
+	for id, cursor := range r.store.outboxCursors.rows {
+		if cursor.Sink == sink {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// outboxAfter сообщает, идёт ли позиция (txID, id) после позиции (afterTxID, afterID)
+func outboxAfter(txID, id, afterTxID, afterID int64) bool {
+	return txID > afterTxID || txID == afterTxID && id > afterID
+}
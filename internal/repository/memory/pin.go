@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type pinRepository struct {
+	store *Store
+}
+
+func NewPinRepository(store *Store) repository.PinRepository {
+	return &pinRepository{store: store}
+}
+
+// Add закрепляет сообщение
+func (r *pinRepository) Add(ctx context.Context, pin *models.ChatPin) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.messages.rows[pin.MessageID]; !ok {
+		return repository.ErrMessageNotFound
+	}
+	if _, ok := r.store.pins.rows[pin.MessageID]; ok {
+		return repository.ErrAlreadyPinned
+	}
+
+	if pin.PinnedAt.IsZero() {
+		pin.PinnedAt = time.Now()
+	}
+
+	stored := *pin
+	stored.Message = nil
+	stored.Chat = nil
+	r.store.pins.put(pin.MessageID, stored)
+
+	return nil
+}
+
+// Remove открепляет сообщение чата
+func (r *pinRepository) Remove(ctx context.Context, chatID, messageID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	pin, ok := r.store.pins.rows[messageID]
+	if !ok || pin.ChatID != chatID {
+		return repository.ErrPinNotFound
+	}
+
+	r.store.pins.remove(messageID)
+	return nil
+}
+
+// ListByChat получает закреплённые сообщения чата, последние закреплённые первыми
+func (r *pinRepository) ListByChat(ctx context.Context, chatID int64) ([]models.ChatPin, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	now := time.Now()
+	pins := make([]models.ChatPin, 0)
+	for _, pin := range r.store.pins.rows {
+		msg, ok := r.store.messages.rows[pin.MessageID]
+		if pin.ChatID == chatID && !(ok && expired(msg, now)) {
+			pins = append(pins, pin)
+		}
+	}
+
+	sort.Slice(pins, func(i, j int) bool {
+		if !pins[i].PinnedAt.Equal(pins[j].PinnedAt) {
+			return pins[i].PinnedAt.After(pins[j].PinnedAt)
+		}
+		return pins[i].MessageID > pins[j].MessageID
+	})
+
+	for i := range pins {
+		if msg, ok := r.store.messages.rows[pins[i].MessageID]; ok {
+			pins[i].Message = &msg
+		}
+	}
+
+	return pins, nil
+}
+
+// CountByChat считает закреплённые сообщения чата
+func (r *pinRepository) CountByChat(ctx context.Context, chatID int64) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	var count int64
+	for _, pin := range r.store.pins.rows {
+		if pin.ChatID == chatID {
+			count++
+		}
+	}
+
+	return count, nil
+}
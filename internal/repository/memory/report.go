@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type reportRepository struct {
+	store *Store
+}
+
+func NewReportRepository(store *Store) repository.ReportRepository {
+	return &reportRepository{store: store}
+}
+
+// Create сохраняет жалобу
+func (r *reportRepository) Create(ctx context.Context, report *models.MessageReport) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if report.MessageID == nil {
+		return repository.ErrMessageNotFound
+	}
+	if _, ok := r.store.messages.rows[*report.MessageID]; !ok {
+		return repository.ErrMessageNotFound
+	}
+	for _, existing := range r.store.reports.rows {
+		if existing.MessageID != nil && *existing.MessageID == *report.MessageID && existing.ReporterID == report.ReporterID {
+			return repository.ErrAlreadyReported
+		}
+	}
+
+	report.ID = r.store.reports.nextID()
+	if report.Status == "" {
+		report.Status = models.ReportStatusOpen
+	}
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = time.Now()
+	}
+
+	stored := *report
+	stored.Message, stored.Chat, stored.Reporter = nil, nil, nil
+	r.store.reports.put(report.ID, stored)
+
+	return nil
+}
+
+// GetByID получает жалобу, nil, nil если её нет
+func (r *reportRepository) GetByID(ctx context.Context, id int64) (*models.MessageReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	report, ok := r.store.reports.rows[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &report, nil
+}
+
+// List получает жалобы со статусом status (пустой — с любым) с ID больше afterID,
+// старые первыми
+func (r *reportRepository) List(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	reports := make([]models.MessageReport, 0)
+	for _, report := range r.store.reports.rows {
+		if report.ID > afterID && (status == "" || report.Status == status) {
+			reports = append(reports, report)
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ID < reports[j].ID
+	})
+
+	if limit >= 0 && len(reports) > limit {
+		reports = reports[:limit]
+	}
+
+	return reports, nil
+}
+
+// Close закрывает открытую жалобу со статусом status
+func (r *reportRepository) Close(ctx context.Context, id int64, status, resolution string, closedAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	report, ok := r.store.reports.rows[id]
+	if !ok {
+		return repository.ErrReportNotFound
+	}
+	if report.Status != models.ReportStatusOpen {
+		return repository.ErrReportClosed
+	}
+
+	report.Status = status
+	report.Resolution = resolution
+	report.ResolvedAt = &closedAt
+	r.store.reports.put(id, report)
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type retentionRepository struct {
+	store *Store
+}
+
+func NewRetentionRepository(store *Store) repository.RetentionRepository {
+	return &retentionRepository{store: store}
+}
+
+// Get получает политику хранения чата, nil, nil если своей политики у чата нет
+func (r *retentionRepository) Get(ctx context.Context, chatID int64) (*models.RetentionPolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	policy, ok := r.store.retention.rows[chatID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &policy, nil
+}
+
+// Set создаёт или заменяет политику хранения чата
+func (r *retentionRepository) Set(ctx context.Context, policy *models.RetentionPolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[policy.ChatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	policy.UpdatedAt = time.Now()
+	stored := *policy
+	stored.Chat = nil
+	r.store.retention.put(policy.ChatID, stored)
+
+	return nil
+}
+
+// Delete удаляет политику чата
+func (r *retentionRepository) Delete(ctx context.Context, chatID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	r.store.retention.remove(chatID)
+	return nil
+}
+
+// List получает политики всех чатов по возрастанию ID чата
+func (r *retentionRepository) List(ctx context.Context) ([]models.RetentionPolicy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	policies := make([]models.RetentionPolicy, 0, len(r.store.retention.rows))
+	for _, policy := range r.store.retention.rows {
+		policies = append(policies, policy)
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].ChatID < policies[j].ChatID
+	})
+
+	return policies, nil
+}
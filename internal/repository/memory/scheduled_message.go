@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type scheduledMessageRepository struct {
+	store *Store
+}
+
+func NewScheduledMessageRepository(store *Store) repository.ScheduledMessageRepository {
+	return &scheduledMessageRepository{store: store}
+}
+
+// Create ставит сообщение в очередь. Если чата нет, возвращает ErrChatNotFound.
+func (r *scheduledMessageRepository) Create(ctx context.Context, message *models.ScheduledMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.chats.rows[message.ChatID]; !ok {
+		return repository.ErrChatNotFound
+	}
+
+	message.ID = r.store.scheduled.nextID()
+	now := time.Now()
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+	message.UpdatedAt = now
+
+	stored := *message
+	stored.Chat, stored.Author = nil, nil
+	r.store.scheduled.put(message.ID, stored)
+
+	return nil
+}
+
+// GetByID получает отложенное сообщение, nil, nil если его нет
+func (r *scheduledMessageRepository) GetByID(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	message, ok := r.store.scheduled.rows[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &message, nil
+}
+
+// GetForUpdate получает отложенное сообщение как GetByID. Транзакции Store
+// сериализуются, поэтому отдельная блокировка строки не нужна.
+func (r *scheduledMessageRepository) GetForUpdate(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	return r.GetByID(ctx, id)
+}
+
+// ListByAuthor получает отложенные сообщения автора в чате, ближайшие первыми
+func (r *scheduledMessageRepository) ListByAuthor(ctx context.Context, chatID, authorID int64) ([]models.ScheduledMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	messages := make([]models.ScheduledMessage, 0)
+	for _, message := range r.store.scheduled.rows {
+		if message.ChatID == chatID && message.AuthorID == authorID {
+			messages = append(messages, message)
+		}
+	}
+
+	sortBySendAt(messages)
+	return messages, nil
+}
+
+// Update сохраняет текст, формат, срок жизни, время отправки и состояние попыток
+func (r *scheduledMessageRepository) Update(ctx context.Context, message *models.ScheduledMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	stored, ok := r.store.scheduled.rows[message.ID]
+	if !ok {
+		return repository.ErrScheduledMessageNotFound
+	}
+
+	message.UpdatedAt = time.Now()
+	stored.Text = message.Text
+	stored.Format = message.Format
+	stored.ExpiresIn = message.ExpiresIn
+	stored.SendAt = message.SendAt
+	stored.Attempts = message.Attempts
+	stored.LastError = message.LastError
+	stored.FailedAt = message.FailedAt
+	stored.ClaimedUntil = message.ClaimedUntil
+	stored.UpdatedAt = message.UpdatedAt
+	r.store.scheduled.put(message.ID, stored)
+
+	return nil
+}
+
+// Delete убирает сообщение из очереди
+func (r *scheduledMessageRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.scheduled.rows[id]; !ok {
+		return repository.ErrScheduledMessageNotFound
+	}
+	r.store.scheduled.remove(id)
+
+	return nil
+}
+
+// ClaimDue захватывает до limit сообщений, которым пора уйти (кроме брошенных и уже захваченных),
+// до момента until
+func (r *scheduledMessageRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.ScheduledMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	messages := make([]models.ScheduledMessage, 0)
+	for _, message := range r.store.scheduled.rows {
+		claimed := message.ClaimedUntil != nil && message.ClaimedUntil.After(now)
+		if message.FailedAt == nil && !claimed && !message.SendAt.After(now) {
+			messages = append(messages, message)
+		}
+	}
+
+	sortBySendAt(messages)
+	if len(messages) > limit {
+		messages = messages[:limit]
+	}
+	for i := range messages {
+		messages[i].ClaimedUntil = &until
+		r.store.scheduled.put(messages[i].ID, messages[i])
+	}
+
+	return messages, nil
+}
+
+func sortBySendAt(messages []models.ScheduledMessage) {
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].SendAt.Equal(messages[j].SendAt) {
+			return messages[i].SendAt.Before(messages[j].SendAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+}
@@ -0,0 +1,222 @@
+// Package memory содержит потокобезопасные in-memory реализации репозиториев.
+// Данные живут только в памяти процесса, поэтому пакет подходит для тестов и
+// локальных демо (--storage=memory), но не для продакшена.
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+// Store — общее хранилище для всех in-memory репозиториев.
+// Один Store нужно передавать во все репозитории, чтобы они видели одни и те же данные
+// (например, каскадное удаление сообщений при удалении чата).
+type Store struct {
+	mu sync.RWMutex
+	// undo — журнал отката открытой транзакции, nil вне транзакции
+	undo []func()
+
+	chats      *table[models.Chat]
+	messages   *table[models.Message]
+	outbox     *table[models.OutboxEvent]
+	webhooks   *table[models.Webhook]
+	deliveries *table[models.WebhookDelivery]
+	users      *table[models.User]
+	// incoming — входящие вебхуки
+	incoming    *table[models.IncomingWebhook]
+	attachments *table[models.Attachment]
+	// linkPreviews — кеш карточек ссылок, не привязан к чатам
+	linkPreviews *table[models.LinkPreview]
+	// members и mentions хранятся под суррогатными ID: в базе у них составной ключ
+	members  *table[models.ChatMember]
+	mentions *table[models.Mention]
+	// pins хранятся по ID сообщения
+	pins *table[models.ChatPin]
+	// retention — политики хранения по ID чата
+	retention *table[models.RetentionPolicy]
+	scheduled *table[models.ScheduledMessage]
+	// moderationRules — правила модерации чатов, moderationFlags — очередь проверки по ID сообщения
+	moderationRules *table[models.ModerationRule]
+	moderationFlags *table[models.ModerationFlag]
+	// blocks хранятся под суррогатными ID, как members
+	blocks  *table[models.UserBlock]
+	reports *table[models.MessageReport]
+	// audit не удаляется вместе с чатами
+	audit *table[models.AuditEntry]
+	// outboxCursors хранятся под суррогатными ID, в базе ключ — имя приёмника
+	outboxCursors *table[models.OutboxCursor]
+}
+
+func NewStore() *Store {
+	s := &Store{}
+	s.chats = newTable[models.Chat](s)
+	s.messages = newTable[models.Message](s)
+	s.outbox = newTable[models.OutboxEvent](s)
+	s.webhooks = newTable[models.Webhook](s)
+	s.deliveries = newTable[models.WebhookDelivery](s)
+	s.users = newTable[models.User](s)
+	s.incoming = newTable[models.IncomingWebhook](s)
+	s.attachments = newTable[models.Attachment](s)
+	s.linkPreviews = newTable[models.LinkPreview](s)
+	s.members = newTable[models.ChatMember](s)
+	s.mentions = newTable[models.Mention](s)
+	s.pins = newTable[models.ChatPin](s)
+	s.retention = newTable[models.RetentionPolicy](s)
+	s.scheduled = newTable[models.ScheduledMessage](s)
+	s.moderationRules = newTable[models.ModerationRule](s)
+	s.moderationFlags = newTable[models.ModerationFlag](s)
+	s.blocks = newTable[models.UserBlock](s)
+	s.reports = newTable[models.MessageReport](s)
+	s.audit = newTable[models.AuditEntry](s)
+	s.outboxCursors = newTable[models.OutboxCursor](s)
+	return s
+}
+
+// table — строки одной "таблицы" по первичному ключу и счётчик для следующего ID.
+// Строки меняются только через put и remove, чтобы транзакция могла их откатить.
+type table[T any] struct {
+	rows  map[int64]T
+	seq   int64
+	store *Store
+}
+
+func newTable[T any](s *Store) *table[T] {
+	return &table[T]{rows: make(map[int64]T), store: s}
+}
+
+// nextID выдаёт следующий ID, как BIGSERIAL. Счётчик при откате не возвращается —
+// так же ведут себя последовательности в PostgreSQL.
+func (t *table[T]) nextID() int64 {
+	t.seq++
+	return t.seq
+}
+
+// put сохраняет строку id
+func (t *table[T]) put(id int64, row T) {
+	t.remember(id)
+	t.rows[id] = row
+}
+
+// remove удаляет строку id
+func (t *table[T]) remove(id int64) {
+	t.remember(id)
+	delete(t.rows, id)
+}
+
+// remember записывает в журнал транзакции, как вернуть строку id к текущему состоянию.
+// Вне транзакции ничего не делает, поэтому откат стоит пропорционально числу изменений,
+// а не размеру хранилища.
+func (t *table[T]) remember(id int64) {
+	if t.store.undo == nil {
+		return
+	}
+	row, existed := t.rows[id]
+	t.store.undo = append(t.store.undo, func() {
+		if existed {
+			t.rows[id] = row
+		} else {
+			delete(t.rows, id)
+		}
+	})
+}
+
+// begin начинает журнал отката. Вызывается под блокировкой на запись.
+func (s *Store) begin() {
+	s.undo = make([]func(), 0)
+}
+
+// rollback отменяет изменения транзакции в обратном порядке, commit их оставляет.
+// Оба закрывают журнал.
+func (s *Store) rollback() {
+	for i := len(s.undo) - 1; i >= 0; i-- {
+		s.undo[i]()
+	}
+	s.undo = nil
+}
+
+func (s *Store) commit() {
+	s.undo = nil
+}
+
+type txKey struct{}
+
+// inTx сообщает, что ctx принадлежит транзакции, открытой на этом Store.
+// Внутри транзакции мьютекс уже захвачен TxManager'ом.
+func (s *Store) inTx(ctx context.Context) bool {
+	store, ok := ctx.Value(txKey{}).(*Store)
+	return ok && store == s
+}
+
+// lock захватывает Store на запись, если это не сделала транзакция
+func (s *Store) lock(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// rlock захватывает Store на чтение, если это не сделала транзакция
+func (s *Store) rlock(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// attachmentsOf возвращает вложения сообщения по порядку ID.
+// Вызывается под блокировкой.
+func (s *Store) attachmentsOf(messageID int64) []models.Attachment {
+	var attachments []models.Attachment
+	for _, attachment := range s.attachments.rows {
+		if attachment.MessageID == messageID {
+			attachments = append(attachments, attachment)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+	return attachments
+}
+
+// deleteMessage удаляет сообщение и всё, что ссылается на него внешними ключами
+// с ON DELETE CASCADE. Вызывается под блокировкой на запись.
+func (s *Store) deleteMessage(id int64) {
+	s.messages.remove(id)
+	for attachmentID, attachment := range s.attachments.rows {
+		if attachment.MessageID == id {
+			s.attachments.remove(attachmentID)
+		}
+	}
+	for key, mention := range s.mentions.rows {
+		if mention.MessageID == id {
+			s.mentions.remove(key)
+		}
+	}
+	s.pins.remove(id)
+	s.moderationFlags.remove(id)
+	// Жалобы переживают сообщение: ON DELETE SET NULL
+	for reportID, report := range s.reports.rows {
+		if report.MessageID != nil && *report.MessageID == id {
+			report.MessageID = nil
+			s.reports.put(reportID, report)
+		}
+	}
+}
+
+// blockedBy возвращает множество пользователей, заблокированных blockerID.
+// Вызывается под блокировкой.
+func (s *Store) blockedBy(blockerID int64) map[int64]bool {
+	blocked := make(map[int64]bool)
+	if blockerID == 0 {
+		return blocked
+	}
+	for _, block := range s.blocks.rows {
+		if block.BlockerID == blockerID {
+			blocked[block.BlockedID] = true
+		}
+	}
+	return blocked
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type txManager struct {
+	store *Store
+}
+
+func NewTxManager(store *Store) repository.TxManager {
+	return &txManager{store: store}
+}
+
+// WithinTransaction выполняет fn под эксклюзивной блокировкой Store.
+// Транзакции сериализуются, а при ошибке (или панике) изменённые строки
+// возвращаются по журналу отката.
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if m.store.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	m.store.begin()
+	defer func() {
+		if p := recover(); p != nil {
+			m.store.rollback()
+			panic(p)
+		}
+		if err != nil {
+			m.store.rollback()
+			return
+		}
+		m.store.commit()
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, m.store))
+}
@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{store: store}
+}
+
+// Create создаёт пользователя. Если имя занято, возвращает ErrUsernameTaken.
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	for _, existing := range r.store.users.rows {
+		if existing.Username == user.Username {
+			return repository.ErrUsernameTaken
+		}
+	}
+
+	user.ID = r.store.users.nextID()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	r.store.users.put(user.ID, *user)
+
+	return nil
+}
+
+// GetByID получает пользователя по ID, nil, nil если его нет
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	user, ok := r.store.users.rows[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &user, nil
+}
+
+// GetByUsername получает пользователя по имени, nil, nil если его нет
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	for _, user := range r.store.users.rows {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+
+	return nil, nil
+}
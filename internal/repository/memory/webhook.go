@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type webhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) repository.WebhookRepository {
+	return &webhookRepository{store: store}
+}
+
+// Create создаёт подписку
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	webhook.ID = r.store.webhooks.nextID()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+	r.store.webhooks.put(webhook.ID, cloneWebhook(*webhook))
+
+	return nil
+}
+
+// Delete удаляет подписку вместе с журналом доставок
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.webhooks.rows[id]; !ok {
+		return repository.ErrWebhookNotFound
+	}
+
+	r.store.webhooks.remove(id)
+	for deliveryID, delivery := range r.store.deliveries.rows {
+		if delivery.WebhookID == id {
+			r.store.deliveries.remove(deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// GetByID получает подписку по ID, nil, nil если её нет
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	webhook, ok := r.store.webhooks.rows[id]
+	if !ok {
+		return nil, nil
+	}
+
+	webhook = cloneWebhook(webhook)
+	return &webhook, nil
+}
+
+// List получает все подписки
+func (r *webhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	return r.list(ctx, false)
+}
+
+// ListActive получает включённые подписки
+func (r *webhookRepository) ListActive(ctx context.Context) ([]models.Webhook, error) {
+	return r.list(ctx, true)
+}
+
+func (r *webhookRepository) list(ctx context.Context, activeOnly bool) ([]models.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	webhooks := make([]models.Webhook, 0)
+	for _, webhook := range r.store.webhooks.rows {
+		if activeOnly && !webhook.Active {
+			continue
+		}
+		webhooks = append(webhooks, cloneWebhook(webhook))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return webhooks, nil
+}
+
+// SetActive включает или выключает подписку. Включение сбрасывает счётчик ошибок.
+func (r *webhookRepository) SetActive(ctx context.Context, id int64, active bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	webhook, ok := r.store.webhooks.rows[id]
+	if !ok {
+		return repository.ErrWebhookNotFound
+	}
+
+	webhook.Active = active
+	if active {
+		webhook.ConsecutiveFailures = 0
+		webhook.DisabledAt = nil
+	} else {
+		now := time.Now()
+		webhook.DisabledAt = &now
+	}
+	r.store.webhooks.put(id, webhook)
+
+	return nil
+}
+
+// RecordFailure увеличивает счётчик ошибок подряд и выключает подписку,
+// когда он достигает disableAfter
+func (r *webhookRepository) RecordFailure(ctx context.Context, id int64, disableAfter int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	webhook, ok := r.store.webhooks.rows[id]
+	if !ok {
+		return false, nil
+	}
+
+	webhook.ConsecutiveFailures++
+	disabled := false
+	if webhook.Active && webhook.ConsecutiveFailures >= disableAfter {
+		now := time.Now()
+		webhook.Active = false
+		webhook.DisabledAt = &now
+		disabled = true
+	}
+	r.store.webhooks.put(id, webhook)
+
+	return disabled, nil
+}
+
+// ResetFailures обнуляет счётчик ошибок подряд после успешной доставки
+func (r *webhookRepository) ResetFailures(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	webhook, ok := r.store.webhooks.rows[id]
+	if !ok {
+		return nil
+	}
+	webhook.ConsecutiveFailures = 0
+	r.store.webhooks.put(id, webhook)
+
+	return nil
+}
+
+// cloneWebhook копирует срез событий, чтобы вызывающий код не менял данные хранилища
+func cloneWebhook(webhook models.Webhook) models.Webhook {
+	webhook.Events = slices.Clone(webhook.Events)
+	return webhook
+}
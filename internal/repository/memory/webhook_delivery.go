@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+type webhookDeliveryRepository struct {
+	store *Store
+}
+
+func NewWebhookDeliveryRepository(store *Store) repository.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{store: store}
+}
+
+// Enqueue ставит доставку в очередь. Повторная постановка того же события
+// тому же вебхуку игнорируется.
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	for _, existing := range r.store.deliveries.rows {
+		if existing.WebhookID == delivery.WebhookID && existing.EventID == delivery.EventID {
+			return nil
+		}
+	}
+
+	delivery.ID = r.store.deliveries.nextID()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	stored := *delivery
+	stored.Webhook = nil
+	r.store.deliveries.put(delivery.ID, stored)
+
+	return nil
+}
+
+// ClaimDue захватывает до limit доставок, время очередной попытки которых наступило,
+// сдвигая NextAttemptAt на until
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.lock(ctx)()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, delivery := range r.store.deliveries.rows {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		if !deliveries[i].NextAttemptAt.Equal(deliveries[j].NextAttemptAt) {
+			return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+		}
+		return deliveries[i].ID < deliveries[j].ID
+	})
+
+	if limit >= 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	for i := range deliveries {
+		deliveries[i].NextAttemptAt = until
+		r.store.deliveries.put(deliveries[i].ID, deliveries[i])
+	}
+
+	return deliveries, nil
+}
+
+// Update сохраняет результат попытки доставки
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer r.store.lock(ctx)()
+
+	stored, ok := r.store.deliveries.rows[delivery.ID]
+	if !ok {
+		return nil
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.ResponseCode = delivery.ResponseCode
+	stored.LastError = delivery.LastError
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.DeliveredAt = delivery.DeliveredAt
+	r.store.deliveries.put(delivery.ID, stored)
+
+	return nil
+}
+
+// ListByWebhook получает журнал последних доставок вебхука (новые первыми)
+func (r *webhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer r.store.rlock(ctx)()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, delivery := range r.store.deliveries.rows {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID > deliveries[j].ID
+	})
+
+	if limit >= 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
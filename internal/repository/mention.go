@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_mention_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository MentionRepository
+
+type MentionRepository interface {
+	Create(ctx context.Context, mentions []models.Mention) error
+	ListByUser(ctx context.Context, userID, beforeMessageID int64, unreadOnly bool, limit int) ([]models.Mention, error)
+	CountUnread(ctx context.Context, userID int64) (int64, error)
+	MarkRead(ctx context.Context, userID int64, messageIDs []int64) (int64, error)
+}
+
+type mentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+// Create сохраняет упоминания одного или нескольких сообщений
+func (r *mentionRepository) Create(ctx context.Context, mentions []models.Mention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&mentions).Error
+}
+
+// ListByUser получает упоминания пользователя вместе с сообщениями, новые первыми.
+// beforeMessageID > 0 — курсор: вернуть только упоминания в более старых сообщениях.
+func (r *mentionRepository) ListByUser(ctx context.Context, userID, beforeMessageID int64, unreadOnly bool, limit int) ([]models.Mention, error) {
+	query := conn(ctx, r.db).
+		Preload("Message").
+		Where("user_id = ?", userID)
+
+	if beforeMessageID > 0 {
+		query = query.Where("message_id < ?", beforeMessageID)
+	}
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var mentions []models.Mention
+	err := query.Order("message_id DESC").Limit(limit).Find(&mentions).Error
+	return mentions, err
+}
+
+// CountUnread считает непрочитанные упоминания пользователя
+func (r *mentionRepository) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.Mention{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead отмечает упоминания прочитанными и возвращает их число.
+// Пустой messageIDs означает все непрочитанные упоминания пользователя.
+func (r *mentionRepository) MarkRead(ctx context.Context, userID int64, messageIDs []int64) (int64, error) {
+	query := conn(ctx, r.db).
+		Model(&models.Mention{}).
+		Where("user_id = ? AND read_at IS NULL", userID)
+
+	if len(messageIDs) > 0 {
+		query = query.Where("message_id IN ?", messageIDs)
+	}
+
+	result := query.Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/GlebMoskalev/chat-golang/internal/models"
 )
@@ -12,9 +15,21 @@ import (
 
 type MessageRepository interface {
 	Create(ctx context.Context, message *models.Message) error
-	GetByChatID(ctx context.Context, chatID int64, limit int) ([]models.Message, error)
+	GetByChatID(ctx context.Context, chatID, viewerID int64, limit int) ([]models.Message, error)
+	GetByID(ctx context.Context, id int64) (*models.Message, error)
+	ListAfter(ctx context.Context, chatID int64, afterCreatedAt time.Time, afterID int64, limit int) ([]models.Message, error)
+	CreateBatch(ctx context.Context, messages []models.Message) error
+	ImportedKeys(ctx context.Context, keys []string) ([]string, error)
+	NthNewest(ctx context.Context, chatID int64, n int) (*models.Message, error)
+	CountUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64) (int64, error)
+	DeleteUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64, limit int) ([]models.Message, error)
+	DeleteExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error)
+	Delete(ctx context.Context, id int64) error
 }
 
+// messageBatchSize — сколько сообщений вставляется одним INSERT в CreateBatch
+const messageBatchSize = 500
+
 type messageRepository struct {
 	db *gorm.DB
 }
@@ -23,20 +38,206 @@ func NewMessageRepository(db *gorm.DB) MessageRepository {
 	return &messageRepository{db: db}
 }
 
-// Create создаёт сообщение
+// Create создаёт сообщение. Если чата уже нет (например, его удалили параллельно),
+// возвращает ErrChatNotFound вместо ошибки внешнего ключа.
 func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
-	return r.db.WithContext(ctx).Create(&message).Error
+	err := conn(ctx, r.db).Create(&message).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
 }
 
-// GetByChatID получает последние N сообщений чата
-func (r *messageRepository) GetByChatID(ctx context.Context, chatID int64, limit int) ([]models.Message, error) {
+// GetByChatID получает последние N сообщений чата вместе с вложениями.
+// Истёкшие исчезающие сообщения не возвращаются, даже если их ещё не удалили.
+// С ненулевым viewerID пропускаются сообщения авторов, которых он заблокировал.
+func (r *messageRepository) GetByChatID(ctx context.Context, chatID, viewerID int64, limit int) ([]models.Message, error) {
 	var messages []models.Message
 
-	err := r.db.WithContext(ctx).
+	query := conn(ctx, r.db).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
 		Where("chat_id = ?", chatID).
-		Order("created_at DESC").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	if viewerID != 0 {
+		blocked := conn(ctx, r.db).Model(&models.UserBlock{}).Select("blocked_id").Where("blocker_id = ?", viewerID)
+		query = query.Where("author_id IS NULL OR author_id NOT IN (?)", blocked)
+	}
+	err := query.
+		Order("created_at DESC, id DESC").
 		Limit(limit).
 		Find(&messages).Error
 
 	return messages, err
 }
+
+// GetByID получает сообщение без вложений, nil, nil если его нет или оно истекло
+func (r *messageRepository) GetByID(ctx context.Context, id int64) (*models.Message, error) {
+	var message models.Message
+	err := conn(ctx, r.db).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		First(&message, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// ListAfter получает до limit сообщений чата от старых к новым, идущих после сообщения
+// (afterCreatedAt, afterID), вместе с вложениями. Нулевой курсор означает начало чата.
+// Курсор по (created_at, id) позволяет пройти всю историю порциями по индексу idx_messages_chat_created.
+// Истёкшие исчезающие сообщения пропускаются.
+func (r *messageRepository) ListAfter(ctx context.Context, chatID int64, afterCreatedAt time.Time, afterID int64, limit int) ([]models.Message, error) {
+	var messages []models.Message
+
+	err := conn(ctx, r.db).
+		Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("chat_id = ?", chatID).
+		Where("created_at > ? OR (created_at = ? AND id > ?)", afterCreatedAt, afterCreatedAt, afterID).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&messages).Error
+
+	return messages, err
+}
+
+// CreateBatch вставляет сообщения пачками по messageBatchSize и заполняет их ID.
+// Заданный CreatedAt сохраняется как есть, нулевой заменяется текущим временем.
+// Если какого-то чата нет, возвращает ErrChatNotFound, если сообщение с таким ключом
+// импорта уже есть — ErrAlreadyImported.
+func (r *messageRepository) CreateBatch(ctx context.Context, messages []models.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	err := conn(ctx, r.db).CreateInBatches(messages, messageBatchSize).Error
+	switch translated := translateError(r.db, err); {
+	case errors.Is(translated, gorm.ErrForeignKeyViolated):
+		return ErrChatNotFound
+	case errors.Is(translated, gorm.ErrDuplicatedKey):
+		return ErrAlreadyImported
+	}
+	return err
+}
+
+// ImportedKeys возвращает те из ключей импорта, сообщения с которыми уже есть
+func (r *messageRepository) ImportedKeys(ctx context.Context, keys []string) ([]string, error) {
+	existing := make([]string, 0)
+	if len(keys) == 0 {
+		return existing, nil
+	}
+
+	err := conn(ctx, r.db).
+		Model(&models.Message{}).
+		Where("import_key IN ?", keys).
+		Pluck("import_key", &existing).Error
+
+	return existing, err
+}
+
+// NthNewest получает сообщение чата, перед которым есть ровно n более новых
+// (n = 0 — самое новое), nil, nil если сообщений не больше n
+func (r *messageRepository) NthNewest(ctx context.Context, chatID int64, n int) (*models.Message, error) {
+	var messages []models.Message
+	err := conn(ctx, r.db).
+		Where("chat_id = ?", chatID).
+		Order("created_at DESC, id DESC").
+		Offset(n).
+		Limit(1).
+		Find(&messages).Error
+	if err != nil || len(messages) == 0 {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// CountUpTo считает сообщения чата не новее сообщения (createdAt, id) включительно.
+// С id = 0 считаются сообщения строго старше createdAt.
+func (r *messageRepository) CountUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.Message{}).
+		Where("chat_id = ?", chatID).
+		Where("created_at < ? OR (created_at = ? AND id <= ?)", createdAt, createdAt, id).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteUpTo удаляет до limit самых старых сообщений чата не новее (createdAt, id) и возвращает
+// их ID и ChatID вместе с вложениями. Небольшие порции по индексу idx_messages_chat_created
+// не держат долгих блокировок.
+func (r *messageRepository) DeleteUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64, limit int) ([]models.Message, error) {
+	oldest := conn(ctx, r.db).
+		Model(&models.Message{}).
+		Where("chat_id = ?", chatID).
+		Where("created_at < ? OR (created_at = ? AND id <= ?)", createdAt, createdAt, id).
+		Order("created_at ASC, id ASC").
+		Limit(limit)
+
+	return deleteMessages(conn(ctx, r.db), oldest)
+}
+
+// DeleteExpired удаляет до limit исчезающих сообщений, истёкших к now, и возвращает
+// их ID и ChatID вместе с вложениями. Поиск идёт по частичному индексу idx_messages_expires_at.
+func (r *messageRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error) {
+	expired := conn(ctx, r.db).
+		Model(&models.Message{}).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Order("expires_at ASC").
+		Limit(limit)
+
+	return deleteMessages(conn(ctx, r.db), expired)
+}
+
+// deleteMessages удаляет сообщения, отобранные запросом query, и возвращает их ID и ChatID.
+// Строки вложений уходят каскадом, поэтому они читаются заранее: файлы в хранилище
+// по их ключам удаляет вызывающий.
+func deleteMessages(db *gorm.DB, query *gorm.DB) ([]models.Message, error) {
+	var ids []int64
+	if err := query.Pluck("id", &ids).Error; err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var attachments []models.Attachment
+	if err := db.Where("message_id IN ?", ids).Order("id ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+
+	var deleted []models.Message
+	err := db.
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "chat_id"}}}).
+		Where("id IN ?", ids).
+		Delete(&deleted).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byMessage := make(map[int64][]models.Attachment)
+	for _, attachment := range attachments {
+		byMessage[attachment.MessageID] = append(byMessage[attachment.MessageID], attachment)
+	}
+	for i := range deleted {
+		deleted[i].Attachments = byMessage[deleted[i].ID]
+	}
+
+	return deleted, nil
+}
+
+// Delete удаляет сообщение вместе с вложениями, упоминаниями и закрепами (каскадом).
+// Если сообщения нет, возвращает ErrMessageNotFound.
+func (r *messageRepository) Delete(ctx context.Context, id int64) error {
+	result := conn(ctx, r.db).Delete(&models.Message{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
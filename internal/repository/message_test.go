@@ -59,7 +59,7 @@ func TestMessageRepository_GetByChatID(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	found, err := msgRepo.GetByChatID(ctx, chat.ID, 10)
+	found, err := msgRepo.GetByChatID(ctx, chat.ID, 0, 10)
 	assert.NoError(t, err)
 	assert.Len(t, found, 3)
 
@@ -91,7 +91,7 @@ func TestMessageRepository_GetByChatID_WithLimit(t *testing.T) {
 		require.NoError(t, err)
 	}
 
-	found, err := msgRepo.GetByChatID(ctx, chat.ID, 2)
+	found, err := msgRepo.GetByChatID(ctx, chat.ID, 0, 2)
 	assert.NoError(t, err)
 	assert.Len(t, found, 2)
 }
@@ -109,7 +109,7 @@ func TestMessageRepository_GetByChatID_EmptyChat(t *testing.T) {
 	err := chatRepo.Create(ctx, chat)
 	require.NoError(t, err)
 
-	found, err := msgRepo.GetByChatID(ctx, chat.ID, 10)
+	found, err := msgRepo.GetByChatID(ctx, chat.ID, 0, 10)
 	assert.NoError(t, err)
 	assert.Empty(t, found)
 }
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: BlockRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_block_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository BlockRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockRepository is a mock of BlockRepository interface.
+type MockBlockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockRepositoryMockRecorder is the mock recorder for MockBlockRepository.
+type MockBlockRepositoryMockRecorder struct {
+	mock *MockBlockRepository
+}
+
+// NewMockBlockRepository creates a new mock instance.
+func NewMockBlockRepository(ctrl *gomock.Controller) *MockBlockRepository {
+	mock := &MockBlockRepository{ctrl: ctrl}
+	mock.recorder = &MockBlockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockRepository) EXPECT() *MockBlockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockBlockRepository) Block(ctx context.Context, blockerID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockBlockRepositoryMockRecorder) Block(ctx, blockerID, blockedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockBlockRepository)(nil).Block), ctx, blockerID, blockedID)
+}
+
+// ListBlocked mocks base method.
+func (m *MockBlockRepository) ListBlocked(ctx context.Context, blockerID int64) ([]models.UserBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlocked", ctx, blockerID)
+	ret0, _ := ret[0].([]models.UserBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlocked indicates an expected call of ListBlocked.
+func (mr *MockBlockRepositoryMockRecorder) ListBlocked(ctx, blockerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlocked", reflect.TypeOf((*MockBlockRepository)(nil).ListBlocked), ctx, blockerID)
+}
+
+// Unblock mocks base method.
+func (m *MockBlockRepository) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, blockerID, blockedID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockBlockRepositoryMockRecorder) Unblock(ctx, blockerID, blockedID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockBlockRepository)(nil).Unblock), ctx, blockerID, blockedID)
+}
@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: ChatMemberRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_chat_member_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ChatMemberRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockChatMemberRepository is a mock of ChatMemberRepository interface.
+type MockChatMemberRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockChatMemberRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockChatMemberRepositoryMockRecorder is the mock recorder for MockChatMemberRepository.
+type MockChatMemberRepositoryMockRecorder struct {
+	mock *MockChatMemberRepository
+}
+
+// NewMockChatMemberRepository creates a new mock instance.
+func NewMockChatMemberRepository(ctrl *gomock.Controller) *MockChatMemberRepository {
+	mock := &MockChatMemberRepository{ctrl: ctrl}
+	mock.recorder = &MockChatMemberRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChatMemberRepository) EXPECT() *MockChatMemberRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockChatMemberRepository) Add(ctx context.Context, chatID, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, chatID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockChatMemberRepositoryMockRecorder) Add(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockChatMemberRepository)(nil).Add), ctx, chatID, userID)
+}
+
+// IsMember mocks base method.
+func (m *MockChatMemberRepository) IsMember(ctx context.Context, chatID, userID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsMember", ctx, chatID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsMember indicates an expected call of IsMember.
+func (mr *MockChatMemberRepositoryMockRecorder) IsMember(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMember", reflect.TypeOf((*MockChatMemberRepository)(nil).IsMember), ctx, chatID, userID)
+}
+
+// ListChatIDs mocks base method.
+func (m *MockChatMemberRepository) ListChatIDs(ctx context.Context, userID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChatIDs", ctx, userID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChatIDs indicates an expected call of ListChatIDs.
+func (mr *MockChatMemberRepositoryMockRecorder) ListChatIDs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChatIDs", reflect.TypeOf((*MockChatMemberRepository)(nil).ListChatIDs), ctx, userID)
+}
+
+// ListChats mocks base method.
+func (m *MockChatMemberRepository) ListChats(ctx context.Context, userID int64) ([]models.ChatSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, userID)
+	ret0, _ := ret[0].([]models.ChatSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockChatMemberRepositoryMockRecorder) ListChats(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockChatMemberRepository)(nil).ListChats), ctx, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockChatMemberRepository) ListMembers(ctx context.Context, chatID int64) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, chatID)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockChatMemberRepositoryMockRecorder) ListMembers(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockChatMemberRepository)(nil).ListMembers), ctx, chatID)
+}
+
+// ListReadStates mocks base method.
+func (m *MockChatMemberRepository) ListReadStates(ctx context.Context, chatID int64) ([]models.ChatMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReadStates", ctx, chatID)
+	ret0, _ := ret[0].([]models.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReadStates indicates an expected call of ListReadStates.
+func (mr *MockChatMemberRepositoryMockRecorder) ListReadStates(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReadStates", reflect.TypeOf((*MockChatMemberRepository)(nil).ListReadStates), ctx, chatID)
+}
+
+// MarkRead mocks base method.
+func (m *MockChatMemberRepository) MarkRead(ctx context.Context, chatID, userID, messageID int64, createdAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, chatID, userID, messageID, createdAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockChatMemberRepositoryMockRecorder) MarkRead(ctx, chatID, userID, messageID, createdAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockChatMemberRepository)(nil).MarkRead), ctx, chatID, userID, messageID, createdAt)
+}
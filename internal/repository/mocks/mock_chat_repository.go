@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -destination=internal/repository/mocks/mock_chat_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ChatRepository
+//	mockgen -destination=mocks/mock_chat_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ChatRepository
 //
 
 // Package mocks is a generated GoMock package.
@@ -84,6 +84,21 @@ func (mr *MockChatRepositoryMockRecorder) Exists(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockChatRepository)(nil).Exists), ctx, id)
 }
 
+// GetByDMKey mocks base method.
+func (m *MockChatRepository) GetByDMKey(ctx context.Context, key string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByDMKey", ctx, key)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByDMKey indicates an expected call of GetByDMKey.
+func (mr *MockChatRepositoryMockRecorder) GetByDMKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByDMKey", reflect.TypeOf((*MockChatRepository)(nil).GetByDMKey), ctx, key)
+}
+
 // GetByID mocks base method.
 func (m *MockChatRepository) GetByID(ctx context.Context, id int64) (*models.Chat, error) {
 	m.ctrl.T.Helper()
@@ -98,3 +113,33 @@ func (mr *MockChatRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockChatRepository)(nil).GetByID), ctx, id)
 }
+
+// GetByImportKey mocks base method.
+func (m *MockChatRepository) GetByImportKey(ctx context.Context, key string) (*models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByImportKey", ctx, key)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByImportKey indicates an expected call of GetByImportKey.
+func (mr *MockChatRepositoryMockRecorder) GetByImportKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByImportKey", reflect.TypeOf((*MockChatRepository)(nil).GetByImportKey), ctx, key)
+}
+
+// ListIDs mocks base method.
+func (m *MockChatRepository) ListIDs(ctx context.Context, afterID int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIDs", ctx, afterID, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIDs indicates an expected call of ListIDs.
+func (mr *MockChatRepositoryMockRecorder) ListIDs(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIDs", reflect.TypeOf((*MockChatRepository)(nil).ListIDs), ctx, afterID, limit)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: IncomingWebhookRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_incoming_webhook_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository IncomingWebhookRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIncomingWebhookRepository is a mock of IncomingWebhookRepository interface.
+type MockIncomingWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomingWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIncomingWebhookRepositoryMockRecorder is the mock recorder for MockIncomingWebhookRepository.
+type MockIncomingWebhookRepositoryMockRecorder struct {
+	mock *MockIncomingWebhookRepository
+}
+
+// NewMockIncomingWebhookRepository creates a new mock instance.
+func NewMockIncomingWebhookRepository(ctrl *gomock.Controller) *MockIncomingWebhookRepository {
+	mock := &MockIncomingWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockIncomingWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomingWebhookRepository) EXPECT() *MockIncomingWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIncomingWebhookRepository) Create(ctx context.Context, hook *models.IncomingWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, hook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) Create(ctx, hook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).Create), ctx, hook)
+}
+
+// Delete mocks base method.
+func (m *MockIncomingWebhookRepository) Delete(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) Delete(ctx, chatID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).Delete), ctx, chatID, id)
+}
+
+// GetByTokenHash mocks base method.
+func (m *MockIncomingWebhookRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*models.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTokenHash indicates an expected call of GetByTokenHash.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) GetByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTokenHash", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).GetByTokenHash), ctx, tokenHash)
+}
+
+// ListByChat mocks base method.
+func (m *MockIncomingWebhookRepository) ListByChat(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByChat", ctx, chatID)
+	ret0, _ := ret[0].([]models.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByChat indicates an expected call of ListByChat.
+func (mr *MockIncomingWebhookRepositoryMockRecorder) ListByChat(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByChat", reflect.TypeOf((*MockIncomingWebhookRepository)(nil).ListByChat), ctx, chatID)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: LinkPreviewRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_link_preview_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository LinkPreviewRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLinkPreviewRepository is a mock of LinkPreviewRepository interface.
+type MockLinkPreviewRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLinkPreviewRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLinkPreviewRepositoryMockRecorder is the mock recorder for MockLinkPreviewRepository.
+type MockLinkPreviewRepositoryMockRecorder struct {
+	mock *MockLinkPreviewRepository
+}
+
+// NewMockLinkPreviewRepository creates a new mock instance.
+func NewMockLinkPreviewRepository(ctrl *gomock.Controller) *MockLinkPreviewRepository {
+	mock := &MockLinkPreviewRepository{ctrl: ctrl}
+	mock.recorder = &MockLinkPreviewRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLinkPreviewRepository) EXPECT() *MockLinkPreviewRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByURL mocks base method.
+func (m *MockLinkPreviewRepository) GetByURL(ctx context.Context, url string) (*models.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByURL", ctx, url)
+	ret0, _ := ret[0].(*models.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByURL indicates an expected call of GetByURL.
+func (mr *MockLinkPreviewRepositoryMockRecorder) GetByURL(ctx, url any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByURL", reflect.TypeOf((*MockLinkPreviewRepository)(nil).GetByURL), ctx, url)
+}
+
+// GetByURLs mocks base method.
+func (m *MockLinkPreviewRepository) GetByURLs(ctx context.Context, urls []string) ([]models.LinkPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByURLs", ctx, urls)
+	ret0, _ := ret[0].([]models.LinkPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByURLs indicates an expected call of GetByURLs.
+func (mr *MockLinkPreviewRepositoryMockRecorder) GetByURLs(ctx, urls any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByURLs", reflect.TypeOf((*MockLinkPreviewRepository)(nil).GetByURLs), ctx, urls)
+}
+
+// Upsert mocks base method.
+func (m *MockLinkPreviewRepository) Upsert(ctx context.Context, preview *models.LinkPreview) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, preview)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockLinkPreviewRepositoryMockRecorder) Upsert(ctx, preview any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockLinkPreviewRepository)(nil).Upsert), ctx, preview)
+}
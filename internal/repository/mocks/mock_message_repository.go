@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -destination=internal/repository/mocks/mock_message_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository MessageRepository
+//	mockgen -destination=mocks/mock_message_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository MessageRepository
 //
 
 // Package mocks is a generated GoMock package.
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	models "github.com/GlebMoskalev/chat-golang/internal/models"
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +42,21 @@ func (m *MockMessageRepository) EXPECT() *MockMessageRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountUpTo mocks base method.
+func (m *MockMessageRepository) CountUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUpTo", ctx, chatID, createdAt, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUpTo indicates an expected call of CountUpTo.
+func (mr *MockMessageRepositoryMockRecorder) CountUpTo(ctx, chatID, createdAt, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUpTo", reflect.TypeOf((*MockMessageRepository)(nil).CountUpTo), ctx, chatID, createdAt, id)
+}
+
 // Create mocks base method.
 func (m *MockMessageRepository) Create(ctx context.Context, message *models.Message) error {
 	m.ctrl.T.Helper()
@@ -55,17 +71,135 @@ func (mr *MockMessageRepositoryMockRecorder) Create(ctx, message any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockMessageRepository)(nil).Create), ctx, message)
 }
 
+// CreateBatch mocks base method.
+func (m *MockMessageRepository) CreateBatch(ctx context.Context, messages []models.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockMessageRepositoryMockRecorder) CreateBatch(ctx, messages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockMessageRepository)(nil).CreateBatch), ctx, messages)
+}
+
+// Delete mocks base method.
+func (m *MockMessageRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMessageRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMessageRepository)(nil).Delete), ctx, id)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockMessageRepository) DeleteExpired(ctx context.Context, now time.Time, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockMessageRepositoryMockRecorder) DeleteExpired(ctx, now, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockMessageRepository)(nil).DeleteExpired), ctx, now, limit)
+}
+
+// DeleteUpTo mocks base method.
+func (m *MockMessageRepository) DeleteUpTo(ctx context.Context, chatID int64, createdAt time.Time, id int64, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUpTo", ctx, chatID, createdAt, id, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUpTo indicates an expected call of DeleteUpTo.
+func (mr *MockMessageRepositoryMockRecorder) DeleteUpTo(ctx, chatID, createdAt, id, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUpTo", reflect.TypeOf((*MockMessageRepository)(nil).DeleteUpTo), ctx, chatID, createdAt, id, limit)
+}
+
 // GetByChatID mocks base method.
-func (m *MockMessageRepository) GetByChatID(ctx context.Context, chatID int64, limit int) ([]models.Message, error) {
+func (m *MockMessageRepository) GetByChatID(ctx context.Context, chatID, viewerID int64, limit int) ([]models.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByChatID", ctx, chatID, limit)
+	ret := m.ctrl.Call(m, "GetByChatID", ctx, chatID, viewerID, limit)
 	ret0, _ := ret[0].([]models.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByChatID indicates an expected call of GetByChatID.
-func (mr *MockMessageRepositoryMockRecorder) GetByChatID(ctx, chatID, limit any) *gomock.Call {
+func (mr *MockMessageRepositoryMockRecorder) GetByChatID(ctx, chatID, viewerID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByChatID", reflect.TypeOf((*MockMessageRepository)(nil).GetByChatID), ctx, chatID, viewerID, limit)
+}
+
+// GetByID mocks base method.
+func (m *MockMessageRepository) GetByID(ctx context.Context, id int64) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockMessageRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockMessageRepository)(nil).GetByID), ctx, id)
+}
+
+// ImportedKeys mocks base method.
+func (m *MockMessageRepository) ImportedKeys(ctx context.Context, keys []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportedKeys", ctx, keys)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportedKeys indicates an expected call of ImportedKeys.
+func (mr *MockMessageRepositoryMockRecorder) ImportedKeys(ctx, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportedKeys", reflect.TypeOf((*MockMessageRepository)(nil).ImportedKeys), ctx, keys)
+}
+
+// ListAfter mocks base method.
+func (m *MockMessageRepository) ListAfter(ctx context.Context, chatID int64, afterCreatedAt time.Time, afterID int64, limit int) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAfter", ctx, chatID, afterCreatedAt, afterID, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAfter indicates an expected call of ListAfter.
+func (mr *MockMessageRepositoryMockRecorder) ListAfter(ctx, chatID, afterCreatedAt, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAfter", reflect.TypeOf((*MockMessageRepository)(nil).ListAfter), ctx, chatID, afterCreatedAt, afterID, limit)
+}
+
+// NthNewest mocks base method.
+func (m *MockMessageRepository) NthNewest(ctx context.Context, chatID int64, n int) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NthNewest", ctx, chatID, n)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NthNewest indicates an expected call of NthNewest.
+func (mr *MockMessageRepositoryMockRecorder) NthNewest(ctx, chatID, n any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByChatID", reflect.TypeOf((*MockMessageRepository)(nil).GetByChatID), ctx, chatID, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NthNewest", reflect.TypeOf((*MockMessageRepository)(nil).NthNewest), ctx, chatID, n)
 }
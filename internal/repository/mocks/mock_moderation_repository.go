@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: ModerationRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_moderation_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ModerationRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerationRepository is a mock of ModerationRepository interface.
+type MockModerationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationRepositoryMockRecorder is the mock recorder for MockModerationRepository.
+type MockModerationRepositoryMockRecorder struct {
+	mock *MockModerationRepository
+}
+
+// NewMockModerationRepository creates a new mock instance.
+func NewMockModerationRepository(ctrl *gomock.Controller) *MockModerationRepository {
+	mock := &MockModerationRepository{ctrl: ctrl}
+	mock.recorder = &MockModerationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationRepository) EXPECT() *MockModerationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddFlag mocks base method.
+func (m *MockModerationRepository) AddFlag(ctx context.Context, flag *models.ModerationFlag) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddFlag", ctx, flag)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddFlag indicates an expected call of AddFlag.
+func (mr *MockModerationRepositoryMockRecorder) AddFlag(ctx, flag any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFlag", reflect.TypeOf((*MockModerationRepository)(nil).AddFlag), ctx, flag)
+}
+
+// CreateRule mocks base method.
+func (m *MockModerationRepository) CreateRule(ctx context.Context, rule *models.ModerationRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockModerationRepositoryMockRecorder) CreateRule(ctx, rule any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockModerationRepository)(nil).CreateRule), ctx, rule)
+}
+
+// DeleteFlag mocks base method.
+func (m *MockModerationRepository) DeleteFlag(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFlag", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFlag indicates an expected call of DeleteFlag.
+func (mr *MockModerationRepositoryMockRecorder) DeleteFlag(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFlag", reflect.TypeOf((*MockModerationRepository)(nil).DeleteFlag), ctx, messageID)
+}
+
+// DeleteRule mocks base method.
+func (m *MockModerationRepository) DeleteRule(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockModerationRepositoryMockRecorder) DeleteRule(ctx, chatID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockModerationRepository)(nil).DeleteRule), ctx, chatID, id)
+}
+
+// GetFlag mocks base method.
+func (m *MockModerationRepository) GetFlag(ctx context.Context, messageID int64) (*models.ModerationFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFlag", ctx, messageID)
+	ret0, _ := ret[0].(*models.ModerationFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFlag indicates an expected call of GetFlag.
+func (mr *MockModerationRepositoryMockRecorder) GetFlag(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlag", reflect.TypeOf((*MockModerationRepository)(nil).GetFlag), ctx, messageID)
+}
+
+// ListFlags mocks base method.
+func (m *MockModerationRepository) ListFlags(ctx context.Context) ([]models.ModerationFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFlags", ctx)
+	ret0, _ := ret[0].([]models.ModerationFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlags indicates an expected call of ListFlags.
+func (mr *MockModerationRepositoryMockRecorder) ListFlags(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlags", reflect.TypeOf((*MockModerationRepository)(nil).ListFlags), ctx)
+}
+
+// ListFlagsByOwner mocks base method.
+func (m *MockModerationRepository) ListFlagsByOwner(ctx context.Context, ownerID int64) ([]models.ModerationFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFlagsByOwner", ctx, ownerID)
+	ret0, _ := ret[0].([]models.ModerationFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFlagsByOwner indicates an expected call of ListFlagsByOwner.
+func (mr *MockModerationRepositoryMockRecorder) ListFlagsByOwner(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFlagsByOwner", reflect.TypeOf((*MockModerationRepository)(nil).ListFlagsByOwner), ctx, ownerID)
+}
+
+// ListRules mocks base method.
+func (m *MockModerationRepository) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx, chatID)
+	ret0, _ := ret[0].([]models.ModerationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockModerationRepositoryMockRecorder) ListRules(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockModerationRepository)(nil).ListRules), ctx, chatID)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: PinRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_pin_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository PinRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPinRepository is a mock of PinRepository interface.
+type MockPinRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPinRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPinRepositoryMockRecorder is the mock recorder for MockPinRepository.
+type MockPinRepositoryMockRecorder struct {
+	mock *MockPinRepository
+}
+
+// NewMockPinRepository creates a new mock instance.
+func NewMockPinRepository(ctrl *gomock.Controller) *MockPinRepository {
+	mock := &MockPinRepository{ctrl: ctrl}
+	mock.recorder = &MockPinRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPinRepository) EXPECT() *MockPinRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockPinRepository) Add(ctx context.Context, pin *models.ChatPin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, pin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockPinRepositoryMockRecorder) Add(ctx, pin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockPinRepository)(nil).Add), ctx, pin)
+}
+
+// CountByChat mocks base method.
+func (m *MockPinRepository) CountByChat(ctx context.Context, chatID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByChat", ctx, chatID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByChat indicates an expected call of CountByChat.
+func (mr *MockPinRepositoryMockRecorder) CountByChat(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByChat", reflect.TypeOf((*MockPinRepository)(nil).CountByChat), ctx, chatID)
+}
+
+// ListByChat mocks base method.
+func (m *MockPinRepository) ListByChat(ctx context.Context, chatID int64) ([]models.ChatPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByChat", ctx, chatID)
+	ret0, _ := ret[0].([]models.ChatPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByChat indicates an expected call of ListByChat.
+func (mr *MockPinRepositoryMockRecorder) ListByChat(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByChat", reflect.TypeOf((*MockPinRepository)(nil).ListByChat), ctx, chatID)
+}
+
+// Remove mocks base method.
+func (m *MockPinRepository) Remove(ctx context.Context, chatID, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockPinRepositoryMockRecorder) Remove(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockPinRepository)(nil).Remove), ctx, chatID, messageID)
+}
@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/repository (interfaces: ScheduledMessageRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_scheduled_message_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ScheduledMessageRepository
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScheduledMessageRepository is a mock of ScheduledMessageRepository interface.
+type MockScheduledMessageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduledMessageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduledMessageRepositoryMockRecorder is the mock recorder for MockScheduledMessageRepository.
+type MockScheduledMessageRepositoryMockRecorder struct {
+	mock *MockScheduledMessageRepository
+}
+
+// NewMockScheduledMessageRepository creates a new mock instance.
+func NewMockScheduledMessageRepository(ctrl *gomock.Controller) *MockScheduledMessageRepository {
+	mock := &MockScheduledMessageRepository{ctrl: ctrl}
+	mock.recorder = &MockScheduledMessageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduledMessageRepository) EXPECT() *MockScheduledMessageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockScheduledMessageRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", ctx, now, until, limit)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockScheduledMessageRepositoryMockRecorder) ClaimDue(ctx, now, until, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockScheduledMessageRepository)(nil).ClaimDue), ctx, now, until, limit)
+}
+
+// Create mocks base method.
+func (m *MockScheduledMessageRepository) Create(ctx context.Context, message *models.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Create(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Create), ctx, message)
+}
+
+// Delete mocks base method.
+func (m *MockScheduledMessageRepository) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Delete), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockScheduledMessageRepository) GetByID(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockScheduledMessageRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockScheduledMessageRepository)(nil).GetByID), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockScheduledMessageRepository) GetForUpdate(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockScheduledMessageRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockScheduledMessageRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// ListByAuthor mocks base method.
+func (m *MockScheduledMessageRepository) ListByAuthor(ctx context.Context, chatID, authorID int64) ([]models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAuthor", ctx, chatID, authorID)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAuthor indicates an expected call of ListByAuthor.
+func (mr *MockScheduledMessageRepositoryMockRecorder) ListByAuthor(ctx, chatID, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAuthor", reflect.TypeOf((*MockScheduledMessageRepository)(nil).ListByAuthor), ctx, chatID, authorID)
+}
+
+// Update mocks base method.
+func (m *MockScheduledMessageRepository) Update(ctx context.Context, message *models.ScheduledMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockScheduledMessageRepositoryMockRecorder) Update(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockScheduledMessageRepository)(nil).Update), ctx, message)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_moderation_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ModerationRepository
+
+var (
+	ErrModerationRuleNotFound = errors.New("moderation rule not found")
+	ErrFlagNotFound           = errors.New("flag not found")
+)
+
+type ModerationRepository interface {
+	ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error)
+	CreateRule(ctx context.Context, rule *models.ModerationRule) error
+	DeleteRule(ctx context.Context, chatID, id int64) error
+
+	AddFlag(ctx context.Context, flag *models.ModerationFlag) error
+	GetFlag(ctx context.Context, messageID int64) (*models.ModerationFlag, error)
+	ListFlagsByOwner(ctx context.Context, ownerID int64) ([]models.ModerationFlag, error)
+	ListFlags(ctx context.Context) ([]models.ModerationFlag, error)
+	DeleteFlag(ctx context.Context, messageID int64) error
+}
+
+type moderationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository(db *gorm.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+// ListRules получает правила чата в порядке создания
+func (r *moderationRepository) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	rules := make([]models.ModerationRule, 0)
+	err := conn(ctx, r.db).
+		Where("chat_id = ?", chatID).
+		Order("id ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// CreateRule добавляет правило. Если чата нет, возвращает ErrChatNotFound.
+func (r *moderationRepository) CreateRule(ctx context.Context, rule *models.ModerationRule) error {
+	err := conn(ctx, r.db).Create(rule).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
+}
+
+// DeleteRule удаляет правило чата. Если его нет, возвращает ErrModerationRuleNotFound.
+func (r *moderationRepository) DeleteRule(ctx context.Context, chatID, id int64) error {
+	result := conn(ctx, r.db).
+		Where("chat_id = ? AND id = ?", chatID, id).
+		Delete(&models.ModerationRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrModerationRuleNotFound
+	}
+	return nil
+}
+
+// AddFlag ставит сообщение в очередь на проверку. Если сообщения нет, возвращает ErrMessageNotFound.
+func (r *moderationRepository) AddFlag(ctx context.Context, flag *models.ModerationFlag) error {
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = time.Now()
+	}
+
+	err := conn(ctx, r.db).Create(flag).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// GetFlag получает отметку сообщения, nil, nil если сообщение не на проверке
+func (r *moderationRepository) GetFlag(ctx context.Context, messageID int64) (*models.ModerationFlag, error) {
+	var flag models.ModerationFlag
+	err := conn(ctx, r.db).Where("message_id = ?", messageID).First(&flag).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &flag, nil
+}
+
+// ListFlagsByOwner получает очередь проверки по чатам владельца вместе с сообщениями,
+// старые первыми
+func (r *moderationRepository) ListFlagsByOwner(ctx context.Context, ownerID int64) ([]models.ModerationFlag, error) {
+	flags := make([]models.ModerationFlag, 0)
+	err := conn(ctx, r.db).
+		Preload("Message").
+		Joins("JOIN chats ON chats.id = moderation_flags.chat_id").
+		Where("chats.owner_id = ?", ownerID).
+		Order("moderation_flags.created_at ASC, moderation_flags.message_id ASC").
+		Find(&flags).Error
+	return flags, err
+}
+
+// ListFlags получает всю очередь проверки вместе с сообщениями, старые первыми
+func (r *moderationRepository) ListFlags(ctx context.Context) ([]models.ModerationFlag, error) {
+	flags := make([]models.ModerationFlag, 0)
+	err := conn(ctx, r.db).
+		Preload("Message").
+		Order("created_at ASC, message_id ASC").
+		Find(&flags).Error
+	return flags, err
+}
+
+// DeleteFlag убирает сообщение из очереди. Если его там нет, возвращает ErrFlagNotFound.
+func (r *moderationRepository) DeleteFlag(ctx context.Context, messageID int64) error {
+	result := conn(ctx, r.db).
+		Where("message_id = ?", messageID).
+		Delete(&models.ModerationFlag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrFlagNotFound
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_outbox_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository OutboxRepository
+
+type OutboxRepository interface {
+	Add(ctx context.Context, event *models.OutboxEvent) error
+	ListAfter(ctx context.Context, afterTxID, afterID int64, limit int) ([]models.OutboxEvent, error)
+	Head(ctx context.Context) (txID, id int64, err error)
+	GetCursor(ctx context.Context, sink string) (*models.OutboxCursor, error)
+	SaveCursor(ctx context.Context, cursor *models.OutboxCursor) error
+	Prune(ctx context.Context, sinks []string, before time.Time, limit int) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Add записывает событие в outbox (в транзакции из ctx, если она есть)
+func (r *outboxRepository) Add(ctx context.Context, event *models.OutboxEvent) error {
+	return conn(ctx, r.db).Create(event).Error
+}
+
+// ListAfter получает до limit событий, записанных после позиции (afterTxID, afterID),
+// в порядке фиксации их транзакций. События транзакций, которые ещё не завершились, не возвращаются:
+// они появятся позже, но после всего, что уже прочитано, поэтому курсор их не пропустит.
+func (r *outboxRepository) ListAfter(ctx context.Context, afterTxID, afterID int64, limit int) ([]models.OutboxEvent, error) {
+	var events []models.OutboxEvent
+
+	err := committed(conn(ctx, r.db)).
+		Where("tx_id > ? OR (tx_id = ? AND id > ?)", afterTxID, afterTxID, afterID).
+		Order("tx_id ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error
+
+	return events, err
+}
+
+// Head возвращает позицию последнего события завершённых транзакций: с неё начинает
+// приёмник, которому не нужны события, записанные до его запуска
+func (r *outboxRepository) Head(ctx context.Context) (int64, int64, error) {
+	var event models.OutboxEvent
+	err := committed(conn(ctx, r.db)).Order("tx_id DESC, id DESC").Take(&event).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, nil
+	}
+
+	return event.TxID, event.ID, err
+}
+
+// committed оставляет события транзакций старше самой старой незавершённой. В Postgres
+// ID событий и транзакций выдаются до фиксации, и транзакция с меньшим ID может стать видимой
+// позже; в SQLite записи сериализованы и tx_id не заполняется, поэтому ограничение не нужно.
+func committed(db *gorm.DB) *gorm.DB {
+	if db.Dialector.Name() != "postgres" {
+		return db
+	}
+	return db.Where("tx_id < pg_snapshot_xmin(pg_current_snapshot())::text::bigint")
+}
+
+// GetCursor получает позицию приёмника sink. У нового приёмника курсора ещё нет:
+// ему доставляются все события, оставшиеся в outbox.
+func (r *outboxRepository) GetCursor(ctx context.Context, sink string) (*models.OutboxCursor, error) {
+	var cursor models.OutboxCursor
+	err := conn(ctx, r.db).Where("sink = ?", sink).Take(&cursor).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.OutboxCursor{Sink: sink}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+// SaveCursor сохраняет позицию приёмника и состояние его повторов
+func (r *outboxRepository) SaveCursor(ctx context.Context, cursor *models.OutboxCursor) error {
+	cursor.UpdatedAt = time.Now()
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(cursor).Error
+}
+
+// Prune удаляет до limit событий старше before, которые уже доставлены всем приёмникам sinks,
+// и возвращает, сколько удалено. Пока у какого-то из приёмников нет курсора, ничего не удаляется.
+func (r *outboxRepository) Prune(ctx context.Context, sinks []string, before time.Time, limit int) (int64, error) {
+	db := conn(ctx, r.db)
+
+	// Самая отстающая позиция среди приёмников: всё до неё включительно доставлено всем
+	var delivered []models.OutboxCursor
+	err := db.Where("sink IN ?", sinks).
+		Order("last_tx_id ASC, last_event_id ASC").
+		Find(&delivered).Error
+	if err != nil || len(delivered) == 0 || len(delivered) < len(sinks) {
+		return 0, err
+	}
+	last := delivered[0]
+
+	old := db.
+		Model(&models.OutboxEvent{}).
+		Select("id").
+		Where("(tx_id < ? OR (tx_id = ? AND id <= ?)) AND created_at < ?", last.LastTxID, last.LastTxID, last.LastEventID, before).
+		Order("tx_id ASC, id ASC").
+		Limit(limit)
+
+	result := db.Where("id IN (?)", old).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
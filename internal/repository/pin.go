@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_pin_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository PinRepository
+
+var (
+	ErrAlreadyPinned = errors.New("message already pinned")
+	ErrPinNotFound   = errors.New("pin not found")
+)
+
+type PinRepository interface {
+	Add(ctx context.Context, pin *models.ChatPin) error
+	Remove(ctx context.Context, chatID, messageID int64) error
+	ListByChat(ctx context.Context, chatID int64) ([]models.ChatPin, error)
+	CountByChat(ctx context.Context, chatID int64) (int64, error)
+}
+
+type pinRepository struct {
+	db *gorm.DB
+}
+
+func NewPinRepository(db *gorm.DB) PinRepository {
+	return &pinRepository{db: db}
+}
+
+// Add закрепляет сообщение. Если оно уже закреплено, возвращает ErrAlreadyPinned,
+// если сообщения нет — ErrMessageNotFound.
+func (r *pinRepository) Add(ctx context.Context, pin *models.ChatPin) error {
+	if pin.PinnedAt.IsZero() {
+		pin.PinnedAt = time.Now()
+	}
+
+	err := conn(ctx, r.db).Create(pin).Error
+	switch translated := translateError(r.db, err); {
+	case errors.Is(translated, gorm.ErrDuplicatedKey):
+		return ErrAlreadyPinned
+	case errors.Is(translated, gorm.ErrForeignKeyViolated):
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// Remove открепляет сообщение чата. Если оно не закреплено, возвращает ErrPinNotFound.
+func (r *pinRepository) Remove(ctx context.Context, chatID, messageID int64) error {
+	result := conn(ctx, r.db).
+		Where("chat_id = ? AND message_id = ?", chatID, messageID).
+		Delete(&models.ChatPin{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPinNotFound
+	}
+	return nil
+}
+
+// ListByChat получает закреплённые сообщения чата, последние закреплённые первыми.
+// Закрепы истёкших исчезающих сообщений пропускаются.
+func (r *pinRepository) ListByChat(ctx context.Context, chatID int64) ([]models.ChatPin, error) {
+	var pins []models.ChatPin
+	err := conn(ctx, r.db).
+		Preload("Message").
+		Joins("JOIN messages ON messages.id = chat_pins.message_id").
+		Where("chat_pins.chat_id = ?", chatID).
+		Where("messages.expires_at IS NULL OR messages.expires_at > ?", time.Now()).
+		Order("chat_pins.pinned_at DESC, chat_pins.message_id DESC").
+		Find(&pins).Error
+	return pins, err
+}
+
+// CountByChat считает закреплённые сообщения чата
+func (r *pinRepository) CountByChat(ctx context.Context, chatID int64) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&models.ChatPin{}).
+		Where("chat_id = ?", chatID).
+		Count(&count).Error
+	return count, err
+}
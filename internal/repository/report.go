@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_report_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ReportRepository
+
+var (
+	ErrReportNotFound  = errors.New("report not found")
+	ErrAlreadyReported = errors.New("message already reported")
+	ErrReportClosed    = errors.New("report already closed")
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *models.MessageReport) error
+	GetByID(ctx context.Context, id int64) (*models.MessageReport, error)
+	List(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error)
+	Close(ctx context.Context, id int64, status, resolution string, closedAt time.Time) error
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// Create сохраняет жалобу. Если пользователь уже жаловался на это сообщение,
+// возвращает ErrAlreadyReported, если сообщения нет — ErrMessageNotFound.
+func (r *reportRepository) Create(ctx context.Context, report *models.MessageReport) error {
+	if report.Status == "" {
+		report.Status = models.ReportStatusOpen
+	}
+
+	err := conn(ctx, r.db).Create(report).Error
+	switch translated := translateError(r.db, err); {
+	case errors.Is(translated, gorm.ErrDuplicatedKey):
+		return ErrAlreadyReported
+	case errors.Is(translated, gorm.ErrForeignKeyViolated):
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+// GetByID получает жалобу, nil, nil если её нет
+func (r *reportRepository) GetByID(ctx context.Context, id int64) (*models.MessageReport, error) {
+	var report models.MessageReport
+	err := conn(ctx, r.db).First(&report, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// List получает жалобы со статусом status (пустой — с любым) с ID больше afterID,
+// старые первыми
+func (r *reportRepository) List(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error) {
+	reports := make([]models.MessageReport, 0)
+	query := conn(ctx, r.db).Where("id > ?", afterID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("id ASC").Limit(limit).Find(&reports).Error
+	return reports, err
+}
+
+// Close закрывает открытую жалобу со статусом status. Если жалобы нет, возвращает
+// ErrReportNotFound, если она уже закрыта — ErrReportClosed.
+func (r *reportRepository) Close(ctx context.Context, id int64, status, resolution string, closedAt time.Time) error {
+	result := conn(ctx, r.db).
+		Model(&models.MessageReport{}).
+		Where("id = ? AND status = ?", id, models.ReportStatusOpen).
+		Updates(map[string]any{"status": status, "resolution": resolution, "resolved_at": closedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := conn(ctx, r.db).Model(&models.MessageReport{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrReportNotFound
+	}
+	return ErrReportClosed
+}
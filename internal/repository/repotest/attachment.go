@@ -0,0 +1,92 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func createAttachment(t *testing.T, repos Repositories, message *models.Message, fileName string) *models.Attachment {
+	t.Helper()
+
+	attachment := &models.Attachment{
+		ChatID:      message.ChatID,
+		MessageID:   message.ID,
+		FileName:    fileName,
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		StorageKey:  "chats/" + fileName,
+	}
+	require.NoError(t, repos.Attachments.Create(context.Background(), attachment))
+	return attachment
+}
+
+func testAttachments(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "Files")
+	message := createMessage(t, repos, chat.ID, "report.txt", time.Now())
+	plain := createMessage(t, repos, chat.ID, "no files", time.Now().Add(time.Second))
+
+	first := createAttachment(t, repos, message, "report.txt")
+	second := createAttachment(t, repos, message, "notes.txt")
+	assert.NotZero(t, first.ID)
+	assert.False(t, first.CreatedAt.IsZero())
+
+	err := repos.Attachments.Create(ctx, &models.Attachment{
+		ChatID: chat.ID, MessageID: plain.ID + 1000, FileName: "ghost.txt", StorageKey: "chats/ghost.txt",
+	})
+	assert.ErrorIs(t, err, repository.ErrMessageNotFound)
+
+	found, err := repos.Attachments.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "report.txt", found.FileName)
+	assert.Equal(t, "chats/report.txt", found.StorageKey)
+	assert.Equal(t, int64(5), found.Size)
+
+	first.Width, first.Height = 1920, 1080
+	first.ThumbnailKey = first.StorageKey + "-thumb"
+	first.ThumbnailWidth, first.ThumbnailHeight = 320, 180
+	require.NoError(t, repos.Attachments.UpdateThumbnail(ctx, first))
+	assert.ErrorIs(t, repos.Attachments.UpdateThumbnail(ctx, &models.Attachment{ID: second.ID + 1000}), repository.ErrAttachmentNotFound)
+
+	messages, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Empty(t, messages[0].Attachments)
+	require.Len(t, messages[1].Attachments, 2)
+	assert.Equal(t, first.ID, messages[1].Attachments[0].ID)
+	assert.Equal(t, "chats/report.txt-thumb", messages[1].Attachments[0].ThumbnailKey)
+	assert.Equal(t, 1920, messages[1].Attachments[0].Width)
+	assert.Equal(t, 180, messages[1].Attachments[0].ThumbnailHeight)
+	assert.Equal(t, "report.txt", messages[1].Attachments[0].FileName, "UpdateThumbnail не должен трогать другие поля")
+	assert.Equal(t, second.ID, messages[1].Attachments[1].ID)
+
+	byMessage, err := repos.Attachments.ListByMessage(ctx, message.ID)
+	require.NoError(t, err)
+	require.Len(t, byMessage, 2)
+	assert.Equal(t, "chats/report.txt-thumb", byMessage[0].ThumbnailKey)
+	assert.Equal(t, second.ID, byMessage[1].ID)
+	byMessage, err = repos.Attachments.ListByMessage(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Empty(t, byMessage)
+
+	other := createChat(t, repos, "Other")
+	createAttachment(t, repos, createMessage(t, repos, other.ID, "чужое", time.Now()), "other.txt")
+	byChat, err := repos.Attachments.ListByChat(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, byChat, 2, "вложения других чатов не попадают")
+	assert.Equal(t, first.ID, byChat[0].ID)
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+	found, err = repos.Attachments.GetByID(ctx, first.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, found, "вложения удаляются вместе с чатом")
+}
@@ -0,0 +1,70 @@
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+func testAudit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	alice, bob := int64(1), int64(2)
+	chatID := int64(10)
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	entries := []*models.AuditEntry{
+		{ActorType: models.AuditActorUser, ActorID: &alice, Action: models.AuditMemberAdd, TargetType: "user", TargetID: bob,
+			ChatID: &chatID, After: json.RawMessage(`{"user_id":2}`), RequestID: "req-1", IP: "10.0.0.1", CreatedAt: base},
+		{ActorType: models.AuditActorUser, ActorID: &bob, Action: models.AuditRetentionUpdate, TargetType: "retention_policy", TargetID: chatID,
+			ChatID: &chatID, CreatedAt: base.Add(time.Minute)},
+		{ActorType: models.AuditActorUser, ActorID: &alice, Action: models.AuditChatDelete, TargetType: "chat", TargetID: chatID,
+			ChatID: &chatID, Before: json.RawMessage(`{"id":10,"title":"General"}`), CreatedAt: base.Add(2 * time.Minute)},
+		{ActorType: models.AuditActorAdmin, Action: models.AuditReportResolve, TargetType: "report", TargetID: 5, CreatedAt: base.Add(3 * time.Minute)},
+	}
+	for _, entry := range entries {
+		require.NoError(t, repos.Audit.Add(ctx, entry))
+		assert.NotZero(t, entry.ID)
+	}
+
+	all, err := repos.Audit.List(ctx, models.AuditFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, entries[3].ID, all[0].ID, "новые первыми")
+	assert.Nil(t, all[0].ActorID)
+
+	deleted := all[1]
+	assert.Equal(t, models.AuditChatDelete, deleted.Action)
+	assert.JSONEq(t, `{"id":10,"title":"General"}`, string(deleted.Before))
+	assert.Empty(t, deleted.After)
+
+	byActor, err := repos.Audit.List(ctx, models.AuditFilter{ActorID: &alice, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, byActor, 2)
+	assert.Equal(t, "req-1", byActor[1].RequestID)
+	assert.Equal(t, "10.0.0.1", byActor[1].IP)
+
+	byAction, err := repos.Audit.List(ctx, models.AuditFilter{Action: models.AuditRetentionUpdate, ChatID: &chatID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, byAction, 1)
+	assert.Equal(t, bob, *byAction[0].ActorID)
+
+	since, until := base.Add(time.Minute), base.Add(3*time.Minute)
+	window, err := repos.Audit.List(ctx, models.AuditFilter{Since: &since, Until: &until, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, window, 2)
+
+	page, err := repos.Audit.List(ctx, models.AuditFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	next, err := repos.Audit.List(ctx, models.AuditFilter{BeforeID: page[1].ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, next, 2)
+	assert.Equal(t, entries[0].ID, next[1].ID)
+}
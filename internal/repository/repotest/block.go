@@ -0,0 +1,135 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testBlocks(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+	carol := createUser(t, repos, "carol")
+
+	require.NoError(t, repos.Blocks.Block(ctx, alice.ID, bob.ID))
+	require.NoError(t, repos.Blocks.Block(ctx, alice.ID, bob.ID), "повторная блокировка")
+	assert.ErrorIs(t, repos.Blocks.Block(ctx, alice.ID, carol.ID+1000), repository.ErrUserNotFound)
+
+	chat := createChat(t, repos, "General")
+	now := time.Now().Truncate(time.Second)
+	for i, author := range []*models.User{alice, bob, carol} {
+		require.NoError(t, repos.Messages.Create(ctx, &models.Message{
+			ChatID: chat.ID, AuthorID: &author.ID, Text: author.Username, CreatedAt: now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+	require.NoError(t, repos.Messages.Create(ctx, &models.Message{ChatID: chat.ID, Text: "без автора", CreatedAt: now.Add(time.Minute)}))
+
+	texts := func(messages []models.Message) []string {
+		var out []string
+		for _, m := range messages {
+			out = append(out, m.Text)
+		}
+		return out
+	}
+
+	visible, err := repos.Messages.GetByChatID(ctx, chat.ID, alice.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"без автора", "carol", "alice"}, texts(visible))
+
+	all, err := repos.Messages.GetByChatID(ctx, chat.ID, bob.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, all, 4, "блокировка односторонняя")
+
+	// Лимит считается по видимым сообщениям
+	limited, err := repos.Messages.GetByChatID(ctx, chat.ID, alice.ID, 3)
+	require.NoError(t, err)
+	assert.Len(t, limited, 3)
+
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, alice.ID))
+	summaries, err := repos.Members.ListChats(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, int64(2), summaries[0].UnreadCount, "сообщения заблокированных не считаются непрочитанными")
+
+	blocks, err := repos.Blocks.ListBlocked(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, bob.ID, blocks[0].BlockedID)
+	require.NotNil(t, blocks[0].Blocked)
+	assert.Equal(t, "bob", blocks[0].Blocked.Username)
+
+	require.NoError(t, repos.Blocks.Unblock(ctx, alice.ID, bob.ID))
+	require.NoError(t, repos.Blocks.Unblock(ctx, alice.ID, bob.ID), "снятие несуществующей блокировки")
+	visible, err = repos.Messages.GetByChatID(ctx, chat.ID, alice.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, visible, 4)
+}
+
+func testReports(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+	chat := createChat(t, repos, "General")
+	message := &models.Message{ChatID: chat.ID, AuthorID: &bob.ID, Text: "спам", CreatedAt: time.Now()}
+	require.NoError(t, repos.Messages.Create(ctx, message))
+
+	report := &models.MessageReport{
+		MessageID: &message.ID, ChatID: chat.ID, ReporterID: alice.ID, AuthorID: &bob.ID,
+		Reason: models.ReportReasonSpam, Text: message.Text,
+	}
+	require.NoError(t, repos.Reports.Create(ctx, report))
+	assert.NotZero(t, report.ID)
+	assert.Equal(t, models.ReportStatusOpen, report.Status)
+
+	duplicate := &models.MessageReport{MessageID: &message.ID, ChatID: chat.ID, ReporterID: alice.ID, Reason: models.ReportReasonAbuse, Text: message.Text}
+	assert.ErrorIs(t, repos.Reports.Create(ctx, duplicate), repository.ErrAlreadyReported)
+	missing := message.ID + 1000
+	assert.ErrorIs(t, repos.Reports.Create(ctx, &models.MessageReport{MessageID: &missing, ChatID: chat.ID, ReporterID: alice.ID, Reason: models.ReportReasonSpam}),
+		repository.ErrMessageNotFound)
+
+	second := &models.MessageReport{MessageID: &message.ID, ChatID: chat.ID, ReporterID: bob.ID, Reason: models.ReportReasonOther, Text: message.Text}
+	require.NoError(t, repos.Reports.Create(ctx, second))
+
+	closedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, repos.Reports.Close(ctx, report.ID, models.ReportStatusResolved, "удалено", closedAt))
+	assert.ErrorIs(t, repos.Reports.Close(ctx, report.ID, models.ReportStatusDismissed, "", closedAt), repository.ErrReportClosed)
+	assert.ErrorIs(t, repos.Reports.Close(ctx, second.ID+1000, models.ReportStatusDismissed, "", closedAt), repository.ErrReportNotFound)
+
+	open, err := repos.Reports.List(ctx, models.ReportStatusOpen, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, second.ID, open[0].ID)
+
+	all, err := repos.Reports.List(ctx, "", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, models.ReportStatusResolved, all[0].Status)
+	assert.Equal(t, "удалено", all[0].Resolution)
+	require.NotNil(t, all[0].ResolvedAt)
+
+	page, err := repos.Reports.List(ctx, "", report.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+
+	// Удаление сообщения не теряет жалобы
+	require.NoError(t, repos.Messages.Delete(ctx, message.ID))
+	found, err := repos.Reports.GetByID(ctx, report.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Nil(t, found.MessageID)
+	assert.Equal(t, "спам", found.Text)
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+	found, err = repos.Reports.GetByID(ctx, report.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "жалобы удаляются вместе с чатом")
+}
@@ -0,0 +1,44 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testDirectChats(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	room := createChat(t, repos, "Room")
+	createChat(t, repos, "Another room")
+
+	found, err := repos.Chats.GetByID(ctx, room.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, models.ChatTypeRoom, found.Type, "тип по умолчанию — комната")
+
+	key := models.DMKey(7, 3)
+	assert.Equal(t, "3:7", key)
+
+	dm := &models.Chat{Type: models.ChatTypeDM, DMKey: &key}
+	require.NoError(t, repos.Chats.Create(ctx, dm))
+
+	duplicate := models.DMKey(3, 7)
+	assert.ErrorIs(t, repos.Chats.Create(ctx, &models.Chat{Type: models.ChatTypeDM, DMKey: &duplicate}), repository.ErrDMExists)
+
+	found, err = repos.Chats.GetByDMKey(ctx, key)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, dm.ID, found.ID)
+	assert.Equal(t, models.ChatTypeDM, found.Type)
+	assert.Empty(t, found.Title)
+
+	missing, err := repos.Chats.GetByDMKey(ctx, models.DMKey(1, 2))
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,92 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+func testMessageExpiry(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+
+	now := time.Now().Truncate(time.Second)
+	createWithExpiry := func(chatID int64, text string, expiresAt *time.Time) *models.Message {
+		message := &models.Message{ChatID: chatID, Text: text, CreatedAt: now.Add(-time.Hour), ExpiresAt: expiresAt}
+		require.NoError(t, repos.Messages.Create(ctx, message))
+		return message
+	}
+
+	past, earlier, future := now.Add(-time.Minute), now.Add(-2*time.Minute), now.Add(time.Hour)
+	regular := createWithExpiry(chat.ID, "обычное", nil)
+	gone := createWithExpiry(chat.ID, "истекло", &past)
+	alive := createWithExpiry(chat.ID, "ещё живо", &future)
+	foreign := createWithExpiry(other.ID, "истекло раньше", &earlier)
+	createAttachment(t, repos, gone, "expired.txt")
+
+	messages, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	assert.ElementsMatch(t, []int64{regular.ID, alive.ID}, ids, "истёкшие скрываются до удаления")
+
+	messages, err = repos.Messages.ListAfter(ctx, chat.ID, time.Time{}, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, messages, 2)
+
+	stored, err := repos.Messages.GetByID(ctx, gone.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "истёкшее сообщение не получить и по ID")
+
+	for _, message := range []*models.Message{gone, alive} {
+		require.NoError(t, repos.Pins.Add(ctx, &models.ChatPin{MessageID: message.ID, ChatID: chat.ID}))
+	}
+	pins, err := repos.Pins.ListByChat(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, pins, 1, "закреп истёкшего сообщения скрывается")
+	assert.Equal(t, alive.ID, pins[0].MessageID)
+
+	reader := createUser(t, repos, "reader")
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, reader.ID))
+	summaries, err := repos.Members.ListChats(ctx, reader.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, int64(2), summaries[0].UnreadCount, "истёкшие не считаются непрочитанными")
+
+	deleted, err := repos.Messages.DeleteExpired(ctx, now, 1)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1, "не больше limit за раз")
+	assert.Equal(t, foreign.ID, deleted[0].ID, "сначала самые давно истёкшие")
+	assert.Equal(t, other.ID, deleted[0].ChatID)
+
+	deleted, err = repos.Messages.DeleteExpired(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, gone.ID, deleted[0].ID)
+	assert.Equal(t, chat.ID, deleted[0].ChatID)
+	require.Len(t, deleted[0].Attachments, 1, "вложения возвращаются для уборки хранилища")
+	assert.Equal(t, "chats/expired.txt", deleted[0].Attachments[0].StorageKey)
+
+	deleted, err = repos.Messages.DeleteExpired(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	stored, err = repos.Messages.GetByID(ctx, gone.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "истёкшее сообщение удалено физически")
+
+	stored, err = repos.Messages.GetByID(ctx, alive.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	require.NotNil(t, stored.ExpiresAt)
+	assert.True(t, stored.ExpiresAt.Equal(future))
+}
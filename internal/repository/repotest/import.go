@@ -0,0 +1,68 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func importKey(key string) *string {
+	return &key
+}
+
+func testImport(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := &models.Chat{Title: "general", ImportKey: importKey("slack:C1")}
+	require.NoError(t, repos.Chats.Create(ctx, chat))
+	assert.ErrorIs(t, repos.Chats.Create(ctx, &models.Chat{Title: "general", ImportKey: importKey("slack:C1")}), repository.ErrAlreadyImported)
+
+	found, err := repos.Chats.GetByImportKey(ctx, "slack:C1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, chat.ID, found.ID)
+
+	missing, err := repos.Chats.GetByImportKey(ctx, "slack:C2")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	// Исходное время сообщений сохраняется
+	sentAt := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	editedAt := sentAt.Add(time.Hour)
+	messages := []models.Message{
+		{ChatID: chat.ID, Text: "first", CreatedAt: sentAt, EditedAt: &editedAt, ImportKey: importKey("slack:C1:1")},
+		{ChatID: chat.ID, Text: "second", CreatedAt: sentAt.Add(time.Minute), ImportKey: importKey("slack:C1:2")},
+	}
+	require.NoError(t, repos.Messages.CreateBatch(ctx, messages))
+	assert.NotZero(t, messages[0].ID)
+	assert.NotEqual(t, messages[0].ID, messages[1].ID)
+
+	stored, err := repos.Messages.GetByID(ctx, messages[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, sentAt.Equal(stored.CreatedAt), "created_at из архива, а не now()")
+	require.NotNil(t, stored.EditedAt)
+	assert.True(t, editedAt.Equal(*stored.EditedAt))
+
+	keys, err := repos.Messages.ImportedKeys(ctx, []string{"slack:C1:1", "slack:C1:3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slack:C1:1"}, keys)
+
+	err = repos.Messages.CreateBatch(ctx, []models.Message{{ChatID: chat.ID, Text: "again", ImportKey: importKey("slack:C1:2")}})
+	assert.ErrorIs(t, err, repository.ErrAlreadyImported)
+
+	err = repos.Messages.CreateBatch(ctx, []models.Message{{ChatID: chat.ID + 1000, Text: "orphan"}})
+	assert.ErrorIs(t, err, repository.ErrChatNotFound)
+
+	require.NoError(t, repos.Messages.CreateBatch(ctx, nil))
+
+	all, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Len(t, all, 2, "неудачные пачки ничего не вставили")
+}
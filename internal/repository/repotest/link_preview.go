@@ -0,0 +1,49 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+func testLinkPreviews(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	fetchedAt := time.Now().UTC().Truncate(time.Second)
+
+	failed := &models.LinkPreview{URL: "https://example.com/a", Status: models.LinkPreviewFailed, Error: "timeout", FetchedAt: fetchedAt}
+	require.NoError(t, repos.LinkPreviews.Upsert(ctx, failed))
+	require.NoError(t, repos.LinkPreviews.Upsert(ctx, &models.LinkPreview{
+		URL: "https://example.com/b", Title: "B", Status: models.LinkPreviewOK, FetchedAt: fetchedAt,
+	}))
+
+	// Повторная загрузка того же URL перезаписывает карточку
+	require.NoError(t, repos.LinkPreviews.Upsert(ctx, &models.LinkPreview{
+		URL: "https://example.com/a", Title: "A", Description: "Описание", Status: models.LinkPreviewOK, FetchedAt: fetchedAt.Add(time.Hour),
+	}))
+
+	found, err := repos.LinkPreviews.GetByURL(ctx, "https://example.com/a")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "A", found.Title)
+	assert.Equal(t, "Описание", found.Description)
+	assert.Equal(t, models.LinkPreviewOK, found.Status)
+	assert.Empty(t, found.Error)
+	assert.WithinDuration(t, fetchedAt.Add(time.Hour), found.FetchedAt, time.Second)
+
+	missing, err := repos.LinkPreviews.GetByURL(ctx, "https://example.com/missing")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	previews, err := repos.LinkPreviews.GetByURLs(ctx, []string{"https://example.com/a", "https://example.com/b", "https://example.com/missing"})
+	require.NoError(t, err)
+	assert.Len(t, previews, 2)
+
+	previews, err = repos.LinkPreviews.GetByURLs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, previews)
+}
@@ -0,0 +1,194 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testChatMembers(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, alice.ID))
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, bob.ID))
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, alice.ID), "повторное добавление не должно быть ошибкой")
+
+	assert.ErrorIs(t, repos.Members.Add(ctx, chat.ID+1000, alice.ID), repository.ErrChatNotFound)
+
+	isMember, err := repos.Members.IsMember(ctx, chat.ID, bob.ID)
+	require.NoError(t, err)
+	assert.True(t, isMember)
+
+	other := createChat(t, repos, "Other")
+	isMember, err = repos.Members.IsMember(ctx, other.ID, bob.ID)
+	require.NoError(t, err)
+	assert.False(t, isMember)
+
+	members, err := repos.Members.ListMembers(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, []string{members[0].Username, members[1].Username})
+
+	require.NoError(t, repos.Members.Add(ctx, other.ID, alice.ID))
+	ids, err := repos.Members.ListChatIDs(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{chat.ID, other.ID}, ids)
+	ids, err = repos.Members.ListChatIDs(ctx, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{chat.ID}, ids)
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+	members, err = repos.Members.ListMembers(ctx, chat.ID)
+	require.NoError(t, err)
+	assert.Empty(t, members, "участники удаляются вместе с чатом")
+
+	ids, err = repos.Members.ListChatIDs(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{other.ID}, ids)
+}
+
+func testMentions(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+
+	now := time.Now()
+	var messages []*models.Message
+	for i := 0; i < 3; i++ {
+		msg := createMessage(t, repos, chat.ID, "hi @alice", now.Add(time.Duration(i)*time.Second))
+		messages = append(messages, msg)
+		require.NoError(t, repos.Mentions.Create(ctx, []models.Mention{
+			{MessageID: msg.ID, UserID: alice.ID, ChatID: chat.ID},
+		}))
+	}
+	require.NoError(t, repos.Mentions.Create(ctx, []models.Mention{
+		{MessageID: messages[0].ID, UserID: bob.ID, ChatID: chat.ID},
+	}))
+	require.NoError(t, repos.Mentions.Create(ctx, nil))
+
+	mentions, err := repos.Mentions.ListByUser(ctx, alice.ID, 0, false, 2)
+	require.NoError(t, err)
+	require.Len(t, mentions, 2)
+	assert.Equal(t, messages[2].ID, mentions[0].MessageID, "новые упоминания первыми")
+	assert.Equal(t, messages[1].ID, mentions[1].MessageID)
+	require.NotNil(t, mentions[0].Message)
+	assert.Equal(t, "hi @alice", mentions[0].Message.Text)
+	assert.Nil(t, mentions[0].ReadAt)
+
+	mentions, err = repos.Mentions.ListByUser(ctx, alice.ID, messages[1].ID, false, 10)
+	require.NoError(t, err)
+	require.Len(t, mentions, 1)
+	assert.Equal(t, messages[0].ID, mentions[0].MessageID)
+
+	unread, err := repos.Mentions.CountUnread(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), unread)
+
+	updated, err := repos.Mentions.MarkRead(ctx, alice.ID, []int64{messages[2].ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	mentions, err = repos.Mentions.ListByUser(ctx, alice.ID, 0, true, 10)
+	require.NoError(t, err)
+	require.Len(t, mentions, 2)
+	assert.Equal(t, messages[1].ID, mentions[0].MessageID)
+
+	updated, err = repos.Mentions.MarkRead(ctx, alice.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	unread, err = repos.Mentions.CountUnread(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Zero(t, unread)
+
+	unread, err = repos.Mentions.CountUnread(ctx, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread, "отметки одного пользователя не трогают чужие упоминания")
+
+	mentions, err = repos.Mentions.ListByUser(ctx, alice.ID, 0, false, 10)
+	require.NoError(t, err)
+	require.Len(t, mentions, 3)
+	assert.NotNil(t, mentions[0].ReadAt)
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+	mentions, err = repos.Mentions.ListByUser(ctx, bob.ID, 0, false, 10)
+	require.NoError(t, err)
+	assert.Empty(t, mentions, "упоминания удаляются вместе с сообщениями")
+}
+
+func testReadState(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	quiet := createChat(t, repos, "Quiet")
+	foreign := createChat(t, repos, "Foreign")
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, alice.ID))
+	require.NoError(t, repos.Members.Add(ctx, chat.ID, bob.ID))
+	require.NoError(t, repos.Members.Add(ctx, quiet.ID, alice.ID))
+
+	base := time.Now().Truncate(time.Second)
+	var messages []*models.Message
+	for i, author := range []int64{bob.ID, bob.ID, alice.ID, bob.ID} {
+		msg := &models.Message{ChatID: chat.ID, AuthorID: &author, Text: "msg", CreatedAt: base.Add(time.Duration(i) * time.Second)}
+		require.NoError(t, repos.Messages.Create(ctx, msg))
+		messages = append(messages, msg)
+	}
+	createMessage(t, repos, foreign.ID, "не наш чат", base)
+
+	summaries, err := repos.Members.ListChats(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, chat.ID, summaries[0].ID)
+	assert.Equal(t, "General", summaries[0].Title)
+	assert.Equal(t, int64(3), summaries[0].UnreadCount, "свои сообщения не считаются непрочитанными")
+	assert.Nil(t, summaries[0].LastReadMessageID)
+	assert.Equal(t, quiet.ID, summaries[1].ID)
+	assert.Zero(t, summaries[1].UnreadCount)
+
+	require.NoError(t, repos.Members.MarkRead(ctx, chat.ID, alice.ID, messages[1].ID, messages[1].CreatedAt))
+	require.NoError(t, repos.Members.MarkRead(ctx, chat.ID, alice.ID, messages[0].ID, messages[0].CreatedAt),
+		"отметка не должна двигаться назад")
+
+	summaries, err = repos.Members.ListChats(ctx, alice.ID)
+	require.NoError(t, err)
+	require.NotNil(t, summaries[0].LastReadMessageID)
+	assert.Equal(t, messages[1].ID, *summaries[0].LastReadMessageID)
+	assert.Equal(t, int64(1), summaries[0].UnreadCount)
+
+	// Сообщения с одинаковым временем упорядочиваются по ID
+	same := &models.Message{ChatID: chat.ID, AuthorID: &bob.ID, Text: "same time", CreatedAt: messages[3].CreatedAt}
+	require.NoError(t, repos.Messages.Create(ctx, same))
+	require.NoError(t, repos.Members.MarkRead(ctx, chat.ID, alice.ID, messages[3].ID, messages[3].CreatedAt))
+
+	summaries, err = repos.Members.ListChats(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), summaries[0].UnreadCount)
+
+	require.NoError(t, repos.Members.MarkRead(ctx, foreign.ID, alice.ID, 1, base), "не участник — ничего не происходит")
+
+	states, err := repos.Members.ListReadStates(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.Equal(t, alice.ID, states[0].UserID)
+	require.NotNil(t, states[0].LastReadMessageID)
+	assert.Equal(t, messages[3].ID, *states[0].LastReadMessageID)
+	assert.True(t, states[0].HasRead(*messages[2]))
+	assert.False(t, states[0].HasRead(*same))
+	assert.Equal(t, bob.ID, states[1].UserID)
+	assert.Nil(t, states[1].LastReadMessageID)
+}
@@ -0,0 +1,104 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testModerationRules(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+
+	first := &models.ModerationRule{ChatID: chat.ID, Pattern: `(?i)casino`, Action: models.ModerationActionReject}
+	second := &models.ModerationRule{ChatID: chat.ID, Pattern: `\d{16}`, Action: models.ModerationActionMask}
+	require.NoError(t, repos.Moderation.CreateRule(ctx, first))
+	require.NoError(t, repos.Moderation.CreateRule(ctx, second))
+	require.NoError(t, repos.Moderation.CreateRule(ctx, &models.ModerationRule{ChatID: other.ID, Pattern: "x", Action: models.ModerationActionFlag}))
+	assert.ErrorIs(t, repos.Moderation.CreateRule(ctx, &models.ModerationRule{ChatID: other.ID + 1000, Pattern: "x", Action: models.ModerationActionFlag}),
+		repository.ErrChatNotFound)
+
+	rules, err := repos.Moderation.ListRules(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, first.ID, rules[0].ID)
+	assert.Equal(t, `\d{16}`, rules[1].Pattern)
+	assert.Equal(t, models.ModerationActionMask, rules[1].Action)
+
+	assert.ErrorIs(t, repos.Moderation.DeleteRule(ctx, other.ID, first.ID), repository.ErrModerationRuleNotFound, "чужой чат")
+	require.NoError(t, repos.Moderation.DeleteRule(ctx, chat.ID, first.ID))
+	assert.ErrorIs(t, repos.Moderation.DeleteRule(ctx, chat.ID, first.ID), repository.ErrModerationRuleNotFound)
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+	rules, err = repos.Moderation.ListRules(ctx, chat.ID)
+	require.NoError(t, err)
+	assert.Empty(t, rules, "правила удаляются вместе с чатом")
+}
+
+func testModerationFlags(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+	chat := &models.Chat{Title: "General", OwnerID: &alice.ID}
+	require.NoError(t, repos.Chats.Create(ctx, chat))
+	foreign := &models.Chat{Title: "Bob's", OwnerID: &bob.ID}
+	require.NoError(t, repos.Chats.Create(ctx, foreign))
+	ownerless := &models.Chat{Title: "Imported"}
+	require.NoError(t, repos.Chats.Create(ctx, ownerless))
+
+	now := time.Now().Truncate(time.Second)
+	first := createMessage(t, repos, chat.ID, "первое", now)
+	second := createMessage(t, repos, chat.ID, "второе", now)
+	other := createMessage(t, repos, foreign.ID, "чужое", now)
+	orphan := createMessage(t, repos, ownerless.ID, "ничьё", now)
+
+	require.NoError(t, repos.Moderation.AddFlag(ctx, &models.ModerationFlag{MessageID: second.ID, ChatID: chat.ID, Reason: "word", CreatedAt: now.Add(time.Second)}))
+	require.NoError(t, repos.Moderation.AddFlag(ctx, &models.ModerationFlag{MessageID: first.ID, ChatID: chat.ID, Reason: "classifier", CreatedAt: now}))
+	require.NoError(t, repos.Moderation.AddFlag(ctx, &models.ModerationFlag{MessageID: other.ID, ChatID: foreign.ID, Reason: "word", CreatedAt: now.Add(2 * time.Second)}))
+	require.NoError(t, repos.Moderation.AddFlag(ctx, &models.ModerationFlag{MessageID: orphan.ID, ChatID: ownerless.ID, Reason: "word", CreatedAt: now.Add(3 * time.Second)}))
+	assert.ErrorIs(t, repos.Moderation.AddFlag(ctx, &models.ModerationFlag{MessageID: other.ID + 1000, ChatID: chat.ID, Reason: "x"}),
+		repository.ErrMessageNotFound)
+
+	flags, err := repos.Moderation.ListFlagsByOwner(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, flags, 2, "только чаты владельца")
+	assert.Equal(t, first.ID, flags[0].MessageID, "старые первыми")
+	assert.Equal(t, "classifier", flags[0].Reason)
+	require.NotNil(t, flags[0].Message)
+	assert.Equal(t, "первое", flags[0].Message.Text)
+
+	flags, err = repos.Moderation.ListFlags(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 4, "вся очередь, включая чаты без владельца")
+	assert.Equal(t, first.ID, flags[0].MessageID, "старые первыми")
+	assert.Equal(t, orphan.ID, flags[3].MessageID)
+	require.NotNil(t, flags[3].Message)
+	assert.Equal(t, "ничьё", flags[3].Message.Text)
+
+	flag, err := repos.Moderation.GetFlag(ctx, second.ID)
+	require.NoError(t, err)
+	require.NotNil(t, flag)
+	assert.Equal(t, chat.ID, flag.ChatID)
+
+	require.NoError(t, repos.Moderation.DeleteFlag(ctx, second.ID))
+	assert.ErrorIs(t, repos.Moderation.DeleteFlag(ctx, second.ID), repository.ErrFlagNotFound)
+
+	require.NoError(t, repos.Messages.Delete(ctx, first.ID))
+	assert.ErrorIs(t, repos.Messages.Delete(ctx, first.ID), repository.ErrMessageNotFound)
+	flag, err = repos.Moderation.GetFlag(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, flag, "отметка удаляется вместе с сообщением")
+
+	flags, err = repos.Moderation.ListFlagsByOwner(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+}
@@ -0,0 +1,56 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testPins(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+	alice := createUser(t, repos, "alice")
+
+	now := time.Now().Truncate(time.Second)
+	first := createMessage(t, repos, chat.ID, "первое", now)
+	second := createMessage(t, repos, chat.ID, "второе", now.Add(time.Second))
+	foreign := createMessage(t, repos, other.ID, "чужое", now)
+
+	require.NoError(t, repos.Pins.Add(ctx, &models.ChatPin{ChatID: chat.ID, MessageID: first.ID, PinnedBy: &alice.ID, PinnedAt: now}))
+	require.NoError(t, repos.Pins.Add(ctx, &models.ChatPin{ChatID: chat.ID, MessageID: second.ID, PinnedAt: now.Add(time.Minute)}))
+	require.NoError(t, repos.Pins.Add(ctx, &models.ChatPin{ChatID: other.ID, MessageID: foreign.ID}))
+
+	assert.ErrorIs(t, repos.Pins.Add(ctx, &models.ChatPin{ChatID: chat.ID, MessageID: first.ID}), repository.ErrAlreadyPinned)
+	assert.ErrorIs(t, repos.Pins.Add(ctx, &models.ChatPin{ChatID: chat.ID, MessageID: foreign.ID + 1000}), repository.ErrMessageNotFound)
+
+	count, err := repos.Pins.CountByChat(ctx, chat.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	pins, err := repos.Pins.ListByChat(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, pins, 2)
+	assert.Equal(t, second.ID, pins[0].MessageID, "последние закреплённые первыми")
+	assert.Equal(t, first.ID, pins[1].MessageID)
+	require.NotNil(t, pins[1].Message)
+	assert.Equal(t, "первое", pins[1].Message.Text)
+	require.NotNil(t, pins[1].PinnedBy)
+	assert.Equal(t, alice.ID, *pins[1].PinnedBy)
+
+	assert.ErrorIs(t, repos.Pins.Remove(ctx, chat.ID, foreign.ID), repository.ErrPinNotFound, "чужой чат")
+	require.NoError(t, repos.Pins.Remove(ctx, chat.ID, first.ID))
+	assert.ErrorIs(t, repos.Pins.Remove(ctx, chat.ID, first.ID), repository.ErrPinNotFound)
+
+	require.NoError(t, repos.Chats.Delete(ctx, other.ID))
+	count, err = repos.Pins.CountByChat(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Zero(t, count, "закрепы удаляются вместе с чатом")
+}
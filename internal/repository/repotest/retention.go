@@ -0,0 +1,135 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testRetentionPolicies(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+
+	policy, err := repos.Retention.Get(ctx, chat.ID)
+	require.NoError(t, err)
+	assert.Nil(t, policy, "своей политики у чата нет")
+
+	day, hundred, zero := int64(86400), int64(100), int64(0)
+	require.NoError(t, repos.Retention.Set(ctx, &models.RetentionPolicy{ChatID: other.ID, MaxMessages: &hundred}))
+	require.NoError(t, repos.Retention.Set(ctx, &models.RetentionPolicy{ChatID: chat.ID, MaxAgeSeconds: &day}))
+	require.NoError(t, repos.Retention.Set(ctx, &models.RetentionPolicy{ChatID: chat.ID, MaxAgeSeconds: &day, MaxMessages: &zero}))
+	assert.ErrorIs(t, repos.Retention.Set(ctx, &models.RetentionPolicy{ChatID: other.ID + 1000}), repository.ErrChatNotFound)
+
+	policy, err = repos.Retention.Get(ctx, chat.ID)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	require.NotNil(t, policy.MaxAgeSeconds)
+	assert.Equal(t, day, *policy.MaxAgeSeconds)
+	require.NotNil(t, policy.MaxMessages, "повторный Set заменяет политику")
+	assert.Zero(t, *policy.MaxMessages)
+	assert.False(t, policy.UpdatedAt.IsZero())
+
+	policies, err := repos.Retention.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+	assert.Equal(t, chat.ID, policies[0].ChatID)
+	assert.Equal(t, other.ID, policies[1].ChatID)
+	assert.Nil(t, policies[1].MaxAgeSeconds)
+
+	require.NoError(t, repos.Retention.Delete(ctx, chat.ID))
+	require.NoError(t, repos.Retention.Delete(ctx, chat.ID), "удаление отсутствующей политики не ошибка")
+	policy, err = repos.Retention.Get(ctx, chat.ID)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	require.NoError(t, repos.Chats.Delete(ctx, other.ID))
+	policies, err = repos.Retention.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, policies, "политика удаляется вместе с чатом")
+}
+
+func testRetentionPurge(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+
+	ids, err := repos.Chats.ListIDs(ctx, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{chat.ID}, ids)
+	ids, err = repos.Chats.ListIDs(ctx, chat.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{other.ID}, ids)
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	var messages []*models.Message
+	for i := range 5 {
+		messages = append(messages, createMessage(t, repos, chat.ID, "сообщение", base.Add(time.Duration(i)*time.Minute)))
+	}
+	foreign := createMessage(t, repos, other.ID, "чужое", base)
+
+	nth, err := repos.Messages.NthNewest(ctx, chat.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, nth)
+	assert.Equal(t, messages[4].ID, nth.ID)
+	nth, err = repos.Messages.NthNewest(ctx, chat.ID, 2)
+	require.NoError(t, err)
+	require.NotNil(t, nth)
+	assert.Equal(t, messages[2].ID, nth.ID)
+	nth, err = repos.Messages.NthNewest(ctx, chat.ID, 5)
+	require.NoError(t, err)
+	assert.Nil(t, nth, "сообщений не больше n")
+
+	count, err := repos.Messages.CountUpTo(ctx, chat.ID, messages[2].CreatedAt, messages[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	count, err = repos.Messages.CountUpTo(ctx, chat.ID, messages[2].CreatedAt, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "с id = 0 граница не включается")
+
+	attachment := &models.Attachment{ChatID: chat.ID, MessageID: messages[0].ID, FileName: "a.txt", ContentType: "text/plain", StorageKey: "a", ThumbnailKey: "a-thumb"}
+	require.NoError(t, repos.Attachments.Create(ctx, attachment))
+
+	deleted, err := repos.Messages.DeleteUpTo(ctx, chat.ID, messages[2].CreatedAt, messages[2].ID, 2)
+	require.NoError(t, err)
+	require.Len(t, deleted, 2, "не больше limit за раз")
+	assert.ElementsMatch(t, []int64{messages[0].ID, messages[1].ID}, []int64{deleted[0].ID, deleted[1].ID})
+	var keys []string
+	for _, message := range deleted {
+		assert.Equal(t, chat.ID, message.ChatID)
+		for _, attachment := range message.Attachments {
+			keys = append(keys, attachment.StorageKey, attachment.ThumbnailKey)
+		}
+	}
+	assert.Equal(t, []string{"a", "a-thumb"}, keys, "ключи файлов возвращаются для уборки хранилища")
+
+	deleted, err = repos.Messages.DeleteUpTo(ctx, chat.ID, messages[2].CreatedAt, messages[2].ID, 2)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	assert.Equal(t, messages[2].ID, deleted[0].ID)
+	assert.Empty(t, deleted[0].Attachments)
+	deleted, err = repos.Messages.DeleteUpTo(ctx, chat.ID, messages[2].CreatedAt, messages[2].ID, 2)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	left, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, left, 2)
+	assert.ElementsMatch(t, []int64{messages[3].ID, messages[4].ID}, []int64{left[0].ID, left[1].ID})
+
+	gone, err := repos.Attachments.GetByID(ctx, attachment.ID)
+	require.NoError(t, err)
+	assert.Nil(t, gone, "вложения удаляются вместе с сообщением")
+
+	kept, err := repos.Messages.GetByID(ctx, foreign.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, kept, "другие чаты не затрагиваются")
+}
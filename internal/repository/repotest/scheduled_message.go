@@ -0,0 +1,120 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func testScheduledMessages(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "General")
+	other := createChat(t, repos, "Other")
+	alice := createUser(t, repos, "alice")
+	bob := createUser(t, repos, "bob")
+
+	now := time.Now().Truncate(time.Second)
+	schedule := func(chatID, authorID int64, text string, sendAt time.Time) *models.ScheduledMessage {
+		message := &models.ScheduledMessage{ChatID: chatID, AuthorID: authorID, Text: text, Format: models.MessageFormatPlain, SendAt: sendAt}
+		require.NoError(t, repos.Scheduled.Create(ctx, message))
+		return message
+	}
+
+	later := schedule(chat.ID, alice.ID, "позже", now.Add(time.Hour))
+	due := schedule(chat.ID, alice.ID, "пора", now.Add(-time.Minute))
+	overdue := schedule(other.ID, bob.ID, "давно пора", now.Add(-time.Hour))
+	schedule(chat.ID, bob.ID, "чужое", now.Add(time.Hour))
+	assert.NotZero(t, later.ID)
+
+	assert.ErrorIs(t, repos.Scheduled.Create(ctx, &models.ScheduledMessage{ChatID: other.ID + 1000, AuthorID: alice.ID, Text: "x", SendAt: now}),
+		repository.ErrChatNotFound)
+
+	list, err := repos.Scheduled.ListByAuthor(ctx, chat.ID, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "только свои сообщения этого чата")
+	assert.Equal(t, due.ID, list[0].ID, "ближайшие первыми")
+	assert.Equal(t, later.ID, list[1].ID)
+
+	later.Text = "исправлено"
+	later.SendAt = now.Add(2 * time.Hour)
+	later.ExpiresIn = 60
+	require.NoError(t, repos.Scheduled.Update(ctx, later))
+	stored, err := repos.Scheduled.GetByID(ctx, later.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "исправлено", stored.Text)
+	assert.Equal(t, int64(60), stored.ExpiresIn)
+	assert.True(t, stored.SendAt.Equal(now.Add(2*time.Hour)))
+
+	claim := func(now time.Time, limit int) []models.ScheduledMessage {
+		var claimed []models.ScheduledMessage
+		require.NoError(t, repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			claimed, err = repos.Scheduled.ClaimDue(ctx, now, now.Add(5*time.Minute), limit)
+			return err
+		}))
+		return claimed
+	}
+
+	claimed := claim(now, 10)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, overdue.ID, claimed[0].ID, "сначала самые просроченные")
+	assert.Equal(t, due.ID, claimed[1].ID)
+	require.NotNil(t, claimed[0].ClaimedUntil)
+	assert.True(t, claimed[0].ClaimedUntil.Equal(now.Add(5*time.Minute)))
+
+	require.NoError(t, repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := repos.Scheduled.GetForUpdate(ctx, overdue.ID)
+		require.NoError(t, err)
+		require.NotNil(t, locked)
+		require.NotNil(t, locked.ClaimedUntil, "захват виден при чтении с блокировкой")
+		assert.True(t, locked.ClaimedUntil.Equal(now.Add(5*time.Minute)))
+
+		missing, err := repos.Scheduled.GetForUpdate(ctx, overdue.ID+1000)
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+		return nil
+	}))
+
+	assert.Empty(t, claim(now.Add(time.Minute), 10), "захваченные сообщения не берутся, пока захват не истёк")
+
+	// Захват истёк: реплика, отправлявшая сообщения, считается упавшей
+	now = now.Add(10 * time.Minute)
+	assert.Len(t, claim(now, 1), 1, "не больше limit за раз")
+
+	// Брошенное сообщение больше не захватывается
+	failedAt := now
+	overdue.Attempts = 5
+	overdue.LastError = "timeout"
+	overdue.FailedAt = &failedAt
+	require.NoError(t, repos.Scheduled.Update(ctx, overdue))
+	stored, err = repos.Scheduled.GetByID(ctx, overdue.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 5, stored.Attempts)
+	assert.Equal(t, "timeout", stored.LastError)
+	require.NotNil(t, stored.FailedAt)
+	assert.Nil(t, stored.ClaimedUntil, "сохранение без захвата его снимает")
+	claimed = claim(now.Add(10*time.Minute), 10)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, due.ID, claimed[0].ID)
+
+	require.NoError(t, repos.Scheduled.Delete(ctx, due.ID))
+	assert.ErrorIs(t, repos.Scheduled.Delete(ctx, due.ID), repository.ErrScheduledMessageNotFound)
+	assert.ErrorIs(t, repos.Scheduled.Update(ctx, due), repository.ErrScheduledMessageNotFound)
+	stored, err = repos.Scheduled.GetByID(ctx, due.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	require.NoError(t, repos.Chats.Delete(ctx, other.ID))
+	stored, err = repos.Scheduled.GetByID(ctx, overdue.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored, "очередь чата удаляется вместе с ним")
+}
@@ -0,0 +1,549 @@
+// Package repotest содержит общий набор тестов (conformance suite), которому обязана
+// соответствовать любая реализация интерфейсов из пакета repository.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// Repositories — набор репозиториев одного бэкенда, разделяющих общее хранилище
+type Repositories struct {
+	Tx       repository.TxManager
+	Chats    repository.ChatRepository
+	Messages repository.MessageRepository
+	Outbox   repository.OutboxRepository
+
+	Webhooks   repository.WebhookRepository
+	Deliveries repository.WebhookDeliveryRepository
+
+	Users    repository.UserRepository
+	Incoming repository.IncomingWebhookRepository
+
+	Attachments  repository.AttachmentRepository
+	LinkPreviews repository.LinkPreviewRepository
+
+	Members  repository.ChatMemberRepository
+	Mentions repository.MentionRepository
+	Pins     repository.PinRepository
+
+	Retention repository.RetentionRepository
+	Scheduled repository.ScheduledMessageRepository
+
+	Moderation repository.ModerationRepository
+
+	Blocks  repository.BlockRepository
+	Reports repository.ReportRepository
+	Audit   repository.AuditRepository
+}
+
+// Factory должна возвращать репозитории поверх нового пустого хранилища
+type Factory func(t *testing.T) Repositories
+
+// Run прогоняет весь набор тестов против переданного бэкенда
+func Run(t *testing.T, newRepos Factory) {
+	t.Run("ChatCreate", func(t *testing.T) { testChatCreate(t, newRepos(t)) })
+	t.Run("ChatGetByID", func(t *testing.T) { testChatGetByID(t, newRepos(t)) })
+	t.Run("ChatExists", func(t *testing.T) { testChatExists(t, newRepos(t)) })
+	t.Run("ChatDelete", func(t *testing.T) { testChatDelete(t, newRepos(t)) })
+	t.Run("ChatDeleteCascade", func(t *testing.T) { testChatDeleteCascade(t, newRepos(t)) })
+	t.Run("MessageCreate", func(t *testing.T) { testMessageCreate(t, newRepos(t)) })
+	t.Run("MessageGetByChatIDOrder", func(t *testing.T) { testMessageGetByChatIDOrder(t, newRepos(t)) })
+	t.Run("MessageGetByChatIDLimit", func(t *testing.T) { testMessageGetByChatIDLimit(t, newRepos(t)) })
+	t.Run("MessageGetByChatIDIsolation", func(t *testing.T) { testMessageGetByChatIDIsolation(t, newRepos(t)) })
+	t.Run("MessageListAfter", func(t *testing.T) { testMessageListAfter(t, newRepos(t)) })
+	t.Run("MessageExpiry", func(t *testing.T) { testMessageExpiry(t, newRepos(t)) })
+	t.Run("ConcurrentCreate", func(t *testing.T) { testConcurrentCreate(t, newRepos(t)) })
+	t.Run("TxCommit", func(t *testing.T) { testTxCommit(t, newRepos(t)) })
+	t.Run("TxRollback", func(t *testing.T) { testTxRollback(t, newRepos(t)) })
+	t.Run("OutboxListAfter", func(t *testing.T) { testOutboxListAfter(t, newRepos(t)) })
+	t.Run("OutboxCursors", func(t *testing.T) { testOutboxCursors(t, newRepos(t)) })
+	t.Run("OutboxPrune", func(t *testing.T) { testOutboxPrune(t, newRepos(t)) })
+	t.Run("OutboxRollback", func(t *testing.T) { testOutboxRollback(t, newRepos(t)) })
+	t.Run("Webhooks", func(t *testing.T) { testWebhooks(t, newRepos(t)) })
+	t.Run("WebhookFailures", func(t *testing.T) { testWebhookFailures(t, newRepos(t)) })
+	t.Run("WebhookDeliveries", func(t *testing.T) { testWebhookDeliveries(t, newRepos(t)) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepos(t)) })
+	t.Run("IncomingWebhooks", func(t *testing.T) { testIncomingWebhooks(t, newRepos(t)) })
+	t.Run("Attachments", func(t *testing.T) { testAttachments(t, newRepos(t)) })
+	t.Run("LinkPreviews", func(t *testing.T) { testLinkPreviews(t, newRepos(t)) })
+	t.Run("ChatMembers", func(t *testing.T) { testChatMembers(t, newRepos(t)) })
+	t.Run("Mentions", func(t *testing.T) { testMentions(t, newRepos(t)) })
+	t.Run("ReadState", func(t *testing.T) { testReadState(t, newRepos(t)) })
+	t.Run("Pins", func(t *testing.T) { testPins(t, newRepos(t)) })
+	t.Run("RetentionPolicies", func(t *testing.T) { testRetentionPolicies(t, newRepos(t)) })
+	t.Run("RetentionPurge", func(t *testing.T) { testRetentionPurge(t, newRepos(t)) })
+	t.Run("ScheduledMessages", func(t *testing.T) { testScheduledMessages(t, newRepos(t)) })
+	t.Run("ModerationRules", func(t *testing.T) { testModerationRules(t, newRepos(t)) })
+	t.Run("ModerationFlags", func(t *testing.T) { testModerationFlags(t, newRepos(t)) })
+	t.Run("Blocks", func(t *testing.T) { testBlocks(t, newRepos(t)) })
+	t.Run("Reports", func(t *testing.T) { testReports(t, newRepos(t)) })
+	t.Run("Audit", func(t *testing.T) { testAudit(t, newRepos(t)) })
+	t.Run("DirectChats", func(t *testing.T) { testDirectChats(t, newRepos(t)) })
+	t.Run("Import", func(t *testing.T) { testImport(t, newRepos(t)) })
+}
+
+func createChat(t *testing.T, repos Repositories, title string) *models.Chat {
+	t.Helper()
+
+	chat := &models.Chat{Title: title}
+	require.NoError(t, repos.Chats.Create(context.Background(), chat))
+	return chat
+}
+
+func createMessage(t *testing.T, repos Repositories, chatID int64, text string, createdAt time.Time) *models.Message {
+	t.Helper()
+
+	message := &models.Message{ChatID: chatID, Text: text, CreatedAt: createdAt}
+	require.NoError(t, repos.Messages.Create(context.Background(), message))
+	return message
+}
+
+func testChatCreate(t *testing.T, repos Repositories) {
+	first := createChat(t, repos, "First")
+	second := createChat(t, repos, "Second")
+
+	assert.NotZero(t, first.ID)
+	assert.NotZero(t, second.ID)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.False(t, first.CreatedAt.IsZero(), "CreatedAt должен заполняться при создании")
+}
+
+func testChatGetByID(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+
+	found, err := repos.Chats.GetByID(ctx, chat.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, chat.ID, found.ID)
+	assert.Equal(t, "Test Chat", found.Title)
+
+	notFound, err := repos.Chats.GetByID(ctx, chat.ID+1000)
+	assert.NoError(t, err)
+	assert.Nil(t, notFound, "для несуществующего чата ожидается nil, nil")
+}
+
+func testChatExists(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+
+	exists, err := repos.Chats.Exists(ctx, chat.ID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repos.Chats.Exists(ctx, chat.ID+1000)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testChatDelete(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+
+	found, err := repos.Chats.GetByID(ctx, chat.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	err = repos.Chats.Delete(ctx, chat.ID)
+	assert.ErrorIs(t, err, repository.ErrChatNotFound)
+}
+
+func testChatDeleteCascade(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Deleted")
+	other := createChat(t, repos, "Kept")
+
+	createMessage(t, repos, chat.ID, "gone", time.Now())
+	createMessage(t, repos, other.ID, "kept", time.Now())
+
+	require.NoError(t, repos.Chats.Delete(ctx, chat.ID))
+
+	messages, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	messages, err = repos.Messages.GetByChatID(ctx, other.ID, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func testMessageCreate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+
+	message := &models.Message{ChatID: chat.ID, Text: "Hello"}
+	require.NoError(t, repos.Messages.Create(ctx, message))
+	assert.NotZero(t, message.ID)
+	assert.False(t, message.CreatedAt.IsZero(), "CreatedAt должен заполняться при создании")
+
+	orphan := &models.Message{ChatID: chat.ID + 1000, Text: "Orphan"}
+	err := repos.Messages.Create(ctx, orphan)
+	assert.ErrorIs(t, err, repository.ErrChatNotFound, "сообщение в несуществующий чат создаваться не должно")
+}
+
+func testMessageGetByChatIDOrder(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+	base := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	// Создаём не по порядку, чтобы сортировка не совпадала с порядком вставки
+	createMessage(t, repos, chat.ID, "Message 2", base.Add(2*time.Second))
+	createMessage(t, repos, chat.ID, "Message 1", base.Add(1*time.Second))
+	createMessage(t, repos, chat.ID, "Message 3", base.Add(3*time.Second))
+
+	// При одинаковом created_at новее считается сообщение с большим ID
+	createMessage(t, repos, chat.ID, "Message 4a", base.Add(4*time.Second))
+	createMessage(t, repos, chat.ID, "Message 4b", base.Add(4*time.Second))
+
+	found, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+
+	texts := make([]string, 0, len(found))
+	for _, msg := range found {
+		texts = append(texts, msg.Text)
+	}
+	assert.Equal(t, []string{"Message 4b", "Message 4a", "Message 3", "Message 2", "Message 1"}, texts)
+}
+
+func testMessageGetByChatIDLimit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Test Chat")
+	base := time.Now().Add(-time.Hour)
+
+	for i := 0; i < 5; i++ {
+		createMessage(t, repos, chat.ID, "Message", base.Add(time.Duration(i)*time.Second))
+	}
+
+	found, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 3)
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+}
+
+func testMessageGetByChatIDIsolation(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "First")
+	other := createChat(t, repos, "Second")
+
+	createMessage(t, repos, chat.ID, "mine", time.Now())
+	createMessage(t, repos, other.ID, "theirs", time.Now())
+
+	found, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "mine", found[0].Text)
+	assert.Equal(t, chat.ID, found[0].ChatID)
+
+	empty, err := repos.Messages.GetByChatID(ctx, other.ID+1000, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func testMessageListAfter(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "First")
+	other := createChat(t, repos, "Second")
+
+	base := time.Now().Truncate(time.Second)
+	// Два сообщения с одинаковым временем: порядок между ними задаёт id
+	third := createMessage(t, repos, chat.ID, "third", base.Add(2*time.Second))
+	first := createMessage(t, repos, chat.ID, "first", base)
+	second := createMessage(t, repos, chat.ID, "second", base)
+	createMessage(t, repos, other.ID, "theirs", base)
+
+	require.NoError(t, repos.Attachments.Create(ctx, &models.Attachment{
+		ChatID: chat.ID, MessageID: third.ID, FileName: "a.txt", ContentType: "text/plain", Size: 1, StorageKey: "a",
+	}))
+
+	var texts []string
+	var afterCreatedAt time.Time
+	var afterID int64
+	for {
+		page, err := repos.Messages.ListAfter(ctx, chat.ID, afterCreatedAt, afterID, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		for _, message := range page {
+			texts = append(texts, message.Text)
+		}
+		last := page[len(page)-1]
+		afterCreatedAt, afterID = last.CreatedAt, last.ID
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, texts)
+	assert.Less(t, first.ID, second.ID)
+
+	last, err := repos.Messages.ListAfter(ctx, chat.ID, second.CreatedAt, second.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, last, 1)
+	require.Len(t, last[0].Attachments, 1, "вложения подгружаются")
+	assert.Equal(t, "a.txt", last[0].Attachments[0].FileName)
+}
+
+func testConcurrentCreate(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chat := createChat(t, repos, "Busy Chat")
+
+	const workers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repos.Messages.Create(ctx, &models.Message{ChatID: chat.ID, Text: "concurrent"})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	found, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 100)
+	assert.NoError(t, err)
+	assert.Len(t, found, workers)
+}
+
+func testTxCommit(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	var chat *models.Chat
+	err := repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		chat = &models.Chat{Title: "In Tx"}
+		if err := repos.Chats.Create(ctx, chat); err != nil {
+			return err
+		}
+
+		exists, err := repos.Chats.Exists(ctx, chat.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New("chat is not visible inside its own transaction")
+		}
+
+		return repos.Messages.Create(ctx, &models.Message{ChatID: chat.ID, Text: "In Tx"})
+	})
+	require.NoError(t, err)
+
+	messages, err := repos.Messages.GetByChatID(ctx, chat.ID, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func testTxRollback(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	kept := createChat(t, repos, "Kept")
+	alice := createUser(t, repos, "alice")
+	scheduled := &models.ScheduledMessage{ChatID: kept.ID, AuthorID: alice.ID, Text: "исходный", Format: models.MessageFormatPlain, SendAt: time.Now().Add(time.Hour)}
+	require.NoError(t, repos.Scheduled.Create(ctx, scheduled))
+	errAbort := errors.New("abort")
+
+	var created *models.Chat
+	err := repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		created = &models.Chat{Title: "Rolled Back"}
+		if err := repos.Chats.Create(ctx, created); err != nil {
+			return err
+		}
+		if err := repos.Messages.Create(ctx, &models.Message{ChatID: kept.ID, Text: "Rolled Back"}); err != nil {
+			return err
+		}
+		// Строка меняется дважды и удаляется: откат должен вернуть исходное состояние
+		for _, text := range []string{"первая правка", "вторая правка"} {
+			edited := *scheduled
+			edited.Text = text
+			if err := repos.Scheduled.Update(ctx, &edited); err != nil {
+				return err
+			}
+		}
+		if err := repos.Chats.Delete(ctx, kept.ID); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	found, err := repos.Chats.GetByID(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, found, "созданный в откаченной транзакции чат не должен существовать")
+
+	exists, err := repos.Chats.Exists(ctx, kept.ID)
+	assert.NoError(t, err)
+	assert.True(t, exists, "удаление в откаченной транзакции не должно примениться")
+
+	messages, err := repos.Messages.GetByChatID(ctx, kept.ID, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, messages)
+
+	stored, err := repos.Scheduled.GetByID(ctx, scheduled.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored, "каскадное удаление в откаченной транзакции не должно примениться")
+	assert.Equal(t, "исходный", stored.Text)
+
+	// Паника внутри транзакции тоже откатывает изменения
+	assert.Panics(t, func() {
+		repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := repos.Chats.Delete(ctx, kept.ID); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+	exists, err = repos.Chats.Exists(ctx, kept.ID)
+	assert.NoError(t, err)
+	assert.True(t, exists, "удаление перед паникой не должно примениться")
+}
+
+func testOutboxListAfter(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	events := []*models.OutboxEvent{
+		{EventType: models.EventChatCreated, ChatID: 1, Payload: `{"id":1}`},
+		{EventType: models.EventMessageCreated, ChatID: 1, Payload: `{"id":1}`},
+		{EventType: models.EventChatDeleted, ChatID: 1, Payload: `{"id":1}`},
+	}
+	for _, event := range events {
+		require.NoError(t, repos.Outbox.Add(ctx, event))
+		assert.NotZero(t, event.ID)
+	}
+
+	pending, err := repos.Outbox.ListAfter(ctx, 0, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, models.EventChatCreated, pending[0].EventType)
+	assert.Equal(t, models.EventMessageCreated, pending[1].EventType)
+	assert.Equal(t, models.EventChatDeleted, pending[2].EventType)
+	assert.JSONEq(t, `{"id":1}`, pending[0].Payload)
+
+	next, err := repos.Outbox.ListAfter(ctx, pending[0].TxID, pending[0].ID, 1)
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.Equal(t, events[1].ID, next[0].ID)
+
+	next, err = repos.Outbox.ListAfter(ctx, pending[2].TxID, pending[2].ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, next)
+
+	txID, id, err := repos.Outbox.Head(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, pending[2].TxID, txID)
+	assert.Equal(t, events[2].ID, id, "Head указывает на последнее событие")
+}
+
+func testOutboxCursors(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	first := &models.OutboxEvent{EventType: models.EventChatCreated, ChatID: 1, Payload: `{}`}
+	require.NoError(t, repos.Outbox.Add(ctx, first))
+
+	cursor, err := repos.Outbox.GetCursor(ctx, "http")
+	require.NoError(t, err)
+	assert.Equal(t, "http", cursor.Sink)
+	assert.Zero(t, cursor.LastEventID, "новый приёмник начинает с начала outbox")
+
+	cursor.LastEventID = first.ID
+	cursor.Attempts = 2
+	cursor.LastError = "sink unavailable"
+	require.NoError(t, repos.Outbox.SaveCursor(ctx, cursor))
+
+	stored, err := repos.Outbox.GetCursor(ctx, "http")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, stored.LastEventID)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.Equal(t, "sink unavailable", stored.LastError)
+
+	stored.Attempts = 0
+	stored.LastError = ""
+	require.NoError(t, repos.Outbox.SaveCursor(ctx, stored))
+
+	stored, err = repos.Outbox.GetCursor(ctx, "http")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, stored.LastEventID, "повторное сохранение заменяет курсор")
+	assert.Zero(t, stored.Attempts)
+	assert.Empty(t, stored.LastError)
+
+	other, err := repos.Outbox.GetCursor(ctx, "bus")
+	require.NoError(t, err)
+	assert.Zero(t, other.LastEventID, "курсоры приёмников независимы")
+}
+
+func testOutboxPrune(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	bus, err := repos.Outbox.GetCursor(ctx, "bus")
+	require.NoError(t, err)
+	httpCursor, err := repos.Outbox.GetCursor(ctx, "http")
+	require.NoError(t, err)
+
+	old := time.Now().Add(-2 * time.Hour)
+	var events []*models.OutboxEvent
+	for i := range 3 {
+		event := &models.OutboxEvent{EventType: models.EventChatCreated, ChatID: 1, Payload: `{}`, CreatedAt: old.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, repos.Outbox.Add(ctx, event))
+		events = append(events, event)
+	}
+	fresh := &models.OutboxEvent{EventType: models.EventChatCreated, ChatID: 1, Payload: `{}`}
+	require.NoError(t, repos.Outbox.Add(ctx, fresh))
+
+	written, err := repos.Outbox.ListAfter(ctx, 0, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, written, 4)
+
+	bus.LastTxID, bus.LastEventID = written[3].TxID, written[3].ID
+	require.NoError(t, repos.Outbox.SaveCursor(ctx, bus))
+	httpCursor.LastTxID, httpCursor.LastEventID = written[1].TxID, written[1].ID
+	require.NoError(t, repos.Outbox.SaveCursor(ctx, httpCursor))
+
+	before := time.Now().Add(-time.Hour)
+	pruned, err := repos.Outbox.Prune(ctx, []string{"bus", "http", "log"}, before, 10)
+	require.NoError(t, err)
+	assert.Zero(t, pruned, "приёмник log ещё ничего не получил")
+
+	pruned, err = repos.Outbox.Prune(ctx, []string{"bus", "http"}, before, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned, "не больше limit за раз")
+	pruned, err = repos.Outbox.Prune(ctx, []string{"bus", "http"}, before, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned, "событие, не доставленное http, остаётся")
+
+	left, err := repos.Outbox.ListAfter(ctx, 0, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, left, 2)
+	assert.Equal(t, events[2].ID, left[0].ID)
+	assert.Equal(t, fresh.ID, left[1].ID, "свежие события не удаляются")
+}
+
+func testOutboxRollback(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	err := repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		chat := &models.Chat{Title: "Rolled Back"}
+		if err := repos.Chats.Create(ctx, chat); err != nil {
+			return err
+		}
+		event := &models.OutboxEvent{EventType: models.EventChatCreated, ChatID: chat.ID, Payload: `{}`}
+		if err := repos.Outbox.Add(ctx, event); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	pending, err := repos.Outbox.ListAfter(ctx, 0, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending, "события откаченной транзакции не должны попадать в outbox")
+}
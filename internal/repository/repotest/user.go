@@ -0,0 +1,96 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func createUser(t *testing.T, repos Repositories, username string) *models.User {
+	t.Helper()
+
+	user := &models.User{Username: username}
+	require.NoError(t, repos.Users.Create(context.Background(), user))
+	return user
+}
+
+func testUsers(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	alice := createUser(t, repos, "alice")
+	assert.NotZero(t, alice.ID)
+	assert.False(t, alice.CreatedAt.IsZero())
+
+	assert.ErrorIs(t, repos.Users.Create(ctx, &models.User{Username: "alice"}), repository.ErrUsernameTaken)
+
+	found, err := repos.Users.GetByID(ctx, alice.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "alice", found.Username)
+
+	found, err = repos.Users.GetByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, alice.ID, found.ID)
+
+	missing, err := repos.Users.GetByID(ctx, alice.ID+1000)
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	missing, err = repos.Users.GetByUsername(ctx, "bob")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func testIncomingWebhooks(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	chat := createChat(t, repos, "CI")
+	other := createChat(t, repos, "Other")
+	bot := &models.User{Username: "ci-bot", IsBot: true}
+	require.NoError(t, repos.Users.Create(ctx, bot))
+
+	hook := &models.IncomingWebhook{ChatID: chat.ID, Name: "CI", TokenHash: "hash-1", BotUserID: bot.ID}
+	require.NoError(t, repos.Incoming.Create(ctx, hook))
+	assert.NotZero(t, hook.ID)
+	assert.False(t, hook.CreatedAt.IsZero())
+
+	require.NoError(t, repos.Incoming.Create(ctx, &models.IncomingWebhook{
+		ChatID: other.ID, Name: "Other", TokenHash: "hash-2", BotUserID: bot.ID,
+	}))
+
+	err := repos.Incoming.Create(ctx, &models.IncomingWebhook{
+		ChatID: other.ID + 1000, Name: "Ghost", TokenHash: "hash-3", BotUserID: bot.ID,
+	})
+	assert.ErrorIs(t, err, repository.ErrChatNotFound)
+
+	found, err := repos.Incoming.GetByTokenHash(ctx, "hash-1")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, hook.ID, found.ID)
+	assert.Equal(t, bot.ID, found.BotUserID)
+
+	missing, err := repos.Incoming.GetByTokenHash(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	list, err := repos.Incoming.ListByChat(ctx, chat.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "CI", list[0].Name)
+
+	assert.ErrorIs(t, repos.Incoming.Delete(ctx, other.ID, hook.ID), repository.ErrIncomingWebhookNotFound)
+	require.NoError(t, repos.Incoming.Delete(ctx, chat.ID, hook.ID))
+	assert.ErrorIs(t, repos.Incoming.Delete(ctx, chat.ID, hook.ID), repository.ErrIncomingWebhookNotFound)
+
+	// Токены удалённого чата перестают работать
+	require.NoError(t, repos.Chats.Delete(ctx, other.ID))
+	missing, err = repos.Incoming.GetByTokenHash(ctx, "hash-2")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,172 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+func createWebhook(t *testing.T, repos Repositories, events ...string) *models.Webhook {
+	t.Helper()
+
+	webhook := &models.Webhook{
+		URL:    "https://example.com/hook",
+		Events: events,
+		Secret: "secret",
+		Active: true,
+	}
+	require.NoError(t, repos.Webhooks.Create(context.Background(), webhook))
+	return webhook
+}
+
+func testWebhooks(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	chatID := int64(7)
+
+	all := createWebhook(t, repos)
+	filtered := &models.Webhook{
+		URL:    "https://example.com/filtered",
+		Events: []string{models.EventMessageCreated},
+		ChatID: &chatID,
+		Secret: "secret",
+		Active: true,
+	}
+	require.NoError(t, repos.Webhooks.Create(ctx, filtered))
+	assert.NotZero(t, filtered.ID)
+	assert.False(t, filtered.CreatedAt.IsZero())
+
+	found, err := repos.Webhooks.GetByID(ctx, filtered.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, []string{models.EventMessageCreated}, found.Events)
+	require.NotNil(t, found.ChatID)
+	assert.Equal(t, chatID, *found.ChatID)
+	assert.Equal(t, "secret", found.Secret)
+
+	notFound, err := repos.Webhooks.GetByID(ctx, filtered.ID+1000)
+	assert.NoError(t, err)
+	assert.Nil(t, notFound)
+
+	require.NoError(t, repos.Webhooks.SetActive(ctx, all.ID, false))
+
+	list, err := repos.Webhooks.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	active, err := repos.Webhooks.ListActive(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, filtered.ID, active[0].ID)
+
+	require.NoError(t, repos.Webhooks.Delete(ctx, filtered.ID))
+	assert.ErrorIs(t, repos.Webhooks.Delete(ctx, filtered.ID), repository.ErrWebhookNotFound)
+	assert.ErrorIs(t, repos.Webhooks.SetActive(ctx, filtered.ID, true), repository.ErrWebhookNotFound)
+}
+
+func testWebhookFailures(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	webhook := createWebhook(t, repos)
+
+	disabled, err := repos.Webhooks.RecordFailure(ctx, webhook.ID, 3)
+	require.NoError(t, err)
+	assert.False(t, disabled)
+
+	require.NoError(t, repos.Webhooks.ResetFailures(ctx, webhook.ID))
+	for i := 0; i < 2; i++ {
+		disabled, err = repos.Webhooks.RecordFailure(ctx, webhook.ID, 3)
+		require.NoError(t, err)
+		assert.False(t, disabled)
+	}
+
+	disabled, err = repos.Webhooks.RecordFailure(ctx, webhook.ID, 3)
+	require.NoError(t, err)
+	assert.True(t, disabled, "третья ошибка подряд должна выключить вебхук")
+
+	found, err := repos.Webhooks.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Active)
+	assert.Equal(t, 3, found.ConsecutiveFailures)
+	assert.NotNil(t, found.DisabledAt)
+
+	require.NoError(t, repos.Webhooks.SetActive(ctx, webhook.ID, true))
+	found, err = repos.Webhooks.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Active)
+	assert.Zero(t, found.ConsecutiveFailures)
+	assert.Nil(t, found.DisabledAt)
+}
+
+func testWebhookDeliveries(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	webhook := createWebhook(t, repos)
+	now := time.Now()
+
+	newDelivery := func(eventID int64, nextAttempt time.Time) *models.WebhookDelivery {
+		return &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       eventID,
+			EventType:     models.EventMessageCreated,
+			Payload:       `{"id":1}`,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: nextAttempt,
+		}
+	}
+
+	first := newDelivery(1, now.Add(-time.Minute))
+	require.NoError(t, repos.Deliveries.Enqueue(ctx, first))
+	require.NoError(t, repos.Deliveries.Enqueue(ctx, newDelivery(2, now.Add(time.Hour))))
+
+	// Повторная постановка того же события не создаёт дубликат
+	require.NoError(t, repos.Deliveries.Enqueue(ctx, newDelivery(1, now.Add(-time.Minute))))
+
+	claim := func(now time.Time) []models.WebhookDelivery {
+		var claimed []models.WebhookDelivery
+		require.NoError(t, repos.Tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			claimed, err = repos.Deliveries.ClaimDue(ctx, now, now.Add(5*time.Minute), 10)
+			return err
+		}))
+		return claimed
+	}
+
+	due := claim(now)
+	require.Len(t, due, 1)
+	assert.Equal(t, first.ID, due[0].ID)
+	assert.JSONEq(t, `{"id":1}`, due[0].Payload)
+	assert.True(t, due[0].NextAttemptAt.Equal(now.Add(5*time.Minute)))
+
+	assert.Empty(t, claim(now.Add(time.Minute)), "захваченная доставка не берётся, пока захват не истёк")
+	// Захват истёк: реплика, отправлявшая доставку, считается упавшей
+	due = claim(now.Add(10 * time.Minute))
+	require.Len(t, due, 1)
+	assert.Equal(t, first.ID, due[0].ID)
+
+	delivered := due[0]
+	deliveredAt := time.Now()
+	delivered.Status = models.DeliveryDelivered
+	delivered.Attempts = 1
+	delivered.ResponseCode = 200
+	delivered.DeliveredAt = &deliveredAt
+	require.NoError(t, repos.Deliveries.Update(ctx, &delivered))
+
+	assert.Empty(t, claim(now.Add(30*time.Minute)), "доставленное больше не захватывается")
+
+	log, err := repos.Deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, log, 2)
+	assert.Equal(t, int64(2), log[0].EventID, "журнал отсортирован от новых к старым")
+	assert.Equal(t, models.DeliveryDelivered, log[1].Status)
+	assert.Equal(t, 200, log[1].ResponseCode)
+	assert.NotNil(t, log[1].DeliveredAt)
+
+	require.NoError(t, repos.Webhooks.Delete(ctx, webhook.ID))
+	log, err = repos.Deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, log, "доставки удаляются вместе с вебхуком")
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_retention_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository RetentionRepository
+
+type RetentionRepository interface {
+	Get(ctx context.Context, chatID int64) (*models.RetentionPolicy, error)
+	Set(ctx context.Context, policy *models.RetentionPolicy) error
+	Delete(ctx context.Context, chatID int64) error
+	List(ctx context.Context) ([]models.RetentionPolicy, error)
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+// Get получает политику хранения чата, nil, nil если своей политики у чата нет
+func (r *retentionRepository) Get(ctx context.Context, chatID int64) (*models.RetentionPolicy, error) {
+	var policy models.RetentionPolicy
+	err := conn(ctx, r.db).Where("chat_id = ?", chatID).First(&policy).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// Set создаёт или заменяет политику хранения чата. Если чата нет, возвращает ErrChatNotFound.
+func (r *retentionRepository) Set(ctx context.Context, policy *models.RetentionPolicy) error {
+	policy.UpdatedAt = time.Now()
+
+	err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "chat_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"max_age_seconds", "max_messages", "updated_at"}),
+		}).
+		Create(policy).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
+}
+
+// Delete удаляет политику чата, после чего для него действует глобальная. Отсутствие политики не ошибка.
+func (r *retentionRepository) Delete(ctx context.Context, chatID int64) error {
+	return conn(ctx, r.db).Where("chat_id = ?", chatID).Delete(&models.RetentionPolicy{}).Error
+}
+
+// List получает политики всех чатов по возрастанию ID чата
+func (r *retentionRepository) List(ctx context.Context) ([]models.RetentionPolicy, error) {
+	var policies []models.RetentionPolicy
+	err := conn(ctx, r.db).Order("chat_id ASC").Find(&policies).Error
+	return policies, err
+}
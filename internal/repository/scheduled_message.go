@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_scheduled_message_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository ScheduledMessageRepository
+
+var ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+type ScheduledMessageRepository interface {
+	Create(ctx context.Context, message *models.ScheduledMessage) error
+	GetByID(ctx context.Context, id int64) (*models.ScheduledMessage, error)
+	GetForUpdate(ctx context.Context, id int64) (*models.ScheduledMessage, error)
+	ListByAuthor(ctx context.Context, chatID, authorID int64) ([]models.ScheduledMessage, error)
+	Update(ctx context.Context, message *models.ScheduledMessage) error
+	Delete(ctx context.Context, id int64) error
+	ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.ScheduledMessage, error)
+}
+
+type scheduledMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduledMessageRepository(db *gorm.DB) ScheduledMessageRepository {
+	return &scheduledMessageRepository{db: db}
+}
+
+// Create ставит сообщение в очередь. Если чата нет, возвращает ErrChatNotFound.
+func (r *scheduledMessageRepository) Create(ctx context.Context, message *models.ScheduledMessage) error {
+	err := conn(ctx, r.db).Create(message).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrForeignKeyViolated) {
+		return ErrChatNotFound
+	}
+	return err
+}
+
+// GetByID получает отложенное сообщение, nil, nil если его нет (уже отправлено или отменено)
+func (r *scheduledMessageRepository) GetByID(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	var message models.ScheduledMessage
+	err := conn(ctx, r.db).First(&message, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// GetForUpdate получает отложенное сообщение как GetByID и блокирует строку до конца
+// транзакции: ClaimDue другой реплики её пропустит, а захват, начатый раньше, сначала
+// завершится. Вызывается внутри транзакции.
+func (r *scheduledMessageRepository) GetForUpdate(ctx context.Context, id int64) (*models.ScheduledMessage, error) {
+	var message models.ScheduledMessage
+	err := conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		First(&message, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// ListByAuthor получает отложенные сообщения автора в чате, ближайшие первыми
+func (r *scheduledMessageRepository) ListByAuthor(ctx context.Context, chatID, authorID int64) ([]models.ScheduledMessage, error) {
+	messages := make([]models.ScheduledMessage, 0)
+	err := conn(ctx, r.db).
+		Where("chat_id = ? AND author_id = ?", chatID, authorID).
+		Order("send_at ASC, id ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+// Update сохраняет текст, формат, срок жизни, время отправки и состояние попыток. Если сообщение
+// уже отправлено или отменено, возвращает ErrScheduledMessageNotFound.
+func (r *scheduledMessageRepository) Update(ctx context.Context, message *models.ScheduledMessage) error {
+	message.UpdatedAt = time.Now()
+
+	result := conn(ctx, r.db).
+		Model(&models.ScheduledMessage{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]any{
+			"text":          message.Text,
+			"format":        message.Format,
+			"expires_in":    message.ExpiresIn,
+			"send_at":       message.SendAt,
+			"attempts":      message.Attempts,
+			"last_error":    message.LastError,
+			"failed_at":     message.FailedAt,
+			"claimed_until": message.ClaimedUntil,
+			"updated_at":    message.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduledMessageNotFound
+	}
+	return nil
+}
+
+// Delete убирает сообщение из очереди. Если его уже нет, возвращает ErrScheduledMessageNotFound.
+func (r *scheduledMessageRepository) Delete(ctx context.Context, id int64) error {
+	result := conn(ctx, r.db).Delete(&models.ScheduledMessage{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduledMessageNotFound
+	}
+	return nil
+}
+
+// ClaimDue захватывает до limit сообщений, которым пора уйти (кроме брошенных и уже захваченных),
+// до момента until. Строки выбираются с FOR UPDATE SKIP LOCKED, поэтому параллельный захват
+// другой реплики их пропускает, а после коммита их не берут до until.
+// Вызывается внутри транзакции.
+func (r *scheduledMessageRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.ScheduledMessage, error) {
+	db := conn(ctx, r.db)
+
+	var messages []models.ScheduledMessage
+	err := db.
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Where("send_at <= ? AND failed_at IS NULL AND (claimed_until IS NULL OR claimed_until <= ?)", now, now).
+		Order("send_at ASC, id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	if err != nil || len(messages) == 0 {
+		return messages, err
+	}
+
+	ids := make([]int64, 0, len(messages))
+	for i := range messages {
+		ids = append(ids, messages[i].ID)
+		messages[i].ClaimedUntil = &until
+	}
+
+	err = db.Model(&models.ScheduledMessage{}).
+		Where("id IN ?", ids).
+		Update("claimed_until", until).Error
+	return messages, err
+}
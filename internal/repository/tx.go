@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+//go:generate mockgen -destination=mocks/mock_tx_manager.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository TxManager
+
+// TxManager объединяет несколько вызовов репозиториев в одну транзакцию (unit of work)
+type TxManager interface {
+	// WithinTransaction выполняет fn в транзакции. Все репозитории, вызванные с ctx,
+	// переданным в fn, работают внутри этой транзакции. Если fn вернула ошибку,
+	// транзакция откатывается. Вложенные вызовы переиспользуют внешнюю транзакцию.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db}
+}
+
+// WithinTransaction выполняет fn внутри gorm.DB.Transaction
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn возвращает текущую транзакцию из ctx, а если её нет — обычное соединение
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// inTransaction сообщает, выполняется ли запрос внутри TxManager.WithinTransaction
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// translateError приводит ошибки драйвера БД к ошибкам GORM (gorm.ErrForeignKeyViolated и т.д.)
+// независимо от того, включён ли TranslateError в конфигурации
+func translateError(db *gorm.DB, err error) error {
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_user_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository UserRepository
+
+var (
+	ErrUsernameTaken = errors.New("username already taken")
+	ErrUserNotFound  = errors.New("user not found")
+)
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id int64) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+}
+
+type userRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
+}
+
+// Create создаёт пользователя. Если имя занято, возвращает ErrUsernameTaken.
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	err := conn(ctx, r.db).Create(user).Error
+	if errors.Is(translateError(r.db, err), gorm.ErrDuplicatedKey) {
+		return ErrUsernameTaken
+	}
+	return err
+}
+
+// GetByID получает пользователя по ID, nil, nil если его нет
+func (r *userRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).First(&user, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetByUsername получает пользователя по имени, nil, nil если его нет
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	err := conn(ctx, r.db).Where("username = ?", username).First(&user).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_webhook_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository WebhookRepository
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id int64) error
+	GetByID(ctx context.Context, id int64) (*models.Webhook, error)
+	List(ctx context.Context) ([]models.Webhook, error)
+	ListActive(ctx context.Context) ([]models.Webhook, error)
+	SetActive(ctx context.Context, id int64, active bool) error
+	RecordFailure(ctx context.Context, id int64, disableAfter int) (disabled bool, err error)
+	ResetFailures(ctx context.Context, id int64) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// Create создаёт подписку
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	return conn(ctx, r.db).Create(webhook).Error
+}
+
+// Delete удаляет подписку вместе с журналом доставок
+func (r *webhookRepository) Delete(ctx context.Context, id int64) error {
+	result := conn(ctx, r.db).Delete(&models.Webhook{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// GetByID получает подписку по ID, nil, nil если её нет
+func (r *webhookRepository) GetByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := conn(ctx, r.db).First(&webhook, id).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// List получает все подписки
+func (r *webhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := conn(ctx, r.db).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListActive получает включённые подписки
+func (r *webhookRepository) ListActive(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := conn(ctx, r.db).Where("active = ?", true).Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// SetActive включает или выключает подписку. Включение сбрасывает счётчик ошибок.
+func (r *webhookRepository) SetActive(ctx context.Context, id int64, active bool) error {
+	updates := map[string]any{"active": active}
+	if active {
+		updates["consecutive_failures"] = 0
+		updates["disabled_at"] = nil
+	} else {
+		updates["disabled_at"] = time.Now()
+	}
+
+	result := conn(ctx, r.db).Model(&models.Webhook{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// RecordFailure увеличивает счётчик ошибок подряд и выключает подписку,
+// когда он достигает disableAfter. Возвращает true, если подписка была выключена.
+func (r *webhookRepository) RecordFailure(ctx context.Context, id int64, disableAfter int) (bool, error) {
+	err := conn(ctx, r.db).
+		Model(&models.Webhook{}).
+		Where("id = ?", id).
+		Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error
+	if err != nil {
+		return false, err
+	}
+
+	result := conn(ctx, r.db).
+		Model(&models.Webhook{}).
+		Where("id = ? AND active = ? AND consecutive_failures >= ?", id, true, disableAfter).
+		Updates(map[string]any{"active": false, "disabled_at": time.Now()})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResetFailures обнуляет счётчик ошибок подряд после успешной доставки
+func (r *webhookRepository) ResetFailures(ctx context.Context, id int64) error {
+	return conn(ctx, r.db).
+		Model(&models.Webhook{}).
+		Where("id = ? AND consecutive_failures <> 0", id).
+		Update("consecutive_failures", 0).Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+)
+
+//go:generate mockgen -destination=mocks/mock_webhook_delivery_repository.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/repository WebhookDeliveryRepository
+
+type WebhookDeliveryRepository interface {
+	Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error
+	ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListByWebhook(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error)
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+// Enqueue ставит доставку в очередь. Повторная постановка того же события
+// тому же вебхуку игнорируется, поэтому повторная публикация из outbox безопасна.
+func (r *webhookDeliveryRepository) Enqueue(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(delivery).Error
+}
+
+// ClaimDue захватывает до limit доставок, время очередной попытки которых наступило,
+// сдвигая next_attempt_at на until: это срок захвата, до него доставку не возьмёт никто.
+// Строки выбираются с FOR UPDATE SKIP LOCKED, поэтому параллельный захват другой
+// реплики их пропускает. Результат попытки Update записывает поверх срока захвата,
+// а если реплика упала, доставка снова станет due в until. Вызывается внутри транзакции.
+func (r *webhookDeliveryRepository) ClaimDue(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error) {
+	db := conn(ctx, r.db)
+
+	var deliveries []models.WebhookDelivery
+	err := db.
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+		Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	ids := make([]int64, 0, len(deliveries))
+	for i := range deliveries {
+		ids = append(ids, deliveries[i].ID)
+		deliveries[i].NextAttemptAt = until
+	}
+
+	err = db.Model(&models.WebhookDelivery{}).
+		Where("id IN ?", ids).
+		Update("next_attempt_at", until).Error
+	return deliveries, err
+}
+
+// Update сохраняет результат попытки доставки
+func (r *webhookDeliveryRepository) Update(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error
+}
+
+// ListByWebhook получает журнал последних доставок вебхука (новые первыми)
+func (r *webhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := conn(ctx, r.db).
+		Where("webhook_id = ?", webhookID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	return deliveries, err
+}
@@ -0,0 +1,18 @@
+// Package retry содержит расчёт задержек между повторными попытками, общий для
+// outbox, исходящих вебхуков и отложенных сообщений.
+package retry
+
+import "time"
+
+// Backoff возвращает экспоненциальную задержку перед попыткой номер attempt:
+// min * 2^(attempt-1), но не больше max
+func Backoff(attempt int, min, max time.Duration) time.Duration {
+	delay := min
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
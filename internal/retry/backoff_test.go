@@ -0,0 +1,15 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(1, time.Second, time.Minute))
+	assert.Equal(t, 2*time.Second, Backoff(2, time.Second, time.Minute))
+	assert.Equal(t, 8*time.Second, Backoff(4, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, Backoff(20, time.Second, time.Minute))
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_attachment_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service AttachmentServiceInterface
+
+// sniffLen — сколько первых байт файла нужно http.DetectContentType
+const sniffLen = 512
+
+type AttachmentServiceInterface interface {
+	Upload(ctx context.Context, chatID int64, fileName, text string, content io.Reader) (*models.Message, error)
+	Open(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error)
+	OpenThumbnail(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error)
+}
+
+//go:generate mockgen -destination=mocks/mock_thumbnail_queue.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ThumbnailQueue
+
+// ThumbnailQueue принимает загруженные вложения на генерацию миниатюр (см. пакет thumbnail)
+type ThumbnailQueue interface {
+	Enqueue(attachment models.Attachment) bool
+}
+
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	memberRepo     repository.ChatMemberRepository
+	chatRepo       repository.ChatRepository
+	blobs          blob.Store
+	chatService    ChatServiceInterface
+	moderator      Moderator
+	thumbnails     ThumbnailQueue
+	maxSize        int64
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, blobs blob.Store, chatService ChatServiceInterface, moderator Moderator, thumbnails ThumbnailQueue, maxSize int64) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		memberRepo:     memberRepo,
+		chatRepo:       chatRepo,
+		blobs:          blobs,
+		chatService:    chatService,
+		moderator:      moderator,
+		thumbnails:     thumbnails,
+		maxSize:        maxSize,
+	}
+}
+
+// Upload сохраняет файл в blob-хранилище и создаёт сообщение с вложением.
+// Текст сообщения — подпись к файлу, если она пустая, используется имя файла.
+// Тип содержимого определяется по самим данным, а не по заголовкам клиента.
+// Доступ к чату и подпись проверяются до записи файла: запрос, который всё равно
+// будет отклонён, не должен писать в хранилище.
+func (s *AttachmentService) Upload(ctx context.Context, chatID int64, fileName, text string, content io.Reader) (*models.Message, error) {
+	fileName = strings.TrimSpace(filepath.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return nil, errors.New("file name cannot be empty")
+	}
+	if len(fileName) > 255 {
+		return nil, errors.New("file name must be at most 255 characters")
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		text = fileName
+	}
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+	if s.moderator != nil {
+		// CreateMessage проверит подпись ещё раз и применит маскировку или пометку
+		result, err := s.moderator.Check(ctx, chatID, text)
+		if err != nil {
+			return nil, err
+		}
+		if result.Rejected {
+			return nil, fmt.Errorf("%w: %s", errRejected, strings.Join(result.Reasons, "; "))
+		}
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("file cannot be empty")
+	}
+	head = head[:n]
+
+	suffix, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("chats/%d/%s", chatID, suffix[:32])
+
+	// Читаем на байт больше лимита, чтобы отличить файл ровно maxSize от слишком большого
+	size, err := s.blobs.Put(ctx, key, io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		s.blobs.Delete(context.WithoutCancel(ctx), key)
+		return nil, errors.New("file too large")
+	}
+
+	attachment := models.Attachment{
+		FileName:    fileName,
+		ContentType: http.DetectContentType(head),
+		Size:        size,
+		StorageKey:  key,
+	}
+	message, err := s.chatService.CreateMessage(ctx, chatID, models.MessageInput{
+		Text:        text,
+		Format:      models.MessageFormatPlain,
+		Attachments: []models.Attachment{attachment},
+	})
+	if err != nil {
+		// Сообщение не создано — файл больше никому не нужен
+		s.blobs.Delete(context.WithoutCancel(ctx), key)
+		return nil, err
+	}
+
+	// Миниатюра генерируется только после коммита, иначе воркер может не увидеть вложение
+	s.thumbnails.Enqueue(message.Attachments[0])
+
+	return message, nil
+}
+
+// Open возвращает метаданные вложения и его содержимое. Вызывающий обязан закрыть файл.
+// Скачать вложение может любой, кому видны сообщения его чата.
+func (s *AttachmentService) Open(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil {
+		return nil, nil, errors.New("attachment not found")
+	}
+
+	file, err := s.blobs.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, errors.New("attachment not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, file, nil
+}
+
+// OpenThumbnail возвращает вложение и содержимое его миниатюры. Вызывающий обязан закрыть файл.
+// Миниатюра доступна тем же, кому и файл.
+func (s *AttachmentService) OpenThumbnail(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil || attachment.ThumbnailKey == "" {
+		return nil, nil, errors.New("thumbnail not found")
+	}
+
+	file, err := s.blobs.Open(ctx, attachment.ThumbnailKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, errors.New("thumbnail not found")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, file, nil
+}
+
+// get получает вложение, если текущему пользователю виден его чат: вложения комнат
+// открыты так же, как их сообщения, а вложения личного чата — только его участникам.
+// Недоступное вложение неотличимо от несуществующего (nil), чтобы ID нельзя было подобрать перебором.
+func (s *AttachmentService) get(ctx context.Context, id int64) (*models.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil || attachment == nil {
+		return nil, err
+	}
+
+	_, err = authorizeChat(ctx, s.memberRepo, s.chatRepo, attachment.ChatID)
+	if errors.Is(err, errForbidden) || errors.Is(err, errChatNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// deleteBlobs удаляет из хранилища файлы и миниатюры вложений, строки которых уже удалены
+// из базы. Вызывается только после коммита: при откате файлы ещё нужны. Ошибка хранилища
+// лишь логируется — удаление в базе уже состоялось.
+func deleteBlobs(ctx context.Context, blobs blob.Store, attachments []models.Attachment) {
+	ctx = context.WithoutCancel(ctx)
+	for _, attachment := range attachments {
+		for _, key := range []string{attachment.StorageKey, attachment.ThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := blobs.Delete(ctx, key); err != nil {
+				log.Printf("blob: delete %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// attachmentsOf собирает вложения удалённых сообщений
+func attachmentsOf(messages []models.Message) []models.Attachment {
+	var attachments []models.Attachment
+	for _, message := range messages {
+		attachments = append(attachments, message.Attachments...)
+	}
+	return attachments
+}
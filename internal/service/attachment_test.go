@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/moderation"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	serviceMocks "github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+// countFiles считает файлы в каталоге blob-хранилища
+func countFiles(t *testing.T, root string) int {
+	t.Helper()
+
+	count := 0
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestUpload(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		text        string
+		content     string
+		setupMock   func(*serviceMocks.MockChatServiceInterface)
+		chat        *models.Chat
+		missingChat bool
+		rejected    bool
+		expectQueue bool
+		expectError bool
+		errorMsg    string
+		expectFiles int
+	}{
+		{
+			name:     "успешная загрузка без подписи",
+			fileName: "../../report.txt",
+			content:  "hello",
+			setupMock: func(cs *serviceMocks.MockChatServiceInterface) {
+				cs.EXPECT().
+					CreateMessage(gomock.Any(), int64(1), gomock.Cond(func(input models.MessageInput) bool {
+						return input.Text == "report.txt" && input.Format == models.MessageFormatPlain
+					})).
+					DoAndReturn(func(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
+						if len(input.Attachments) != 1 {
+							t.Fatalf("ожидалось одно вложение, получено %d", len(input.Attachments))
+						}
+						attachment := input.Attachments[0]
+						if attachment.FileName != "report.txt" {
+							t.Errorf("имя файла должно очищаться от пути, получено '%s'", attachment.FileName)
+						}
+						if attachment.ContentType != "text/plain; charset=utf-8" {
+							t.Errorf("неверный content type: %s", attachment.ContentType)
+						}
+						if attachment.Size != 5 {
+							t.Errorf("неверный size: %d", attachment.Size)
+						}
+						attachment.ID = 1
+						return &models.Message{ID: 7, ChatID: 1, Text: "report.txt", Attachments: []models.Attachment{attachment}}, nil
+					})
+			},
+			expectQueue: true,
+			expectFiles: 1,
+		},
+		{
+			name:     "PNG определяется по содержимому",
+			fileName: "image.txt",
+			text:     "смотрите",
+			content:  "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 16),
+			setupMock: func(cs *serviceMocks.MockChatServiceInterface) {
+				cs.EXPECT().
+					CreateMessage(gomock.Any(), int64(1), gomock.Cond(func(input models.MessageInput) bool {
+						return input.Text == "смотрите" && len(input.Attachments) == 1 && input.Attachments[0].ContentType == "image/png"
+					})).
+					DoAndReturn(func(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
+						return &models.Message{ID: 7, ChatID: 1, Attachments: input.Attachments}, nil
+					})
+			},
+			expectQueue: true,
+			expectFiles: 1,
+		},
+		{
+			name:        "слишком большой файл",
+			fileName:    "big.bin",
+			content:     strings.Repeat("x", 33),
+			setupMock:   func(*serviceMocks.MockChatServiceInterface) {},
+			expectError: true,
+			errorMsg:    "file too large",
+		},
+		{
+			name:        "пустой файл",
+			fileName:    "empty.txt",
+			setupMock:   func(*serviceMocks.MockChatServiceInterface) {},
+			expectError: true,
+			errorMsg:    "file cannot be empty",
+		},
+		{
+			name:     "сообщение не создано",
+			fileName: "report.txt",
+			content:  "hello",
+			setupMock: func(cs *serviceMocks.MockChatServiceInterface) {
+				cs.EXPECT().CreateMessage(gomock.Any(), int64(1), gomock.Any()).Return(nil, errors.New("chat not found"))
+			},
+			expectError: true,
+			errorMsg:    "chat not found",
+		},
+		// Отказы ниже приходят до записи файла в хранилище
+		{
+			name:        "чат не найден",
+			fileName:    "report.txt",
+			content:     "hello",
+			setupMock:   func(*serviceMocks.MockChatServiceInterface) {},
+			missingChat: true,
+			expectError: true,
+			errorMsg:    "chat not found",
+		},
+		{
+			name:        "личный чат без участия",
+			fileName:    "report.txt",
+			content:     "hello",
+			setupMock:   func(*serviceMocks.MockChatServiceInterface) {},
+			chat:        &models.Chat{ID: 1, Type: models.ChatTypeDM},
+			expectError: true,
+			errorMsg:    "forbidden",
+		},
+		{
+			name:        "подпись отклонена модерацией",
+			fileName:    "report.txt",
+			text:        "спам",
+			content:     "hello",
+			setupMock:   func(*serviceMocks.MockChatServiceInterface) {},
+			rejected:    true,
+			expectError: true,
+			errorMsg:    "message rejected: спам",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			root := t.TempDir()
+			blobs, err := blob.NewLocalStore(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chatService := serviceMocks.NewMockChatServiceInterface(ctrl)
+			tt.setupMock(chatService)
+
+			thumbnails := serviceMocks.NewMockThumbnailQueue(ctrl)
+			if tt.expectQueue {
+				thumbnails.EXPECT().Enqueue(gomock.Any()).Return(true)
+			}
+
+			chat := &models.Chat{ID: 1, Type: models.ChatTypeRoom}
+			if tt.chat != nil {
+				chat = tt.chat
+			}
+			chatRepo := mocks.NewMockChatRepository(ctrl)
+			if tt.missingChat {
+				chatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, nil).AnyTimes()
+			} else {
+				chatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(chat, nil).AnyTimes()
+			}
+			memberRepo := mocks.NewMockChatMemberRepository(ctrl)
+			memberRepo.EXPECT().IsMember(gomock.Any(), int64(1), int64(3)).Return(false, nil).AnyTimes()
+
+			moderator := serviceMocks.NewMockModerator(ctrl)
+			moderator.EXPECT().Check(gomock.Any(), int64(1), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, chatID int64, text string) (moderation.Result, error) {
+					if tt.rejected {
+						return moderation.Result{Rejected: true, Reasons: []string{text}}, nil
+					}
+					return moderation.Result{Text: text}, nil
+				}).
+				AnyTimes()
+
+			service := NewAttachmentService(mocks.NewMockAttachmentRepository(ctrl), memberRepo, chatRepo, blobs, chatService, moderator, thumbnails, 32)
+
+			ctx := auth.WithUserID(context.Background(), 3)
+			message, err := service.Upload(ctx, 1, tt.fileName, tt.text, strings.NewReader(tt.content))
+
+			if files := countFiles(t, root); files != tt.expectFiles {
+				t.Errorf("ожидалось файлов в хранилище: %d, найдено %d", tt.expectFiles, files)
+			}
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("ожидалась ошибка, но её не было")
+				} else if err.Error() != tt.errorMsg {
+					t.Errorf("ожидалась ошибка '%s', получена '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if len(message.Attachments) != 1 {
+				t.Errorf("ожидалось одно вложение, получено %d", len(message.Attachments))
+			}
+		})
+	}
+}
+
+func TestOpenAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blobs.Put(context.Background(), "chats/1/file", strings.NewReader("content")); err != nil {
+		t.Fatal(err)
+	}
+
+	attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Attachment{ID: 1, ChatID: 1, StorageKey: "chats/1/file"}, nil).Times(2)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Attachment{ID: 2, ChatID: 1, StorageKey: "chats/1/missing"}, nil)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(nil, nil)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(4)).Return(&models.Attachment{ID: 4, ChatID: 2, StorageKey: "chats/2/file"}, nil)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Attachment{ID: 5, ChatID: 3, StorageKey: "chats/3/file"}, nil).Times(2)
+	chatRepo := mocks.NewMockChatRepository(ctrl)
+	chatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil).AnyTimes()
+	chatRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Chat{ID: 2, Type: models.ChatTypeDM}, nil)
+	chatRepo.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&models.Chat{ID: 3, Type: models.ChatTypeDM}, nil).Times(2)
+	memberRepo := mocks.NewMockChatMemberRepository(ctrl)
+	memberRepo.EXPECT().IsMember(gomock.Any(), int64(2), int64(3)).Return(false, nil)
+	memberRepo.EXPECT().IsMember(gomock.Any(), int64(3), int64(3)).Return(true, nil)
+
+	service := NewAttachmentService(attachmentRepo, memberRepo, chatRepo, blobs, serviceMocks.NewMockChatServiceInterface(ctrl), nil, serviceMocks.NewMockThumbnailQueue(ctrl), 32)
+
+	ctx := auth.WithUserID(context.Background(), 3)
+	_, file, err := service.Open(ctx, 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "content" {
+		t.Errorf("ожидалось содержимое 'content', получено %q", content)
+	}
+
+	// Вложение чужого личного чата неотличимо от несуществующего; файл личного чата
+	// есть у участника, но его нет в хранилище
+	for _, id := range []int64{2, 3, 4, 5} {
+		if _, _, err := service.Open(ctx, id); err == nil || err.Error() != "attachment not found" {
+			t.Errorf("вложение %d: ожидалась ошибка 'attachment not found', получена %v", id, err)
+		}
+	}
+
+	// Вложения комнаты открыты, как и её сообщения, а личного чата — только участникам
+	if _, file, err := service.Open(context.Background(), 1); err != nil {
+		t.Errorf("вложение комнаты без пользователя: неожиданная ошибка %v", err)
+	} else {
+		file.Close()
+	}
+	if _, _, err := service.Open(context.Background(), 5); err == nil || err.Error() != "authentication required" {
+		t.Errorf("ожидалась ошибка 'authentication required', получена %v", err)
+	}
+}
+
+func TestOpenThumbnail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := blobs.Put(context.Background(), "chats/1/file-thumb", strings.NewReader("thumb")); err != nil {
+		t.Fatal(err)
+	}
+
+	attachmentRepo := mocks.NewMockAttachmentRepository(ctrl)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Attachment{ID: 1, ChatID: 1, ThumbnailKey: "chats/1/file-thumb"}, nil).Times(2)
+	attachmentRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Attachment{ID: 2, ChatID: 1}, nil)
+	chatRepo := mocks.NewMockChatRepository(ctrl)
+	chatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, nil).Times(3)
+	memberRepo := mocks.NewMockChatMemberRepository(ctrl)
+	memberRepo.EXPECT().IsMember(gomock.Any(), int64(1), int64(3)).Return(true, nil).Times(2)
+	memberRepo.EXPECT().IsMember(gomock.Any(), int64(1), int64(4)).Return(false, nil)
+
+	service := NewAttachmentService(attachmentRepo, memberRepo, chatRepo, blobs, serviceMocks.NewMockChatServiceInterface(ctrl), nil, serviceMocks.NewMockThumbnailQueue(ctrl), 32)
+
+	ctx := auth.WithUserID(context.Background(), 3)
+	_, file, err := service.OpenThumbnail(ctx, 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	file.Close()
+	if string(content) != "thumb" {
+		t.Errorf("ожидалось содержимое 'thumb', получено %q", content)
+	}
+
+	if _, _, err := service.OpenThumbnail(ctx, 2); err == nil || err.Error() != "thumbnail not found" {
+		t.Errorf("ожидалась ошибка 'thumbnail not found', получена %v", err)
+	}
+	if _, _, err := service.OpenThumbnail(auth.WithUserID(context.Background(), 4), 1); err == nil || err.Error() != "thumbnail not found" {
+		t.Errorf("посторонний: ожидалась ошибка 'thumbnail not found', получена %v", err)
+	}
+}
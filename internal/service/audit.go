@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/GlebMoskalev/chat-golang/internal/audit"
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_audit_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service AuditServiceInterface
+
+type AuditServiceInterface interface {
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
+
+type AuditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// List получает записи журнала аудита по фильтру, новые первыми
+func (s *AuditService) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Since != nil && filter.Until != nil && !filter.Since.Before(*filter.Until) {
+		return nil, errors.New("since must be before until")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 200 {
+		filter.Limit = 200
+	}
+
+	return s.auditRepo.List(ctx, filter)
+}
+
+// recordAudit дописывает в журнал аудита запись о действии entry. Исполнитель, ID запроса
+// и IP берутся из контекста, before и after сохраняются JSON-снимками (nil — без снимка).
+// Вызывается в той же транзакции, что и само действие: без записи в журнале действие откатывается.
+func recordAudit(ctx context.Context, auditRepo repository.AuditRepository, entry models.AuditEntry, before, after any) error {
+	userID, ok := auth.UserID(ctx)
+	switch {
+	case auth.IsAdmin(ctx):
+		entry.ActorType = models.AuditActorAdmin
+	case ok:
+		entry.ActorType = models.AuditActorUser
+	default:
+		entry.ActorType = models.AuditActorSystem
+	}
+	if ok {
+		entry.ActorID = &userID
+	}
+
+	request := audit.FromContext(ctx)
+	entry.RequestID = request.ID
+	entry.IP = request.IP
+
+	var err error
+	if entry.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return err
+	}
+
+	return auditRepo.Add(ctx, &entry)
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockAudit.EXPECT().List(gomock.Any(), models.AuditFilter{Limit: 50}).Return(nil, nil)
+	mockAudit.EXPECT().List(gomock.Any(), models.AuditFilter{Limit: 200}).Return(nil, nil)
+
+	service := NewAuditService(mockAudit)
+
+	if _, err := service.List(context.Background(), models.AuditFilter{}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if _, err := service.List(context.Background(), models.AuditFilter{Limit: 1000}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	since := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	until := since.Add(-time.Hour)
+	_, err := service.List(context.Background(), models.AuditFilter{Since: &since, Until: &until})
+	if err == nil || err.Error() != "since must be before until" {
+		t.Errorf("ожидалась ошибка диапазона, получена %v", err)
+	}
+}
+
+func TestRecordAuditActor(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		expectType  string
+		expectActor bool
+	}{
+		{name: "пользователь", ctx: auth.WithUserID(context.Background(), 7), expectType: models.AuditActorUser, expectActor: true},
+		{name: "администратор", ctx: auth.WithAdmin(context.Background()), expectType: models.AuditActorAdmin},
+		{name: "фоновая задача", ctx: context.Background(), expectType: models.AuditActorSystem},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var entry *models.AuditEntry
+			mockAudit := mocks.NewMockAuditRepository(ctrl)
+			mockAudit.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *models.AuditEntry) error {
+				entry = e
+				return nil
+			})
+
+			err := recordAudit(tt.ctx, mockAudit, models.AuditEntry{Action: models.AuditChatDelete}, nil, map[string]int{"id": 1})
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if entry.ActorType != tt.expectType || (entry.ActorID != nil) != tt.expectActor {
+				t.Errorf("исполнитель = %s/%v, ожидался %s", entry.ActorType, entry.ActorID, tt.expectType)
+			}
+			if entry.Before != nil || string(entry.After) != `{"id":1}` {
+				t.Errorf("неожиданные снимки: before=%s after=%s", entry.Before, entry.After)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_block_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service BlockServiceInterface
+
+type BlockServiceInterface interface {
+	Block(ctx context.Context, userID int64) error
+	Unblock(ctx context.Context, userID int64) error
+	ListBlocked(ctx context.Context) ([]models.UserBlock, error)
+}
+
+type BlockService struct {
+	blockRepo repository.BlockRepository
+	userRepo  repository.UserRepository
+}
+
+func NewBlockService(blockRepo repository.BlockRepository, userRepo repository.UserRepository) *BlockService {
+	return &BlockService{blockRepo: blockRepo, userRepo: userRepo}
+}
+
+// Block скрывает от текущего пользователя сообщения userID в GetChat.
+// Блокировка односторонняя: заблокированный по-прежнему видит сообщения блокирующего.
+func (s *BlockService) Block(ctx context.Context, userID int64) error {
+	blockerID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+	if blockerID == userID {
+		return errors.New("cannot block yourself")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return errors.New("user not found")
+	}
+
+	if err := s.blockRepo.Block(ctx, blockerID, userID); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// Unblock снимает блокировку userID
+func (s *BlockService) Unblock(ctx context.Context, userID int64) error {
+	blockerID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+
+	return s.blockRepo.Unblock(ctx, blockerID, userID)
+}
+
+// ListBlocked получает пользователей, заблокированных текущим
+func (s *BlockService) ListBlocked(ctx context.Context) ([]models.UserBlock, error) {
+	blockerID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	return s.blockRepo.ListBlocked(ctx, blockerID)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBlockUser(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userID    int64
+		setupMock func(*mocks.MockBlockRepository, *mocks.MockUserRepository)
+		expectErr string
+	}{
+		{
+			name:   "успешная блокировка",
+			ctx:    auth.WithUserID(context.Background(), 1),
+			userID: 2,
+			setupMock: func(br *mocks.MockBlockRepository, ur *mocks.MockUserRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.User{ID: 2}, nil)
+				br.EXPECT().Block(gomock.Any(), int64(1), int64(2)).Return(nil)
+			},
+		},
+		{
+			name:   "нет пользователя",
+			ctx:    auth.WithUserID(context.Background(), 1),
+			userID: 2,
+			setupMock: func(br *mocks.MockBlockRepository, ur *mocks.MockUserRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(2)).Return(nil, nil)
+			},
+			expectErr: "user not found",
+		},
+		{
+			name:      "самого себя",
+			ctx:       auth.WithUserID(context.Background(), 1),
+			userID:    1,
+			setupMock: func(br *mocks.MockBlockRepository, ur *mocks.MockUserRepository) {},
+			expectErr: "cannot block yourself",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			userID:    2,
+			setupMock: func(br *mocks.MockBlockRepository, ur *mocks.MockUserRepository) {},
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockBlocks := mocks.NewMockBlockRepository(ctrl)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			tt.setupMock(mockBlocks, mockUsers)
+
+			err := NewBlockService(mockBlocks, mockUsers).Block(tt.ctx, tt.userID)
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestGetChatHidesBlockedAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1}, nil)
+	// Фильтрация по блокировкам — в репозитории, сервис передаёт ему текущего пользователя
+	mockMessageRepo.EXPECT().GetByChatID(gomock.Any(), int64(1), int64(42), 20).Return([]models.Message{}, nil)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	if _, err := service.GetChatWithMessages(auth.WithUserID(context.Background(), 42), 1, 20); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
@@ -2,35 +2,72 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/linkpreview"
+	"github.com/GlebMoskalev/chat-golang/internal/markdown"
 	"github.com/GlebMoskalev/chat-golang/internal/models"
 	"github.com/GlebMoskalev/chat-golang/internal/repository"
 )
 
 //go:generate mockgen -destination=mocks/mock_chat_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ChatServiceInterface
 
+// MaxBatchMessages — сколько сообщений можно отправить одним пакетом
+const MaxBatchMessages = 100
+
+// MaxMessageTTL — наибольший срок жизни исчезающего сообщения
+const MaxMessageTTL = 7 * 24 * time.Hour
+
 type ChatServiceInterface interface {
 	CreateChat(ctx context.Context, title string) (*models.Chat, error)
 	GetChatWithMessages(ctx context.Context, chatID int64, limit int) (*models.ChatWithMessages, error)
 	DeleteChat(ctx context.Context, chatID int64) error
-	CreateMessage(ctx context.Context, chatID int64, text string) (*models.Message, error)
+	CreateMessage(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error)
+	CreateMessages(ctx context.Context, chatID int64, inputs []models.MessageInput, atomic bool) ([]models.BatchItemResult, error)
+	ListChats(ctx context.Context) ([]models.ChatSummary, error)
+	MarkRead(ctx context.Context, chatID, messageID int64) error
 }
 
 type ChatService struct {
-	chatRepo    repository.ChatRepository
-	messageRepo repository.MessageRepository
+	chatRepo        repository.ChatRepository
+	messageRepo     repository.MessageRepository
+	txManager       repository.TxManager
+	outboxRepo      repository.OutboxRepository
+	linkPreviewRepo repository.LinkPreviewRepository
+	memberRepo      repository.ChatMemberRepository
+	mentionRepo     repository.MentionRepository
+	pinRepo         repository.PinRepository
+	auditRepo       repository.AuditRepository
+	moderator       Moderator
+	attachmentRepo  repository.AttachmentRepository
+	blobs           blob.Store
 }
 
-func NewChatService(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository) *ChatService {
+func NewChatService(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, txManager repository.TxManager, outboxRepo repository.OutboxRepository, linkPreviewRepo repository.LinkPreviewRepository, memberRepo repository.ChatMemberRepository, mentionRepo repository.MentionRepository, pinRepo repository.PinRepository, auditRepo repository.AuditRepository, moderator Moderator, attachmentRepo repository.AttachmentRepository, blobs blob.Store) *ChatService {
 	return &ChatService{
-		chatRepo:    chatRepo,
-		messageRepo: messageRepo,
+		chatRepo:        chatRepo,
+		messageRepo:     messageRepo,
+		txManager:       txManager,
+		outboxRepo:      outboxRepo,
+		linkPreviewRepo: linkPreviewRepo,
+		memberRepo:      memberRepo,
+		mentionRepo:     mentionRepo,
+		pinRepo:         pinRepo,
+		auditRepo:       auditRepo,
+		moderator:       moderator,
+		attachmentRepo:  attachmentRepo,
+		blobs:           blobs,
 	}
 }
 
-// CreateChat создаёт новый чат
+// CreateChat создаёт новый чат. Текущий пользователь, если он известен, становится владельцем
+// и первым участником.
 func (s *ChatService) CreateChat(ctx context.Context, title string) (*models.Chat, error) {
 	title = strings.TrimSpace(title)
 	if title == "" {
@@ -42,16 +79,33 @@ func (s *ChatService) CreateChat(ctx context.Context, title string) (*models.Cha
 
 	chat := &models.Chat{
 		Title: title,
+		Type:  models.ChatTypeRoom,
+	}
+	if userID, ok := auth.UserID(ctx); ok {
+		chat.OwnerID = &userID
 	}
 
-	if err := s.chatRepo.Create(ctx, chat); err != nil {
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.chatRepo.Create(ctx, chat); err != nil {
+			return err
+		}
+		if chat.OwnerID != nil {
+			if err := s.memberRepo.Add(ctx, chat.ID, *chat.OwnerID); err != nil {
+				return err
+			}
+		}
+		return recordEvent(ctx, s.outboxRepo, models.EventChatCreated, chat.ID, chat)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return chat, nil
 }
 
-// GetChatWithMessages получает чат с сообщениями
+// GetChatWithMessages получает чат с сообщениями. Для каждого сообщения заполняются read_by и pinned.
+// Сообщения пользователей, которых заблокировал текущий пользователь, не возвращаются.
+// Личный чат видят только его участники.
 func (s *ChatService) GetChatWithMessages(ctx context.Context, chatID int64, limit int) (*models.ChatWithMessages, error) {
 	if limit <= 0 {
 		limit = 20
@@ -60,56 +114,571 @@ func (s *ChatService) GetChatWithMessages(ctx context.Context, chatID int64, lim
 		limit = 100
 	}
 
-	chat, err := s.chatRepo.GetByID(ctx, chatID)
+	var result *models.ChatWithMessages
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		chat, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID)
+		if err != nil {
+			return err
+		}
+
+		viewerID, _ := auth.UserID(ctx)
+		messages, err := s.messageRepo.GetByChatID(ctx, chatID, viewerID, limit)
+		if err != nil {
+			return err
+		}
+
+		states, err := s.memberRepo.ListReadStates(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		fillReadBy(messages, states)
+
+		pins, err := s.pinRepo.ListByChat(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		pinned := make(map[int64]bool, len(pins))
+		for _, pin := range pins {
+			pinned[pin.MessageID] = true
+		}
+		for i := range messages {
+			messages[i].Pinned = pinned[messages[i].ID]
+		}
+
+		result = &models.ChatWithMessages{
+			Chat:     *chat,
+			Messages: messages,
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if chat == nil {
-		return nil, errors.New("chat not found")
+
+	for i := range result.Messages {
+		renderHTML(&result.Messages[i])
 	}
+	if err := s.attachLinkPreviews(ctx, result.Messages); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
 
-	messages, err := s.messageRepo.GetByChatID(ctx, chatID, limit)
+// attachLinkPreviews подставляет в сообщения готовые карточки ссылок из их текста.
+// Карточки, которые ещё грузятся или не загрузились, просто не показываются.
+func (s *ChatService) attachLinkPreviews(ctx context.Context, messages []models.Message) error {
+	urlsByMessage := make([][]string, len(messages))
+	var urls []string
+	for i, message := range messages {
+		urlsByMessage[i] = linkpreview.ExtractURLs(message.Text, linkpreview.MaxPerMessage)
+		urls = append(urls, urlsByMessage[i]...)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	previews, err := s.linkPreviewRepo.GetByURLs(ctx, urls)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &models.ChatWithMessages{
-		Chat:     *chat,
-		Messages: messages,
-	}, nil
+	byURL := make(map[string]models.LinkPreview, len(previews))
+	for _, preview := range previews {
+		if preview.Status == models.LinkPreviewOK {
+			byURL[preview.URL] = preview
+		}
+	}
+
+	for i := range messages {
+		for _, url := range urlsByMessage[i] {
+			if preview, ok := byURL[url]; ok {
+				messages[i].LinkPreviews = append(messages[i].LinkPreviews, preview)
+			}
+		}
+	}
+
+	return nil
 }
 
-// DeleteChat удаляет чат
+// DeleteChat удаляет чат. Комнату удаляет только её владелец, личный чат — любой из двух
+// участников, администратор — любой чат, включая комнаты без владельца. Снимок удалённого
+// чата остаётся в журнале аудита. Файлы вложений удаляются из хранилища после коммита.
 func (s *ChatService) DeleteChat(ctx context.Context, chatID int64) error {
-	return s.chatRepo.Delete(ctx, chatID)
+	var attachments []models.Attachment
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		chat, err := s.authorizeDelete(ctx, chatID)
+		if err != nil {
+			return err
+		}
+
+		// Строки вложений уйдут каскадом вместе с чатом, ключи файлов нужны до этого
+		if attachments, err = s.attachmentRepo.ListByChat(ctx, chatID); err != nil {
+			return err
+		}
+
+		if err := s.chatRepo.Delete(ctx, chatID); err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditChatDelete, TargetType: "chat", TargetID: chatID, ChatID: &chatID}
+		if err := recordAudit(ctx, s.auditRepo, entry, chat, nil); err != nil {
+			return err
+		}
+		return recordEvent(ctx, s.outboxRepo, models.EventChatDeleted, chatID, map[string]int64{"id": chatID})
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteBlobs(ctx, s.blobs, attachments)
+	return nil
+}
+
+// authorizeDelete получает чат, который текущий пользователь вправе удалить
+func (s *ChatService) authorizeDelete(ctx context.Context, chatID int64) (*models.Chat, error) {
+	if auth.IsAdmin(ctx) {
+		chat, err := s.chatRepo.GetByID(ctx, chatID)
+		if err != nil {
+			return nil, err
+		}
+		if chat == nil {
+			return nil, errChatNotFound
+		}
+		return chat, nil
+	}
+
+	chat, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat.Type != models.ChatTypeDM {
+		if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+			return nil, err
+		}
+	}
+	return chat, nil
+}
+
+// CreateMessage создаёт сообщение от имени текущего пользователя. Проверка чата и вставка выполняются в одной транзакции,
+// поэтому параллельный DeleteChat не может удалить чат между ними. Пустой format означает plain.
+// Автор становится участником чата, упоминания участников сохраняются вместе с сообщением.
+// В личный чат пишут только двое его участников, посторонний в него не вступает.
+// С ExpiresIn сообщение исчезает через указанное число секунд.
+// Перед сохранением текст проходит модерацию: сообщение может быть отклонено, замаскировано или поставлено на проверку.
+// Вложения из input сохраняются в той же транзакции и попадают в событие message.created.
+func (s *ChatService) CreateMessage(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
+	format, err := normalizeFormat(input.Format)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt, err := expiryFor(input.ExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+	text, err := normalizeText(input.Text)
+	if err != nil {
+		return nil, err
+	}
+	chat, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID)
+	if err != nil {
+		return nil, err
+	}
+	// Классификатор может отвечать долго, поэтому модерация идёт до транзакции,
+	// а не под блокировкой чата
+	text, flags, err := s.moderate(ctx, chatID, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var message *models.Message
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		exists, err := s.chatRepo.Exists(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errChatNotFound
+		}
+
+		message = &models.Message{
+			ChatID:    chatID,
+			Text:      text,
+			Format:    format,
+			ExpiresAt: expiresAt,
+		}
+		if userID, ok := auth.UserID(ctx); ok {
+			message.AuthorID = &userID
+		}
+
+		if err := s.messageRepo.Create(ctx, message); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+		if flags != nil {
+			if err := s.moderator.Flag(ctx, message, flags); err != nil {
+				return err
+			}
+		}
+		if message.AuthorID != nil {
+			if err := s.join(ctx, chat, *message.AuthorID); err != nil {
+				return err
+			}
+			// Своё сообщение автор уже видел
+			if err := s.memberRepo.MarkRead(ctx, chatID, *message.AuthorID, message.ID, message.CreatedAt); err != nil {
+				return err
+			}
+		}
+		if err := s.recordMentions(ctx, message); err != nil {
+			return err
+		}
+		// Вложения создаются до события, чтобы подписчики получили сообщение целиком
+		for _, attachment := range input.Attachments {
+			attachment.ChatID = chatID
+			attachment.MessageID = message.ID
+			attachment.UploaderID = message.AuthorID
+			if err := s.attachmentRepo.Create(ctx, &attachment); err != nil {
+				return err
+			}
+			message.Attachments = append(message.Attachments, attachment)
+		}
+		renderHTML(message)
+		return recordEvent(ctx, s.outboxRepo, models.EventMessageCreated, chatID, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
 }
 
-// CreateMessage создаёт сообщение
-func (s *ChatService) CreateMessage(ctx context.Context, chatID int64, text string) (*models.Message, error) {
-	exists, err := s.chatRepo.Exists(ctx, chatID)
+// CreateMessages создаёт пакет сообщений от имени текущего пользователя в одной транзакции.
+// Каждое сообщение проверяется по тем же правилам, что и в CreateMessage, результат
+// возвращается для каждого элемента по порядку. Без atomic невалидные сообщения пропускаются,
+// а остальные сохраняются; с atomic хотя бы одно невалидное отклоняет весь пакет
+// с ошибкой "batch rejected" (результаты при этом тоже возвращаются).
+func (s *ChatService) CreateMessages(ctx context.Context, chatID int64, inputs []models.MessageInput, atomic bool) ([]models.BatchItemResult, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("messages cannot be empty")
+	}
+	if len(inputs) > MaxBatchMessages {
+		return nil, fmt.Errorf("batch must contain at most %d messages", MaxBatchMessages)
+	}
+
+	chat, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BatchItemResult, len(inputs))
+	messages := make([]models.Message, 0, len(inputs))
+	indexes := make([]int, 0, len(inputs))
+	flags := make([][]string, 0, len(inputs))
+	for i, input := range inputs {
+		results[i].Index = i
+
+		format, err := normalizeFormat(input.Format)
+		if err == nil {
+			input.Text, err = normalizeText(input.Text)
+		}
+		var expiresAt *time.Time
+		if err == nil {
+			expiresAt, err = expiryFor(input.ExpiresIn)
+		}
+		var reasons []string
+		if err == nil {
+			input.Text, reasons, err = s.moderate(ctx, chatID, input.Text)
+			if err != nil && !isRejection(err) {
+				return nil, err
+			}
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		message := models.Message{
+			ChatID:    chatID,
+			Text:      input.Text,
+			Format:    format,
+			ExpiresAt: expiresAt,
+		}
+		if userID, ok := auth.UserID(ctx); ok {
+			message.AuthorID = &userID
+		}
+		messages = append(messages, message)
+		indexes = append(indexes, i)
+		flags = append(flags, reasons)
+	}
+
+	if atomic && len(messages) < len(inputs) {
+		return results, errors.New("batch rejected")
+	}
+	if len(messages) == 0 {
+		return results, nil
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		exists, err := s.chatRepo.Exists(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errChatNotFound
+		}
+
+		if err := s.messageRepo.CreateBatch(ctx, messages); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+
+		if authorID := messages[0].AuthorID; authorID != nil {
+			last := messages[len(messages)-1]
+			if err := s.join(ctx, chat, *authorID); err != nil {
+				return err
+			}
+			if err := s.memberRepo.MarkRead(ctx, chatID, *authorID, last.ID, last.CreatedAt); err != nil {
+				return err
+			}
+		}
+
+		for i := range messages {
+			if flags[i] != nil {
+				if err := s.moderator.Flag(ctx, &messages[i], flags[i]); err != nil {
+					return err
+				}
+			}
+			if err := s.recordMentions(ctx, &messages[i]); err != nil {
+				return err
+			}
+			renderHTML(&messages[i])
+			if err := recordEvent(ctx, s.outboxRepo, models.EventMessageCreated, chatID, messages[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, errors.New("chat not found")
+
+	for i := range messages {
+		results[indexes[i]].Message = &messages[i]
+	}
+
+	return results, nil
+}
+
+// ListChats получает чаты текущего пользователя с числом непрочитанных сообщений
+func (s *ChatService) ListChats(ctx context.Context) ([]models.ChatSummary, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	return s.memberRepo.ListChats(ctx, userID)
+}
+
+// MarkRead отмечает, что текущий пользователь прочитал чат до сообщения messageID включительно.
+// Отметка двигается только вперёд, поэтому повторная или запоздавшая отметка безопасна.
+func (s *ChatService) MarkRead(ctx context.Context, chatID, messageID int64) error {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		message, err := s.messageRepo.GetByID(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		if message == nil || message.ChatID != chatID {
+			return errors.New("message not found")
+		}
+
+		isMember, err := s.memberRepo.IsMember(ctx, chatID, userID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return errForbidden
+		}
+
+		return s.memberRepo.MarkRead(ctx, chatID, userID, message.ID, message.CreatedAt)
+	})
+}
+
+// join делает автора сообщения участником чата. Состав личного чата не меняется:
+// писать в него могут только участники, это проверено раньше.
+func (s *ChatService) join(ctx context.Context, chat *models.Chat, userID int64) error {
+	if chat.Type == models.ChatTypeDM {
+		return nil
+	}
+	return s.memberRepo.Add(ctx, chat.ID, userID)
+}
+
+// fillReadBy заполняет ReadBy сообщений по отметкам прочтения участников. Автор в read_by не попадает.
+func fillReadBy(messages []models.Message, states []models.ChatMember) {
+	for i := range messages {
+		for _, state := range states {
+			if messages[i].AuthorID != nil && *messages[i].AuthorID == state.UserID {
+				continue
+			}
+			if state.HasRead(messages[i]) {
+				messages[i].ReadBy = append(messages[i].ReadBy, state.UserID)
+			}
+		}
+	}
+}
+
+// recordMentions находит в тексте @username и @channel, оставляет только участников чата
+// (кроме самого автора) и сохраняет упоминания. Упоминания посторонних игнорируются.
+func (s *ChatService) recordMentions(ctx context.Context, message *models.Message) error {
+	usernames, channel := parseMentions(message.Text)
+	if len(usernames) == 0 && !channel {
+		return nil
+	}
+
+	members, err := s.memberRepo.ListMembers(ctx, message.ChatID)
+	if err != nil {
+		return err
+	}
+
+	mentioned := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		mentioned[username] = true
 	}
 
+	var mentions []models.Mention
+	for _, member := range members {
+		if message.AuthorID != nil && member.ID == *message.AuthorID {
+			continue
+		}
+		if mentioned[member.Username] || (channel && !member.IsBot) {
+			mentions = append(mentions, models.Mention{
+				MessageID: message.ID,
+				UserID:    member.ID,
+				ChatID:    message.ChatID,
+			})
+			message.Mentions = append(message.Mentions, member.ID)
+		}
+	}
+
+	return s.mentionRepo.Create(ctx, mentions)
+}
+
+// Ошибки в данных сообщения: повтор с теми же данными вернёт ту же ошибку.
+// Тексты ошибок — часть API, по ним обработчики выбирают HTTP-статус.
+var (
+	errChatNotFound  = errors.New("chat not found")
+	errForbidden     = errors.New("forbidden")
+	errInvalidFormat = errors.New("format must be plain or markdown")
+	errEmptyText     = errors.New("text cannot be empty")
+	errTextTooLong   = errors.New("text must be 1-5000 characters")
+	errInvalidExpiry = fmt.Errorf("expires_in must be between 1 and %d seconds", int64(MaxMessageTTL/time.Second))
+	errRejected      = errors.New("message rejected")
+)
+
+// isInvalidMessage сообщает, что CreateMessage отказал из-за данных сообщения или прав
+// автора, а не из-за сбоя, и повторная попытка ничего не изменит
+func isInvalidMessage(err error) bool {
+	for _, target := range []error{errChatNotFound, errForbidden, errInvalidFormat, errEmptyText, errTextTooLong, errInvalidExpiry, errRejected} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFormat проверяет формат сообщения. Пустой format означает plain.
+func normalizeFormat(format string) (string, error) {
+	if format == "" {
+		format = models.MessageFormatPlain
+	}
+	if format != models.MessageFormatPlain && format != models.MessageFormatMarkdown {
+		return "", errInvalidFormat
+	}
+	return format, nil
+}
+
+// normalizeText обрезает пробелы по краям и проверяет длину текста сообщения
+func normalizeText(text string) (string, error) {
 	text = strings.TrimSpace(text)
 	if text == "" {
-		return nil, errors.New("text cannot be empty")
+		return "", errEmptyText
 	}
 	if len(text) > 5000 {
-		return nil, errors.New("text must be 1-5000 characters")
+		return "", errTextTooLong
+	}
+	return text, nil
+}
+
+// expiryFor переводит срок жизни исчезающего сообщения в секундах в момент удаления.
+// 0 означает обычное сообщение (nil).
+func expiryFor(expiresIn int64) (*time.Time, error) {
+	if expiresIn == 0 {
+		return nil, nil
+	}
+	if expiresIn < 0 || expiresIn > int64(MaxMessageTTL/time.Second) {
+		return nil, errInvalidExpiry
 	}
 
-	message := &models.Message{
-		ChatID: chatID,
-		Text:   text,
+	at := time.Now().Add(time.Duration(expiresIn) * time.Second)
+	return &at, nil
+}
+
+// moderate пропускает нормализованный текст через модерацию и возвращает текст для
+// сохранения. Непустой flags означает, что после сохранения сообщение нужно поставить
+// на проверку. Отклонённое сообщение даёт ошибку "message rejected: <причина>".
+func (s *ChatService) moderate(ctx context.Context, chatID int64, text string) (string, []string, error) {
+	if s.moderator == nil {
+		return text, nil, nil
 	}
 
-	if err := s.messageRepo.Create(ctx, message); err != nil {
-		return nil, err
+	result, err := s.moderator.Check(ctx, chatID, text)
+	if err != nil {
+		return "", nil, err
+	}
+	if result.Rejected {
+		return "", nil, fmt.Errorf("%w: %s", errRejected, strings.Join(result.Reasons, "; "))
+	}
+	if result.Text != text {
+		// Внешний классификатор мог вернуть произвольный текст — проверяем его заново
+		if text, err = normalizeText(result.Text); err != nil {
+			return "", nil, err
+		}
+	}
+	if result.Flagged {
+		return text, result.Reasons, nil
 	}
+	return text, nil, nil
+}
 
-	return message, nil
+// renderHTML заполняет HTML сообщения. Сообщения без формата (созданные до его
+// появления) считаются обычным текстом.
+func renderHTML(message *models.Message) {
+	if message.Format == models.MessageFormatMarkdown {
+		message.HTML = markdown.Render(message.Text)
+		return
+	}
+	message.HTML = markdown.Plain(message.Text)
+}
+
+// recordEvent записывает доменное событие в outbox. Вызывается внутри транзакции,
+// поэтому событие сохраняется тогда и только тогда, когда сохраняется само изменение.
+func recordEvent(ctx context.Context, outboxRepo repository.OutboxRepository, eventType string, chatID int64, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.Add(ctx, &models.OutboxEvent{
+		EventType: eventType,
+		ChatID:    chatID,
+		Payload:   string(payload),
+	})
 }
@@ -3,19 +3,101 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/GlebMoskalev/chat-golang/internal/audit"
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
 	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
 	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
 	"go.uber.org/mock/gomock"
 )
 
+// newTxManager возвращает мок TxManager, который просто выполняет переданную функцию
+func newTxManager(ctrl *gomock.Controller) *mocks.MockTxManager {
+	m := mocks.NewMockTxManager(ctrl)
+	m.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+	return m
+}
+
+// newOutbox возвращает мок outbox, ожидающий ровно одно событие eventType
+// (или ни одного, если eventType пустой)
+func newOutbox(ctrl *gomock.Controller, eventType string) *mocks.MockOutboxRepository {
+	m := mocks.NewMockOutboxRepository(ctrl)
+	if eventType != "" {
+		m.EXPECT().
+			Add(gomock.Any(), gomock.Cond(func(event *models.OutboxEvent) bool {
+				return event.EventType == eventType && event.Payload != ""
+			})).
+			Return(nil)
+	}
+	return m
+}
+
+// newAudit возвращает мок журнала аудита, ожидающий ровно одну запись action
+// (или ни одной, если action пустой)
+func newAudit(ctrl *gomock.Controller, action string) *mocks.MockAuditRepository {
+	m := mocks.NewMockAuditRepository(ctrl)
+	if action != "" {
+		m.EXPECT().
+			Add(gomock.Any(), gomock.Cond(func(entry *models.AuditEntry) bool {
+				return entry.Action == action
+			})).
+			Return(nil)
+	}
+	return m
+}
+
+// newLinkPreviews возвращает мок кеша карточек ссылок без единой карточки
+func newLinkPreviews(ctrl *gomock.Controller) *mocks.MockLinkPreviewRepository {
+	m := mocks.NewMockLinkPreviewRepository(ctrl)
+	m.EXPECT().GetByURLs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return m
+}
+
+// newMembers возвращает мок участников: добавление и отметки прочтения всегда успешны, участников нет
+func newMembers(ctrl *gomock.Controller) *mocks.MockChatMemberRepository {
+	m := mocks.NewMockChatMemberRepository(ctrl)
+	m.EXPECT().Add(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.EXPECT().MarkRead(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	m.EXPECT().ListMembers(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	m.EXPECT().ListReadStates(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return m
+}
+
+// newMentions возвращает мок упоминаний, принимающий любые записи
+func newMentions(ctrl *gomock.Controller) *mocks.MockMentionRepository {
+	m := mocks.NewMockMentionRepository(ctrl)
+	m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return m
+}
+
+// newPins возвращает мок закрепов без единого закреплённого сообщения
+func newPins(ctrl *gomock.Controller) *mocks.MockPinRepository {
+	m := mocks.NewMockPinRepository(ctrl)
+	m.EXPECT().ListByChat(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	return m
+}
+
+// expectRoom ожидает, что сервис получит чат chatID, и отдаёт его как обычную комнату
+func expectRoom(m *mocks.MockChatRepository, chatID int64) {
+	m.EXPECT().GetByID(gomock.Any(), chatID).Return(&models.Chat{ID: chatID, Type: models.ChatTypeRoom}, nil)
+}
+
 func TestCreateChat(t *testing.T) {
 	tests := []struct {
 		name        string
 		title       string
 		setupMock   func(*mocks.MockChatRepository)
+		expectEvent string
 		expectError bool
 		errorMsg    string
 	}{
@@ -31,6 +113,7 @@ func TestCreateChat(t *testing.T) {
 						return nil
 					})
 			},
+			expectEvent: models.EventChatCreated,
 			expectError: false,
 		},
 		{
@@ -48,6 +131,7 @@ func TestCreateChat(t *testing.T) {
 						return nil
 					})
 			},
+			expectEvent: models.EventChatCreated,
 			expectError: false,
 		},
 		{
@@ -83,7 +167,7 @@ func TestCreateChat(t *testing.T) {
 
 			tt.setupMock(mockChatRepo)
 
-			service := NewChatService(mockChatRepo, mockMessageRepo)
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, tt.expectEvent), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
 
 			chat, err := service.CreateChat(context.Background(), tt.title)
 
@@ -129,7 +213,7 @@ func TestGetChatWithMessages(t *testing.T) {
 					}, nil)
 
 				mr.EXPECT().
-					GetByChatID(gomock.Any(), int64(1), 10).
+					GetByChatID(gomock.Any(), int64(1), gomock.Any(), 10).
 					Return([]models.Message{
 						{ID: 1, ChatID: 1, Text: "Привет", CreatedAt: time.Now()},
 					}, nil)
@@ -162,7 +246,7 @@ func TestGetChatWithMessages(t *testing.T) {
 					}, nil)
 
 				mr.EXPECT().
-					GetByChatID(gomock.Any(), int64(1), 20).
+					GetByChatID(gomock.Any(), int64(1), gomock.Any(), 20).
 					Return([]models.Message{}, nil)
 			},
 			expectError:   false,
@@ -182,7 +266,7 @@ func TestGetChatWithMessages(t *testing.T) {
 					}, nil)
 
 				mr.EXPECT().
-					GetByChatID(gomock.Any(), int64(1), 100).
+					GetByChatID(gomock.Any(), int64(1), gomock.Any(), 100).
 					Return([]models.Message{}, nil)
 			},
 			expectError:   false,
@@ -200,7 +284,7 @@ func TestGetChatWithMessages(t *testing.T) {
 
 			tt.setupMock(mockChatRepo, mockMessageRepo)
 
-			service := NewChatService(mockChatRepo, mockMessageRepo)
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
 
 			result, err := service.GetChatWithMessages(context.Background(), tt.chatID, tt.limit)
 
@@ -225,7 +309,9 @@ func TestCreateMessage(t *testing.T) {
 		name        string
 		chatID      int64
 		text        string
+		expiresIn   int64
 		setupMock   func(*mocks.MockChatRepository, *mocks.MockMessageRepository)
+		expectEvent string
 		expectError bool
 		errorMsg    string
 	}{
@@ -234,6 +320,7 @@ func TestCreateMessage(t *testing.T) {
 			chatID: 1,
 			text:   "Привет!",
 			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
+				expectRoom(cr, 1)
 				cr.EXPECT().
 					Exists(gomock.Any(), int64(1)).
 					Return(true, nil)
@@ -246,6 +333,7 @@ func TestCreateMessage(t *testing.T) {
 						return nil
 					})
 			},
+			expectEvent: models.EventMessageCreated,
 			expectError: false,
 		},
 		{
@@ -253,6 +341,7 @@ func TestCreateMessage(t *testing.T) {
 			chatID: 1,
 			text:   "  Сообщение с пробелами  ",
 			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
+				expectRoom(cr, 1)
 				cr.EXPECT().
 					Exists(gomock.Any(), int64(1)).
 					Return(true, nil)
@@ -268,53 +357,92 @@ func TestCreateMessage(t *testing.T) {
 						return nil
 					})
 			},
+			expectEvent: models.EventMessageCreated,
 			expectError: false,
 		},
+		{
+			name:      "исчезающее сообщение",
+			chatID:    1,
+			text:      "Привет!",
+			expiresIn: 60,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
+				expectRoom(cr, 1)
+				cr.EXPECT().
+					Exists(gomock.Any(), int64(1)).
+					Return(true, nil)
+
+				mr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, message *models.Message) error {
+						if message.ExpiresAt == nil || time.Until(*message.ExpiresAt) <= 55*time.Second || time.Until(*message.ExpiresAt) > time.Minute {
+							t.Errorf("ожидался expires_at через минуту, получен %v", message.ExpiresAt)
+						}
+						message.ID = 1
+						message.CreatedAt = time.Now()
+						return nil
+					})
+			},
+			expectEvent: models.EventMessageCreated,
+		},
+		{
+			name:        "слишком долгий срок жизни",
+			chatID:      1,
+			text:        "Привет!",
+			expiresIn:   int64(MaxMessageTTL/time.Second) + 1,
+			setupMock:   func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {},
+			expectError: true,
+			errorMsg:    "expires_in must be between 1 and 604800 seconds",
+		},
 		{
 			name:   "чат не существует",
 			chatID: 999,
 			text:   "Привет!",
 			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
 				cr.EXPECT().
-					Exists(gomock.Any(), int64(999)).
-					Return(false, nil)
+					GetByID(gomock.Any(), int64(999)).
+					Return(nil, nil)
 			},
 			expectError: true,
 			errorMsg:    "chat not found",
 		},
 		{
-			name:   "пустой текст",
+			name:   "чат удалён параллельно перед вставкой",
 			chatID: 1,
-			text:   "",
+			text:   "Привет!",
 			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
+				expectRoom(cr, 1)
 				cr.EXPECT().
 					Exists(gomock.Any(), int64(1)).
 					Return(true, nil)
+
+				mr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(repository.ErrChatNotFound)
 			},
 			expectError: true,
+			errorMsg:    "chat not found",
+		},
+		{
+			name:        "пустой текст",
+			chatID:      1,
+			text:        "",
+			setupMock:   func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {},
+			expectError: true,
 			errorMsg:    "text cannot be empty",
 		},
 		{
-			name:   "текст только из пробелов",
-			chatID: 1,
-			text:   "   ",
-			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
-				cr.EXPECT().
-					Exists(gomock.Any(), int64(1)).
-					Return(true, nil)
-			},
+			name:        "текст только из пробелов",
+			chatID:      1,
+			text:        "   ",
+			setupMock:   func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {},
 			expectError: true,
 			errorMsg:    "text cannot be empty",
 		},
 		{
-			name:   "слишком длинный текст",
-			chatID: 1,
-			text:   string(make([]byte, 5001)),
-			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {
-				cr.EXPECT().
-					Exists(gomock.Any(), int64(1)).
-					Return(true, nil)
-			},
+			name:        "слишком длинный текст",
+			chatID:      1,
+			text:        string(make([]byte, 5001)),
+			setupMock:   func(cr *mocks.MockChatRepository, mr *mocks.MockMessageRepository) {},
 			expectError: true,
 			errorMsg:    "text must be 1-5000 characters",
 		},
@@ -330,9 +458,9 @@ func TestCreateMessage(t *testing.T) {
 
 			tt.setupMock(mockChatRepo, mockMessageRepo)
 
-			service := NewChatService(mockChatRepo, mockMessageRepo)
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, tt.expectEvent), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
 
-			message, err := service.CreateMessage(context.Background(), tt.chatID, tt.text)
+			message, err := service.CreateMessage(context.Background(), tt.chatID, models.MessageInput{Text: tt.text, ExpiresIn: tt.expiresIn})
 
 			if tt.expectError {
 				if err == nil {
@@ -354,32 +482,380 @@ func TestCreateMessage(t *testing.T) {
 }
 
 func TestDeleteChat(t *testing.T) {
+	owner := int64(42)
+	room := &models.Chat{ID: 1, Title: "General", Type: models.ChatTypeRoom, OwnerID: &owner}
+	dm := &models.Chat{ID: 2, Type: models.ChatTypeDM}
+	ownerless := &models.Chat{ID: 3, Title: "Imported", Type: models.ChatTypeRoom}
+
 	tests := []struct {
 		name        string
+		ctx         context.Context
 		chatID      int64
-		setupMock   func(*mocks.MockChatRepository)
-		expectError bool
+		setupMock   func(*mocks.MockChatRepository, *mocks.MockChatMemberRepository)
+		expectEvent string
+		expectAudit string
+		expectErr   string
 	}{
 		{
-			name:   "успешное удаление",
+			name:   "владелец удаляет комнату",
+			ctx:    auth.WithUserID(context.Background(), owner),
 			chatID: 1,
-			setupMock: func(m *mocks.MockChatRepository) {
-				m.EXPECT().
-					Delete(gomock.Any(), int64(1)).
-					Return(nil)
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(room, nil).Times(2)
+				cr.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
 			},
-			expectError: false,
+			expectEvent: models.EventChatDeleted,
+			expectAudit: models.AuditChatDelete,
 		},
 		{
-			name:   "ошибка при удалении",
+			name:   "участник удаляет личный чат",
+			ctx:    auth.WithUserID(context.Background(), 1),
+			chatID: 2,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(2)).Return(dm, nil)
+				mr.EXPECT().IsMember(gomock.Any(), int64(2), int64(1)).Return(true, nil)
+				cr.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
+			},
+			expectEvent: models.EventChatDeleted,
+			expectAudit: models.AuditChatDelete,
+		},
+		{
+			name:   "не владелец комнаты",
+			ctx:    auth.WithUserID(context.Background(), 7),
+			chatID: 1,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(room, nil).Times(2)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:   "комната без владельца недоступна пользователю",
+			ctx:    auth.WithUserID(context.Background(), owner),
+			chatID: 3,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(3)).Return(ownerless, nil).Times(2)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:   "администратор удаляет комнату без владельца",
+			ctx:    auth.WithAdmin(context.Background()),
+			chatID: 3,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(3)).Return(ownerless, nil)
+				cr.EXPECT().Delete(gomock.Any(), int64(3)).Return(nil)
+			},
+			expectEvent: models.EventChatDeleted,
+			expectAudit: models.AuditChatDelete,
+		},
+		{
+			name:   "администратор удаляет личный чат",
+			ctx:    auth.WithAdmin(context.Background()),
+			chatID: 2,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(2)).Return(dm, nil)
+				cr.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil)
+			},
+			expectEvent: models.EventChatDeleted,
+			expectAudit: models.AuditChatDelete,
+		},
+		{
+			name:   "посторонний в личном чате",
+			ctx:    auth.WithUserID(context.Background(), 3),
+			chatID: 2,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(2)).Return(dm, nil)
+				mr.EXPECT().IsMember(gomock.Any(), int64(2), int64(3)).Return(false, nil)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:   "без пользователя",
+			ctx:    context.Background(),
+			chatID: 1,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(room, nil)
+			},
+			expectErr: "authentication required",
+		},
+		{
+			name:   "чат не найден",
+			ctx:    auth.WithUserID(context.Background(), owner),
 			chatID: 999,
-			setupMock: func(m *mocks.MockChatRepository) {
-				m.EXPECT().
-					Delete(gomock.Any(), int64(999)).
-					Return(errors.New("chat not found"))
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(999)).Return(nil, nil)
 			},
-			expectError: true,
+			expectErr: "chat not found",
 		},
+		{
+			name:   "ошибка при удалении",
+			ctx:    auth.WithUserID(context.Background(), owner),
+			chatID: 1,
+			setupMock: func(cr *mocks.MockChatRepository, mr *mocks.MockChatMemberRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(room, nil).Times(2)
+				cr.EXPECT().Delete(gomock.Any(), int64(1)).Return(errors.New("db error"))
+			},
+			expectErr: "db error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChatRepo := mocks.NewMockChatRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+			mockAttachments.EXPECT().ListByChat(gomock.Any(), tt.chatID).Return(nil, nil).AnyTimes()
+
+			tt.setupMock(mockChatRepo, mockMembers)
+
+			service := NewChatService(mockChatRepo, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, tt.expectEvent), newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), newPins(ctrl), newAudit(ctrl, tt.expectAudit), nil, mockAttachments, nil)
+
+			err := service.DeleteChat(tt.ctx, tt.chatID)
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestDeleteChat_AuditEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+
+	owner := int64(7)
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "General", OwnerID: &owner}, nil).Times(2)
+	mockChatRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+	mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+	mockAttachments.EXPECT().ListByChat(gomock.Any(), int64(1)).Return(nil, nil)
+
+	var entry *models.AuditEntry
+	mockAudit.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *models.AuditEntry) error {
+		entry = e
+		return nil
+	})
+
+	service := NewChatService(mockChatRepo, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, models.EventChatDeleted), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), mockAudit, nil, mockAttachments, nil)
+
+	ctx := audit.WithRequest(auth.WithUserID(context.Background(), 7), audit.Request{ID: "req-1", IP: "192.0.2.1"})
+	if err := service.DeleteChat(ctx, 1); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if entry == nil {
+		t.Fatal("удаление чата должно попасть в журнал аудита")
+	}
+	if entry.ActorType != models.AuditActorUser || entry.ActorID == nil || *entry.ActorID != 7 {
+		t.Errorf("исполнитель = %s/%v, ожидался пользователь 7", entry.ActorType, entry.ActorID)
+	}
+	if entry.RequestID != "req-1" || entry.IP != "192.0.2.1" {
+		t.Errorf("запрос = %q/%q, ожидались req-1/192.0.2.1", entry.RequestID, entry.IP)
+	}
+	if string(entry.Before) == "" || entry.After != nil {
+		t.Errorf("ожидался только снимок до удаления, получено before=%s after=%s", entry.Before, entry.After)
+	}
+}
+
+func TestDeleteChat_DeletesBlobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("хранилище: %v", err)
+	}
+	ctx := auth.WithUserID(context.Background(), 7)
+	for _, key := range []string{"chats/1/file", "chats/1/file-thumb"} {
+		if _, err := blobs.Put(ctx, key, strings.NewReader("данные")); err != nil {
+			t.Fatalf("запись %s: %v", key, err)
+		}
+	}
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	owner := int64(7)
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "General", OwnerID: &owner}, nil).Times(2)
+	mockChatRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+	mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+	mockAttachments.EXPECT().
+		ListByChat(gomock.Any(), int64(1)).
+		Return([]models.Attachment{{ID: 5, ChatID: 1, StorageKey: "chats/1/file", ThumbnailKey: "chats/1/file-thumb"}}, nil)
+
+	service := NewChatService(mockChatRepo, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, models.EventChatDeleted), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, models.AuditChatDelete), nil, mockAttachments, blobs)
+
+	if err := service.DeleteChat(ctx, 1); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	for _, key := range []string{"chats/1/file", "chats/1/file-thumb"} {
+		if _, err := blobs.Open(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+			t.Errorf("файл %s должен быть удалён из хранилища, получено %v", key, err)
+		}
+	}
+}
+
+func TestCreateMessage_OutboxFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+
+	expectRoom(mockChatRepo, 1)
+	mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+	mockMessageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+	mockOutbox.EXPECT().Add(gomock.Any(), gomock.Any()).Return(errors.New("outbox unavailable"))
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), mockOutbox, newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	message, err := service.CreateMessage(context.Background(), 1, models.MessageInput{Text: "Привет!"})
+	if err == nil {
+		t.Error("ошибка записи события должна откатывать создание сообщения")
+	}
+	if message != nil {
+		t.Error("сообщение должно быть nil при ошибке")
+	}
+}
+
+func TestCreateChat_SetsOwner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockChatRepo.EXPECT().
+		Create(gomock.Any(), gomock.Cond(func(chat *models.Chat) bool {
+			return chat.OwnerID != nil && *chat.OwnerID == 42
+		})).
+		Return(nil)
+
+	service := NewChatService(mockChatRepo, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, models.EventChatCreated), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	if _, err := service.CreateChat(auth.WithUserID(context.Background(), 42), "Чат"); err != nil {
+		t.Errorf("неожиданная ошибка: %v", err)
+	}
+}
+
+func TestCreateMessage_SetsAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+
+	expectRoom(mockChatRepo, 1)
+	mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+	mockMessageRepo.EXPECT().
+		Create(gomock.Any(), gomock.Cond(func(message *models.Message) bool {
+			return message.AuthorID != nil && *message.AuthorID == 42
+		})).
+		Return(nil)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, models.EventMessageCreated), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	if _, err := service.CreateMessage(auth.WithUserID(context.Background(), 42), 1, models.MessageInput{Text: "Привет!"}); err != nil {
+		t.Errorf("неожиданная ошибка: %v", err)
+	}
+}
+
+func TestCreateMessage_Attachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+
+	expectRoom(mockChatRepo, 1)
+	mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+	mockMessageRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, message *models.Message) error {
+			message.ID = 7
+			return nil
+		})
+	// Событие пишется уже со вложением, иначе подписчики получат сообщение без файла
+	gomock.InOrder(
+		mockAttachments.EXPECT().
+			Create(gomock.Any(), gomock.Cond(func(attachment *models.Attachment) bool {
+				return attachment.ChatID == 1 && attachment.MessageID == 7 && attachment.UploaderID != nil && *attachment.UploaderID == 42
+			})).
+			DoAndReturn(func(ctx context.Context, attachment *models.Attachment) error {
+				attachment.ID = 3
+				return nil
+			}),
+		mockOutbox.EXPECT().
+			Add(gomock.Any(), gomock.Cond(func(event *models.OutboxEvent) bool {
+				return event.EventType == models.EventMessageCreated && strings.Contains(event.Payload, `"file_name":"report.txt"`)
+			})).
+			Return(nil),
+	)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), mockOutbox, newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, mockAttachments, nil)
+
+	input := models.MessageInput{Text: "report.txt", Attachments: []models.Attachment{{FileName: "report.txt", StorageKey: "chats/1/file"}}}
+	message, err := service.CreateMessage(auth.WithUserID(context.Background(), 42), 1, input)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(message.Attachments) != 1 || message.Attachments[0].ID != 3 {
+		t.Errorf("ожидалось вложение 3, получено %+v", message.Attachments)
+	}
+}
+
+func TestGetChatWithMessages_LinkPreviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockPreviews := mocks.NewMockLinkPreviewRepository(ctrl)
+
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "Тест"}, nil)
+	mockMessageRepo.EXPECT().GetByChatID(gomock.Any(), int64(1), gomock.Any(), 20).Return([]models.Message{
+		{ID: 2, ChatID: 1, Text: "смотри https://example.com/a и https://example.com/b"},
+		{ID: 1, ChatID: 1, Text: "без ссылок"},
+	}, nil)
+	mockPreviews.EXPECT().
+		GetByURLs(gomock.Any(), []string{"https://example.com/a", "https://example.com/b"}).
+		Return([]models.LinkPreview{
+			{URL: "https://example.com/b", Title: "B", Status: models.LinkPreviewOK},
+			{URL: "https://example.com/a", Status: models.LinkPreviewFailed},
+		}, nil)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), mockPreviews, newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	result, err := service.GetChatWithMessages(context.Background(), 1, 20)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if len(result.Messages[0].LinkPreviews) != 1 || result.Messages[0].LinkPreviews[0].Title != "B" {
+		t.Errorf("ожидалась одна карточка 'B', получено %+v", result.Messages[0].LinkPreviews)
+	}
+	if len(result.Messages[1].LinkPreviews) != 0 {
+		t.Errorf("у сообщения без ссылок не должно быть карточек, получено %+v", result.Messages[1].LinkPreviews)
+	}
+}
+
+func TestCreateMessage_Format(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		expectHTML  string
+		expectError string
+	}{
+		{name: "plain по умолчанию", format: "", expectHTML: "<p>**Привет** &lt;b&gt;</p>"},
+		{name: "markdown", format: models.MessageFormatMarkdown, expectHTML: "<p><strong>Привет</strong> &lt;b&gt;</p>"},
+		{name: "неизвестный формат", format: "html", expectError: "format must be plain or markdown"},
 	}
 
 	for _, tt := range tests {
@@ -389,22 +865,251 @@ func TestDeleteChat(t *testing.T) {
 
 			mockChatRepo := mocks.NewMockChatRepository(ctrl)
 			mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+			expectEvent := ""
+			if tt.expectError == "" {
+				expectEvent = models.EventMessageCreated
+				expectRoom(mockChatRepo, 1)
+				mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+				mockMessageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
 
-			tt.setupMock(mockChatRepo)
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, expectEvent), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
 
-			service := NewChatService(mockChatRepo, mockMessageRepo)
+			message, err := service.CreateMessage(context.Background(), 1, models.MessageInput{Text: "**Привет** <b>", Format: tt.format})
+			if tt.expectError != "" {
+				if err == nil || err.Error() != tt.expectError {
+					t.Errorf("ожидалась ошибка '%s', получена %v", tt.expectError, err)
+				}
+				return
+			}
 
-			err := service.DeleteChat(context.Background(), tt.chatID)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if message.HTML != tt.expectHTML {
+				t.Errorf("ожидался HTML %q, получен %q", tt.expectHTML, message.HTML)
+			}
+		})
+	}
+}
 
-			if tt.expectError {
-				if err == nil {
-					t.Error("ожидалась ошибка, но её не было")
+func TestMarkRead(t *testing.T) {
+	createdAt := time.Now()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		message   *models.Message
+		isMember  bool
+		expectErr string
+	}{
+		{
+			name:     "отметка прочтения",
+			ctx:      auth.WithUserID(context.Background(), 42),
+			message:  &models.Message{ID: 10, ChatID: 1, CreatedAt: createdAt},
+			isMember: true,
+		},
+		{
+			name:      "сообщение из другого чата",
+			ctx:       auth.WithUserID(context.Background(), 42),
+			message:   &models.Message{ID: 10, ChatID: 2},
+			expectErr: "message not found",
+		},
+		{
+			name:      "сообщение не найдено",
+			ctx:       auth.WithUserID(context.Background(), 42),
+			expectErr: "message not found",
+		},
+		{
+			name:      "не участник",
+			ctx:       auth.WithUserID(context.Background(), 42),
+			message:   &models.Message{ID: 10, ChatID: 1, CreatedAt: createdAt},
+			expectErr: "forbidden",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+
+			if tt.expectErr != "authentication required" {
+				mockMessageRepo.EXPECT().GetByID(gomock.Any(), int64(10)).Return(tt.message, nil)
+			}
+			if tt.message != nil && tt.message.ChatID == 1 {
+				mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(tt.isMember, nil)
+			}
+			if tt.isMember {
+				mockMembers.EXPECT().MarkRead(gomock.Any(), int64(1), int64(42), int64(10), createdAt).Return(nil)
+			}
+
+			service := NewChatService(mocks.NewMockChatRepository(ctrl), mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+			err := service.MarkRead(tt.ctx, 1, 10)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestGetChatWithMessages_ReadBy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	base := time.Now()
+	alice, bob := int64(1), int64(2)
+	readUpTo := int64(2)
+	readAt := base.Add(time.Second)
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "Тест"}, nil)
+	mockMessageRepo.EXPECT().GetByChatID(gomock.Any(), int64(1), gomock.Any(), 20).Return([]models.Message{
+		{ID: 3, ChatID: 1, AuthorID: &alice, Text: "третье", CreatedAt: base.Add(2 * time.Second)},
+		{ID: 2, ChatID: 1, AuthorID: &alice, Text: "второе", CreatedAt: readAt},
+		{ID: 1, ChatID: 1, AuthorID: &bob, Text: "первое", CreatedAt: base},
+	}, nil)
+	mockMembers.EXPECT().ListReadStates(gomock.Any(), int64(1)).Return([]models.ChatMember{
+		{ChatID: 1, UserID: alice},
+		{ChatID: 1, UserID: bob, LastReadMessageID: &readUpTo, LastReadAt: &readAt},
+	}, nil)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	result, err := service.GetChatWithMessages(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if len(result.Messages[0].ReadBy) != 0 {
+		t.Errorf("третье сообщение ещё никто не прочитал, получено %v", result.Messages[0].ReadBy)
+	}
+	if len(result.Messages[1].ReadBy) != 1 || result.Messages[1].ReadBy[0] != bob {
+		t.Errorf("второе сообщение прочитал bob, получено %v", result.Messages[1].ReadBy)
+	}
+	if len(result.Messages[2].ReadBy) != 0 {
+		t.Errorf("автор не попадает в read_by, получено %v", result.Messages[2].ReadBy)
+	}
+}
+
+func TestCreateMessages(t *testing.T) {
+	inputs := []models.MessageInput{
+		{Text: "  первое  "},
+		{Text: "   "},
+		{Text: "**второе**", Format: models.MessageFormatMarkdown},
+		{Text: "третье", Format: "html"},
+	}
+
+	tests := []struct {
+		name          string
+		atomic        bool
+		expectErr     string
+		expectCreated []int
+		expectFailed  []int
+	}{
+		{
+			name:          "частичная вставка",
+			expectCreated: []int{0, 2},
+			expectFailed:  []int{1, 3},
+		},
+		{
+			name:         "всё или ничего",
+			atomic:       true,
+			expectErr:    "batch rejected",
+			expectFailed: []int{1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChatRepo := mocks.NewMockChatRepository(ctrl)
+			mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+			mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+
+			expectRoom(mockChatRepo, 1)
+			if !tt.atomic {
+				mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+				mockMessageRepo.EXPECT().
+					CreateBatch(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, messages []models.Message) error {
+						if len(messages) != 2 || messages[0].Text != "первое" || messages[1].Format != models.MessageFormatMarkdown {
+							t.Errorf("неверный пакет %+v", messages)
+						}
+						for i := range messages {
+							messages[i].ID = int64(10 + i)
+							messages[i].CreatedAt = time.Now()
+						}
+						return nil
+					})
+				mockMembers.EXPECT().Add(gomock.Any(), int64(1), int64(5)).Return(nil)
+				mockMembers.EXPECT().MarkRead(gomock.Any(), int64(1), int64(5), int64(11), gomock.Any()).Return(nil)
+				mockOutbox.EXPECT().
+					Add(gomock.Any(), gomock.Cond(func(event *models.OutboxEvent) bool {
+						return event.EventType == models.EventMessageCreated
+					})).
+					Return(nil).
+					Times(2)
+			}
+
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), mockOutbox, newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+			results, err := service.CreateMessages(auth.WithUserID(context.Background(), 5), 1, inputs, tt.atomic)
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Fatalf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+			if len(results) != len(inputs) {
+				t.Fatalf("ожидалось %d результатов, получено %d", len(inputs), len(results))
+			}
+			for _, i := range tt.expectCreated {
+				if results[i].Message == nil || results[i].Error != "" {
+					t.Errorf("сообщение %d должно быть создано: %+v", i, results[i])
 				}
-			} else {
-				if err != nil {
-					t.Errorf("неожиданная ошибка: %v", err)
+			}
+			for _, i := range tt.expectFailed {
+				if results[i].Message != nil || results[i].Error == "" {
+					t.Errorf("сообщение %d должно быть отклонено: %+v", i, results[i])
 				}
 			}
+			if !tt.atomic && results[2].Message.HTML != "<p><strong>второе</strong></p>" {
+				t.Errorf("ожидался HTML, получен %q", results[2].Message.HTML)
+			}
 		})
 	}
 }
+
+func TestCreateMessages_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := NewChatService(mocks.NewMockChatRepository(ctrl), mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+	if _, err := service.CreateMessages(context.Background(), 1, nil, false); err == nil {
+		t.Error("пустой пакет должен отклоняться")
+	}
+
+	tooMany := make([]models.MessageInput, MaxBatchMessages+1)
+	if _, err := service.CreateMessages(context.Background(), 1, tooMany, false); err == nil {
+		t.Error("пакет больше MaxBatchMessages должен отклоняться")
+	}
+}
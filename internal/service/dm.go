@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_dm_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service DMServiceInterface
+
+type DMServiceInterface interface {
+	OpenDM(ctx context.Context, userID int64) (chat *models.Chat, created bool, err error)
+}
+
+type DMService struct {
+	chatRepo   repository.ChatRepository
+	userRepo   repository.UserRepository
+	memberRepo repository.ChatMemberRepository
+	txManager  repository.TxManager
+	outboxRepo repository.OutboxRepository
+}
+
+func NewDMService(chatRepo repository.ChatRepository, userRepo repository.UserRepository, memberRepo repository.ChatMemberRepository, txManager repository.TxManager, outboxRepo repository.OutboxRepository) *DMService {
+	return &DMService{
+		chatRepo:   chatRepo,
+		userRepo:   userRepo,
+		memberRepo: memberRepo,
+		txManager:  txManager,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// OpenDM возвращает личный чат текущего пользователя с userID, создавая его при первом
+// обращении. Личный чат без названия и владельца, оба пользователя сразу становятся участниками.
+// created сообщает, был ли чат создан этим вызовом.
+func (s *DMService) OpenDM(ctx context.Context, userID int64) (*models.Chat, bool, error) {
+	currentID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, false, errors.New("authentication required")
+	}
+	if userID == currentID {
+		return nil, false, errors.New("cannot start a direct message with yourself")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if user == nil {
+		return nil, false, errors.New("user not found")
+	}
+
+	key := models.DMKey(currentID, userID)
+
+	var chat *models.Chat
+	created := false
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.chatRepo.GetByDMKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			chat = existing
+			return nil
+		}
+
+		chat = &models.Chat{Type: models.ChatTypeDM, DMKey: &key}
+		if err := s.chatRepo.Create(ctx, chat); err != nil {
+			return err
+		}
+		for _, memberID := range []int64{currentID, userID} {
+			if err := s.memberRepo.Add(ctx, chat.ID, memberID); err != nil {
+				return err
+			}
+		}
+		created = true
+		return recordEvent(ctx, s.outboxRepo, models.EventChatCreated, chat.ID, chat)
+	})
+
+	// Параллельный запрос успел создать этот же чат: транзакция откатилась, берём готовый
+	if errors.Is(err, repository.ErrDMExists) {
+		chat, err = s.chatRepo.GetByDMKey(ctx, key)
+		if err == nil && chat == nil {
+			err = errChatNotFound
+		}
+		created = false
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return chat, created, nil
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOpenDM(t *testing.T) {
+	key := models.DMKey(1, 2)
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		userID        int64
+		eventType     string
+		setupMock     func(*mocks.MockChatRepository, *mocks.MockUserRepository, *mocks.MockChatMemberRepository)
+		expectCreated bool
+		expectErr     string
+	}{
+		{
+			name:      "новый личный чат",
+			ctx:       auth.WithUserID(context.Background(), 1),
+			userID:    2,
+			eventType: models.EventChatCreated,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.User{ID: 2, Username: "bob"}, nil)
+				cr.EXPECT().GetByDMKey(gomock.Any(), key).Return(nil, nil)
+				cr.EXPECT().
+					Create(gomock.Any(), gomock.Cond(func(chat *models.Chat) bool {
+						return chat.Type == models.ChatTypeDM && chat.Title == "" && chat.DMKey != nil && *chat.DMKey == key
+					})).
+					DoAndReturn(func(ctx context.Context, chat *models.Chat) error {
+						chat.ID = 10
+						return nil
+					})
+				mr.EXPECT().Add(gomock.Any(), int64(10), int64(1)).Return(nil)
+				mr.EXPECT().Add(gomock.Any(), int64(10), int64(2)).Return(nil)
+			},
+			expectCreated: true,
+		},
+		{
+			name:   "существующий личный чат",
+			ctx:    auth.WithUserID(context.Background(), 2),
+			userID: 1,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.User{ID: 1, Username: "alice"}, nil)
+				cr.EXPECT().GetByDMKey(gomock.Any(), key).Return(&models.Chat{ID: 10, Type: models.ChatTypeDM}, nil)
+			},
+		},
+		{
+			name:   "гонка создания",
+			ctx:    auth.WithUserID(context.Background(), 1),
+			userID: 2,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.User{ID: 2, Username: "bob"}, nil)
+				gomock.InOrder(
+					cr.EXPECT().GetByDMKey(gomock.Any(), key).Return(nil, nil),
+					cr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrDMExists),
+					cr.EXPECT().GetByDMKey(gomock.Any(), key).Return(&models.Chat{ID: 10, Type: models.ChatTypeDM}, nil),
+				)
+			},
+		},
+		{
+			name:      "чат с самим собой",
+			ctx:       auth.WithUserID(context.Background(), 1),
+			userID:    1,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {},
+			expectErr: "cannot start a direct message with yourself",
+		},
+		{
+			name:   "пользователь не найден",
+			ctx:    auth.WithUserID(context.Background(), 1),
+			userID: 99,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {
+				ur.EXPECT().GetByID(gomock.Any(), int64(99)).Return(nil, nil)
+			},
+			expectErr: "user not found",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			userID:    2,
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, mr *mocks.MockChatMemberRepository) {},
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			tt.setupMock(mockChats, mockUsers, mockMembers)
+
+			service := NewDMService(mockChats, mockUsers, mockMembers, newTxManager(ctrl), newOutbox(ctrl, tt.eventType))
+
+			chat, created, err := service.OpenDM(tt.ctx, tt.userID)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if chat == nil || chat.ID != 10 {
+				t.Errorf("ожидался чат 10, получен %+v", chat)
+			}
+			if created != tt.expectCreated {
+				t.Errorf("ожидалось created=%v, получено %v", tt.expectCreated, created)
+			}
+		})
+	}
+}
+
+func TestCreateMessage_DM(t *testing.T) {
+	dm := &models.Chat{ID: 10, Type: models.ChatTypeDM}
+
+	tests := []struct {
+		name      string
+		userID    int64
+		isMember  bool
+		expectErr string
+	}{
+		{name: "участник пишет, но не вступает повторно", userID: 1, isMember: true},
+		{name: "посторонний не пишет и не вступает", userID: 3, expectErr: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockMessages := mocks.NewMockMessageRepository(ctrl)
+			// Add не ожидается: состав личного чата сообщением не меняется
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			mockChats.EXPECT().GetByID(gomock.Any(), int64(10)).Return(dm, nil)
+			mockMembers.EXPECT().IsMember(gomock.Any(), int64(10), tt.userID).Return(tt.isMember, nil)
+
+			eventType := ""
+			if tt.expectErr == "" {
+				eventType = models.EventMessageCreated
+				mockChats.EXPECT().Exists(gomock.Any(), int64(10)).Return(true, nil)
+				mockMessages.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockMembers.EXPECT().MarkRead(gomock.Any(), int64(10), tt.userID, gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			service := NewChatService(mockChats, mockMessages, newTxManager(ctrl), newOutbox(ctrl, eventType), newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+			_, err := service.CreateMessage(auth.WithUserID(context.Background(), tt.userID), 10, models.MessageInput{Text: "привет"})
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestReadDM_ThirdParty(t *testing.T) {
+	dm := &models.Chat{ID: 10, Type: models.ChatTypeDM}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		expectErr string
+	}{
+		{name: "посторонний", ctx: auth.WithUserID(context.Background(), 3), expectErr: "forbidden"},
+		{name: "без пользователя", ctx: context.Background(), expectErr: "authentication required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			// Сообщения, участники и закрепы не читаются вовсе
+			mockChats.EXPECT().GetByID(gomock.Any(), int64(10)).Return(dm, nil).Times(3)
+			mockMembers.EXPECT().IsMember(gomock.Any(), int64(10), int64(3)).Return(false, nil).AnyTimes()
+
+			chats := NewChatService(mockChats, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), mockMembers, newMentions(ctrl), mocks.NewMockPinRepository(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+			if _, err := chats.GetChatWithMessages(tt.ctx, 10, 20); err == nil || err.Error() != tt.expectErr {
+				t.Errorf("чтение: ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+
+			exports := NewExportService(mockChats, mocks.NewMockMessageRepository(ctrl), mockMembers)
+			if err := exports.ExportChat(tt.ctx, 10, nil); err == nil || err.Error() != tt.expectErr {
+				t.Errorf("выгрузка: ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+
+			pins := NewPinService(mocks.NewMockPinRepository(ctrl), mocks.NewMockMessageRepository(ctrl), mockMembers, mockChats, newTxManager(ctrl), 3)
+			if _, err := pins.ListPins(tt.ctx, 10); err == nil || err.Error() != tt.expectErr {
+				t.Errorf("закрепы: ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// expiryBatchSize — сколько истёкших сообщений удаляется одной транзакцией
+const expiryBatchSize = 500
+
+// ExpiryService удаляет истёкшие исчезающие сообщения. До удаления они уже скрыты
+// из выдачи, поэтому задержка уборки на пользователях не сказывается.
+type ExpiryService struct {
+	messageRepo repository.MessageRepository
+	outboxRepo  repository.OutboxRepository
+	txManager   repository.TxManager
+	blobs       blob.Store
+	now         func() time.Time
+}
+
+func NewExpiryService(messageRepo repository.MessageRepository, outboxRepo repository.OutboxRepository, txManager repository.TxManager, blobs blob.Store) *ExpiryService {
+	return &ExpiryService{
+		messageRepo: messageRepo,
+		outboxRepo:  outboxRepo,
+		txManager:   txManager,
+		blobs:       blobs,
+		now:         time.Now,
+	}
+}
+
+// Sweep удаляет все истёкшие сообщения порциями и возвращает, сколько удалено.
+// На каждое удалённое сообщение в той же транзакции пишется событие message.deleted,
+// файлы вложений удаляются из хранилища после коммита.
+func (s *ExpiryService) Sweep(ctx context.Context) (int, error) {
+	var swept int
+	for {
+		var deleted []models.Message
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			deleted, err = s.messageRepo.DeleteExpired(ctx, s.now(), expiryBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, message := range deleted {
+				data := map[string]int64{"id": message.ID, "chat_id": message.ChatID}
+				if err := recordEvent(ctx, s.outboxRepo, models.EventMessageDeleted, message.ChatID, data); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return swept, err
+		}
+		deleteBlobs(ctx, s.blobs, attachmentsOf(deleted))
+
+		swept += len(deleted)
+		if len(deleted) < expiryBatchSize {
+			return swept, nil
+		}
+	}
+}
+
+// Run запускает уборку раз в interval, пока не отменён ctx
+func (s *ExpiryService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Sweep(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("expiry: sweep: %v", err)
+			}
+		}
+	}
+}
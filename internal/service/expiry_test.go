@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	blobMocks "github.com/GlebMoskalev/chat-golang/internal/blob/mocks"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExpirySweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	messageRepo := mocks.NewMockMessageRepository(ctrl)
+	messageRepo.EXPECT().
+		DeleteExpired(gomock.Any(), now, expiryBatchSize).
+		Return([]models.Message{{ID: 10, ChatID: 1}, {ID: 11, ChatID: 2, Attachments: []models.Attachment{{ID: 3, StorageKey: "chats/2/file"}}}}, nil)
+	blobs := blobMocks.NewMockStore(ctrl)
+	blobs.EXPECT().Delete(gomock.Any(), "chats/2/file").Return(nil)
+
+	var events []models.OutboxEvent
+	outboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	outboxRepo.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *models.OutboxEvent) error {
+			events = append(events, *event)
+			return nil
+		}).
+		Times(2)
+
+	service := NewExpiryService(messageRepo, outboxRepo, newTxManager(ctrl), blobs)
+	service.now = func() time.Time { return now }
+
+	swept, err := service.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if swept != 2 {
+		t.Errorf("ожидалось 2 удалённых сообщения, получено %d", swept)
+	}
+
+	for i, want := range []int64{10, 11} {
+		if events[i].EventType != models.EventMessageDeleted {
+			t.Errorf("ожидалось событие %s, получено %s", models.EventMessageDeleted, events[i].EventType)
+		}
+		var data map[string]int64
+		if err := json.Unmarshal([]byte(events[i].Payload), &data); err != nil {
+			t.Fatalf("невалидный payload: %v", err)
+		}
+		if data["id"] != want {
+			t.Errorf("ожидалось удаление сообщения %d, в событии %v", want, data)
+		}
+	}
+}
+
+func TestExpirySweepError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	messageRepo := mocks.NewMockMessageRepository(ctrl)
+	messageRepo.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), expiryBatchSize).Return(nil, errors.New("db is down"))
+
+	service := NewExpiryService(messageRepo, newOutbox(ctrl, ""), newTxManager(ctrl), nil)
+
+	if _, err := service.Sweep(context.Background()); err == nil {
+		t.Error("ожидалась ошибка")
+	}
+}
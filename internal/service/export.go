@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github.com/GlebMoskalev/chat-golang/internal/export"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_export_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ExportServiceInterface
+
+// exportBatchSize — сколько сообщений читается из базы за один запрос при выгрузке
+const exportBatchSize = 500
+
+type ExportServiceInterface interface {
+	ExportChat(ctx context.Context, chatID int64, enc export.Encoder) error
+}
+
+type ExportService struct {
+	chatRepo    repository.ChatRepository
+	messageRepo repository.MessageRepository
+	memberRepo  repository.ChatMemberRepository
+}
+
+func NewExportService(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, memberRepo repository.ChatMemberRepository) *ExportService {
+	return &ExportService{
+		chatRepo:    chatRepo,
+		messageRepo: messageRepo,
+		memberRepo:  memberRepo,
+	}
+}
+
+// ExportChat выгружает участников и всю историю чата от старых сообщений к новым. Сообщения читаются
+// порциями по exportBatchSize и сразу передаются кодировщику, поэтому память не растёт
+// с размером чата. Ошибки "chat not found" и доступа к личному чату возвращаются до того,
+// как что-либо записано.
+func (s *ExportService) ExportChat(ctx context.Context, chatID int64, enc export.Encoder) error {
+	chat, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID)
+	if err != nil {
+		return err
+	}
+
+	users, err := s.memberRepo.ListMembers(ctx, chatID)
+	if err != nil {
+		return err
+	}
+
+	if err := enc.Begin(*chat, users); err != nil {
+		return err
+	}
+
+	var last models.Message
+	for {
+		messages, err := s.messageRepo.ListAfter(ctx, chatID, last.CreatedAt, last.ID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if err := enc.Write(messages); err != nil {
+			return err
+		}
+		if len(messages) < exportBatchSize {
+			break
+		}
+		last = messages[len(messages)-1]
+	}
+
+	return enc.End()
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/export"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMessages := mocks.NewMockMessageRepository(ctrl)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+
+	base := time.Date(2026, 1, 28, 10, 0, 0, 0, time.UTC)
+	full := make([]models.Message, exportBatchSize)
+	for i := range full {
+		full[i] = models.Message{ID: int64(i + 1), ChatID: 1, Text: "m", CreatedAt: base.Add(time.Duration(i) * time.Second)}
+	}
+	last := full[len(full)-1]
+
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "General"}, nil)
+	mockMembers.EXPECT().ListMembers(gomock.Any(), int64(1)).Return([]models.User{{ID: 2, Username: "alice"}}, nil)
+	gomock.InOrder(
+		mockMessages.EXPECT().ListAfter(gomock.Any(), int64(1), time.Time{}, int64(0), exportBatchSize).Return(full, nil),
+		// Следующая порция начинается после последнего сообщения предыдущей
+		mockMessages.EXPECT().ListAfter(gomock.Any(), int64(1), last.CreatedAt, last.ID, exportBatchSize).
+			Return([]models.Message{{ID: 9999, ChatID: 1, Text: "последнее", CreatedAt: last.CreatedAt.Add(time.Second)}}, nil),
+	)
+
+	service := NewExportService(mockChats, mockMessages, mockMembers)
+
+	var buf bytes.Buffer
+	enc, err := export.New(export.FormatJSON, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ExportChat(context.Background(), 1, enc); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	var doc struct {
+		Messages []json.RawMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("невалидный JSON: %v", err)
+	}
+	if len(doc.Messages) != exportBatchSize+1 {
+		t.Errorf("ожидалось %d сообщений, получено %d", exportBatchSize+1, len(doc.Messages))
+	}
+}
+
+func TestExportChatNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, nil)
+
+	service := NewExportService(mockChats, mocks.NewMockMessageRepository(ctrl), mocks.NewMockChatMemberRepository(ctrl))
+
+	var buf bytes.Buffer
+	enc, _ := export.New(export.FormatCSV, &buf)
+	err := service.ExportChat(context.Background(), 1, enc)
+	if err == nil || err.Error() != "chat not found" {
+		t.Errorf("ожидалась ошибка %q, получена %v", "chat not found", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("до ошибки ничего не должно быть записано, получено %q", buf.String())
+	}
+}
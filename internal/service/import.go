@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GlebMoskalev/chat-golang/internal/importer"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_import_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ImportServiceInterface
+
+// importBatchSize — сколько сообщений сохраняется в одной транзакции при импорте
+const importBatchSize = 500
+
+type ImportServiceInterface interface {
+	Import(ctx context.Context, archive *importer.Archive) (*models.ImportReport, error)
+}
+
+type ImportService struct {
+	chatRepo    repository.ChatRepository
+	messageRepo repository.MessageRepository
+	userRepo    repository.UserRepository
+	memberRepo  repository.ChatMemberRepository
+	txManager   repository.TxManager
+	auditRepo   repository.AuditRepository
+}
+
+func NewImportService(chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, userRepo repository.UserRepository, memberRepo repository.ChatMemberRepository, txManager repository.TxManager, auditRepo repository.AuditRepository) *ImportService {
+	return &ImportService{
+		chatRepo:    chatRepo,
+		messageRepo: messageRepo,
+		userRepo:    userRepo,
+		memberRepo:  memberRepo,
+		txManager:   txManager,
+		auditRepo:   auditRepo,
+	}
+}
+
+// Import переносит архив: пользователей сопоставляет по имени (недостающих создаёт),
+// чаты и сообщения создаёт с исходным временем. Каждая порция из importBatchSize сообщений
+// сохраняется в своей транзакции, а чаты и сообщения помечаются ключами импорта, поэтому
+// прерванный импорт можно просто запустить заново: перенесённое будет пропущено.
+// События в outbox и упоминания для импортированных сообщений не создаются.
+// Каждый созданный импортом чат попадает в журнал аудита.
+func (s *ImportService) Import(ctx context.Context, archive *importer.Archive) (*models.ImportReport, error) {
+	report := &models.ImportReport{}
+
+	userIDs, err := s.importUsers(ctx, archive.Users, report)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, source := range archive.Chats {
+		if err := s.importChat(ctx, archive, source, userIDs, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// importUsers возвращает ID пользователей сервиса по их ID в архиве
+func (s *ImportService) importUsers(ctx context.Context, users []importer.User, report *models.ImportReport) (map[string]int64, error) {
+	userIDs := make(map[string]int64, len(users))
+
+	for _, source := range users {
+		user, err := s.userRepo.GetByUsername(ctx, source.Username)
+		if err != nil {
+			return nil, err
+		}
+
+		if user == nil {
+			user = &models.User{Username: source.Username, IsBot: source.IsBot}
+			err := s.userRepo.Create(ctx, user)
+			switch {
+			case err == nil:
+				report.UsersCreated++
+			case errors.Is(err, repository.ErrUsernameTaken):
+				// Имя заняли параллельно
+				if user, err = s.userRepo.GetByUsername(ctx, source.Username); err != nil {
+					return nil, err
+				}
+				if user == nil {
+					return nil, errors.New("user not found")
+				}
+			default:
+				return nil, err
+			}
+		}
+
+		userIDs[source.ExternalID] = user.ID
+	}
+
+	return userIDs, nil
+}
+
+func (s *ImportService) importChat(ctx context.Context, archive *importer.Archive, source importer.Chat, userIDs map[string]int64, report *models.ImportReport) error {
+	chat, err := s.findOrCreateChat(ctx, archive.ChatKey(source), source, report)
+	if err != nil {
+		return err
+	}
+
+	authors := make(map[int64]bool)
+	var last *models.Message
+
+	for start := 0; start < len(source.Messages); start += importBatchSize {
+		batch := source.Messages[start:min(start+importBatchSize, len(source.Messages))]
+
+		keys := make([]string, len(batch))
+		for i, message := range batch {
+			keys[i] = archive.MessageKey(source, message)
+		}
+		existing, err := s.messageRepo.ImportedKeys(ctx, keys)
+		if err != nil {
+			return err
+		}
+		skip := make(map[string]bool, len(existing))
+		for _, key := range existing {
+			skip[key] = true
+		}
+
+		messages := make([]models.Message, 0, len(batch))
+		for i, message := range batch {
+			if skip[keys[i]] {
+				report.MessagesSkipped++
+				continue
+			}
+
+			key := keys[i]
+			imported := models.Message{
+				ChatID:    chat.ID,
+				Text:      message.Text,
+				Format:    message.Format,
+				CreatedAt: message.CreatedAt,
+				EditedAt:  message.EditedAt,
+				ImportKey: &key,
+			}
+			if authorID, ok := userIDs[message.AuthorExternalID]; ok {
+				imported.AuthorID = &authorID
+			}
+			messages = append(messages, imported)
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.messageRepo.CreateBatch(ctx, messages); err != nil {
+				return err
+			}
+			for _, message := range messages {
+				if message.AuthorID == nil || authors[*message.AuthorID] {
+					continue
+				}
+				if err := s.memberRepo.Add(ctx, chat.ID, *message.AuthorID); err != nil {
+					return err
+				}
+				authors[*message.AuthorID] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		report.MessagesImported += len(messages)
+		last = &messages[len(messages)-1]
+	}
+
+	// Перенесённая история считается прочитанной её авторами
+	if last != nil {
+		for authorID := range authors {
+			if err := s.memberRepo.MarkRead(ctx, chat.ID, authorID, last.ID, last.CreatedAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// findOrCreateChat находит чат, созданный прошлым запуском импорта, или создаёт новый
+// вместе с записью в журнале аудита
+func (s *ImportService) findOrCreateChat(ctx context.Context, key string, source importer.Chat, report *models.ImportReport) (*models.Chat, error) {
+	chat, err := s.chatRepo.GetByImportKey(ctx, key)
+	if err != nil || chat != nil {
+		return chat, err
+	}
+
+	title := strings.TrimSpace(source.Title)
+	if title == "" {
+		title = key
+	}
+	if utf8.RuneCountInString(title) > 200 {
+		title = string([]rune(title)[:200])
+	}
+
+	chat = &models.Chat{
+		Title:     title,
+		Type:      models.ChatTypeRoom,
+		CreatedAt: source.CreatedAt,
+		ImportKey: &key,
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.chatRepo.Create(ctx, chat); err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditImport, TargetType: "chat", TargetID: chat.ID, ChatID: &chat.ID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, chat)
+	})
+	if errors.Is(err, repository.ErrAlreadyImported) {
+		return s.chatRepo.GetByImportKey(ctx, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report.ChatsCreated++
+	return chat, nil
+}
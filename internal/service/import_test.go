@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/importer"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func testArchive() *importer.Archive {
+	sentAt := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	return &importer.Archive{
+		Source: importer.FormatSlack,
+		Users: []importer.User{
+			{ExternalID: "U1", Username: "alice"},
+			{ExternalID: "U2", Username: "deploy_bot", IsBot: true},
+		},
+		Chats: []importer.Chat{{
+			ExternalID: "C1",
+			Title:      "general",
+			CreatedAt:  sentAt,
+			Messages: []importer.Message{
+				{ExternalID: "1.0", AuthorExternalID: "U1", Text: "привет", Format: models.MessageFormatPlain, CreatedAt: sentAt},
+				{ExternalID: "2.0", AuthorExternalID: "U2", Text: "deployed", Format: models.MessageFormatPlain, CreatedAt: sentAt.Add(time.Minute)},
+				{ExternalID: "3.0", AuthorExternalID: "U9", Text: "аноним", Format: models.MessageFormatPlain, CreatedAt: sentAt.Add(2 * time.Minute)},
+			},
+		}},
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMessages := mocks.NewMockMessageRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+
+	// alice уже есть в сервисе, бот создаётся
+	mockUsers.EXPECT().GetByUsername(gomock.Any(), "alice").Return(&models.User{ID: 1, Username: "alice"}, nil)
+	mockUsers.EXPECT().GetByUsername(gomock.Any(), "deploy_bot").Return(nil, nil)
+	mockUsers.EXPECT().
+		Create(gomock.Any(), gomock.Cond(func(user *models.User) bool { return user.Username == "deploy_bot" && user.IsBot })).
+		DoAndReturn(func(ctx context.Context, user *models.User) error {
+			user.ID = 2
+			return nil
+		})
+
+	mockChats.EXPECT().GetByImportKey(gomock.Any(), "slack:C1").Return(nil, nil)
+	mockChats.EXPECT().
+		Create(gomock.Any(), gomock.Cond(func(chat *models.Chat) bool {
+			return chat.Title == "general" && chat.ImportKey != nil && *chat.ImportKey == "slack:C1" && chat.CreatedAt.Year() == 2020
+		})).
+		DoAndReturn(func(ctx context.Context, chat *models.Chat) error {
+			chat.ID = 10
+			return nil
+		})
+
+	// Первое сообщение перенёс прерванный прошлый запуск
+	mockMessages.EXPECT().
+		ImportedKeys(gomock.Any(), []string{"slack:C1:1.0", "slack:C1:2.0", "slack:C1:3.0"}).
+		Return([]string{"slack:C1:1.0"}, nil)
+	mockMessages.EXPECT().
+		CreateBatch(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, messages []models.Message) error {
+			if len(messages) != 2 {
+				t.Fatalf("ожидалось 2 сообщения, получено %d", len(messages))
+			}
+			if messages[0].AuthorID == nil || *messages[0].AuthorID != 2 || messages[0].CreatedAt.Minute() != 1 {
+				t.Errorf("неверное сообщение %+v", messages[0])
+			}
+			if messages[1].AuthorID != nil {
+				t.Errorf("автор вне архива не сопоставляется, получен %d", *messages[1].AuthorID)
+			}
+			for i := range messages {
+				messages[i].ID = int64(100 + i)
+			}
+			return nil
+		})
+	mockMembers.EXPECT().Add(gomock.Any(), int64(10), int64(2)).Return(nil)
+	mockMembers.EXPECT().MarkRead(gomock.Any(), int64(10), int64(2), int64(101), gomock.Any()).Return(nil)
+
+	service := NewImportService(mockChats, mockMessages, mockUsers, mockMembers, newTxManager(ctrl), newAudit(ctrl, models.AuditImport))
+
+	report, err := service.Import(context.Background(), testArchive())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := models.ImportReport{UsersCreated: 1, ChatsCreated: 1, MessagesImported: 2, MessagesSkipped: 1}
+	if *report != expected {
+		t.Errorf("ожидался отчёт %+v, получен %+v", expected, *report)
+	}
+}
+
+func TestImportRerun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMessages := mocks.NewMockMessageRepository(ctrl)
+	mockUsers := mocks.NewMockUserRepository(ctrl)
+
+	mockUsers.EXPECT().GetByUsername(gomock.Any(), "alice").Return(&models.User{ID: 1, Username: "alice"}, nil)
+	mockUsers.EXPECT().GetByUsername(gomock.Any(), "deploy_bot").Return(nil, nil)
+	// Имя заняли между проверкой и созданием
+	mockUsers.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrUsernameTaken)
+	mockUsers.EXPECT().GetByUsername(gomock.Any(), "deploy_bot").Return(&models.User{ID: 2, Username: "deploy_bot"}, nil)
+
+	mockChats.EXPECT().GetByImportKey(gomock.Any(), "slack:C1").Return(&models.Chat{ID: 10, Title: "general"}, nil)
+	mockMessages.EXPECT().
+		ImportedKeys(gomock.Any(), gomock.Any()).
+		Return([]string{"slack:C1:1.0", "slack:C1:2.0", "slack:C1:3.0"}, nil)
+
+	service := NewImportService(mockChats, mockMessages, mockUsers, mocks.NewMockChatMemberRepository(ctrl), newTxManager(ctrl), newAudit(ctrl, ""))
+
+	report, err := service.Import(context.Background(), testArchive())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := models.ImportReport{MessagesSkipped: 3}
+	if *report != expected {
+		t.Errorf("ожидался отчёт %+v, получен %+v", expected, *report)
+	}
+}
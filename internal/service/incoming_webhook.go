@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_incoming_webhook_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service IncomingWebhookServiceInterface
+
+type IncomingWebhookServiceInterface interface {
+	CreateHook(ctx context.Context, chatID int64, name string) (*models.IncomingWebhook, error)
+	ListHooks(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error)
+	RevokeHook(ctx context.Context, chatID, id int64) error
+	PostMessage(ctx context.Context, token string, payload models.IncomingMessage) (*models.Message, error)
+}
+
+type IncomingWebhookService struct {
+	hookRepo    repository.IncomingWebhookRepository
+	userRepo    repository.UserRepository
+	chatRepo    repository.ChatRepository
+	txManager   repository.TxManager
+	auditRepo   repository.AuditRepository
+	chatService ChatServiceInterface
+}
+
+func NewIncomingWebhookService(hookRepo repository.IncomingWebhookRepository, userRepo repository.UserRepository, chatRepo repository.ChatRepository, txManager repository.TxManager, auditRepo repository.AuditRepository, chatService ChatServiceInterface) *IncomingWebhookService {
+	return &IncomingWebhookService{
+		hookRepo:    hookRepo,
+		userRepo:    userRepo,
+		chatRepo:    chatRepo,
+		txManager:   txManager,
+		auditRepo:   auditRepo,
+		chatService: chatService,
+	}
+}
+
+// CreateHook выпускает токен входящего вебхука и заводит для него пользователя-бота.
+// Токен возвращается только в ответе на создание, в базе хранится его хеш.
+func (s *IncomingWebhookService) CreateHook(ctx context.Context, chatID int64, name string) (*models.IncomingWebhook, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, errors.New("name must be 1-100 characters")
+	}
+
+	var hook *models.IncomingWebhook
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		userID, err := authorizeOwner(ctx, s.chatRepo, chatID)
+		if err != nil {
+			return err
+		}
+
+		token, err := generateSecret()
+		if err != nil {
+			return err
+		}
+
+		bot := &models.User{Username: "bot-" + token[:16], IsBot: true}
+		if err := s.userRepo.Create(ctx, bot); err != nil {
+			return err
+		}
+
+		hook = &models.IncomingWebhook{
+			ChatID:    chatID,
+			Name:      name,
+			TokenHash: hashToken(token),
+			Token:     token,
+			BotUserID: bot.ID,
+			CreatedBy: userID,
+		}
+		if err := s.hookRepo.Create(ctx, hook); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+
+		// Токен в журнал не попадает: он показывается только в ответе на создание
+		after := map[string]any{"id": hook.ID, "name": hook.Name, "bot_user_id": hook.BotUserID}
+		entry := models.AuditEntry{Action: models.AuditHookCreate, TargetType: "incoming_webhook", TargetID: hook.ID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+// ListHooks получает входящие вебхуки чата, доступно только владельцу
+func (s *IncomingWebhookService) ListHooks(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error) {
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	return s.hookRepo.ListByChat(ctx, chatID)
+}
+
+// RevokeHook отзывает токен, доступно только владельцу чата
+func (s *IncomingWebhookService) RevokeHook(ctx context.Context, chatID, id int64) error {
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.hookRepo.Delete(ctx, chatID, id)
+		if errors.Is(err, repository.ErrIncomingWebhookNotFound) {
+			return errors.New("hook not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditHookRevoke, TargetType: "incoming_webhook", TargetID: id, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, nil)
+	})
+}
+
+// PostMessage публикует сообщение по токену от имени бота вебхука
+func (s *IncomingWebhookService) PostMessage(ctx context.Context, token string, payload models.IncomingMessage) (*models.Message, error) {
+	hook, err := s.hookRepo.GetByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return nil, errors.New("hook not found")
+	}
+
+	return s.chatService.CreateMessage(auth.WithUserID(ctx, hook.BotUserID), hook.ChatID, models.MessageInput{
+		Text:   renderIncomingMessage(payload),
+		Format: models.MessageFormatMarkdown,
+	})
+}
+
+// renderIncomingMessage собирает текст сообщения в markdown:
+// жирный заголовок, текст, поля вида "**Название:** значение" и ссылку.
+func renderIncomingMessage(payload models.IncomingMessage) string {
+	var lines []string
+
+	if title := strings.TrimSpace(payload.Title); title != "" {
+		lines = append(lines, "**"+title+"**")
+	}
+	if text := strings.TrimSpace(payload.Text); text != "" {
+		lines = append(lines, text)
+	}
+	for _, field := range payload.Fields {
+		title := strings.TrimSpace(field.Title)
+		value := strings.TrimSpace(field.Value)
+		if title == "" && value == "" {
+			continue
+		}
+		if title == "" {
+			lines = append(lines, value)
+			continue
+		}
+		lines = append(lines, "**"+title+":** "+value)
+	}
+	if link := strings.TrimSpace(payload.URL); link != "" {
+		lines = append(lines, link)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
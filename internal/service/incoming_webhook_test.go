@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	serviceMocks "github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateHook(t *testing.T) {
+	ownerID := int64(10)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		hookName    string
+		setupMock   func(*mocks.MockChatRepository, *mocks.MockUserRepository, *mocks.MockIncomingWebhookRepository)
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:     "владелец создаёт токен",
+			ctx:      auth.WithUserID(context.Background(), ownerID),
+			hookName: "CI",
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, hr *mocks.MockIncomingWebhookRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &ownerID}, nil)
+				ur.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, user *models.User) error {
+						if !user.IsBot {
+							t.Error("пользователь вебхука должен быть ботом")
+						}
+						user.ID = 99
+						return nil
+					})
+				hr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, hook *models.IncomingWebhook) error {
+						if hook.BotUserID != 99 || hook.CreatedBy != ownerID {
+							t.Errorf("неверные bot_user_id/created_by: %d/%d", hook.BotUserID, hook.CreatedBy)
+						}
+						if hook.TokenHash != hashToken(hook.Token) {
+							t.Error("в базу должен сохраняться хеш токена")
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:        "анонимный запрос",
+			ctx:         context.Background(),
+			hookName:    "CI",
+			setupMock:   func(*mocks.MockChatRepository, *mocks.MockUserRepository, *mocks.MockIncomingWebhookRepository) {},
+			expectError: true,
+			errorMsg:    "authentication required",
+		},
+		{
+			name:     "не владелец",
+			ctx:      auth.WithUserID(context.Background(), 11),
+			hookName: "CI",
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, hr *mocks.MockIncomingWebhookRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &ownerID}, nil)
+			},
+			expectError: true,
+			errorMsg:    "forbidden",
+		},
+		{
+			name:     "чат не найден",
+			ctx:      auth.WithUserID(context.Background(), ownerID),
+			hookName: "CI",
+			setupMock: func(cr *mocks.MockChatRepository, ur *mocks.MockUserRepository, hr *mocks.MockIncomingWebhookRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			expectError: true,
+			errorMsg:    "chat not found",
+		},
+		{
+			name:        "пустое имя",
+			ctx:         auth.WithUserID(context.Background(), ownerID),
+			hookName:    "   ",
+			setupMock:   func(*mocks.MockChatRepository, *mocks.MockUserRepository, *mocks.MockIncomingWebhookRepository) {},
+			expectError: true,
+			errorMsg:    "name must be 1-100 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			chatRepo := mocks.NewMockChatRepository(ctrl)
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			hookRepo := mocks.NewMockIncomingWebhookRepository(ctrl)
+			tt.setupMock(chatRepo, userRepo, hookRepo)
+
+			action := models.AuditHookCreate
+			if tt.expectError {
+				action = ""
+			}
+			service := NewIncomingWebhookService(hookRepo, userRepo, chatRepo, newTxManager(ctrl), newAudit(ctrl, action), serviceMocks.NewMockChatServiceInterface(ctrl))
+
+			hook, err := service.CreateHook(tt.ctx, 1, tt.hookName)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("ожидалась ошибка, но её не было")
+				} else if err.Error() != tt.errorMsg {
+					t.Errorf("ожидалась ошибка '%s', получена '%s'", tt.errorMsg, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if len(hook.Token) != 64 {
+				t.Errorf("ожидался токен из 64 символов, получен '%s'", hook.Token)
+			}
+		})
+	}
+}
+
+func TestPostMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hookRepo := mocks.NewMockIncomingWebhookRepository(ctrl)
+	chatService := serviceMocks.NewMockChatServiceInterface(ctrl)
+
+	hookRepo.EXPECT().
+		GetByTokenHash(gomock.Any(), hashToken("token")).
+		Return(&models.IncomingWebhook{ID: 1, ChatID: 5, BotUserID: 99}, nil)
+	hookRepo.EXPECT().
+		GetByTokenHash(gomock.Any(), hashToken("unknown")).
+		Return(nil, nil)
+
+	want := "**Build failed**\nmain is red\n**Branch:** main\nhttps://ci.example.com/1"
+	chatService.EXPECT().
+		CreateMessage(gomock.Any(), int64(5), models.MessageInput{Text: want, Format: models.MessageFormatMarkdown}).
+		DoAndReturn(func(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
+			userID, ok := auth.UserID(ctx)
+			if !ok || userID != 99 {
+				t.Errorf("сообщение должно создаваться от имени бота, получен пользователь %d", userID)
+			}
+			return &models.Message{ID: 1, ChatID: chatID, Text: input.Text, AuthorID: &userID}, nil
+		})
+
+	service := NewIncomingWebhookService(hookRepo, mocks.NewMockUserRepository(ctrl), mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), newAudit(ctrl, ""), chatService)
+
+	message, err := service.PostMessage(context.Background(), "token", models.IncomingMessage{
+		Title:  "Build failed",
+		Text:   "main is red",
+		URL:    "https://ci.example.com/1",
+		Fields: []models.IncomingField{{Title: "Branch", Value: "main"}},
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if message.Text != want {
+		t.Errorf("ожидался текст %q, получен %q", want, message.Text)
+	}
+
+	if _, err := service.PostMessage(context.Background(), "unknown", models.IncomingMessage{Text: "hi"}); err == nil || err.Error() != "hook not found" {
+		t.Errorf("ожидалась ошибка 'hook not found', получена %v", err)
+	}
+}
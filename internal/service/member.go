@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_member_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service MemberServiceInterface
+
+type MemberServiceInterface interface {
+	AddMember(ctx context.Context, chatID int64, username string) (*models.User, error)
+	ListMembers(ctx context.Context, chatID int64) ([]models.User, error)
+}
+
+type MemberService struct {
+	memberRepo repository.ChatMemberRepository
+	userRepo   repository.UserRepository
+	chatRepo   repository.ChatRepository
+	txManager  repository.TxManager
+	auditRepo  repository.AuditRepository
+}
+
+func NewMemberService(memberRepo repository.ChatMemberRepository, userRepo repository.UserRepository, chatRepo repository.ChatRepository, txManager repository.TxManager, auditRepo repository.AuditRepository) *MemberService {
+	return &MemberService{
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		chatRepo:   chatRepo,
+		txManager:  txManager,
+		auditRepo:  auditRepo,
+	}
+}
+
+// AddMember добавляет пользователя в чат. Вступить самому может любой пользователь,
+// добавить другого — только участник чата. Состав личного чата не меняется.
+// Добавление попадает в журнал аудита.
+func (s *MemberService) AddMember(ctx context.Context, chatID int64, username string) (*models.User, error) {
+	currentID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	chat, err := s.chatRepo.GetByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, errChatNotFound
+	}
+	if chat.Type == models.ChatTypeDM {
+		return nil, errors.New("cannot add members to a direct message")
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, strings.ToLower(strings.TrimSpace(username)))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	if user.ID != currentID {
+		if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, currentID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.memberRepo.Add(ctx, chatID, user.ID); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditMemberAdd, TargetType: "user", TargetID: user.ID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, map[string]any{"chat_id": chatID, "user_id": user.ID, "username": user.Username})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListMembers получает участников чата. Участников личного чата видят только они сами.
+func (s *MemberService) ListMembers(ctx context.Context, chatID int64) ([]models.User, error) {
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	return s.memberRepo.ListMembers(ctx, chatID)
+}
+
+// authorizeMember проверяет, что пользователь состоит в чате. Для постороннего
+// различает несуществующий чат ("chat not found") и чужой ("forbidden").
+func authorizeMember(ctx context.Context, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, chatID, userID int64) error {
+	isMember, err := memberRepo.IsMember(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if isMember {
+		return nil
+	}
+
+	exists, err := chatRepo.Exists(ctx, chatID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errChatNotFound
+	}
+	return errForbidden
+}
+
+// authorizeChat получает чат и проверяет, что текущий пользователь может его читать и
+// писать в него. В личный чат пускаются только двое его участников, в остальные — все.
+func authorizeChat(ctx context.Context, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, chatID int64) (*models.Chat, error) {
+	chat, err := chatRepo.GetByID(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		return nil, errChatNotFound
+	}
+	if chat.Type != models.ChatTypeDM {
+		return chat, nil
+	}
+
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+	isMember, err := memberRepo.IsMember(ctx, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errForbidden
+	}
+
+	return chat, nil
+}
+
+// authorizeOwner проверяет, что текущий пользователь — владелец чата, и возвращает его ID.
+// Администратор управляет любым чатом, в том числе комнатой без владельца (созданной
+// анонимно или импортом); для него возвращается ID 0.
+func authorizeOwner(ctx context.Context, chatRepo repository.ChatRepository, chatID int64) (int64, error) {
+	userID, ok := auth.UserID(ctx)
+	admin := auth.IsAdmin(ctx)
+	if !ok && !admin {
+		return 0, errors.New("authentication required")
+	}
+
+	chat, err := chatRepo.GetByID(ctx, chatID)
+	if err != nil {
+		return 0, err
+	}
+	if chat == nil {
+		return 0, errChatNotFound
+	}
+	if admin {
+		return 0, nil
+	}
+	if chat.OwnerID == nil || *chat.OwnerID != userID {
+		return 0, errForbidden
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAddMember(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		username    string
+		setupMock   func(*mocks.MockChatMemberRepository, *mocks.MockUserRepository, *mocks.MockChatRepository)
+		expectAudit bool
+		expectErr   string
+	}{
+		{
+			name:     "вступление в чат",
+			ctx:      auth.WithUserID(context.Background(), 2),
+			username: "Bob",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+				ur.EXPECT().GetByUsername(gomock.Any(), "bob").Return(&models.User{ID: 2, Username: "bob"}, nil)
+				mr.EXPECT().Add(gomock.Any(), int64(1), int64(2)).Return(nil)
+			},
+			expectAudit: true,
+		},
+		{
+			name:     "участник добавляет другого",
+			ctx:      auth.WithUserID(context.Background(), 1),
+			username: "bob",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+				ur.EXPECT().GetByUsername(gomock.Any(), "bob").Return(&models.User{ID: 2, Username: "bob"}, nil)
+				mr.EXPECT().IsMember(gomock.Any(), int64(1), int64(1)).Return(true, nil)
+				mr.EXPECT().Add(gomock.Any(), int64(1), int64(2)).Return(nil)
+			},
+			expectAudit: true,
+		},
+		{
+			name:     "не участник добавляет другого",
+			ctx:      auth.WithUserID(context.Background(), 3),
+			username: "bob",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+				ur.EXPECT().GetByUsername(gomock.Any(), "bob").Return(&models.User{ID: 2, Username: "bob"}, nil)
+				mr.EXPECT().IsMember(gomock.Any(), int64(1), int64(3)).Return(false, nil)
+				cr.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:     "пользователь не найден",
+			ctx:      auth.WithUserID(context.Background(), 1),
+			username: "ghost",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+				ur.EXPECT().GetByUsername(gomock.Any(), "ghost").Return(nil, nil)
+			},
+			expectErr: "user not found",
+		},
+		{
+			name:     "личный чат",
+			ctx:      auth.WithUserID(context.Background(), 1),
+			username: "bob",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, nil)
+			},
+			expectErr: "cannot add members to a direct message",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			username:  "bob",
+			setupMock: func(mr *mocks.MockChatMemberRepository, ur *mocks.MockUserRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			mockUsers := mocks.NewMockUserRepository(ctrl)
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			tt.setupMock(mockMembers, mockUsers, mockChats)
+
+			action := ""
+			if tt.expectAudit {
+				action = models.AuditMemberAdd
+			}
+			service := NewMemberService(mockMembers, mockUsers, mockChats, newTxManager(ctrl), newAudit(ctrl, action))
+
+			_, err := service.AddMember(tt.ctx, 1, tt.username)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_mention_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service MentionServiceInterface
+
+// channelMention — @channel упоминает всех участников чата, кроме автора и ботов
+const channelMention = "channel"
+
+// mentionPattern находит @username. Перед @ не должно быть буквы, цифры или точки,
+// чтобы адреса почты (bob@example.com) не считались упоминаниями.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([A-Za-z0-9_.-]{3,32})`)
+
+// parseMentions возвращает имена упомянутых пользователей (без повторов, в нижнем регистре)
+// и признак @channel
+func parseMentions(text string) (usernames []string, channel bool) {
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Точка в конце — скорее конец предложения, чем часть имени
+		username := strings.ToLower(strings.TrimRight(match[1], "."))
+		if username == channelMention {
+			channel = true
+			continue
+		}
+		if !usernamePattern.MatchString(username) || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+	return usernames, channel
+}
+
+type MentionServiceInterface interface {
+	ListMentions(ctx context.Context, beforeMessageID int64, unreadOnly bool, limit int) (*models.MentionPage, error)
+	MarkRead(ctx context.Context, messageIDs []int64) (int64, error)
+}
+
+type MentionService struct {
+	mentionRepo repository.MentionRepository
+}
+
+func NewMentionService(mentionRepo repository.MentionRepository) *MentionService {
+	return &MentionService{mentionRepo: mentionRepo}
+}
+
+// ListMentions получает упоминания текущего пользователя, новые первыми.
+// beforeMessageID — курсор из next_before предыдущей страницы.
+func (s *MentionService) ListMentions(ctx context.Context, beforeMessageID int64, unreadOnly bool, limit int) (*models.MentionPage, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Берём на одну запись больше, чтобы понять, есть ли следующая страница
+	mentions, err := s.mentionRepo.ListByUser(ctx, userID, beforeMessageID, unreadOnly, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := s.mentionRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.MentionPage{Mentions: mentions, UnreadCount: unread}
+	if len(mentions) > limit {
+		page.Mentions = mentions[:limit]
+		next := page.Mentions[limit-1].MessageID
+		page.NextBefore = &next
+	}
+	for _, mention := range page.Mentions {
+		if mention.Message != nil {
+			renderHTML(mention.Message)
+		}
+	}
+
+	return page, nil
+}
+
+// MarkRead отмечает упоминания текущего пользователя прочитанными.
+// Пустой messageIDs отмечает все упоминания.
+func (s *MentionService) MarkRead(ctx context.Context, messageIDs []int64) (int64, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, errors.New("authentication required")
+	}
+	if len(messageIDs) > 1000 {
+		return 0, errors.New("at most 1000 message ids per request")
+	}
+
+	return s.mentionRepo.MarkRead(ctx, userID, messageIDs)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		usernames []string
+		channel   bool
+	}{
+		{name: "одно упоминание", text: "привет, @alice!", usernames: []string{"alice"}},
+		{name: "регистр и повторы", text: "@Bob и снова @bob", usernames: []string{"bob"}},
+		{name: "точка в конце предложения", text: "спроси у @carol.", usernames: []string{"carol"}},
+		{name: "точка внутри имени", text: "@john.doe посмотри", usernames: []string{"john.doe"}},
+		{name: "адрес почты не упоминание", text: "пиши на bob@example.com"},
+		{name: "слишком короткое имя", text: "@ab"},
+		{name: "channel", text: "@channel релиз готов, @alice", usernames: []string{"alice"}, channel: true},
+		{name: "без упоминаний", text: "просто текст"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usernames, channel := parseMentions(tt.text)
+			if !reflect.DeepEqual(usernames, tt.usernames) {
+				t.Errorf("ожидались имена %v, получены %v", tt.usernames, usernames)
+			}
+			if channel != tt.channel {
+				t.Errorf("ожидался channel=%v, получен %v", tt.channel, channel)
+			}
+		})
+	}
+}
+
+func TestCreateMessage_Mentions(t *testing.T) {
+	members := []models.User{
+		{ID: 1, Username: "author"},
+		{ID: 2, Username: "alice"},
+		{ID: 3, Username: "bob"},
+		{ID: 4, Username: "ci-bot", IsBot: true},
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []int64
+	}{
+		{name: "упоминание участника", text: "@alice глянь", expected: []int64{2}},
+		{name: "посторонний и сам автор игнорируются", text: "@author @stranger @bob", expected: []int64{3}},
+		{name: "channel без автора и ботов", text: "@channel деплой", expected: []int64{2, 3}},
+		{name: "бота можно упомянуть явно", text: "@ci-bot перезапусти", expected: []int64{4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChatRepo := mocks.NewMockChatRepository(ctrl)
+			mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			mockMentions := mocks.NewMockMentionRepository(ctrl)
+
+			expectRoom(mockChatRepo, 7)
+			mockChatRepo.EXPECT().Exists(gomock.Any(), int64(7)).Return(true, nil)
+			mockMessageRepo.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, message *models.Message) error {
+					message.ID = 100
+					return nil
+				})
+			mockMembers.EXPECT().Add(gomock.Any(), int64(7), int64(1)).Return(nil)
+			mockMembers.EXPECT().MarkRead(gomock.Any(), int64(7), int64(1), int64(100), gomock.Any()).Return(nil)
+			mockMembers.EXPECT().ListMembers(gomock.Any(), int64(7)).Return(members, nil)
+			mockMentions.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, mentions []models.Mention) error {
+					var userIDs []int64
+					for _, mention := range mentions {
+						if mention.MessageID != 100 || mention.ChatID != 7 {
+							t.Errorf("неверное упоминание: %+v", mention)
+						}
+						userIDs = append(userIDs, mention.UserID)
+					}
+					if !reflect.DeepEqual(userIDs, tt.expected) {
+						t.Errorf("ожидались упоминания %v, получены %v", tt.expected, userIDs)
+					}
+					return nil
+				})
+
+			service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, models.EventMessageCreated), newLinkPreviews(ctrl), mockMembers, mockMentions, newPins(ctrl), newAudit(ctrl, ""), nil, nil, nil)
+
+			message, err := service.CreateMessage(auth.WithUserID(context.Background(), 1), 7, models.MessageInput{Text: tt.text})
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if !reflect.DeepEqual(message.Mentions, tt.expected) {
+				t.Errorf("ожидалось поле mentions %v, получено %v", tt.expected, message.Mentions)
+			}
+		})
+	}
+}
+
+func TestListMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMentions := mocks.NewMockMentionRepository(ctrl)
+	mockMentions.EXPECT().
+		ListByUser(gomock.Any(), int64(42), int64(0), true, 3).
+		Return([]models.Mention{
+			{MessageID: 30, Message: &models.Message{ID: 30, Text: "@bob *hi*", Format: models.MessageFormatMarkdown}},
+			{MessageID: 20},
+			{MessageID: 10},
+		}, nil)
+	mockMentions.EXPECT().CountUnread(gomock.Any(), int64(42)).Return(int64(5), nil)
+
+	service := NewMentionService(mockMentions)
+
+	page, err := service.ListMentions(auth.WithUserID(context.Background(), 42), 0, true, 2)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(page.Mentions) != 2 {
+		t.Fatalf("ожидалось 2 упоминания, получено %d", len(page.Mentions))
+	}
+	if page.NextBefore == nil || *page.NextBefore != 20 {
+		t.Errorf("ожидался курсор 20, получен %v", page.NextBefore)
+	}
+	if page.UnreadCount != 5 {
+		t.Errorf("ожидалось 5 непрочитанных, получено %d", page.UnreadCount)
+	}
+	if page.Mentions[0].Message.HTML != "<p>@bob <em>hi</em></p>" {
+		t.Errorf("сообщение должно быть отрендерено, получено %q", page.Mentions[0].Message.HTML)
+	}
+
+	if _, err := service.ListMentions(context.Background(), 0, false, 20); err == nil || err.Error() != "authentication required" {
+		t.Errorf("ожидалась ошибка authentication required, получена %v", err)
+	}
+}
+
+func TestMarkMentionsRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMentions := mocks.NewMockMentionRepository(ctrl)
+	mockMentions.EXPECT().MarkRead(gomock.Any(), int64(42), []int64{1, 2}).Return(int64(2), nil)
+
+	service := NewMentionService(mockMentions)
+
+	updated, err := service.MarkRead(auth.WithUserID(context.Background(), 42), []int64{1, 2})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("ожидалось 2 обновления, получено %d", updated)
+	}
+
+	if _, err := service.MarkRead(context.Background(), nil); err == nil {
+		t.Error("анонимный пользователь не может отмечать упоминания")
+	}
+}
@@ -0,0 +1,90 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: AttachmentServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_attachment_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service AttachmentServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAttachmentServiceInterface is a mock of AttachmentServiceInterface interface.
+type MockAttachmentServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttachmentServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockAttachmentServiceInterfaceMockRecorder is the mock recorder for MockAttachmentServiceInterface.
+type MockAttachmentServiceInterfaceMockRecorder struct {
+	mock *MockAttachmentServiceInterface
+}
+
+// NewMockAttachmentServiceInterface creates a new mock instance.
+func NewMockAttachmentServiceInterface(ctrl *gomock.Controller) *MockAttachmentServiceInterface {
+	mock := &MockAttachmentServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAttachmentServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttachmentServiceInterface) EXPECT() *MockAttachmentServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Open mocks base method.
+func (m *MockAttachmentServiceInterface) Open(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, id)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(io.ReadSeekCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) Open(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).Open), ctx, id)
+}
+
+// OpenThumbnail mocks base method.
+func (m *MockAttachmentServiceInterface) OpenThumbnail(ctx context.Context, id int64) (*models.Attachment, io.ReadSeekCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenThumbnail", ctx, id)
+	ret0, _ := ret[0].(*models.Attachment)
+	ret1, _ := ret[1].(io.ReadSeekCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenThumbnail indicates an expected call of OpenThumbnail.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) OpenThumbnail(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenThumbnail", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).OpenThumbnail), ctx, id)
+}
+
+// Upload mocks base method.
+func (m *MockAttachmentServiceInterface) Upload(ctx context.Context, chatID int64, fileName, text string, content io.Reader) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, chatID, fileName, text, content)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockAttachmentServiceInterfaceMockRecorder) Upload(ctx, chatID, fileName, text, content any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockAttachmentServiceInterface)(nil).Upload), ctx, chatID, fileName, text, content)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: BlockServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_block_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service BlockServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockServiceInterface is a mock of BlockServiceInterface interface.
+type MockBlockServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockServiceInterfaceMockRecorder is the mock recorder for MockBlockServiceInterface.
+type MockBlockServiceInterfaceMockRecorder struct {
+	mock *MockBlockServiceInterface
+}
+
+// NewMockBlockServiceInterface creates a new mock instance.
+func NewMockBlockServiceInterface(ctrl *gomock.Controller) *MockBlockServiceInterface {
+	mock := &MockBlockServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockBlockServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockServiceInterface) EXPECT() *MockBlockServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Block mocks base method.
+func (m *MockBlockServiceInterface) Block(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Block", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Block indicates an expected call of Block.
+func (mr *MockBlockServiceInterfaceMockRecorder) Block(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Block", reflect.TypeOf((*MockBlockServiceInterface)(nil).Block), ctx, userID)
+}
+
+// ListBlocked mocks base method.
+func (m *MockBlockServiceInterface) ListBlocked(ctx context.Context) ([]models.UserBlock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlocked", ctx)
+	ret0, _ := ret[0].([]models.UserBlock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlocked indicates an expected call of ListBlocked.
+func (mr *MockBlockServiceInterfaceMockRecorder) ListBlocked(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlocked", reflect.TypeOf((*MockBlockServiceInterface)(nil).ListBlocked), ctx)
+}
+
+// Unblock mocks base method.
+func (m *MockBlockServiceInterface) Unblock(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unblock", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unblock indicates an expected call of Unblock.
+func (mr *MockBlockServiceInterfaceMockRecorder) Unblock(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unblock", reflect.TypeOf((*MockBlockServiceInterface)(nil).Unblock), ctx, userID)
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -destination=internal/service/mocks/mock_chat_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ChatServiceInterface
+//	mockgen -destination=mocks/mock_chat_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ChatServiceInterface
 //
 
 // Package mocks is a generated GoMock package.
@@ -57,18 +57,33 @@ func (mr *MockChatServiceInterfaceMockRecorder) CreateChat(ctx, title any) *gomo
 }
 
 // CreateMessage mocks base method.
-func (m *MockChatServiceInterface) CreateMessage(ctx context.Context, chatID int64, text string) (*models.Message, error) {
+func (m *MockChatServiceInterface) CreateMessage(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateMessage", ctx, chatID, text)
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, chatID, input)
 	ret0, _ := ret[0].(*models.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateMessage indicates an expected call of CreateMessage.
-func (mr *MockChatServiceInterfaceMockRecorder) CreateMessage(ctx, chatID, text any) *gomock.Call {
+func (mr *MockChatServiceInterfaceMockRecorder) CreateMessage(ctx, chatID, input any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockChatServiceInterface)(nil).CreateMessage), ctx, chatID, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockChatServiceInterface)(nil).CreateMessage), ctx, chatID, input)
+}
+
+// CreateMessages mocks base method.
+func (m *MockChatServiceInterface) CreateMessages(ctx context.Context, chatID int64, inputs []models.MessageInput, atomic bool) ([]models.BatchItemResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessages", ctx, chatID, inputs, atomic)
+	ret0, _ := ret[0].([]models.BatchItemResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessages indicates an expected call of CreateMessages.
+func (mr *MockChatServiceInterfaceMockRecorder) CreateMessages(ctx, chatID, inputs, atomic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessages", reflect.TypeOf((*MockChatServiceInterface)(nil).CreateMessages), ctx, chatID, inputs, atomic)
 }
 
 // DeleteChat mocks base method.
@@ -99,3 +114,32 @@ func (mr *MockChatServiceInterfaceMockRecorder) GetChatWithMessages(ctx, chatID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChatWithMessages", reflect.TypeOf((*MockChatServiceInterface)(nil).GetChatWithMessages), ctx, chatID, limit)
 }
+
+// ListChats mocks base method.
+func (m *MockChatServiceInterface) ListChats(ctx context.Context) ([]models.ChatSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx)
+	ret0, _ := ret[0].([]models.ChatSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockChatServiceInterfaceMockRecorder) ListChats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockChatServiceInterface)(nil).ListChats), ctx)
+}
+
+// MarkRead mocks base method.
+func (m *MockChatServiceInterface) MarkRead(ctx context.Context, chatID, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockChatServiceInterfaceMockRecorder) MarkRead(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockChatServiceInterface)(nil).MarkRead), ctx, chatID, messageID)
+}
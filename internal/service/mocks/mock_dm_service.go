@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: DMServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_dm_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service DMServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDMServiceInterface is a mock of DMServiceInterface interface.
+type MockDMServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDMServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockDMServiceInterfaceMockRecorder is the mock recorder for MockDMServiceInterface.
+type MockDMServiceInterfaceMockRecorder struct {
+	mock *MockDMServiceInterface
+}
+
+// NewMockDMServiceInterface creates a new mock instance.
+func NewMockDMServiceInterface(ctrl *gomock.Controller) *MockDMServiceInterface {
+	mock := &MockDMServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockDMServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDMServiceInterface) EXPECT() *MockDMServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// OpenDM mocks base method.
+func (m *MockDMServiceInterface) OpenDM(ctx context.Context, userID int64) (*models.Chat, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenDM", ctx, userID)
+	ret0, _ := ret[0].(*models.Chat)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenDM indicates an expected call of OpenDM.
+func (mr *MockDMServiceInterfaceMockRecorder) OpenDM(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenDM", reflect.TypeOf((*MockDMServiceInterface)(nil).OpenDM), ctx, userID)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ExportServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_export_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ExportServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	export "github.com/GlebMoskalev/chat-golang/internal/export"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportServiceInterface is a mock of ExportServiceInterface interface.
+type MockExportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockExportServiceInterfaceMockRecorder is the mock recorder for MockExportServiceInterface.
+type MockExportServiceInterfaceMockRecorder struct {
+	mock *MockExportServiceInterface
+}
+
+// NewMockExportServiceInterface creates a new mock instance.
+func NewMockExportServiceInterface(ctrl *gomock.Controller) *MockExportServiceInterface {
+	mock := &MockExportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockExportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportServiceInterface) EXPECT() *MockExportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ExportChat mocks base method.
+func (m *MockExportServiceInterface) ExportChat(ctx context.Context, chatID int64, enc export.Encoder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportChat", ctx, chatID, enc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportChat indicates an expected call of ExportChat.
+func (mr *MockExportServiceInterfaceMockRecorder) ExportChat(ctx, chatID, enc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportChat", reflect.TypeOf((*MockExportServiceInterface)(nil).ExportChat), ctx, chatID, enc)
+}
@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ImportServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_import_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ImportServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	importer "github.com/GlebMoskalev/chat-golang/internal/importer"
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockImportServiceInterface is a mock of ImportServiceInterface interface.
+type MockImportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockImportServiceInterfaceMockRecorder is the mock recorder for MockImportServiceInterface.
+type MockImportServiceInterfaceMockRecorder struct {
+	mock *MockImportServiceInterface
+}
+
+// NewMockImportServiceInterface creates a new mock instance.
+func NewMockImportServiceInterface(ctrl *gomock.Controller) *MockImportServiceInterface {
+	mock := &MockImportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockImportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportServiceInterface) EXPECT() *MockImportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *MockImportServiceInterface) Import(ctx context.Context, archive *importer.Archive) (*models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, archive)
+	ret0, _ := ret[0].(*models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockImportServiceInterfaceMockRecorder) Import(ctx, archive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockImportServiceInterface)(nil).Import), ctx, archive)
+}
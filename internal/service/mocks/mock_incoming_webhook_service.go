@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: IncomingWebhookServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_incoming_webhook_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service IncomingWebhookServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockIncomingWebhookServiceInterface is a mock of IncomingWebhookServiceInterface interface.
+type MockIncomingWebhookServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockIncomingWebhookServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockIncomingWebhookServiceInterfaceMockRecorder is the mock recorder for MockIncomingWebhookServiceInterface.
+type MockIncomingWebhookServiceInterfaceMockRecorder struct {
+	mock *MockIncomingWebhookServiceInterface
+}
+
+// NewMockIncomingWebhookServiceInterface creates a new mock instance.
+func NewMockIncomingWebhookServiceInterface(ctrl *gomock.Controller) *MockIncomingWebhookServiceInterface {
+	mock := &MockIncomingWebhookServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockIncomingWebhookServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIncomingWebhookServiceInterface) EXPECT() *MockIncomingWebhookServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateHook mocks base method.
+func (m *MockIncomingWebhookServiceInterface) CreateHook(ctx context.Context, chatID int64, name string) (*models.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHook", ctx, chatID, name)
+	ret0, _ := ret[0].(*models.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHook indicates an expected call of CreateHook.
+func (mr *MockIncomingWebhookServiceInterfaceMockRecorder) CreateHook(ctx, chatID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHook", reflect.TypeOf((*MockIncomingWebhookServiceInterface)(nil).CreateHook), ctx, chatID, name)
+}
+
+// ListHooks mocks base method.
+func (m *MockIncomingWebhookServiceInterface) ListHooks(ctx context.Context, chatID int64) ([]models.IncomingWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHooks", ctx, chatID)
+	ret0, _ := ret[0].([]models.IncomingWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHooks indicates an expected call of ListHooks.
+func (mr *MockIncomingWebhookServiceInterfaceMockRecorder) ListHooks(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHooks", reflect.TypeOf((*MockIncomingWebhookServiceInterface)(nil).ListHooks), ctx, chatID)
+}
+
+// PostMessage mocks base method.
+func (m *MockIncomingWebhookServiceInterface) PostMessage(ctx context.Context, token string, payload models.IncomingMessage) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostMessage", ctx, token, payload)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostMessage indicates an expected call of PostMessage.
+func (mr *MockIncomingWebhookServiceInterfaceMockRecorder) PostMessage(ctx, token, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostMessage", reflect.TypeOf((*MockIncomingWebhookServiceInterface)(nil).PostMessage), ctx, token, payload)
+}
+
+// RevokeHook mocks base method.
+func (m *MockIncomingWebhookServiceInterface) RevokeHook(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeHook", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeHook indicates an expected call of RevokeHook.
+func (mr *MockIncomingWebhookServiceInterfaceMockRecorder) RevokeHook(ctx, chatID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeHook", reflect.TypeOf((*MockIncomingWebhookServiceInterface)(nil).RevokeHook), ctx, chatID, id)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: MemberServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_member_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service MemberServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMemberServiceInterface is a mock of MemberServiceInterface interface.
+type MockMemberServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMemberServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockMemberServiceInterfaceMockRecorder is the mock recorder for MockMemberServiceInterface.
+type MockMemberServiceInterfaceMockRecorder struct {
+	mock *MockMemberServiceInterface
+}
+
+// NewMockMemberServiceInterface creates a new mock instance.
+func NewMockMemberServiceInterface(ctrl *gomock.Controller) *MockMemberServiceInterface {
+	mock := &MockMemberServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockMemberServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMemberServiceInterface) EXPECT() *MockMemberServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockMemberServiceInterface) AddMember(ctx context.Context, chatID int64, username string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, chatID, username)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockMemberServiceInterfaceMockRecorder) AddMember(ctx, chatID, username any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockMemberServiceInterface)(nil).AddMember), ctx, chatID, username)
+}
+
+// ListMembers mocks base method.
+func (m *MockMemberServiceInterface) ListMembers(ctx context.Context, chatID int64) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, chatID)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockMemberServiceInterfaceMockRecorder) ListMembers(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockMemberServiceInterface)(nil).ListMembers), ctx, chatID)
+}
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: MentionServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_mention_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service MentionServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMentionServiceInterface is a mock of MentionServiceInterface interface.
+type MockMentionServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockMentionServiceInterfaceMockRecorder is the mock recorder for MockMentionServiceInterface.
+type MockMentionServiceInterfaceMockRecorder struct {
+	mock *MockMentionServiceInterface
+}
+
+// NewMockMentionServiceInterface creates a new mock instance.
+func NewMockMentionServiceInterface(ctrl *gomock.Controller) *MockMentionServiceInterface {
+	mock := &MockMentionServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockMentionServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMentionServiceInterface) EXPECT() *MockMentionServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ListMentions mocks base method.
+func (m *MockMentionServiceInterface) ListMentions(ctx context.Context, beforeMessageID int64, unreadOnly bool, limit int) (*models.MentionPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMentions", ctx, beforeMessageID, unreadOnly, limit)
+	ret0, _ := ret[0].(*models.MentionPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMentions indicates an expected call of ListMentions.
+func (mr *MockMentionServiceInterfaceMockRecorder) ListMentions(ctx, beforeMessageID, unreadOnly, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMentions", reflect.TypeOf((*MockMentionServiceInterface)(nil).ListMentions), ctx, beforeMessageID, unreadOnly, limit)
+}
+
+// MarkRead mocks base method.
+func (m *MockMentionServiceInterface) MarkRead(ctx context.Context, messageIDs []int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, messageIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockMentionServiceInterfaceMockRecorder) MarkRead(ctx, messageIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockMentionServiceInterface)(nil).MarkRead), ctx, messageIDs)
+}
@@ -0,0 +1,183 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ModerationServiceInterface,Moderator)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_moderation_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ModerationServiceInterface,Moderator
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	moderation "github.com/GlebMoskalev/chat-golang/internal/moderation"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockModerationServiceInterface is a mock of ModerationServiceInterface interface.
+type MockModerationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockModerationServiceInterfaceMockRecorder is the mock recorder for MockModerationServiceInterface.
+type MockModerationServiceInterfaceMockRecorder struct {
+	mock *MockModerationServiceInterface
+}
+
+// NewMockModerationServiceInterface creates a new mock instance.
+func NewMockModerationServiceInterface(ctrl *gomock.Controller) *MockModerationServiceInterface {
+	mock := &MockModerationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockModerationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationServiceInterface) EXPECT() *MockModerationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockModerationServiceInterface) Approve(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockModerationServiceInterfaceMockRecorder) Approve(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockModerationServiceInterface)(nil).Approve), ctx, messageID)
+}
+
+// CreateRule mocks base method.
+func (m *MockModerationServiceInterface) CreateRule(ctx context.Context, chatID int64, pattern, action string) (*models.ModerationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, chatID, pattern, action)
+	ret0, _ := ret[0].(*models.ModerationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockModerationServiceInterfaceMockRecorder) CreateRule(ctx, chatID, pattern, action any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockModerationServiceInterface)(nil).CreateRule), ctx, chatID, pattern, action)
+}
+
+// DeleteRule mocks base method.
+func (m *MockModerationServiceInterface) DeleteRule(ctx context.Context, chatID, ruleID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, chatID, ruleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockModerationServiceInterfaceMockRecorder) DeleteRule(ctx, chatID, ruleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockModerationServiceInterface)(nil).DeleteRule), ctx, chatID, ruleID)
+}
+
+// ListRules mocks base method.
+func (m *MockModerationServiceInterface) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRules", ctx, chatID)
+	ret0, _ := ret[0].([]models.ModerationRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRules indicates an expected call of ListRules.
+func (mr *MockModerationServiceInterfaceMockRecorder) ListRules(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRules", reflect.TypeOf((*MockModerationServiceInterface)(nil).ListRules), ctx, chatID)
+}
+
+// Queue mocks base method.
+func (m *MockModerationServiceInterface) Queue(ctx context.Context) ([]models.ModerationFlag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Queue", ctx)
+	ret0, _ := ret[0].([]models.ModerationFlag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Queue indicates an expected call of Queue.
+func (mr *MockModerationServiceInterfaceMockRecorder) Queue(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Queue", reflect.TypeOf((*MockModerationServiceInterface)(nil).Queue), ctx)
+}
+
+// Reject mocks base method.
+func (m *MockModerationServiceInterface) Reject(ctx context.Context, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockModerationServiceInterfaceMockRecorder) Reject(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockModerationServiceInterface)(nil).Reject), ctx, messageID)
+}
+
+// MockModerator is a mock of Moderator interface.
+type MockModerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockModeratorMockRecorder
+	isgomock struct{}
+}
+
+// MockModeratorMockRecorder is the mock recorder for MockModerator.
+type MockModeratorMockRecorder struct {
+	mock *MockModerator
+}
+
+// NewMockModerator creates a new mock instance.
+func NewMockModerator(ctrl *gomock.Controller) *MockModerator {
+	mock := &MockModerator{ctrl: ctrl}
+	mock.recorder = &MockModeratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerator) EXPECT() *MockModeratorMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockModerator) Check(ctx context.Context, chatID int64, text string) (moderation.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, chatID, text)
+	ret0, _ := ret[0].(moderation.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockModeratorMockRecorder) Check(ctx, chatID, text any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockModerator)(nil).Check), ctx, chatID, text)
+}
+
+// Flag mocks base method.
+func (m *MockModerator) Flag(ctx context.Context, message *models.Message, reasons []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flag", ctx, message, reasons)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flag indicates an expected call of Flag.
+func (mr *MockModeratorMockRecorder) Flag(ctx, message, reasons any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flag", reflect.TypeOf((*MockModerator)(nil).Flag), ctx, message, reasons)
+}
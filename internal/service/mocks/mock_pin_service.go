@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: PinServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_pin_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service PinServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPinServiceInterface is a mock of PinServiceInterface interface.
+type MockPinServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPinServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPinServiceInterfaceMockRecorder is the mock recorder for MockPinServiceInterface.
+type MockPinServiceInterfaceMockRecorder struct {
+	mock *MockPinServiceInterface
+}
+
+// NewMockPinServiceInterface creates a new mock instance.
+func NewMockPinServiceInterface(ctrl *gomock.Controller) *MockPinServiceInterface {
+	mock := &MockPinServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockPinServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPinServiceInterface) EXPECT() *MockPinServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ListPins mocks base method.
+func (m *MockPinServiceInterface) ListPins(ctx context.Context, chatID int64) ([]models.ChatPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPins", ctx, chatID)
+	ret0, _ := ret[0].([]models.ChatPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPins indicates an expected call of ListPins.
+func (mr *MockPinServiceInterfaceMockRecorder) ListPins(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPins", reflect.TypeOf((*MockPinServiceInterface)(nil).ListPins), ctx, chatID)
+}
+
+// PinMessage mocks base method.
+func (m *MockPinServiceInterface) PinMessage(ctx context.Context, chatID, messageID int64) (*models.ChatPin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(*models.ChatPin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinMessage indicates an expected call of PinMessage.
+func (mr *MockPinServiceInterfaceMockRecorder) PinMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinMessage", reflect.TypeOf((*MockPinServiceInterface)(nil).PinMessage), ctx, chatID, messageID)
+}
+
+// UnpinMessage mocks base method.
+func (m *MockPinServiceInterface) UnpinMessage(ctx context.Context, chatID, messageID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinMessage", ctx, chatID, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinMessage indicates an expected call of UnpinMessage.
+func (mr *MockPinServiceInterfaceMockRecorder) UnpinMessage(ctx, chatID, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinMessage", reflect.TypeOf((*MockPinServiceInterface)(nil).UnpinMessage), ctx, chatID, messageID)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: PresenceServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_presence_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service PresenceServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPresenceServiceInterface is a mock of PresenceServiceInterface interface.
+type MockPresenceServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresenceServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockPresenceServiceInterfaceMockRecorder is the mock recorder for MockPresenceServiceInterface.
+type MockPresenceServiceInterfaceMockRecorder struct {
+	mock *MockPresenceServiceInterface
+}
+
+// NewMockPresenceServiceInterface creates a new mock instance.
+func NewMockPresenceServiceInterface(ctrl *gomock.Controller) *MockPresenceServiceInterface {
+	mock := &MockPresenceServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockPresenceServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresenceServiceInterface) EXPECT() *MockPresenceServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetPresence mocks base method.
+func (m *MockPresenceServiceInterface) GetPresence(ctx context.Context, chatID int64) (*models.ChatPresence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPresence", ctx, chatID)
+	ret0, _ := ret[0].(*models.ChatPresence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPresence indicates an expected call of GetPresence.
+func (mr *MockPresenceServiceInterfaceMockRecorder) GetPresence(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresence", reflect.TypeOf((*MockPresenceServiceInterface)(nil).GetPresence), ctx, chatID)
+}
+
+// Heartbeat mocks base method.
+func (m *MockPresenceServiceInterface) Heartbeat(ctx context.Context, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Heartbeat", ctx, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Heartbeat indicates an expected call of Heartbeat.
+func (mr *MockPresenceServiceInterfaceMockRecorder) Heartbeat(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Heartbeat", reflect.TypeOf((*MockPresenceServiceInterface)(nil).Heartbeat), ctx, status)
+}
+
+// Typing mocks base method.
+func (m *MockPresenceServiceInterface) Typing(ctx context.Context, chatID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Typing", ctx, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Typing indicates an expected call of Typing.
+func (mr *MockPresenceServiceInterfaceMockRecorder) Typing(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Typing", reflect.TypeOf((*MockPresenceServiceInterface)(nil).Typing), ctx, chatID)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ReportServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_report_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ReportServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReportServiceInterface is a mock of ReportServiceInterface interface.
+type MockReportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockReportServiceInterfaceMockRecorder is the mock recorder for MockReportServiceInterface.
+type MockReportServiceInterfaceMockRecorder struct {
+	mock *MockReportServiceInterface
+}
+
+// NewMockReportServiceInterface creates a new mock instance.
+func NewMockReportServiceInterface(ctrl *gomock.Controller) *MockReportServiceInterface {
+	mock := &MockReportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportServiceInterface) EXPECT() *MockReportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ListReports mocks base method.
+func (m *MockReportServiceInterface) ListReports(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReports", ctx, status, afterID, limit)
+	ret0, _ := ret[0].([]models.MessageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReports indicates an expected call of ListReports.
+func (mr *MockReportServiceInterfaceMockRecorder) ListReports(ctx, status, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReports", reflect.TypeOf((*MockReportServiceInterface)(nil).ListReports), ctx, status, afterID, limit)
+}
+
+// ReportMessage mocks base method.
+func (m *MockReportServiceInterface) ReportMessage(ctx context.Context, chatID, messageID int64, reason, comment string) (*models.MessageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportMessage", ctx, chatID, messageID, reason, comment)
+	ret0, _ := ret[0].(*models.MessageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportMessage indicates an expected call of ReportMessage.
+func (mr *MockReportServiceInterfaceMockRecorder) ReportMessage(ctx, chatID, messageID, reason, comment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportMessage", reflect.TypeOf((*MockReportServiceInterface)(nil).ReportMessage), ctx, chatID, messageID, reason, comment)
+}
+
+// ResolveReport mocks base method.
+func (m *MockReportServiceInterface) ResolveReport(ctx context.Context, id int64, status, resolution string, deleteMessage bool) (*models.MessageReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReport", ctx, id, status, resolution, deleteMessage)
+	ret0, _ := ret[0].(*models.MessageReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReport indicates an expected call of ResolveReport.
+func (mr *MockReportServiceInterfaceMockRecorder) ResolveReport(ctx, id, status, resolution, deleteMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReport", reflect.TypeOf((*MockReportServiceInterface)(nil).ResolveReport), ctx, id, status, resolution, deleteMessage)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: RetentionServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_retention_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service RetentionServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRetentionServiceInterface is a mock of RetentionServiceInterface interface.
+type MockRetentionServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockRetentionServiceInterfaceMockRecorder is the mock recorder for MockRetentionServiceInterface.
+type MockRetentionServiceInterfaceMockRecorder struct {
+	mock *MockRetentionServiceInterface
+}
+
+// NewMockRetentionServiceInterface creates a new mock instance.
+func NewMockRetentionServiceInterface(ctrl *gomock.Controller) *MockRetentionServiceInterface {
+	mock := &MockRetentionServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRetentionServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionServiceInterface) EXPECT() *MockRetentionServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeletePolicy mocks base method.
+func (m *MockRetentionServiceInterface) DeletePolicy(ctx context.Context, chatID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", ctx, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockRetentionServiceInterfaceMockRecorder) DeletePolicy(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockRetentionServiceInterface)(nil).DeletePolicy), ctx, chatID)
+}
+
+// GetPolicy mocks base method.
+func (m *MockRetentionServiceInterface) GetPolicy(ctx context.Context, chatID int64) (*models.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicy", ctx, chatID)
+	ret0, _ := ret[0].(*models.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicy indicates an expected call of GetPolicy.
+func (mr *MockRetentionServiceInterfaceMockRecorder) GetPolicy(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicy", reflect.TypeOf((*MockRetentionServiceInterface)(nil).GetPolicy), ctx, chatID)
+}
+
+// Report mocks base method.
+func (m *MockRetentionServiceInterface) Report(ctx context.Context) (*models.RetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx)
+	ret0, _ := ret[0].(*models.RetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockRetentionServiceInterfaceMockRecorder) Report(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockRetentionServiceInterface)(nil).Report), ctx)
+}
+
+// SetPolicy mocks base method.
+func (m *MockRetentionServiceInterface) SetPolicy(ctx context.Context, chatID int64, policy models.RetentionPolicy) (*models.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPolicy", ctx, chatID, policy)
+	ret0, _ := ret[0].(*models.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPolicy indicates an expected call of SetPolicy.
+func (mr *MockRetentionServiceInterfaceMockRecorder) SetPolicy(ctx, chatID, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPolicy", reflect.TypeOf((*MockRetentionServiceInterface)(nil).SetPolicy), ctx, chatID, policy)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ScheduleServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_schedule_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ScheduleServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockScheduleServiceInterface is a mock of ScheduleServiceInterface interface.
+type MockScheduleServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockScheduleServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockScheduleServiceInterfaceMockRecorder is the mock recorder for MockScheduleServiceInterface.
+type MockScheduleServiceInterfaceMockRecorder struct {
+	mock *MockScheduleServiceInterface
+}
+
+// NewMockScheduleServiceInterface creates a new mock instance.
+func NewMockScheduleServiceInterface(ctrl *gomock.Controller) *MockScheduleServiceInterface {
+	mock := &MockScheduleServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockScheduleServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockScheduleServiceInterface) EXPECT() *MockScheduleServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CancelScheduled mocks base method.
+func (m *MockScheduleServiceInterface) CancelScheduled(ctx context.Context, chatID, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelScheduled", ctx, chatID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelScheduled indicates an expected call of CancelScheduled.
+func (mr *MockScheduleServiceInterfaceMockRecorder) CancelScheduled(ctx, chatID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelScheduled", reflect.TypeOf((*MockScheduleServiceInterface)(nil).CancelScheduled), ctx, chatID, id)
+}
+
+// ListScheduled mocks base method.
+func (m *MockScheduleServiceInterface) ListScheduled(ctx context.Context, chatID int64) ([]models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListScheduled", ctx, chatID)
+	ret0, _ := ret[0].([]models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListScheduled indicates an expected call of ListScheduled.
+func (mr *MockScheduleServiceInterfaceMockRecorder) ListScheduled(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScheduled", reflect.TypeOf((*MockScheduleServiceInterface)(nil).ListScheduled), ctx, chatID)
+}
+
+// ScheduleMessage mocks base method.
+func (m *MockScheduleServiceInterface) ScheduleMessage(ctx context.Context, chatID int64, input models.MessageInput, sendAt time.Time) (*models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleMessage", ctx, chatID, input, sendAt)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleMessage indicates an expected call of ScheduleMessage.
+func (mr *MockScheduleServiceInterfaceMockRecorder) ScheduleMessage(ctx, chatID, input, sendAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleMessage", reflect.TypeOf((*MockScheduleServiceInterface)(nil).ScheduleMessage), ctx, chatID, input, sendAt)
+}
+
+// UpdateScheduled mocks base method.
+func (m *MockScheduleServiceInterface) UpdateScheduled(ctx context.Context, chatID, id int64, patch models.ScheduledMessagePatch) (*models.ScheduledMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduled", ctx, chatID, id, patch)
+	ret0, _ := ret[0].(*models.ScheduledMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduled indicates an expected call of UpdateScheduled.
+func (mr *MockScheduleServiceInterfaceMockRecorder) UpdateScheduled(ctx, chatID, id, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduled", reflect.TypeOf((*MockScheduleServiceInterface)(nil).UpdateScheduled), ctx, chatID, id, patch)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: StreamServiceInterface,EventSubscriber)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_stream_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service StreamServiceInterface,EventSubscriber
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStreamServiceInterface is a mock of StreamServiceInterface interface.
+type MockStreamServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStreamServiceInterfaceMockRecorder is the mock recorder for MockStreamServiceInterface.
+type MockStreamServiceInterfaceMockRecorder struct {
+	mock *MockStreamServiceInterface
+}
+
+// NewMockStreamServiceInterface creates a new mock instance.
+func NewMockStreamServiceInterface(ctrl *gomock.Controller) *MockStreamServiceInterface {
+	mock := &MockStreamServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockStreamServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamServiceInterface) EXPECT() *MockStreamServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockStreamServiceInterface) Subscribe(ctx context.Context, chatID int64) (<-chan models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, chatID)
+	ret0, _ := ret[0].(<-chan models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockStreamServiceInterfaceMockRecorder) Subscribe(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockStreamServiceInterface)(nil).Subscribe), ctx, chatID)
+}
+
+// MockEventSubscriber is a mock of EventSubscriber interface.
+type MockEventSubscriber struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventSubscriberMockRecorder
+	isgomock struct{}
+}
+
+// MockEventSubscriberMockRecorder is the mock recorder for MockEventSubscriber.
+type MockEventSubscriberMockRecorder struct {
+	mock *MockEventSubscriber
+}
+
+// NewMockEventSubscriber creates a new mock instance.
+func NewMockEventSubscriber(ctrl *gomock.Controller) *MockEventSubscriber {
+	mock := &MockEventSubscriber{ctrl: ctrl}
+	mock.recorder = &MockEventSubscriberMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventSubscriber) EXPECT() *MockEventSubscriberMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockEventSubscriber) Subscribe(buffer int) (<-chan models.OutboxEvent, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", buffer)
+	ret0, _ := ret[0].(<-chan models.OutboxEvent)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEventSubscriberMockRecorder) Subscribe(buffer any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEventSubscriber)(nil).Subscribe), buffer)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: ThumbnailQueue)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_thumbnail_queue.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ThumbnailQueue
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockThumbnailQueue is a mock of ThumbnailQueue interface.
+type MockThumbnailQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockThumbnailQueueMockRecorder
+	isgomock struct{}
+}
+
+// MockThumbnailQueueMockRecorder is the mock recorder for MockThumbnailQueue.
+type MockThumbnailQueueMockRecorder struct {
+	mock *MockThumbnailQueue
+}
+
+// NewMockThumbnailQueue creates a new mock instance.
+func NewMockThumbnailQueue(ctrl *gomock.Controller) *MockThumbnailQueue {
+	mock := &MockThumbnailQueue{ctrl: ctrl}
+	mock.recorder = &MockThumbnailQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockThumbnailQueue) EXPECT() *MockThumbnailQueueMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockThumbnailQueue) Enqueue(attachment models.Attachment) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", attachment)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockThumbnailQueueMockRecorder) Enqueue(attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockThumbnailQueue)(nil).Enqueue), attachment)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/GlebMoskalev/chat-golang/internal/service (interfaces: WebhookServiceInterface)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks/mock_webhook_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service WebhookServiceInterface
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/GlebMoskalev/chat-golang/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookServiceInterface is a mock of WebhookServiceInterface interface.
+type MockWebhookServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookServiceInterfaceMockRecorder is the mock recorder for MockWebhookServiceInterface.
+type MockWebhookServiceInterfaceMockRecorder struct {
+	mock *MockWebhookServiceInterface
+}
+
+// NewMockWebhookServiceInterface creates a new mock instance.
+func NewMockWebhookServiceInterface(ctrl *gomock.Controller) *MockWebhookServiceInterface {
+	mock := &MockWebhookServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockWebhookServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookServiceInterface) EXPECT() *MockWebhookServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookServiceInterface) CreateWebhook(ctx context.Context, rawURL string, events []string, chatID *int64, secret string) (*models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, rawURL, events, chatID, secret)
+	ret0, _ := ret[0].(*models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) CreateWebhook(ctx, rawURL, events, chatID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).CreateWebhook), ctx, rawURL, events, chatID, secret)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookServiceInterface) DeleteWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).DeleteWebhook), ctx, id)
+}
+
+// EnableWebhook mocks base method.
+func (m *MockWebhookServiceInterface) EnableWebhook(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableWebhook indicates an expected call of EnableWebhook.
+func (mr *MockWebhookServiceInterfaceMockRecorder) EnableWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableWebhook", reflect.TypeOf((*MockWebhookServiceInterface)(nil).EnableWebhook), ctx, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockWebhookServiceInterface) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, webhookID, limit)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockWebhookServiceInterfaceMockRecorder) ListDeliveries(ctx, webhookID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockWebhookServiceInterface)(nil).ListDeliveries), ctx, webhookID, limit)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockWebhookServiceInterface) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockWebhookServiceInterfaceMockRecorder) ListWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockWebhookServiceInterface)(nil).ListWebhooks), ctx)
+}
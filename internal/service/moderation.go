@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/moderation"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_moderation_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ModerationServiceInterface,Moderator
+
+// Moderator проверяет сообщения перед сохранением и ставит отмеченные в очередь проверки
+type Moderator interface {
+	Check(ctx context.Context, chatID int64, text string) (moderation.Result, error)
+	Flag(ctx context.Context, message *models.Message, reasons []string) error
+}
+
+type ModerationServiceInterface interface {
+	Queue(ctx context.Context) ([]models.ModerationFlag, error)
+	Approve(ctx context.Context, messageID int64) error
+	Reject(ctx context.Context, messageID int64) error
+	ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error)
+	CreateRule(ctx context.Context, chatID int64, pattern, action string) (*models.ModerationRule, error)
+	DeleteRule(ctx context.Context, chatID, ruleID int64) error
+}
+
+type ModerationService struct {
+	chain          moderation.Chain
+	moderationRepo repository.ModerationRepository
+	chatRepo       repository.ChatRepository
+	messageRepo    repository.MessageRepository
+	txManager      repository.TxManager
+	outboxRepo     repository.OutboxRepository
+	auditRepo      repository.AuditRepository
+	attachmentRepo repository.AttachmentRepository
+	blobs          blob.Store
+}
+
+// NewModerationService создаёт сервис модерации. chain вызывается для каждого нового
+// сообщения; пустая цепочка пропускает всё.
+func NewModerationService(chain moderation.Chain, moderationRepo repository.ModerationRepository, chatRepo repository.ChatRepository, messageRepo repository.MessageRepository, txManager repository.TxManager, outboxRepo repository.OutboxRepository, auditRepo repository.AuditRepository, attachmentRepo repository.AttachmentRepository, blobs blob.Store) *ModerationService {
+	return &ModerationService{
+		chain:          chain,
+		moderationRepo: moderationRepo,
+		chatRepo:       chatRepo,
+		messageRepo:    messageRepo,
+		txManager:      txManager,
+		outboxRepo:     outboxRepo,
+		auditRepo:      auditRepo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+	}
+}
+
+// Check пропускает текст сообщения через цепочку хуков
+func (s *ModerationService) Check(ctx context.Context, chatID int64, text string) (moderation.Result, error) {
+	return s.chain.Run(ctx, chatID, text)
+}
+
+// Flag ставит только что сохранённое сообщение в очередь проверки владельца чата
+func (s *ModerationService) Flag(ctx context.Context, message *models.Message, reasons []string) error {
+	return s.moderationRepo.AddFlag(ctx, &models.ModerationFlag{
+		MessageID: message.ID,
+		ChatID:    message.ChatID,
+		Reason:    strings.Join(reasons, "; "),
+	})
+}
+
+// Queue получает отмеченные сообщения из чатов, которыми владеет текущий пользователь.
+// Администратор получает всю очередь, в том числе отметки в чатах без владельца.
+func (s *ModerationService) Queue(ctx context.Context) ([]models.ModerationFlag, error) {
+	var flags []models.ModerationFlag
+	var err error
+	if auth.IsAdmin(ctx) {
+		flags, err = s.moderationRepo.ListFlags(ctx)
+	} else {
+		userID, ok := auth.UserID(ctx)
+		if !ok {
+			return nil, errors.New("authentication required")
+		}
+		flags, err = s.moderationRepo.ListFlagsByOwner(ctx, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range flags {
+		if flags[i].Message != nil {
+			renderHTML(flags[i].Message)
+		}
+	}
+
+	return flags, nil
+}
+
+// Approve убирает сообщение из очереди, оставляя его в чате
+func (s *ModerationService) Approve(ctx context.Context, messageID int64) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		flag, err := s.flagged(ctx, messageID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.moderationRepo.DeleteFlag(ctx, messageID); err != nil {
+			if errors.Is(err, repository.ErrFlagNotFound) {
+				return errors.New("flag not found")
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditModerationApprove, TargetType: "message", TargetID: messageID, ChatID: &flag.ChatID}
+		return recordAudit(ctx, s.auditRepo, entry, flag, nil)
+	})
+}
+
+// Reject удаляет отмеченное сообщение из чата вместе с отметкой и файлами вложений.
+// Решения по очереди попадают в журнал аудита.
+func (s *ModerationService) Reject(ctx context.Context, messageID int64) error {
+	var attachments []models.Attachment
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		flag, err := s.flagged(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		// Снимок удаляемого сообщения остаётся только в журнале аудита
+		if flag.Message, err = s.messageRepo.GetByID(ctx, messageID); err != nil {
+			return err
+		}
+		if attachments, err = s.attachmentRepo.ListByMessage(ctx, messageID); err != nil {
+			return err
+		}
+
+		if err := s.messageRepo.Delete(ctx, messageID); err != nil {
+			if errors.Is(err, repository.ErrMessageNotFound) {
+				return errors.New("flag not found")
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditModerationReject, TargetType: "message", TargetID: messageID, ChatID: &flag.ChatID}
+		if err := recordAudit(ctx, s.auditRepo, entry, flag, nil); err != nil {
+			return err
+		}
+
+		data := map[string]int64{"id": messageID, "chat_id": flag.ChatID}
+		return recordEvent(ctx, s.outboxRepo, models.EventMessageDeleted, flag.ChatID, data)
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteBlobs(ctx, s.blobs, attachments)
+	return nil
+}
+
+// ListRules получает правила модерации чата. Видны они только владельцу.
+func (s *ModerationService) ListRules(ctx context.Context, chatID int64) ([]models.ModerationRule, error) {
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	return s.moderationRepo.ListRules(ctx, chatID)
+}
+
+// CreateRule добавляет правило модерации чата: регулярное выражение и действие
+// при совпадении. Добавлять правила может только владелец.
+func (s *ModerationService) CreateRule(ctx context.Context, chatID int64, pattern, action string) (*models.ModerationRule, error) {
+	if _, err := moderation.CompilePattern(pattern); err != nil {
+		return nil, err
+	}
+	if !moderation.ValidAction(action) {
+		return nil, errors.New("action must be reject, mask or flag")
+	}
+
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	rule := &models.ModerationRule{ChatID: chatID, Pattern: pattern, Action: action}
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.moderationRepo.CreateRule(ctx, rule); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditModerationRuleCreate, TargetType: "moderation_rule", TargetID: rule.ID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// DeleteRule удаляет правило модерации чата
+func (s *ModerationService) DeleteRule(ctx context.Context, chatID, ruleID int64) error {
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		rules, err := s.moderationRepo.ListRules(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		var before *models.ModerationRule
+		for i := range rules {
+			if rules[i].ID == ruleID {
+				before = &rules[i]
+			}
+		}
+		if before == nil {
+			return errors.New("rule not found")
+		}
+
+		if err := s.moderationRepo.DeleteRule(ctx, chatID, ruleID); err != nil {
+			if errors.Is(err, repository.ErrModerationRuleNotFound) {
+				return errors.New("rule not found")
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditModerationRuleDelete, TargetType: "moderation_rule", TargetID: ruleID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, before, nil)
+	})
+}
+
+// flagged получает отметку сообщения и проверяет, что текущий пользователь владеет
+// его чатом. Отметки в чужих чатах неотличимы от отсутствующих. Администратор
+// разбирает отметки в любых чатах.
+func (s *ModerationService) flagged(ctx context.Context, messageID int64) (*models.ModerationFlag, error) {
+	userID, ok := auth.UserID(ctx)
+	admin := auth.IsAdmin(ctx)
+	if !ok && !admin {
+		return nil, errors.New("authentication required")
+	}
+
+	flag, err := s.moderationRepo.GetFlag(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if flag == nil {
+		return nil, errors.New("flag not found")
+	}
+	if admin {
+		return flag, nil
+	}
+
+	chat, err := s.chatRepo.GetByID(ctx, flag.ChatID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil || chat.OwnerID == nil || *chat.OwnerID != userID {
+		return nil, errors.New("flag not found")
+	}
+
+	return flag, nil
+}
+
+// isRejection сообщает, что сообщение отклонила модерация
+func isRejection(err error) bool {
+	return errors.Is(err, errRejected)
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	blobMocks "github.com/GlebMoskalev/chat-golang/internal/blob/mocks"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/moderation"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateMessageModeration(t *testing.T) {
+	chain := moderation.Chain{
+		moderation.NewWordList([]string{"запрет"}, models.ModerationActionReject),
+		moderation.NewWordList([]string{"дурак"}, models.ModerationActionMask),
+		moderation.NewWordList([]string{"казино"}, models.ModerationActionFlag),
+	}
+
+	tests := []struct {
+		name        string
+		text        string
+		expectText  string
+		expectFlag  bool
+		expectErr   string
+		expectEvent string
+	}{
+		{
+			name:        "чистое сообщение",
+			text:        "привет",
+			expectText:  "привет",
+			expectEvent: models.EventMessageCreated,
+		},
+		{
+			name:      "отклонено",
+			text:      "это запрет",
+			expectErr: "message rejected: word: запрет",
+		},
+		{
+			name:        "замаскировано",
+			text:        "сам дурак",
+			expectText:  "сам *****",
+			expectEvent: models.EventMessageCreated,
+		},
+		{
+			name:        "на проверку",
+			text:        "лучшее казино",
+			expectText:  "лучшее казино",
+			expectFlag:  true,
+			expectEvent: models.EventMessageCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChatRepo := mocks.NewMockChatRepository(ctrl)
+			mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+			mockModeration := mocks.NewMockModerationRepository(ctrl)
+
+			expectRoom(mockChatRepo, 1)
+			if tt.expectErr == "" {
+				mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+				mockMessageRepo.EXPECT().
+					Create(gomock.Any(), gomock.Cond(func(m *models.Message) bool { return m.Text == tt.expectText })).
+					DoAndReturn(func(ctx context.Context, m *models.Message) error {
+						m.ID = 10
+						return nil
+					})
+			}
+			if tt.expectFlag {
+				mockModeration.EXPECT().
+					AddFlag(gomock.Any(), gomock.Cond(func(f *models.ModerationFlag) bool {
+						return f.MessageID == 10 && f.ChatID == 1 && f.Reason == "word: казино"
+					})).
+					Return(nil)
+			}
+
+			txManager := newTxManager(ctrl)
+			moderator := NewModerationService(chain, mockModeration, mockChatRepo, mockMessageRepo, txManager, nil, nil, nil, nil)
+			service := NewChatService(mockChatRepo, mockMessageRepo, txManager, newOutbox(ctrl, tt.expectEvent), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), moderator, nil, nil)
+
+			message, err := service.CreateMessage(auth.WithUserID(context.Background(), 42), 1, models.MessageInput{Text: tt.text})
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if message.Text != tt.expectText {
+				t.Errorf("ожидался текст %q, получен %q", tt.expectText, message.Text)
+			}
+		})
+	}
+}
+
+// txKey помечает контекст, созданный внутри транзакции
+type txKey struct{}
+
+// txModerator запоминает, вызывали ли проверку внутри транзакции
+type txModerator struct {
+	inTx bool
+}
+
+func (m *txModerator) Check(ctx context.Context, chatID int64, text string) (moderation.Result, error) {
+	m.inTx = ctx.Value(txKey{}) != nil
+	return moderation.Result{Text: text}, nil
+}
+
+func (m *txModerator) Flag(ctx context.Context, message *models.Message, reasons []string) error {
+	return nil
+}
+
+func TestCreateMessage_ModerationOutsideTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	expectRoom(mockChatRepo, 1)
+	mockChatRepo.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+	mockMessageRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	txManager := mocks.NewMockTxManager(ctrl)
+	txManager.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, txKey{}, true))
+		})
+
+	moderator := &txModerator{}
+	service := NewChatService(mockChatRepo, mockMessageRepo, txManager, newOutbox(ctrl, models.EventMessageCreated), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), newPins(ctrl), newAudit(ctrl, ""), moderator, nil, nil)
+
+	if _, err := service.CreateMessage(auth.WithUserID(context.Background(), 42), 1, models.MessageInput{Text: "привет"}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if moderator.inTx {
+		t.Error("классификатор не должен вызываться внутри транзакции")
+	}
+}
+
+func TestModerationReject(t *testing.T) {
+	owner := int64(42)
+
+	tests := []struct {
+		name      string
+		userID    int64
+		admin     bool
+		setupMock func(*mocks.MockModerationRepository, *mocks.MockMessageRepository)
+		event     string
+		audit     string
+		expectErr string
+	}{
+		{
+			name:   "владелец удаляет сообщение",
+			userID: owner,
+			setupMock: func(mr *mocks.MockModerationRepository, msgr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetFlag(gomock.Any(), int64(5)).Return(&models.ModerationFlag{MessageID: 5, ChatID: 1}, nil)
+				msgr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 1, Text: "казино"}, nil)
+				msgr.EXPECT().Delete(gomock.Any(), int64(5)).Return(nil)
+			},
+			event: models.EventMessageDeleted,
+			audit: models.AuditModerationReject,
+		},
+		{
+			name:  "администратор удаляет сообщение из чата без владельца",
+			admin: true,
+			setupMock: func(mr *mocks.MockModerationRepository, msgr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetFlag(gomock.Any(), int64(5)).Return(&models.ModerationFlag{MessageID: 5, ChatID: 2}, nil)
+				msgr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 2, Text: "казино"}, nil)
+				msgr.EXPECT().Delete(gomock.Any(), int64(5)).Return(nil)
+			},
+			event: models.EventMessageDeleted,
+			audit: models.AuditModerationReject,
+		},
+		{
+			name:   "чужой чат",
+			userID: 7,
+			setupMock: func(mr *mocks.MockModerationRepository, msgr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetFlag(gomock.Any(), int64(5)).Return(&models.ModerationFlag{MessageID: 5, ChatID: 1}, nil)
+			},
+			expectErr: "flag not found",
+		},
+		{
+			name:   "нет в очереди",
+			userID: owner,
+			setupMock: func(mr *mocks.MockModerationRepository, msgr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetFlag(gomock.Any(), int64(5)).Return(nil, nil)
+			},
+			expectErr: "flag not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockModeration := mocks.NewMockModerationRepository(ctrl)
+			mockMessages := mocks.NewMockMessageRepository(ctrl)
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &owner}, nil).AnyTimes()
+			tt.setupMock(mockModeration, mockMessages)
+
+			mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+			mockAttachments.EXPECT().
+				ListByMessage(gomock.Any(), int64(5)).
+				Return([]models.Attachment{{ID: 3, MessageID: 5, StorageKey: "chats/1/file"}}, nil).
+				AnyTimes()
+			// Файл удаляется только вместе с сообщением
+			mockBlobs := blobMocks.NewMockStore(ctrl)
+			if tt.expectErr == "" {
+				mockBlobs.EXPECT().Delete(gomock.Any(), "chats/1/file").Return(nil)
+			}
+
+			service := NewModerationService(nil, mockModeration, mockChats, mockMessages, newTxManager(ctrl), newOutbox(ctrl, tt.event), newAudit(ctrl, tt.audit), mockAttachments, mockBlobs)
+
+			ctx := auth.WithUserID(context.Background(), tt.userID)
+			if tt.admin {
+				ctx = auth.WithAdmin(context.Background())
+			}
+			err := service.Reject(ctx, 5)
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestModerationQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModeration := mocks.NewMockModerationRepository(ctrl)
+	mockModeration.EXPECT().ListFlagsByOwner(gomock.Any(), int64(42)).Return([]models.ModerationFlag{{MessageID: 1, ChatID: 1}}, nil)
+	mockModeration.EXPECT().ListFlags(gomock.Any()).Return([]models.ModerationFlag{{MessageID: 1, ChatID: 1}, {MessageID: 2, ChatID: 2}}, nil)
+
+	service := NewModerationService(nil, mockModeration, mocks.NewMockChatRepository(ctrl), mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, ""), nil, nil)
+
+	flags, err := service.Queue(auth.WithUserID(context.Background(), 42))
+	if err != nil || len(flags) != 1 {
+		t.Errorf("владелец должен видеть только свои чаты, получено %d, %v", len(flags), err)
+	}
+
+	flags, err = service.Queue(auth.WithAdmin(context.Background()))
+	if err != nil || len(flags) != 2 {
+		t.Errorf("администратор должен видеть всю очередь, получено %d, %v", len(flags), err)
+	}
+
+	if _, err := service.Queue(context.Background()); err == nil || err.Error() != "authentication required" {
+		t.Errorf("ожидалась ошибка \"authentication required\", получена %v", err)
+	}
+}
+
+func TestCreateModerationRule(t *testing.T) {
+	owner := int64(42)
+
+	tests := []struct {
+		name      string
+		pattern   string
+		action    string
+		expectErr string
+	}{
+		{name: "валидное правило", pattern: `(?i)казино`, action: models.ModerationActionFlag},
+		{name: "невалидное выражение", pattern: `(`, action: models.ModerationActionFlag, expectErr: "invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{name: "неизвестное действие", pattern: `x`, action: "ban", expectErr: "action must be reject, mask or flag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockModeration := mocks.NewMockModerationRepository(ctrl)
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			action := ""
+			if tt.expectErr == "" {
+				mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &owner}, nil)
+				mockModeration.EXPECT().CreateRule(gomock.Any(), gomock.Any()).Return(nil)
+				action = models.AuditModerationRuleCreate
+			}
+
+			service := NewModerationService(nil, mockModeration, mockChats, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), nil, newAudit(ctrl, action), nil, nil)
+
+			rule, err := service.CreateRule(auth.WithUserID(context.Background(), owner), 1, tt.pattern, tt.action)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				if rule.ChatID != 1 || rule.Pattern != tt.pattern || rule.Action != tt.action {
+					t.Errorf("неожиданное правило: %+v", rule)
+				}
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_pin_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service PinServiceInterface
+
+type PinServiceInterface interface {
+	PinMessage(ctx context.Context, chatID, messageID int64) (*models.ChatPin, error)
+	UnpinMessage(ctx context.Context, chatID, messageID int64) error
+	ListPins(ctx context.Context, chatID int64) ([]models.ChatPin, error)
+}
+
+type PinService struct {
+	pinRepo     repository.PinRepository
+	messageRepo repository.MessageRepository
+	memberRepo  repository.ChatMemberRepository
+	chatRepo    repository.ChatRepository
+	txManager   repository.TxManager
+	maxPins     int64
+}
+
+func NewPinService(pinRepo repository.PinRepository, messageRepo repository.MessageRepository, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, txManager repository.TxManager, maxPins int64) *PinService {
+	return &PinService{
+		pinRepo:     pinRepo,
+		messageRepo: messageRepo,
+		memberRepo:  memberRepo,
+		chatRepo:    chatRepo,
+		txManager:   txManager,
+		maxPins:     maxPins,
+	}
+}
+
+// PinMessage закрепляет сообщение чата. Закреплять могут только участники,
+// в чате не больше maxPins закреплённых сообщений.
+func (s *PinService) PinMessage(ctx context.Context, chatID, messageID int64) (*models.ChatPin, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	var pin *models.ChatPin
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, userID); err != nil {
+			return err
+		}
+
+		message, err := s.messageRepo.GetByID(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		if message == nil || message.ChatID != chatID {
+			return errors.New("message not found")
+		}
+
+		count, err := s.pinRepo.CountByChat(ctx, chatID)
+		if err != nil {
+			return err
+		}
+		if count >= s.maxPins {
+			return errors.New("pin limit reached")
+		}
+
+		pin = &models.ChatPin{ChatID: chatID, MessageID: messageID, PinnedBy: &userID}
+		if err := s.pinRepo.Add(ctx, pin); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrAlreadyPinned):
+				return errors.New("message already pinned")
+			case errors.Is(err, repository.ErrMessageNotFound):
+				return errors.New("message not found")
+			}
+			return err
+		}
+		pin.Message = message
+		pin.Message.Pinned = true
+		renderHTML(pin.Message)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pin, nil
+}
+
+// UnpinMessage открепляет сообщение. Открепить может любой участник чата.
+func (s *PinService) UnpinMessage(ctx context.Context, chatID, messageID int64) error {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, userID); err != nil {
+			return err
+		}
+
+		err := s.pinRepo.Remove(ctx, chatID, messageID)
+		if errors.Is(err, repository.ErrPinNotFound) {
+			return errors.New("pin not found")
+		}
+		return err
+	})
+}
+
+// ListPins получает закреплённые сообщения чата, последние закреплённые первыми.
+// Закрепы личного чата видят только его участники.
+func (s *PinService) ListPins(ctx context.Context, chatID int64) ([]models.ChatPin, error) {
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	pins, err := s.pinRepo.ListByChat(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pins {
+		if pins[i].Message != nil {
+			renderHTML(pins[i].Message)
+			pins[i].Message.Pinned = true
+		}
+	}
+
+	return pins, nil
+}
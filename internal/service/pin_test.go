@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPinMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(*mocks.MockPinRepository, *mocks.MockMessageRepository, *mocks.MockChatMemberRepository)
+		expectErr string
+	}{
+		{
+			name: "успешное закрепление",
+			setupMock: func(pr *mocks.MockPinRepository, mr *mocks.MockMessageRepository, cm *mocks.MockChatMemberRepository) {
+				cm.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+				mr.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Message{ID: 10, ChatID: 1, Text: "важно"}, nil)
+				pr.EXPECT().CountByChat(gomock.Any(), int64(1)).Return(int64(2), nil)
+				pr.EXPECT().
+					Add(gomock.Any(), gomock.Cond(func(pin *models.ChatPin) bool {
+						return pin.ChatID == 1 && pin.MessageID == 10 && pin.PinnedBy != nil && *pin.PinnedBy == 42
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name: "лимит закрепов",
+			setupMock: func(pr *mocks.MockPinRepository, mr *mocks.MockMessageRepository, cm *mocks.MockChatMemberRepository) {
+				cm.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+				mr.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Message{ID: 10, ChatID: 1}, nil)
+				pr.EXPECT().CountByChat(gomock.Any(), int64(1)).Return(int64(3), nil)
+			},
+			expectErr: "pin limit reached",
+		},
+		{
+			name: "уже закреплено",
+			setupMock: func(pr *mocks.MockPinRepository, mr *mocks.MockMessageRepository, cm *mocks.MockChatMemberRepository) {
+				cm.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+				mr.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Message{ID: 10, ChatID: 1}, nil)
+				pr.EXPECT().CountByChat(gomock.Any(), int64(1)).Return(int64(1), nil)
+				pr.EXPECT().Add(gomock.Any(), gomock.Any()).Return(repository.ErrAlreadyPinned)
+			},
+			expectErr: "message already pinned",
+		},
+		{
+			name: "сообщение из другого чата",
+			setupMock: func(pr *mocks.MockPinRepository, mr *mocks.MockMessageRepository, cm *mocks.MockChatMemberRepository) {
+				cm.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+				mr.EXPECT().GetByID(gomock.Any(), int64(10)).Return(&models.Message{ID: 10, ChatID: 2}, nil)
+			},
+			expectErr: "message not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockPins := mocks.NewMockPinRepository(ctrl)
+			mockMessages := mocks.NewMockMessageRepository(ctrl)
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			tt.setupMock(mockPins, mockMessages, mockMembers)
+
+			service := NewPinService(mockPins, mockMessages, mockMembers, mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), 3)
+
+			pin, err := service.PinMessage(auth.WithUserID(context.Background(), 42), 1, 10)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				if pin.Message == nil || !pin.Message.Pinned || pin.Message.HTML == "" {
+					t.Errorf("в ответе должно быть отрендеренное закреплённое сообщение: %+v", pin.Message)
+				}
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestUnpinMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPins := mocks.NewMockPinRepository(ctrl)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+	mockPins.EXPECT().Remove(gomock.Any(), int64(1), int64(10)).Return(repository.ErrPinNotFound)
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(7)).Return(false, nil)
+	mockChats.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+
+	service := NewPinService(mockPins, mocks.NewMockMessageRepository(ctrl), mockMembers, mockChats, newTxManager(ctrl), 3)
+
+	if err := service.UnpinMessage(auth.WithUserID(context.Background(), 42), 1, 10); err == nil || err.Error() != "pin not found" {
+		t.Errorf("ожидалась ошибка pin not found, получена %v", err)
+	}
+	if err := service.UnpinMessage(auth.WithUserID(context.Background(), 7), 1, 10); err == nil || err.Error() != "forbidden" {
+		t.Errorf("ожидалась ошибка forbidden, получена %v", err)
+	}
+	if err := service.UnpinMessage(context.Background(), 1, 10); err == nil || err.Error() != "authentication required" {
+		t.Errorf("ожидалась ошибка authentication required, получена %v", err)
+	}
+}
+
+func TestGetChatWithMessages_Pinned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChatRepo := mocks.NewMockChatRepository(ctrl)
+	mockMessageRepo := mocks.NewMockMessageRepository(ctrl)
+	mockPins := mocks.NewMockPinRepository(ctrl)
+
+	mockChatRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Title: "Тест"}, nil)
+	mockMessageRepo.EXPECT().GetByChatID(gomock.Any(), int64(1), gomock.Any(), 20).Return([]models.Message{
+		{ID: 2, ChatID: 1, Text: "второе"},
+		{ID: 1, ChatID: 1, Text: "первое"},
+	}, nil)
+	mockPins.EXPECT().ListByChat(gomock.Any(), int64(1)).Return([]models.ChatPin{{ChatID: 1, MessageID: 1}}, nil)
+
+	service := NewChatService(mockChatRepo, mockMessageRepo, newTxManager(ctrl), newOutbox(ctrl, ""), newLinkPreviews(ctrl), newMembers(ctrl), newMentions(ctrl), mockPins, newAudit(ctrl, ""), nil, nil, nil)
+
+	result, err := service.GetChatWithMessages(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Messages[0].Pinned || !result.Messages[1].Pinned {
+		t.Errorf("закреплено должно быть только первое сообщение: %+v", result.Messages)
+	}
+}
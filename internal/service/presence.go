@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/presence"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_presence_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service PresenceServiceInterface
+//go:generate mockgen -destination=mocks/mock_event_publisher.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service EventPublisher
+
+// EventPublisher рассылает эфемерные события подписчикам чата (см. outbox.Bus)
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+type PresenceServiceInterface interface {
+	Typing(ctx context.Context, chatID int64) error
+	Heartbeat(ctx context.Context, status string) error
+	GetPresence(ctx context.Context, chatID int64) (*models.ChatPresence, error)
+}
+
+type PresenceService struct {
+	tracker    *presence.Tracker
+	memberRepo repository.ChatMemberRepository
+	chatRepo   repository.ChatRepository
+	publisher  EventPublisher
+}
+
+func NewPresenceService(tracker *presence.Tracker, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, publisher EventPublisher) *PresenceService {
+	return &PresenceService{
+		tracker:    tracker,
+		memberRepo: memberRepo,
+		chatRepo:   chatRepo,
+		publisher:  publisher,
+	}
+}
+
+// Typing отмечает, что текущий пользователь печатает в чате. Клиент повторяет сигнал,
+// пока пользователь печатает; без повторов индикатор гаснет сам.
+func (s *PresenceService) Typing(ctx context.Context, chatID int64) error {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+	if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, userID); err != nil {
+		return err
+	}
+
+	expiresAt := s.tracker.StartTyping(chatID, userID)
+	s.publish(ctx, models.EventTyping, chatID, map[string]any{
+		"chat_id":    chatID,
+		"user_id":    userID,
+		"expires_at": expiresAt,
+	})
+	return nil
+}
+
+// Heartbeat продлевает присутствие текущего пользователя. Если статус изменился,
+// об этом узнают все чаты пользователя.
+func (s *PresenceService) Heartbeat(ctx context.Context, status string) error {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return errors.New("authentication required")
+	}
+	if status == "" {
+		status = string(presence.Online)
+	}
+	if status != string(presence.Online) && status != string(presence.Away) {
+		return errors.New("status must be online or away")
+	}
+
+	if prev := s.tracker.Heartbeat(userID, presence.Status(status)); prev != presence.Status(status) {
+		s.broadcastPresence(ctx, userID, presence.Status(status))
+	}
+	return nil
+}
+
+// GetPresence возвращает статусы участников чата и список печатающих. Присутствие видят
+// только участники чата.
+func (s *PresenceService) GetPresence(ctx context.Context, chatID int64) (*models.ChatPresence, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+	if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.ListMembers(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ChatPresence{
+		ChatID:  chatID,
+		Members: make([]models.MemberPresence, 0, len(members)),
+		Typing:  s.tracker.Typing(chatID),
+	}
+	for _, member := range members {
+		result.Members = append(result.Members, models.MemberPresence{
+			UserID:   member.ID,
+			Username: member.Username,
+			Status:   string(s.tracker.Status(member.ID)),
+		})
+	}
+
+	return result, nil
+}
+
+// Publish принимает события outbox как локальный приёмник (см. outbox.Dispatcher.AddLocalSink):
+// отправленное сообщение гасит индикатор набора автора в этом чате, не дожидаясь TTL.
+// Индикаторы хранятся в памяти каждой реплики, поэтому приёмник тоже локальный.
+func (s *PresenceService) Publish(ctx context.Context, event models.OutboxEvent) error {
+	if event.EventType != models.EventMessageCreated {
+		return nil
+	}
+
+	var message struct {
+		AuthorID *int64 `json:"author_id"`
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &message); err != nil {
+		// Повтор не исправит событие, а индикатор погаснет сам по TTL
+		log.Printf("presence: decode event %d: %v", event.ID, err)
+		return nil
+	}
+	if message.AuthorID != nil {
+		s.tracker.StopTyping(event.ChatID, *message.AuthorID)
+	}
+	return nil
+}
+
+// Run периодически чистит истёкшие данные и сообщает об ушедших в offline, пока не отменён ctx
+func (s *PresenceService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, change := range s.tracker.Sweep() {
+				s.broadcastPresence(ctx, change.UserID, change.Status)
+			}
+		}
+	}
+}
+
+// broadcastPresence рассылает смену статуса в каждый чат пользователя
+func (s *PresenceService) broadcastPresence(ctx context.Context, userID int64, status presence.Status) {
+	chatIDs, err := s.memberRepo.ListChatIDs(ctx, userID)
+	if err != nil {
+		log.Printf("presence: list chats of user %d: %v", userID, err)
+		return
+	}
+
+	for _, chatID := range chatIDs {
+		s.publish(ctx, models.EventPresence, chatID, map[string]any{
+			"user_id": userID,
+			"status":  status,
+		})
+	}
+}
+
+// publish отправляет эфемерное событие. Ошибки только логируются: потерянный
+// индикатор набора не стоит того, чтобы отвечать клиенту ошибкой.
+func (s *PresenceService) publish(ctx context.Context, eventType string, chatID int64, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("presence: marshal %s: %v", eventType, err)
+		return
+	}
+
+	event := models.OutboxEvent{
+		EventType: eventType,
+		ChatID:    chatID,
+		Payload:   string(payload),
+		CreatedAt: time.Now(),
+	}
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("presence: publish %s: %v", eventType, err)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/presence"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	serviceMocks "github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTyping(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		setupMock func(*mocks.MockChatMemberRepository, *mocks.MockChatRepository, *serviceMocks.MockEventPublisher)
+		expectErr string
+	}{
+		{
+			name: "участник печатает",
+			ctx:  auth.WithUserID(context.Background(), 42),
+			setupMock: func(mr *mocks.MockChatMemberRepository, cr *mocks.MockChatRepository, p *serviceMocks.MockEventPublisher) {
+				mr.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+				p.EXPECT().
+					Publish(gomock.Any(), gomock.Cond(func(event models.OutboxEvent) bool {
+						return event.EventType == models.EventTyping && event.ChatID == 1 &&
+							strings.Contains(event.Payload, `"user_id":42`)
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name: "не участник",
+			ctx:  auth.WithUserID(context.Background(), 42),
+			setupMock: func(mr *mocks.MockChatMemberRepository, cr *mocks.MockChatRepository, p *serviceMocks.MockEventPublisher) {
+				mr.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(false, nil)
+				cr.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name: "чат не найден",
+			ctx:  auth.WithUserID(context.Background(), 42),
+			setupMock: func(mr *mocks.MockChatMemberRepository, cr *mocks.MockChatRepository, p *serviceMocks.MockEventPublisher) {
+				mr.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(false, nil)
+				cr.EXPECT().Exists(gomock.Any(), int64(1)).Return(false, nil)
+			},
+			expectErr: "chat not found",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockPublisher := serviceMocks.NewMockEventPublisher(ctrl)
+			if tt.setupMock != nil {
+				tt.setupMock(mockMembers, mockChats, mockPublisher)
+			}
+
+			tracker := presence.NewTracker(presence.DefaultTypingTTL, presence.DefaultTimeout)
+			service := NewPresenceService(tracker, mockMembers, mockChats, mockPublisher)
+
+			err := service.Typing(tt.ctx, 1)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				if typing := tracker.Typing(1); len(typing) != 1 || typing[0] != 42 {
+					t.Errorf("ожидался печатающий пользователь 42, получено %v", typing)
+				}
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockPublisher := serviceMocks.NewMockEventPublisher(ctrl)
+
+	// Статус рассылается только при смене: online, затем away
+	mockMembers.EXPECT().
+		ListChatIDs(gomock.Any(), int64(42)).
+		Return([]int64{1, 2}, nil).
+		Times(2)
+	mockPublisher.EXPECT().
+		Publish(gomock.Any(), gomock.Cond(func(event models.OutboxEvent) bool {
+			return event.EventType == models.EventPresence && strings.Contains(event.Payload, `"status":"online"`)
+		})).
+		Return(nil).
+		Times(2)
+	mockPublisher.EXPECT().
+		Publish(gomock.Any(), gomock.Cond(func(event models.OutboxEvent) bool {
+			return event.EventType == models.EventPresence && strings.Contains(event.Payload, `"status":"away"`)
+		})).
+		Return(nil).
+		Times(2)
+
+	tracker := presence.NewTracker(presence.DefaultTypingTTL, presence.DefaultTimeout)
+	service := NewPresenceService(tracker, mockMembers, mocks.NewMockChatRepository(ctrl), mockPublisher)
+	ctx := auth.WithUserID(context.Background(), 42)
+
+	for _, status := range []string{"", "online", "away"} {
+		if err := service.Heartbeat(ctx, status); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+	}
+	if tracker.Status(42) != presence.Away {
+		t.Errorf("ожидался статус away, получен %s", tracker.Status(42))
+	}
+
+	if err := service.Heartbeat(ctx, "busy"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного статуса")
+	}
+}
+
+func TestGetPresence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(2)).Return(true, nil)
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(3)).Return(false, nil)
+	mockChats.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+	mockMembers.EXPECT().ListMembers(gomock.Any(), int64(1)).Return([]models.User{
+		{ID: 1, Username: "alice"},
+		{ID: 2, Username: "bob"},
+	}, nil)
+
+	tracker := presence.NewTracker(time.Minute, presence.DefaultTimeout)
+	tracker.Heartbeat(1, presence.Online)
+	tracker.StartTyping(1, 1)
+
+	service := NewPresenceService(tracker, mockMembers, mockChats, serviceMocks.NewMockEventPublisher(ctrl))
+
+	result, err := service.GetPresence(auth.WithUserID(context.Background(), 2), 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := []models.MemberPresence{
+		{UserID: 1, Username: "alice", Status: "online"},
+		{UserID: 2, Username: "bob", Status: "offline"},
+	}
+	if len(result.Members) != 2 || result.Members[0] != expected[0] || result.Members[1] != expected[1] {
+		t.Errorf("ожидались статусы %v, получены %v", expected, result.Members)
+	}
+	if len(result.Typing) != 1 || result.Typing[0] != 1 {
+		t.Errorf("ожидался печатающий пользователь 1, получено %v", result.Typing)
+	}
+
+	// Статусы участников не видны ни постороннему, ни анонимному клиенту
+	if _, err := service.GetPresence(auth.WithUserID(context.Background(), 3), 1); err == nil || err.Error() != "forbidden" {
+		t.Errorf("посторонний: ожидалась ошибка 'forbidden', получена %v", err)
+	}
+	if _, err := service.GetPresence(context.Background(), 1); err == nil || err.Error() != "authentication required" {
+		t.Errorf("без пользователя: ожидалась ошибка 'authentication required', получена %v", err)
+	}
+}
+
+func TestPresenceStopsTypingOnMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tracker := presence.NewTracker(time.Minute, presence.DefaultTimeout)
+	tracker.StartTyping(1, 1)
+	tracker.StartTyping(1, 2)
+	tracker.StartTyping(2, 1)
+
+	service := NewPresenceService(tracker, mocks.NewMockChatMemberRepository(ctrl), mocks.NewMockChatRepository(ctrl), serviceMocks.NewMockEventPublisher(ctrl))
+
+	events := []models.OutboxEvent{
+		{ID: 1, EventType: models.EventMessageCreated, ChatID: 1, Payload: `{"id":10,"chat_id":1,"author_id":1}`},
+		// Сообщения без автора и другие события индикаторы не трогают
+		{ID: 2, EventType: models.EventMessageCreated, ChatID: 1, Payload: `{"id":11,"chat_id":1}`},
+		{ID: 3, EventType: models.EventMessageDeleted, ChatID: 1, Payload: `{"id":10,"chat_id":1}`},
+	}
+	for _, event := range events {
+		if err := service.Publish(context.Background(), event); err != nil {
+			t.Fatalf("неожиданная ошибка: %v", err)
+		}
+	}
+
+	if typing := tracker.Typing(1); len(typing) != 1 || typing[0] != 2 {
+		t.Errorf("в чате 1 должен печатать только пользователь 2, получено %v", typing)
+	}
+	if typing := tracker.Typing(2); len(typing) != 1 || typing[0] != 1 {
+		t.Errorf("индикатор в другом чате не должен гаснуть, получено %v", typing)
+	}
+}
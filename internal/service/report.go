@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_report_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ReportServiceInterface
+
+// MaxReportComment — наибольшая длина комментария к жалобе в символах
+const MaxReportComment = 1000
+
+type ReportServiceInterface interface {
+	ReportMessage(ctx context.Context, chatID, messageID int64, reason, comment string) (*models.MessageReport, error)
+	ListReports(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error)
+	ResolveReport(ctx context.Context, id int64, status, resolution string, deleteMessage bool) (*models.MessageReport, error)
+}
+
+type ReportService struct {
+	reportRepo     repository.ReportRepository
+	messageRepo    repository.MessageRepository
+	memberRepo     repository.ChatMemberRepository
+	chatRepo       repository.ChatRepository
+	txManager      repository.TxManager
+	outboxRepo     repository.OutboxRepository
+	auditRepo      repository.AuditRepository
+	attachmentRepo repository.AttachmentRepository
+	blobs          blob.Store
+	now            func() time.Time
+}
+
+func NewReportService(reportRepo repository.ReportRepository, messageRepo repository.MessageRepository, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, txManager repository.TxManager, outboxRepo repository.OutboxRepository, auditRepo repository.AuditRepository, attachmentRepo repository.AttachmentRepository, blobs blob.Store) *ReportService {
+	return &ReportService{
+		reportRepo:     reportRepo,
+		messageRepo:    messageRepo,
+		memberRepo:     memberRepo,
+		chatRepo:       chatRepo,
+		txManager:      txManager,
+		outboxRepo:     outboxRepo,
+		auditRepo:      auditRepo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		now:            time.Now,
+	}
+}
+
+// ReportMessage отправляет жалобу текущего пользователя на сообщение чата. На одно
+// сообщение пользователь может пожаловаться один раз, на своё — ни разу. Сообщения
+// чужого личного чата для него не существуют. Снимок текста сохраняется в жалобе для
+// администратора, но жалобщику не возвращается.
+func (s *ReportService) ReportMessage(ctx context.Context, chatID, messageID int64, reason, comment string) (*models.MessageReport, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+	switch reason {
+	case models.ReportReasonSpam, models.ReportReasonAbuse, models.ReportReasonHarassment, models.ReportReasonIllegal, models.ReportReasonOther:
+	default:
+		return nil, errors.New("reason must be spam, abuse, harassment, illegal or other")
+	}
+	if utf8.RuneCountInString(comment) > MaxReportComment {
+		return nil, errors.New("comment must be at most 1000 characters")
+	}
+
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		if errors.Is(err, errChatNotFound) || errors.Is(err, errForbidden) {
+			return nil, errors.New("message not found")
+		}
+		return nil, err
+	}
+
+	message, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || message.ChatID != chatID {
+		return nil, errors.New("message not found")
+	}
+	if message.AuthorID != nil && *message.AuthorID == userID {
+		return nil, errors.New("cannot report own message")
+	}
+
+	report := &models.MessageReport{
+		MessageID:  &message.ID,
+		ChatID:     chatID,
+		ReporterID: userID,
+		AuthorID:   message.AuthorID,
+		Reason:     reason,
+		Comment:    comment,
+		Text:       message.Text,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyReported):
+			return nil, errors.New("message already reported")
+		case errors.Is(err, repository.ErrMessageNotFound):
+			return nil, errors.New("message not found")
+		}
+		return nil, err
+	}
+
+	created := *report
+	created.Text = ""
+	return &created, nil
+}
+
+// ListReports получает жалобы для администратора: со статусом status (пустой — все)
+// и ID больше afterID, старые первыми
+func (s *ReportService) ListReports(ctx context.Context, status string, afterID int64, limit int) ([]models.MessageReport, error) {
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		return nil, errors.New("status must be open, resolved or dismissed")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+
+	return s.reportRepo.List(ctx, status, afterID, limit)
+}
+
+// ResolveReport закрывает открытую жалобу: resolved — нарушение подтверждено,
+// dismissed — жалоба отклонена. С deleteMessage подтверждённое сообщение удаляется
+// из чата с событием message.deleted, файлы его вложений — из хранилища. Решение
+// попадает в журнал аудита.
+func (s *ReportService) ResolveReport(ctx context.Context, id int64, status, resolution string, deleteMessage bool) (*models.MessageReport, error) {
+	if status != models.ReportStatusResolved && status != models.ReportStatusDismissed {
+		return nil, errors.New("status must be resolved or dismissed")
+	}
+	if deleteMessage && status != models.ReportStatusResolved {
+		return nil, errors.New("delete_message requires status resolved")
+	}
+	if utf8.RuneCountInString(resolution) > MaxReportComment {
+		return nil, errors.New("resolution must be at most 1000 characters")
+	}
+
+	var report *models.MessageReport
+	var attachments []models.Attachment
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.reportRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return errors.New("report not found")
+		}
+
+		closedAt := s.now()
+		if err := s.reportRepo.Close(ctx, id, status, resolution, closedAt); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrReportNotFound):
+				return errors.New("report not found")
+			case errors.Is(err, repository.ErrReportClosed):
+				return errors.New("report already closed")
+			}
+			return err
+		}
+
+		closed := *before
+		closed.Status = status
+		closed.Resolution = resolution
+		closed.ResolvedAt = &closedAt
+		report = &closed
+
+		entry := models.AuditEntry{Action: models.AuditReportResolve, TargetType: "report", TargetID: id, ChatID: &report.ChatID}
+		if err := recordAudit(ctx, s.auditRepo, entry, before, map[string]any{
+			"status": status, "resolution": resolution, "delete_message": deleteMessage,
+		}); err != nil {
+			return err
+		}
+
+		if !deleteMessage || report.MessageID == nil {
+			return nil
+		}
+		messageID := *report.MessageID
+		if attachments, err = s.attachmentRepo.ListByMessage(ctx, messageID); err != nil {
+			return err
+		}
+		if err := s.messageRepo.Delete(ctx, messageID); err != nil {
+			// Сообщение уже удалили другим путём — жалоба всё равно закрыта
+			if errors.Is(err, repository.ErrMessageNotFound) {
+				return nil
+			}
+			return err
+		}
+		report.MessageID = nil
+
+		data := map[string]int64{"id": messageID, "chat_id": report.ChatID}
+		return recordEvent(ctx, s.outboxRepo, models.EventMessageDeleted, report.ChatID, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleteBlobs(ctx, s.blobs, attachments)
+	return report, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	blobMocks "github.com/GlebMoskalev/chat-golang/internal/blob/mocks"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestReportMessage(t *testing.T) {
+	author := int64(7)
+
+	tests := []struct {
+		name      string
+		reason    string
+		userID    int64
+		setupMock func(*mocks.MockReportRepository, *mocks.MockMessageRepository)
+		expectErr string
+	}{
+		{
+			name:   "успешная жалоба",
+			reason: models.ReportReasonSpam,
+			userID: 42,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 1, AuthorID: &author, Text: "купи"}, nil)
+				rr.EXPECT().
+					Create(gomock.Any(), gomock.Cond(func(r *models.MessageReport) bool {
+						return *r.MessageID == 5 && r.ChatID == 1 && r.ReporterID == 42 && *r.AuthorID == author &&
+							r.Reason == models.ReportReasonSpam && r.Text == "купи"
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name:   "повторная жалоба",
+			reason: models.ReportReasonSpam,
+			userID: 42,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 1, AuthorID: &author}, nil)
+				rr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrAlreadyReported)
+			},
+			expectErr: "message already reported",
+		},
+		{
+			name:   "сообщение из другого чата",
+			reason: models.ReportReasonAbuse,
+			userID: 42,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 2, AuthorID: &author}, nil)
+			},
+			expectErr: "message not found",
+		},
+		{
+			name:   "своё сообщение",
+			reason: models.ReportReasonAbuse,
+			userID: author,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				mr.EXPECT().GetByID(gomock.Any(), int64(5)).Return(&models.Message{ID: 5, ChatID: 1, AuthorID: &author}, nil)
+			},
+			expectErr: "cannot report own message",
+		},
+		{
+			name:      "неизвестная причина",
+			reason:    "boring",
+			userID:    42,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {},
+			expectErr: "reason must be spam, abuse, harassment, illegal or other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReports := mocks.NewMockReportRepository(ctrl)
+			mockMessages := mocks.NewMockMessageRepository(ctrl)
+			tt.setupMock(mockReports, mockMessages)
+
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil).AnyTimes()
+
+			service := NewReportService(mockReports, mockMessages, mocks.NewMockChatMemberRepository(ctrl), mockChats, newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, ""), nil, nil)
+
+			report, err := service.ReportMessage(auth.WithUserID(context.Background(), tt.userID), 1, 5, tt.reason, "")
+			if tt.expectErr == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+			if report != nil && report.Text != "" {
+				t.Errorf("жалобщику вернулся текст сообщения %q", report.Text)
+			}
+		})
+	}
+}
+
+func TestReportMessageForeignDM(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, nil)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(false, nil)
+
+	// Ни сообщение, ни жалоба не трогаются: посторонний не узнаёт даже, есть ли такое сообщение
+	service := NewReportService(mocks.NewMockReportRepository(ctrl), mocks.NewMockMessageRepository(ctrl), mockMembers, mockChats,
+		newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, ""), nil, nil)
+
+	_, err := service.ReportMessage(auth.WithUserID(context.Background(), 42), 1, 5, models.ReportReasonSpam, "")
+	if err == nil || err.Error() != "message not found" {
+		t.Errorf("ожидалась ошибка \"message not found\", получена %v", err)
+	}
+}
+
+func TestResolveReport(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	messageID := int64(5)
+
+	tests := []struct {
+		name          string
+		status        string
+		deleteMessage bool
+		setupMock     func(*mocks.MockReportRepository, *mocks.MockMessageRepository)
+		expectEvent   string
+		expectAudit   bool
+		expectErr     string
+	}{
+		{
+			name:          "подтверждение с удалением сообщения",
+			status:        models.ReportStatusResolved,
+			deleteMessage: true,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				rr.EXPECT().Close(gomock.Any(), int64(3), models.ReportStatusResolved, "готово", now).Return(nil)
+				rr.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&models.MessageReport{ID: 3, ChatID: 1, MessageID: &messageID}, nil)
+				mr.EXPECT().Delete(gomock.Any(), messageID).Return(nil)
+			},
+			expectEvent: models.EventMessageDeleted,
+			expectAudit: true,
+		},
+		{
+			name:   "отклонение",
+			status: models.ReportStatusDismissed,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				rr.EXPECT().Close(gomock.Any(), int64(3), models.ReportStatusDismissed, "готово", now).Return(nil)
+				rr.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&models.MessageReport{ID: 3, ChatID: 1, MessageID: &messageID}, nil)
+			},
+			expectAudit: true,
+		},
+		{
+			name:   "уже закрыта",
+			status: models.ReportStatusDismissed,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {
+				rr.EXPECT().GetByID(gomock.Any(), int64(3)).Return(&models.MessageReport{ID: 3, ChatID: 1, MessageID: &messageID, Status: models.ReportStatusResolved}, nil)
+				rr.EXPECT().Close(gomock.Any(), int64(3), models.ReportStatusDismissed, "готово", now).Return(repository.ErrReportClosed)
+			},
+			expectErr: "report already closed",
+		},
+		{
+			name:          "удаление при отклонении",
+			status:        models.ReportStatusDismissed,
+			deleteMessage: true,
+			setupMock:     func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {},
+			expectErr:     "delete_message requires status resolved",
+		},
+		{
+			name:      "неизвестный статус",
+			status:    models.ReportStatusOpen,
+			setupMock: func(rr *mocks.MockReportRepository, mr *mocks.MockMessageRepository) {},
+			expectErr: "status must be resolved or dismissed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReports := mocks.NewMockReportRepository(ctrl)
+			mockMessages := mocks.NewMockMessageRepository(ctrl)
+			tt.setupMock(mockReports, mockMessages)
+
+			action := ""
+			if tt.expectAudit {
+				action = models.AuditReportResolve
+			}
+			mockAttachments := mocks.NewMockAttachmentRepository(ctrl)
+			mockAttachments.EXPECT().
+				ListByMessage(gomock.Any(), messageID).
+				Return([]models.Attachment{{ID: 3, MessageID: messageID, StorageKey: "chats/1/file"}}, nil).
+				AnyTimes()
+			mockBlobs := blobMocks.NewMockStore(ctrl)
+			if tt.deleteMessage && tt.expectErr == "" {
+				mockBlobs.EXPECT().Delete(gomock.Any(), "chats/1/file").Return(nil)
+			}
+
+			service := NewReportService(mockReports, mockMessages, nil, nil, newTxManager(ctrl), newOutbox(ctrl, tt.expectEvent), newAudit(ctrl, action), mockAttachments, mockBlobs)
+			service.now = func() time.Time { return now }
+
+			report, err := service.ResolveReport(context.Background(), 3, tt.status, "готово", tt.deleteMessage)
+			if tt.expectErr != "" {
+				if err == nil || err.Error() != tt.expectErr {
+					t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if report.Status != tt.status || report.ResolvedAt == nil || !report.ResolvedAt.Equal(now) {
+				t.Errorf("жалоба должна быть закрыта со статусом %s в %v, получено %s/%v", tt.status, now, report.Status, report.ResolvedAt)
+			}
+			if tt.deleteMessage && report.MessageID != nil {
+				t.Errorf("после удаления сообщения жалоба не должна ссылаться на него")
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_retention_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service RetentionServiceInterface
+
+// Метрики очистки, доступны через expvar
+var (
+	retentionRuns   = expvar.NewInt("retention_runs")
+	retentionPurged = expvar.NewInt("retention_messages_purged")
+	retentionErrors = expvar.NewInt("retention_errors")
+)
+
+type RetentionServiceInterface interface {
+	GetPolicy(ctx context.Context, chatID int64) (*models.RetentionPolicy, error)
+	SetPolicy(ctx context.Context, chatID int64, policy models.RetentionPolicy) (*models.RetentionPolicy, error)
+	DeletePolicy(ctx context.Context, chatID int64) error
+	Report(ctx context.Context) (*models.RetentionReport, error)
+}
+
+type RetentionService struct {
+	retentionRepo repository.RetentionRepository
+	chatRepo      repository.ChatRepository
+	memberRepo    repository.ChatMemberRepository
+	messageRepo   repository.MessageRepository
+	txManager     repository.TxManager
+	outboxRepo    repository.OutboxRepository
+	auditRepo     repository.AuditRepository
+	blobs         blob.Store
+	global        models.RetentionPolicy
+	batchSize     int
+	now           func() time.Time
+}
+
+// NewRetentionService создаёт сервис политик хранения. global действует для чатов
+// без своей политики и для незаданных полей; batchSize — сколько сообщений удаляется за раз.
+// Файлы вложений удалённых сообщений убираются из blobs.
+func NewRetentionService(retentionRepo repository.RetentionRepository, chatRepo repository.ChatRepository, memberRepo repository.ChatMemberRepository, messageRepo repository.MessageRepository, txManager repository.TxManager, outboxRepo repository.OutboxRepository, auditRepo repository.AuditRepository, blobs blob.Store, global models.RetentionPolicy, batchSize int) *RetentionService {
+	return &RetentionService{
+		retentionRepo: retentionRepo,
+		chatRepo:      chatRepo,
+		memberRepo:    memberRepo,
+		messageRepo:   messageRepo,
+		txManager:     txManager,
+		outboxRepo:    outboxRepo,
+		auditRepo:     auditRepo,
+		blobs:         blobs,
+		global:        global,
+		batchSize:     batchSize,
+		now:           time.Now,
+	}
+}
+
+// GetPolicy получает действующую политику чата с подставленными глобальными значениями.
+// Политику видят те же, кому видны сообщения чата.
+func (s *RetentionService) GetPolicy(ctx context.Context, chatID int64) (*models.RetentionPolicy, error) {
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	own, err := s.retentionRepo.Get(ctx, chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.effective(chatID, own)
+	return &policy, nil
+}
+
+// SetPolicy задаёт политику хранения чата. Менять её может только владелец чата,
+// прежняя и новая политика попадают в журнал аудита.
+func (s *RetentionService) SetPolicy(ctx context.Context, chatID int64, policy models.RetentionPolicy) (*models.RetentionPolicy, error) {
+	if policy.MaxAgeSeconds != nil && *policy.MaxAgeSeconds < 0 {
+		return nil, errors.New("max_age_seconds must not be negative")
+	}
+	if policy.MaxMessages != nil && *policy.MaxMessages < 0 {
+		return nil, errors.New("max_messages must not be negative")
+	}
+
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	policy.ChatID = chatID
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.retentionRepo.Get(ctx, chatID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.retentionRepo.Set(ctx, &policy); err != nil {
+			if errors.Is(err, repository.ErrChatNotFound) {
+				return errChatNotFound
+			}
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditRetentionUpdate, TargetType: "retention_policy", TargetID: chatID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, policySnapshot(before), policy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	effective := s.effective(chatID, &policy)
+	return &effective, nil
+}
+
+// DeletePolicy удаляет политику чата, после чего для него действует глобальная
+func (s *RetentionService) DeletePolicy(ctx context.Context, chatID int64) error {
+	if _, err := authorizeOwner(ctx, s.chatRepo, chatID); err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.retentionRepo.Get(ctx, chatID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.retentionRepo.Delete(ctx, chatID); err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditRetentionDelete, TargetType: "retention_policy", TargetID: chatID, ChatID: &chatID}
+		return recordAudit(ctx, s.auditRepo, entry, policySnapshot(before), nil)
+	})
+}
+
+// policySnapshot — снимок своей политики чата для журнала аудита, nil если её не было
+func policySnapshot(policy *models.RetentionPolicy) any {
+	if policy == nil {
+		return nil
+	}
+	return policy
+}
+
+// Report считает, сколько сообщений удалила бы очистка прямо сейчас, ничего не удаляя
+func (s *RetentionService) Report(ctx context.Context) (*models.RetentionReport, error) {
+	report := &models.RetentionReport{GeneratedAt: s.now(), Chats: make([]models.RetentionReportItem, 0)}
+
+	err := s.eachPolicy(ctx, func(policy models.RetentionPolicy) error {
+		cutoff, err := s.cutoff(ctx, policy, report.GeneratedAt)
+		if err != nil || cutoff == nil {
+			return err
+		}
+
+		expired, err := s.messageRepo.CountUpTo(ctx, policy.ChatID, cutoff.CreatedAt, cutoff.ID)
+		if err != nil {
+			return err
+		}
+		if expired == 0 {
+			return nil
+		}
+
+		report.Expired += expired
+		report.Chats = append(report.Chats, models.RetentionReportItem{
+			ChatID:        policy.ChatID,
+			MaxAgeSeconds: *policy.MaxAgeSeconds,
+			MaxMessages:   *policy.MaxMessages,
+			Expired:       expired,
+			Cutoff:        cutoff.CreatedAt,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Purge удаляет сообщения, вышедшие за политику своего чата, порциями по batchSize
+// и возвращает, сколько удалено. Каждая порция удаляется в своей транзакции вместе
+// с событиями message.deleted, файлы вложений — из хранилища после коммита.
+func (s *RetentionService) Purge(ctx context.Context) (int64, error) {
+	now := s.now()
+	var purged int64
+
+	err := s.eachPolicy(ctx, func(policy models.RetentionPolicy) error {
+		cutoff, err := s.cutoff(ctx, policy, now)
+		if err != nil || cutoff == nil {
+			return err
+		}
+
+		for {
+			var deleted []models.Message
+			err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+				var err error
+				deleted, err = s.messageRepo.DeleteUpTo(ctx, policy.ChatID, cutoff.CreatedAt, cutoff.ID, s.batchSize)
+				if err != nil {
+					return err
+				}
+
+				for _, message := range deleted {
+					data := map[string]int64{"id": message.ID, "chat_id": message.ChatID}
+					if err := recordEvent(ctx, s.outboxRepo, models.EventMessageDeleted, message.ChatID, data); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			deleteBlobs(ctx, s.blobs, attachmentsOf(deleted))
+			purged += int64(len(deleted))
+			retentionPurged.Add(int64(len(deleted)))
+			if len(deleted) < s.batchSize {
+				return nil
+			}
+		}
+	})
+
+	return purged, err
+}
+
+// Run запускает очистку раз в interval, пока не отменён ctx
+func (s *RetentionService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			retentionRuns.Add(1)
+			purged, err := s.Purge(ctx)
+			if err != nil && ctx.Err() == nil {
+				retentionErrors.Add(1)
+				log.Printf("retention: purge: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("retention: purged %d messages", purged)
+			}
+		}
+	}
+}
+
+// eachPolicy вызывает fn с действующей политикой каждого чата, у которого есть хоть одно
+// ограничение. Если глобальная политика ничего не ограничивает, обходятся только чаты со своей.
+func (s *RetentionService) eachPolicy(ctx context.Context, fn func(models.RetentionPolicy) error) error {
+	own, err := s.retentionRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	visit := func(chatID int64, policy *models.RetentionPolicy) error {
+		effective := s.effective(chatID, policy)
+		if *effective.MaxAgeSeconds == 0 && *effective.MaxMessages == 0 {
+			return nil
+		}
+		return fn(effective)
+	}
+
+	if limitOf(s.global.MaxAgeSeconds) == 0 && limitOf(s.global.MaxMessages) == 0 {
+		for i := range own {
+			if err := visit(own[i].ChatID, &own[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	byChat := make(map[int64]*models.RetentionPolicy, len(own))
+	for i := range own {
+		byChat[own[i].ChatID] = &own[i]
+	}
+
+	var afterID int64
+	for {
+		ids, err := s.chatRepo.ListIDs(ctx, afterID, s.batchSize)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := visit(id, byChat[id]); err != nil {
+				return err
+			}
+		}
+		if len(ids) < s.batchSize {
+			return nil
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// effective дополняет политику чата глобальными значениями; оба поля результата не nil
+func (s *RetentionService) effective(chatID int64, own *models.RetentionPolicy) models.RetentionPolicy {
+	policy := models.RetentionPolicy{ChatID: chatID}
+	if own != nil {
+		policy = *own
+	}
+
+	maxAge, maxMessages := limitOf(s.global.MaxAgeSeconds), limitOf(s.global.MaxMessages)
+	if policy.MaxAgeSeconds == nil {
+		policy.MaxAgeSeconds = &maxAge
+	}
+	if policy.MaxMessages == nil {
+		policy.MaxMessages = &maxMessages
+	}
+
+	return policy
+}
+
+// cutoff возвращает самое новое сообщение (CreatedAt, ID), которое уже вышло за политику:
+// всё не новее него подлежит удалению. nil, если удалять нечего.
+func (s *RetentionService) cutoff(ctx context.Context, policy models.RetentionPolicy, now time.Time) (*models.Message, error) {
+	var cutoff *models.Message
+
+	if maxAge := *policy.MaxAgeSeconds; maxAge > 0 {
+		// ID 0: граница не включается, удаляются сообщения строго старше
+		cutoff = &models.Message{CreatedAt: now.Add(-time.Duration(maxAge) * time.Second)}
+	}
+
+	if maxMessages := *policy.MaxMessages; maxMessages > 0 {
+		nth, err := s.messageRepo.NthNewest(ctx, policy.ChatID, int(maxMessages))
+		if err != nil {
+			return nil, err
+		}
+		if nth != nil && (cutoff == nil || nth.CreatedAt.After(cutoff.CreatedAt) ||
+			(nth.CreatedAt.Equal(cutoff.CreatedAt) && nth.ID > cutoff.ID)) {
+			cutoff = nth
+		}
+	}
+
+	return cutoff, nil
+}
+
+// limitOf разыменовывает ограничение политики; nil означает отсутствие ограничения
+func limitOf(limit *int64) int64 {
+	if limit == nil {
+		return 0
+	}
+	return *limit
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestSetRetentionPolicy(t *testing.T) {
+	owner := int64(42)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		policy      models.RetentionPolicy
+		setupMock   func(*mocks.MockRetentionRepository, *mocks.MockChatRepository)
+		expectAudit bool
+		expectErr   string
+	}{
+		{
+			name:   "владелец задаёт политику",
+			ctx:    auth.WithUserID(context.Background(), owner),
+			policy: models.RetentionPolicy{MaxMessages: int64Ptr(100)},
+			setupMock: func(rr *mocks.MockRetentionRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &owner}, nil)
+				rr.EXPECT().Get(gomock.Any(), int64(1)).Return(nil, nil)
+				rr.EXPECT().
+					Set(gomock.Any(), gomock.Cond(func(p *models.RetentionPolicy) bool {
+						return p.ChatID == 1 && p.MaxAgeSeconds == nil && *p.MaxMessages == 100
+					})).
+					Return(nil)
+			},
+			expectAudit: true,
+		},
+		{
+			name:   "не владелец",
+			ctx:    auth.WithUserID(context.Background(), 7),
+			policy: models.RetentionPolicy{MaxMessages: int64Ptr(100)},
+			setupMock: func(rr *mocks.MockRetentionRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, OwnerID: &owner}, nil)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:   "администратор задаёт политику комнаты без владельца",
+			ctx:    auth.WithAdmin(context.Background()),
+			policy: models.RetentionPolicy{MaxMessages: int64Ptr(100)},
+			setupMock: func(rr *mocks.MockRetentionRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1}, nil)
+				rr.EXPECT().Get(gomock.Any(), int64(1)).Return(nil, nil)
+				rr.EXPECT().Set(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectAudit: true,
+		},
+		{
+			name:      "отрицательный срок",
+			ctx:       auth.WithUserID(context.Background(), owner),
+			policy:    models.RetentionPolicy{MaxAgeSeconds: int64Ptr(-1)},
+			setupMock: func(rr *mocks.MockRetentionRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "max_age_seconds must not be negative",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			setupMock: func(rr *mocks.MockRetentionRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRetention := mocks.NewMockRetentionRepository(ctrl)
+			mockChats := mocks.NewMockChatRepository(ctrl)
+			tt.setupMock(mockRetention, mockChats)
+
+			action := ""
+			if tt.expectAudit {
+				action = models.AuditRetentionUpdate
+			}
+			service := NewRetentionService(mockRetention, mockChats, mocks.NewMockChatMemberRepository(ctrl), mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, action), nil,
+				models.RetentionPolicy{MaxAgeSeconds: int64Ptr(3600)}, 10)
+
+			policy, err := service.SetPolicy(tt.ctx, 1, tt.policy)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				if *policy.MaxAgeSeconds != 3600 {
+					t.Errorf("незаданный срок должен наследоваться из глобальной политики, получен %d", *policy.MaxAgeSeconds)
+				}
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestGetRetentionPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRetention := mocks.NewMockRetentionRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+	mockRetention.EXPECT().Get(gomock.Any(), int64(1)).Return(&models.RetentionPolicy{ChatID: 1, MaxMessages: int64Ptr(100)}, nil)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.Chat{ID: 2, Type: models.ChatTypeDM}, nil)
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(2), int64(7)).Return(false, nil)
+
+	service := NewRetentionService(mockRetention, mockChats, mockMembers, mocks.NewMockMessageRepository(ctrl), newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, ""), nil,
+		models.RetentionPolicy{MaxAgeSeconds: int64Ptr(3600)}, 10)
+	ctx := auth.WithUserID(context.Background(), 7)
+
+	policy, err := service.GetPolicy(ctx, 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if *policy.MaxAgeSeconds != 3600 || *policy.MaxMessages != 100 {
+		t.Errorf("неожиданная политика: %+v", policy)
+	}
+
+	// Политика чужого личного чата закрыта, как и его сообщения
+	if _, err := service.GetPolicy(ctx, 2); !errors.Is(err, errForbidden) {
+		t.Errorf("ожидалась ошибка forbidden, получена %v", err)
+	}
+}
+
+func TestRetentionPurge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	mockRetention := mocks.NewMockRetentionRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMessages := mocks.NewMockMessageRepository(ctrl)
+
+	// Чат 1 наследует глобальный срок в час, чат 2 хранит 3 последних сообщения
+	// без срока, чат 3 отключил очистку
+	mockRetention.EXPECT().List(gomock.Any()).Return([]models.RetentionPolicy{
+		{ChatID: 2, MaxAgeSeconds: int64Ptr(0), MaxMessages: int64Ptr(3)},
+		{ChatID: 3, MaxAgeSeconds: int64Ptr(0)},
+	}, nil)
+	mockChats.EXPECT().ListIDs(gomock.Any(), int64(0), 2).Return([]int64{1, 2}, nil)
+	mockChats.EXPECT().ListIDs(gomock.Any(), int64(2), 2).Return([]int64{3}, nil)
+
+	// У самого старого сообщения есть вложение с миниатюрой
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("хранилище: %v", err)
+	}
+	keys := []string{"chats/1/file", "chats/1/file-thumb"}
+	for _, key := range keys {
+		if _, err := blobs.Put(context.Background(), key, strings.NewReader("данные")); err != nil {
+			t.Fatalf("запись %s: %v", key, err)
+		}
+	}
+
+	hourAgo := now.Add(-time.Hour)
+	mockMessages.EXPECT().DeleteUpTo(gomock.Any(), int64(1), hourAgo, int64(0), 2).Return([]models.Message{
+		{ID: 1, ChatID: 1, Attachments: []models.Attachment{{ID: 7, StorageKey: keys[0], ThumbnailKey: keys[1]}}},
+		{ID: 2, ChatID: 1},
+	}, nil)
+	mockMessages.EXPECT().DeleteUpTo(gomock.Any(), int64(1), hourAgo, int64(0), 2).Return([]models.Message{{ID: 3, ChatID: 1}}, nil)
+
+	nth := &models.Message{ID: 20, ChatID: 2, CreatedAt: now.Add(-time.Minute)}
+	mockMessages.EXPECT().NthNewest(gomock.Any(), int64(2), 3).Return(nth, nil)
+	mockMessages.EXPECT().DeleteUpTo(gomock.Any(), int64(2), nth.CreatedAt, int64(20), 2).Return(nil, nil)
+
+	var events []models.OutboxEvent
+	mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+	mockOutbox.EXPECT().
+		Add(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, event *models.OutboxEvent) error {
+			events = append(events, *event)
+			return nil
+		}).
+		Times(3)
+
+	service := NewRetentionService(mockRetention, mockChats, mocks.NewMockChatMemberRepository(ctrl), mockMessages, newTxManager(ctrl), mockOutbox, newAudit(ctrl, ""), blobs, models.RetentionPolicy{MaxAgeSeconds: int64Ptr(3600)}, 2)
+	service.now = func() time.Time { return now }
+
+	purged, err := service.Purge(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if purged != 3 {
+		t.Errorf("ожидалось 3 удалённых сообщения, получено %d", purged)
+	}
+	for _, key := range keys {
+		if _, err := blobs.Open(context.Background(), key); !errors.Is(err, blob.ErrNotFound) {
+			t.Errorf("файл %s должен быть удалён из хранилища, получено %v", key, err)
+		}
+	}
+
+	// Подписчики узнают о каждом удалённом сообщении
+	for i, want := range []int64{1, 2, 3} {
+		if events[i].EventType != models.EventMessageDeleted {
+			t.Errorf("ожидалось событие %s, получено %s", models.EventMessageDeleted, events[i].EventType)
+		}
+		var data map[string]int64
+		if err := json.Unmarshal([]byte(events[i].Payload), &data); err != nil {
+			t.Fatalf("невалидный payload: %v", err)
+		}
+		if data["id"] != want || data["chat_id"] != 1 {
+			t.Errorf("ожидалось удаление сообщения %d чата 1, в событии %v", want, data)
+		}
+	}
+}
+
+func TestRetentionReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	mockRetention := mocks.NewMockRetentionRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockMessages := mocks.NewMockMessageRepository(ctrl)
+
+	// Глобальная политика ничего не ограничивает — обходятся только чаты со своей
+	mockRetention.EXPECT().List(gomock.Any()).Return([]models.RetentionPolicy{
+		{ChatID: 5, MaxAgeSeconds: int64Ptr(60), MaxMessages: int64Ptr(10)},
+		{ChatID: 6, MaxMessages: int64Ptr(10)},
+	}, nil)
+
+	// Граница по количеству новее границы по сроку — побеждает она
+	nth := &models.Message{ID: 9, ChatID: 5, CreatedAt: now.Add(-time.Second)}
+	mockMessages.EXPECT().NthNewest(gomock.Any(), int64(5), 10).Return(nth, nil)
+	mockMessages.EXPECT().CountUpTo(gomock.Any(), int64(5), nth.CreatedAt, int64(9)).Return(int64(4), nil)
+	mockMessages.EXPECT().NthNewest(gomock.Any(), int64(6), 10).Return(nil, nil)
+
+	service := NewRetentionService(mockRetention, mockChats, mocks.NewMockChatMemberRepository(ctrl), mockMessages, newTxManager(ctrl), newOutbox(ctrl, ""), newAudit(ctrl, ""), nil, models.RetentionPolicy{}, 100)
+	service.now = func() time.Time { return now }
+
+	report, err := service.Report(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if report.Expired != 4 || len(report.Chats) != 1 {
+		t.Fatalf("ожидался один чат с 4 сообщениями, получено %+v", report)
+	}
+	if item := report.Chats[0]; item.ChatID != 5 || item.MaxAgeSeconds != 60 || item.MaxMessages != 10 || !item.Cutoff.Equal(nth.CreatedAt) {
+		t.Errorf("неожиданная строка отчёта: %+v", item)
+	}
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/retry"
+)
+
+//go:generate mockgen -destination=mocks/mock_schedule_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service ScheduleServiceInterface
+
+// Отправка отложенного сообщения после сбоя повторяется с задержкой от scheduleMinBackoff
+// до scheduleMaxBackoff, после scheduleMaxAttempts неудач сообщение бросается.
+// Захваченное сообщение другие реплики не берут scheduleClaimTTL.
+const (
+	scheduleMaxAttempts = 5
+	scheduleMinBackoff  = time.Minute
+	scheduleMaxBackoff  = time.Hour
+	scheduleClaimTTL    = 5 * time.Minute
+)
+
+type ScheduleServiceInterface interface {
+	ScheduleMessage(ctx context.Context, chatID int64, input models.MessageInput, sendAt time.Time) (*models.ScheduledMessage, error)
+	ListScheduled(ctx context.Context, chatID int64) ([]models.ScheduledMessage, error)
+	UpdateScheduled(ctx context.Context, chatID, id int64, patch models.ScheduledMessagePatch) (*models.ScheduledMessage, error)
+	CancelScheduled(ctx context.Context, chatID, id int64) error
+}
+
+type ScheduleService struct {
+	scheduledRepo repository.ScheduledMessageRepository
+	chatRepo      repository.ChatRepository
+	memberRepo    repository.ChatMemberRepository
+	txManager     repository.TxManager
+	chatService   ChatServiceInterface
+	now           func() time.Time
+}
+
+func NewScheduleService(scheduledRepo repository.ScheduledMessageRepository, chatRepo repository.ChatRepository, memberRepo repository.ChatMemberRepository, txManager repository.TxManager, chatService ChatServiceInterface) *ScheduleService {
+	return &ScheduleService{
+		scheduledRepo: scheduledRepo,
+		chatRepo:      chatRepo,
+		memberRepo:    memberRepo,
+		txManager:     txManager,
+		chatService:   chatService,
+		now:           time.Now,
+	}
+}
+
+// ScheduleMessage откладывает сообщение текущего пользователя до sendAt. Текст, формат
+// и срок жизни проверяются сразу, по тем же правилам, что и в CreateMessage.
+// Откладывать сообщения могут только участники чата.
+func (s *ScheduleService) ScheduleMessage(ctx context.Context, chatID int64, input models.MessageInput, sendAt time.Time) (*models.ScheduledMessage, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	message := &models.ScheduledMessage{ChatID: chatID, AuthorID: userID}
+	if err := s.apply(message, input, sendAt); err != nil {
+		return nil, err
+	}
+
+	if err := authorizeMember(ctx, s.memberRepo, s.chatRepo, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.scheduledRepo.Create(ctx, message); err != nil {
+		if errors.Is(err, repository.ErrChatNotFound) {
+			return nil, errChatNotFound
+		}
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// ListScheduled получает ещё не отправленные сообщения текущего пользователя в чате
+func (s *ScheduleService) ListScheduled(ctx context.Context, chatID int64) ([]models.ScheduledMessage, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	return s.scheduledRepo.ListByAuthor(ctx, chatID, userID)
+}
+
+// UpdateScheduled меняет текст, формат, срок жизни или время отправки своего
+// отложенного сообщения. Уже отправленное изменить нельзя, как и захваченное на отправку:
+// правка потерялась бы, потому что уйдёт прежний текст. Брошенное после неудачных
+// попыток сообщение после изменения снова встаёт в очередь.
+func (s *ScheduleService) UpdateScheduled(ctx context.Context, chatID, id int64, patch models.ScheduledMessagePatch) (*models.ScheduledMessage, error) {
+	var message *models.ScheduledMessage
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		message, err = s.own(ctx, chatID, id)
+		if err != nil {
+			return err
+		}
+
+		input := models.MessageInput{Text: message.Text, Format: message.Format, ExpiresIn: message.ExpiresIn}
+		sendAt := message.SendAt
+		if patch.Text != nil {
+			input.Text = *patch.Text
+		}
+		if patch.Format != nil {
+			input.Format = *patch.Format
+		}
+		if patch.ExpiresIn != nil {
+			input.ExpiresIn = *patch.ExpiresIn
+		}
+		if patch.SendAt != nil {
+			sendAt = *patch.SendAt
+		}
+		if err := s.apply(message, input, sendAt); err != nil {
+			return err
+		}
+
+		if err := s.scheduledRepo.Update(ctx, message); err != nil {
+			if errors.Is(err, repository.ErrScheduledMessageNotFound) {
+				return errors.New("scheduled message not found")
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// CancelScheduled отменяет своё отложенное сообщение, если оно ещё не отправляется
+func (s *ScheduleService) CancelScheduled(ctx context.Context, chatID, id int64) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.own(ctx, chatID, id); err != nil {
+			return err
+		}
+
+		if err := s.scheduledRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrScheduledMessageNotFound) {
+				return errors.New("scheduled message not found")
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// Deliver отправляет все сообщения, которым пора уйти, и возвращает, сколько отправлено.
+// Каждое сообщение захватывается через ClaimDue в короткой транзакции на scheduleClaimTTL,
+// чтобы реплики не брались за одно сообщение одновременно, и отправляется через send.
+// Сбой одного сообщения не задерживает остальные: оно откладывается через fail. Если
+// реплика упала посреди отправки, транзакция send откатится и по истечении захвата
+// сообщение уйдёт снова — ровно один раз.
+func (s *ScheduleService) Deliver(ctx context.Context) (int, error) {
+	var delivered int
+	for {
+		var scheduled *models.ScheduledMessage
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			now := s.now()
+			claimed, err := s.scheduledRepo.ClaimDue(ctx, now, now.Add(scheduleClaimTTL), 1)
+			if err != nil || len(claimed) == 0 {
+				return err
+			}
+			scheduled = &claimed[0]
+			return nil
+		})
+		if err != nil || scheduled == nil {
+			// Очередь пуста или захват не удался
+			return delivered, err
+		}
+
+		sent, err := s.send(ctx, scheduled.ID)
+		if err == nil {
+			if sent {
+				delivered++
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			// Работа остановлена: сообщение уйдёт после истечения захвата
+			return delivered, err
+		}
+
+		// Если не удалось записать и попытку, база, скорее всего, недоступна: выходим,
+		// сообщение уйдёт снова после истечения захвата
+		if err := s.fail(ctx, scheduled.ID, err); err != nil {
+			return delivered, err
+		}
+	}
+}
+
+// send публикует отложенное сообщение и удаляет его из очереди в одной транзакции,
+// поэтому после сбоя нет ни сообщения, ни удаления, и повтор не создаст дубль. Строка
+// блокируется до фиксации: реплика, захватившая сообщение после истечения захвата,
+// дождётся её и уже не найдёт сообщения. Модерация CreateMessage при этом идёт внутри
+// транзакции — это цена атомарности. Возвращает false, если сообщения в очереди уже нет.
+func (s *ScheduleService) send(ctx context.Context, id int64) (bool, error) {
+	var sent bool
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		scheduled, err := s.scheduledRepo.GetForUpdate(ctx, id)
+		if err != nil || scheduled == nil {
+			return err
+		}
+
+		input := models.MessageInput{Text: scheduled.Text, Format: scheduled.Format, ExpiresIn: scheduled.ExpiresIn}
+		if _, err := s.chatService.CreateMessage(auth.WithUserID(ctx, scheduled.AuthorID), scheduled.ChatID, input); err != nil {
+			return err
+		}
+		if err := s.scheduledRepo.Delete(ctx, scheduled.ID); err != nil {
+			return err
+		}
+		sent = true
+		return nil
+	})
+	return sent, err
+}
+
+// fail записывает неудачную попытку отправки. Сообщение откладывается с экспоненциальной
+// задержкой, а после scheduleMaxAttempts попыток или отказа по самим данным (например,
+// автор больше не участник чата) бросается: в очереди оно остаётся с failed_at и last_error.
+func (s *ScheduleService) fail(ctx context.Context, id int64, cause error) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		message, err := s.scheduledRepo.GetByID(ctx, id)
+		if err != nil || message == nil {
+			return err
+		}
+
+		now := s.now()
+		message.Attempts++
+		message.LastError = cause.Error()
+		message.ClaimedUntil = nil
+		if isInvalidMessage(cause) || message.Attempts >= scheduleMaxAttempts {
+			message.FailedAt = &now
+			log.Printf("schedule: give up message %d after %d attempts: %v", id, message.Attempts, cause)
+		} else {
+			message.SendAt = now.Add(retry.Backoff(message.Attempts, scheduleMinBackoff, scheduleMaxBackoff))
+			log.Printf("schedule: retry message %d at %s: %v", id, message.SendAt.Format(time.RFC3339), cause)
+		}
+
+		err = s.scheduledRepo.Update(ctx, message)
+		if errors.Is(err, repository.ErrScheduledMessageNotFound) {
+			return nil
+		}
+		return err
+	})
+}
+
+// Run запускает отправку отложенных сообщений раз в interval, пока не отменён ctx
+func (s *ScheduleService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Deliver(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("schedule: deliver: %v", err)
+			}
+		}
+	}
+}
+
+// own получает для изменения отложенное сообщение текущего пользователя из чата chatID.
+// Чужие сообщения неотличимы от несуществующих. Строка блокируется до конца транзакции,
+// поэтому Deliver не захватит сообщение посреди правки, а захваченное Deliver сообщение
+// менять нельзя, пока не истёк захват. Вызывается внутри транзакции.
+func (s *ScheduleService) own(ctx context.Context, chatID, id int64) (*models.ScheduledMessage, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return nil, errors.New("authentication required")
+	}
+
+	message, err := s.scheduledRepo.GetForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || message.ChatID != chatID || message.AuthorID != userID {
+		return nil, errors.New("scheduled message not found")
+	}
+	if message.ClaimedUntil != nil && message.ClaimedUntil.After(s.now()) {
+		return nil, errors.New("scheduled message is being sent")
+	}
+
+	return message, nil
+}
+
+// apply проверяет поля и переносит их в отложенное сообщение
+func (s *ScheduleService) apply(message *models.ScheduledMessage, input models.MessageInput, sendAt time.Time) error {
+	format, err := normalizeFormat(input.Format)
+	if err != nil {
+		return err
+	}
+	text, err := normalizeText(input.Text)
+	if err != nil {
+		return err
+	}
+	if _, err := expiryFor(input.ExpiresIn); err != nil {
+		return err
+	}
+	if !sendAt.After(s.now()) {
+		return errors.New("send_at must be in the future")
+	}
+
+	message.Text = text
+	message.Format = format
+	message.ExpiresIn = input.ExpiresIn
+	message.SendAt = sendAt
+	message.Attempts = 0
+	message.LastError = ""
+	message.FailedAt = nil
+	return nil
+}
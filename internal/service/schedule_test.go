@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	serviceMocks "github.com/GlebMoskalev/chat-golang/internal/service/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	scheduleNow          = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	scheduleClaimedUntil = scheduleNow.Add(scheduleClaimTTL)
+)
+
+func TestScheduleMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		input     models.MessageInput
+		sendAt    time.Time
+		isMember  bool
+		setupMock func(*mocks.MockScheduledMessageRepository, *mocks.MockChatRepository)
+		expectErr string
+	}{
+		{
+			name:     "успешное планирование",
+			ctx:      auth.WithUserID(context.Background(), 42),
+			input:    models.MessageInput{Text: "  Доброе утро  "},
+			sendAt:   scheduleNow.Add(time.Hour),
+			isMember: true,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {
+				sr.EXPECT().
+					Create(gomock.Any(), gomock.Cond(func(m *models.ScheduledMessage) bool {
+						return m.ChatID == 1 && m.AuthorID == 42 && m.Text == "Доброе утро" &&
+							m.Format == models.MessageFormatPlain && m.SendAt.Equal(scheduleNow.Add(time.Hour))
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name:      "время в прошлом",
+			ctx:       auth.WithUserID(context.Background(), 42),
+			input:     models.MessageInput{Text: "Доброе утро"},
+			sendAt:    scheduleNow,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "send_at must be in the future",
+		},
+		{
+			name:      "пустой текст",
+			ctx:       auth.WithUserID(context.Background(), 42),
+			sendAt:    scheduleNow.Add(time.Hour),
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "text cannot be empty",
+		},
+		{
+			name:   "чат не найден",
+			ctx:    auth.WithUserID(context.Background(), 42),
+			input:  models.MessageInput{Text: "Доброе утро"},
+			sendAt: scheduleNow.Add(time.Hour),
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().Exists(gomock.Any(), int64(1)).Return(false, nil)
+			},
+			expectErr: "chat not found",
+		},
+		{
+			name:   "не участник чата",
+			ctx:    auth.WithUserID(context.Background(), 42),
+			input:  models.MessageInput{Text: "Доброе утро"},
+			sendAt: scheduleNow.Add(time.Hour),
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().Exists(gomock.Any(), int64(1)).Return(true, nil)
+			},
+			expectErr: "forbidden",
+		},
+		{
+			name:      "без пользователя",
+			ctx:       context.Background(),
+			setupMock: func(sr *mocks.MockScheduledMessageRepository, cr *mocks.MockChatRepository) {},
+			expectErr: "authentication required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+			chatRepo := mocks.NewMockChatRepository(ctrl)
+			tt.setupMock(scheduledRepo, chatRepo)
+
+			memberRepo := mocks.NewMockChatMemberRepository(ctrl)
+			memberRepo.EXPECT().IsMember(gomock.Any(), int64(1), int64(42)).Return(tt.isMember, nil).AnyTimes()
+
+			service := NewScheduleService(scheduledRepo, chatRepo, memberRepo, newTxManager(ctrl), serviceMocks.NewMockChatServiceInterface(ctrl))
+			service.now = func() time.Time { return scheduleNow }
+
+			_, err := service.ScheduleMessage(tt.ctx, 1, tt.input, tt.sendAt)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestUpdateScheduled(t *testing.T) {
+	pending := func() *models.ScheduledMessage {
+		return &models.ScheduledMessage{ID: 7, ChatID: 1, AuthorID: 42, Text: "черновик", Format: models.MessageFormatPlain, SendAt: scheduleNow.Add(time.Hour)}
+	}
+	text := "исправлено"
+
+	tests := []struct {
+		name      string
+		userID    int64
+		setupMock func(*mocks.MockScheduledMessageRepository)
+		expectErr string
+	}{
+		{
+			name:   "автор меняет текст",
+			userID: 42,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository) {
+				sr.EXPECT().GetForUpdate(gomock.Any(), int64(7)).Return(pending(), nil)
+				sr.EXPECT().
+					Update(gomock.Any(), gomock.Cond(func(m *models.ScheduledMessage) bool {
+						return m.Text == "исправлено" && m.SendAt.Equal(scheduleNow.Add(time.Hour))
+					})).
+					Return(nil)
+			},
+		},
+		{
+			name:   "сообщение уже отправляется",
+			userID: 42,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository) {
+				claimed := pending()
+				until := scheduleNow.Add(time.Minute)
+				claimed.ClaimedUntil = &until
+				sr.EXPECT().GetForUpdate(gomock.Any(), int64(7)).Return(claimed, nil)
+			},
+			expectErr: "scheduled message is being sent",
+		},
+		{
+			name:   "захват истёк",
+			userID: 42,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository) {
+				claimed := pending()
+				until := scheduleNow.Add(-time.Minute)
+				claimed.ClaimedUntil = &until
+				sr.EXPECT().GetForUpdate(gomock.Any(), int64(7)).Return(claimed, nil)
+				sr.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "чужое сообщение",
+			userID: 5,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository) {
+				sr.EXPECT().GetForUpdate(gomock.Any(), int64(7)).Return(pending(), nil)
+			},
+			expectErr: "scheduled message not found",
+		},
+		{
+			name:   "уже отправлено",
+			userID: 42,
+			setupMock: func(sr *mocks.MockScheduledMessageRepository) {
+				sr.EXPECT().GetForUpdate(gomock.Any(), int64(7)).Return(nil, nil)
+			},
+			expectErr: "scheduled message not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			scheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+			tt.setupMock(scheduledRepo)
+
+			service := NewScheduleService(scheduledRepo, mocks.NewMockChatRepository(ctrl), mocks.NewMockChatMemberRepository(ctrl), newTxManager(ctrl), serviceMocks.NewMockChatServiceInterface(ctrl))
+			service.now = func() time.Time { return scheduleNow }
+
+			_, err := service.UpdateScheduled(auth.WithUserID(context.Background(), tt.userID), 1, 7, models.ScheduledMessagePatch{Text: &text})
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestCancelScheduledDuringDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	until := scheduleNow.Add(time.Minute)
+	scheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+	scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(7)).
+		Return(&models.ScheduledMessage{ID: 7, ChatID: 1, AuthorID: 42, SendAt: scheduleNow, ClaimedUntil: &until}, nil)
+
+	// Delete не вызывается: отмена не должна молча потеряться после отправки
+	service := NewScheduleService(scheduledRepo, mocks.NewMockChatRepository(ctrl), mocks.NewMockChatMemberRepository(ctrl), newTxManager(ctrl), serviceMocks.NewMockChatServiceInterface(ctrl))
+	service.now = func() time.Time { return scheduleNow }
+
+	err := service.CancelScheduled(auth.WithUserID(context.Background(), 42), 1, 7)
+	if err == nil || err.Error() != "scheduled message is being sent" {
+		t.Errorf("ожидалась ошибка \"scheduled message is being sent\", получена %v", err)
+	}
+}
+
+func TestDeliverScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	scheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+	chatService := serviceMocks.NewMockChatServiceInterface(ctrl)
+	first := models.ScheduledMessage{ID: 1, ChatID: 10, AuthorID: 42, Text: "привет", Format: models.MessageFormatPlain, ExpiresIn: 60}
+	second := models.ScheduledMessage{ID: 2, ChatID: 11, AuthorID: 42, Text: "в чужой чат", Format: models.MessageFormatPlain}
+	gomock.InOrder(
+		scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return([]models.ScheduledMessage{first}, nil),
+		scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(1)).Return(&first, nil),
+		chatService.EXPECT().
+			CreateMessage(gomock.Any(), int64(10), models.MessageInput{Text: "привет", Format: models.MessageFormatPlain, ExpiresIn: 60}).
+			DoAndReturn(func(ctx context.Context, chatID int64, input models.MessageInput) (*models.Message, error) {
+				if userID, ok := auth.UserID(ctx); !ok || userID != 42 {
+					t.Errorf("сообщение должно уходить от имени автора, получен пользователь %d", userID)
+				}
+				if ctx.Value(txKey{}) == nil {
+					t.Error("сообщение должно создаваться в одной транзакции с удалением из очереди")
+				}
+				return &models.Message{ID: 100, ChatID: chatID}, nil
+			}),
+		scheduledRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil),
+
+		// Автор больше не может писать в чат: повтор ничего не изменит, сообщение бросается сразу
+		scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return([]models.ScheduledMessage{second}, nil),
+		scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(2)).Return(&second, nil),
+		chatService.EXPECT().CreateMessage(gomock.Any(), int64(11), gomock.Any()).Return(nil, errForbidden),
+		scheduledRepo.EXPECT().GetByID(gomock.Any(), int64(2)).Return(&models.ScheduledMessage{ID: 2, ChatID: 11, AuthorID: 42, SendAt: scheduleNow}, nil),
+		scheduledRepo.EXPECT().
+			Update(gomock.Any(), gomock.Cond(func(m *models.ScheduledMessage) bool {
+				return m.ID == 2 && m.Attempts == 1 && m.LastError == "forbidden" && m.FailedAt != nil
+			})).
+			Return(nil),
+
+		// Захват истёк, и другая реплика успела отправить сообщение: повторно оно не уходит
+		scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return([]models.ScheduledMessage{{ID: 3, ChatID: 10, AuthorID: 42}}, nil),
+		scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(3)).Return(nil, nil),
+
+		scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return(nil, nil),
+	)
+
+	txManager := mocks.NewMockTxManager(ctrl)
+	txManager.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, txKey{}, true))
+		}).
+		AnyTimes()
+
+	service := NewScheduleService(scheduledRepo, mocks.NewMockChatRepository(ctrl), mocks.NewMockChatMemberRepository(ctrl), txManager, chatService)
+	service.now = func() time.Time { return scheduleNow }
+
+	delivered, err := service.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("ожидалось одно отправленное сообщение, получено %d", delivered)
+	}
+}
+
+func TestDeliverScheduledRetriesOnFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int
+		expectSend time.Time
+		expectGone bool
+	}{
+		{name: "первый сбой откладывает на минуту", expectSend: scheduleNow.Add(time.Minute)},
+		{name: "задержка растёт", attempts: 2, expectSend: scheduleNow.Add(4 * time.Minute)},
+		{name: "после последней попытки сообщение бросается", attempts: scheduleMaxAttempts - 1, expectGone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			stored := models.ScheduledMessage{ID: 1, ChatID: 10, AuthorID: 42, Text: "привет", SendAt: scheduleNow, Attempts: tt.attempts, ClaimedUntil: &scheduleClaimedUntil}
+
+			scheduledRepo := mocks.NewMockScheduledMessageRepository(ctrl)
+			chatService := serviceMocks.NewMockChatServiceInterface(ctrl)
+			gomock.InOrder(
+				scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return([]models.ScheduledMessage{stored}, nil),
+				scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(1)).Return(&stored, nil),
+				chatService.EXPECT().CreateMessage(gomock.Any(), int64(10), gomock.Any()).Return(nil, errors.New("connection reset")),
+				scheduledRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&stored, nil),
+				scheduledRepo.EXPECT().
+					Update(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, m *models.ScheduledMessage) error {
+						if m.Attempts != tt.attempts+1 || m.LastError != "connection reset" {
+							t.Errorf("неверная попытка: %d, %q", m.Attempts, m.LastError)
+						}
+						if m.ClaimedUntil != nil {
+							t.Error("после сбоя захват должен сниматься")
+						}
+						if tt.expectGone != (m.FailedAt != nil) {
+							t.Errorf("ожидалось failed_at=%v, получено %v", tt.expectGone, m.FailedAt)
+						}
+						if !tt.expectGone && !m.SendAt.Equal(tt.expectSend) {
+							t.Errorf("ожидалась отправка в %s, получено %s", tt.expectSend, m.SendAt)
+						}
+						return nil
+					}),
+
+				// Сбойное сообщение не задерживает следующие
+				scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return([]models.ScheduledMessage{{ID: 2, ChatID: 10, AuthorID: 42, Text: "следом"}}, nil),
+				scheduledRepo.EXPECT().GetForUpdate(gomock.Any(), int64(2)).Return(&models.ScheduledMessage{ID: 2, ChatID: 10, AuthorID: 42, Text: "следом"}, nil),
+				chatService.EXPECT().CreateMessage(gomock.Any(), int64(10), gomock.Any()).Return(&models.Message{ID: 100}, nil),
+				scheduledRepo.EXPECT().Delete(gomock.Any(), int64(2)).Return(nil),
+				scheduledRepo.EXPECT().ClaimDue(gomock.Any(), scheduleNow, scheduleClaimedUntil, 1).Return(nil, nil),
+			)
+
+			service := NewScheduleService(scheduledRepo, mocks.NewMockChatRepository(ctrl), mocks.NewMockChatMemberRepository(ctrl), newTxManager(ctrl), chatService)
+			service.now = func() time.Time { return scheduleNow }
+
+			delivered, err := service.Deliver(context.Background())
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if delivered != 1 {
+				t.Errorf("ожидалось одно отправленное сообщение, получено %d", delivered)
+			}
+		})
+	}
+}
+
+func TestIsInvalidMessage(t *testing.T) {
+	_, emptyText := normalizeText("  ")
+	_, badFormat := normalizeFormat("html")
+	_, badExpiry := expiryFor(-1)
+	for _, err := range []error{emptyText, badFormat, badExpiry, errForbidden, fmt.Errorf("%w: word: казино", errRejected)} {
+		if !isInvalidMessage(err) {
+			t.Errorf("%q должна считаться ошибкой данных", err)
+		}
+	}
+	if isInvalidMessage(errors.New("connection reset")) {
+		t.Error("сбой базы не должен считаться ошибкой данных")
+	}
+}
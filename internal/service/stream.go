@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_stream_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service StreamServiceInterface,EventSubscriber
+
+// EventSubscriber подписывает на все события приложения (см. outbox.Bus)
+type EventSubscriber interface {
+	Subscribe(buffer int) (<-chan models.OutboxEvent, func())
+}
+
+type StreamServiceInterface interface {
+	Subscribe(ctx context.Context, chatID int64) (<-chan models.OutboxEvent, error)
+}
+
+type StreamService struct {
+	subscriber EventSubscriber
+	memberRepo repository.ChatMemberRepository
+	chatRepo   repository.ChatRepository
+	buffer     int
+}
+
+// NewStreamService создаёт сервис подписки на события чата. buffer — сколько событий
+// копится для медленного подписчика, дальше шина их отбрасывает.
+func NewStreamService(subscriber EventSubscriber, memberRepo repository.ChatMemberRepository, chatRepo repository.ChatRepository, buffer int) *StreamService {
+	return &StreamService{
+		subscriber: subscriber,
+		memberRepo: memberRepo,
+		chatRepo:   chatRepo,
+		buffer:     buffer,
+	}
+}
+
+// Subscribe подписывает текущего пользователя на события чата. Права те же, что на чтение
+// сообщений в REST: на комнату может подписаться любой, на личный чат — только его участники.
+// Канал закрывается, когда отменён ctx.
+func (s *StreamService) Subscribe(ctx context.Context, chatID int64) (<-chan models.OutboxEvent, error) {
+	if _, err := authorizeChat(ctx, s.memberRepo, s.chatRepo, chatID); err != nil {
+		return nil, err
+	}
+
+	events, unsubscribe := s.subscriber.Subscribe(s.buffer)
+	out := make(chan models.OutboxEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if event.ChatID != chatID {
+					continue
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/auth"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/outbox"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestStreamSubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// На комнату подписывается и не участник, как в REST
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeRoom}, nil)
+
+	bus := outbox.NewBus()
+	service := NewStreamService(bus, mocks.NewMockChatMemberRepository(ctrl), mockChats, 16)
+
+	ctx, cancel := context.WithCancel(auth.WithUserID(context.Background(), 7))
+	events, err := service.Subscribe(ctx, 1)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	bus.Publish(context.Background(), models.OutboxEvent{ID: 1, EventType: models.EventMessageCreated, ChatID: 2})
+	bus.Publish(context.Background(), models.OutboxEvent{ID: 2, EventType: models.EventMessageCreated, ChatID: 1})
+
+	select {
+	case event := <-events:
+		if event.ID != 2 {
+			t.Errorf("ожидалось событие чата 1, получено %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("событие чата не доставлено")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("после отмены событий быть не должно")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("канал не закрыт после отмены")
+	}
+}
+
+func TestStreamSubscribeForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMembers := mocks.NewMockChatMemberRepository(ctrl)
+	mockChats := mocks.NewMockChatRepository(ctrl)
+	mockChats.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Chat{ID: 1, Type: models.ChatTypeDM}, nil).Times(2)
+	mockMembers.EXPECT().IsMember(gomock.Any(), int64(1), int64(7)).Return(false, nil)
+
+	service := NewStreamService(outbox.NewBus(), mockMembers, mockChats, 16)
+
+	if _, err := service.Subscribe(auth.WithUserID(context.Background(), 7), 1); err == nil || err.Error() != "forbidden" {
+		t.Errorf("ожидалась ошибка forbidden, получена %v", err)
+	}
+	if _, err := service.Subscribe(context.Background(), 1); err == nil || err.Error() != "authentication required" {
+		t.Errorf("ожидалась ошибка authentication required, получена %v", err)
+	}
+}
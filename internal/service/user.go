@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_user_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service UserServiceInterface
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_.-]{3,32}$`)
+
+type UserServiceInterface interface {
+	CreateUser(ctx context.Context, username string) (*models.User, error)
+	GetUser(ctx context.Context, id int64) (*models.User, error)
+}
+
+type UserService struct {
+	userRepo repository.UserRepository
+}
+
+func NewUserService(userRepo repository.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+// CreateUser регистрирует пользователя. Имя приводится к нижнему регистру.
+func (s *UserService) CreateUser(ctx context.Context, username string) (*models.User, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernamePattern.MatchString(username) {
+		return nil, errors.New("username must be 3-32 characters: a-z, 0-9, '_', '.', '-'")
+	}
+	if username == channelMention {
+		return nil, errors.New("username is reserved")
+	}
+
+	user := &models.User{Username: username}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrUsernameTaken) {
+			return nil, errors.New("username already taken")
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUser получает пользователя по ID
+func (s *UserService) GetUser(ctx context.Context, id int64) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
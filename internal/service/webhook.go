@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/netutil"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+//go:generate mockgen -destination=mocks/mock_webhook_service.go -package=mocks github.com/GlebMoskalev/chat-golang/internal/service WebhookServiceInterface
+
+type WebhookServiceInterface interface {
+	CreateWebhook(ctx context.Context, rawURL string, events []string, chatID *int64, secret string) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	EnableWebhook(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error)
+}
+
+type WebhookService struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	chatRepo     repository.ChatRepository
+	txManager    repository.TxManager
+	auditRepo    repository.AuditRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, deliveryRepo repository.WebhookDeliveryRepository, chatRepo repository.ChatRepository, txManager repository.TxManager, auditRepo repository.AuditRepository) *WebhookService {
+	return &WebhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		chatRepo:     chatRepo,
+		txManager:    txManager,
+		auditRepo:    auditRepo,
+	}
+}
+
+// CreateWebhook создаёт подписку. Если secret не передан, он генерируется;
+// секрет возвращается только в ответе на создание. URL на localhost и внутренние
+// адреса отклоняется сразу, остальное проверяет клиент доставки при соединении.
+// Создание попадает в журнал аудита.
+func (s *WebhookService) CreateWebhook(ctx context.Context, rawURL string, events []string, chatID *int64, secret string) (*models.Webhook, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if len(rawURL) > 2000 {
+		return nil, errors.New("url must be at most 2000 characters")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("url must be an absolute http or https URL")
+	}
+	if !netutil.PublicHost(parsed.Hostname()) {
+		return nil, errors.New("url must not point to a private or loopback address")
+	}
+
+	for _, event := range events {
+		if !models.KnownEvent(event) {
+			return nil, errors.New("unknown event: " + event)
+		}
+	}
+
+	if chatID != nil {
+		exists, err := s.chatRepo.Exists(ctx, *chatID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errChatNotFound
+		}
+	}
+
+	secret = strings.TrimSpace(secret)
+	if len(secret) > 200 {
+		return nil, errors.New("secret must be at most 200 characters")
+	}
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	webhook := &models.Webhook{
+		URL:    rawURL,
+		Events: events,
+		ChatID: chatID,
+		Secret: secret,
+		Active: true,
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditWebhookCreate, TargetType: "webhook", TargetID: webhook.ID, ChatID: webhook.ChatID}
+		return recordAudit(ctx, s.auditRepo, entry, nil, withoutSecret(*webhook))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks получает все подписки без секретов
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook удаляет подписку. Снимок удалённой подписки остаётся в журнале аудита.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		webhook, err := s.get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = s.webhookRepo.Delete(ctx, id)
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return errors.New("webhook not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		entry := models.AuditEntry{Action: models.AuditWebhookDelete, TargetType: "webhook", TargetID: id, ChatID: webhook.ChatID}
+		return recordAudit(ctx, s.auditRepo, entry, withoutSecret(*webhook), nil)
+	})
+}
+
+// EnableWebhook снова включает подписку, выключенную после серии ошибок.
+// Включение попадает в журнал аудита.
+func (s *WebhookService) EnableWebhook(ctx context.Context, id int64) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		webhook, err := s.get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = s.webhookRepo.SetActive(ctx, id, true)
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return errors.New("webhook not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		before := withoutSecret(*webhook)
+		after := before
+		after.Active = true
+		after.ConsecutiveFailures = 0
+		after.DisabledAt = nil
+		entry := models.AuditEntry{Action: models.AuditWebhookUpdate, TargetType: "webhook", TargetID: id, ChatID: webhook.ChatID}
+		return recordAudit(ctx, s.auditRepo, entry, before, after)
+	})
+}
+
+// ListDeliveries получает журнал последних доставок подписки
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	webhook, err := s.webhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, errors.New("webhook not found")
+	}
+
+	return s.deliveryRepo.ListByWebhook(ctx, webhookID, limit)
+}
+
+// get получает подписку или ошибку "webhook not found"
+func (s *WebhookService) get(ctx context.Context, id int64) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, errors.New("webhook not found")
+	}
+	return webhook, nil
+}
+
+// withoutSecret возвращает копию подписки без секрета: в журнал аудита он не попадает
+func withoutSecret(webhook models.Webhook) models.Webhook {
+	webhook.Secret = ""
+	return webhook
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
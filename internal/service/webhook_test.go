@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	chatID := int64(5)
+
+	tests := []struct {
+		name        string
+		url         string
+		events      []string
+		chatID      *int64
+		secret      string
+		setupMock   func(*mocks.MockWebhookRepository, *mocks.MockChatRepository)
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:   "успешное создание с фильтрами",
+			url:    "https://example.com/hook",
+			events: []string{models.EventMessageCreated},
+			chatID: &chatID,
+			secret: "my-secret",
+			setupMock: func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().Exists(gomock.Any(), int64(5)).Return(true, nil)
+				wr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, webhook *models.Webhook) error {
+						if webhook.Secret != "my-secret" {
+							t.Errorf("ожидался secret 'my-secret', получен '%s'", webhook.Secret)
+						}
+						if !webhook.Active {
+							t.Error("новый вебхук должен быть включён")
+						}
+						webhook.ID = 1
+						return nil
+					})
+			},
+		},
+		{
+			name: "секрет генерируется, если не передан",
+			url:  "http://example.com/hook",
+			setupMock: func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {
+				wr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, webhook *models.Webhook) error {
+						if len(webhook.Secret) != 64 {
+							t.Errorf("ожидался сгенерированный secret из 64 символов, получен '%s'", webhook.Secret)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:        "относительный URL",
+			url:         "/hook",
+			setupMock:   func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {},
+			expectError: true,
+			errorMsg:    "url must be an absolute http or https URL",
+		},
+		{
+			name:        "неподдерживаемая схема",
+			url:         "ftp://example.com/hook",
+			setupMock:   func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {},
+			expectError: true,
+			errorMsg:    "url must be an absolute http or https URL",
+		},
+		{
+			name:        "адрес метаданных облака",
+			url:         "http://169.254.169.254/latest/meta-data",
+			setupMock:   func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {},
+			expectError: true,
+			errorMsg:    "url must not point to a private or loopback address",
+		},
+		{
+			name:        "localhost",
+			url:         "http://localhost:8080/admin/import",
+			setupMock:   func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {},
+			expectError: true,
+			errorMsg:    "url must not point to a private or loopback address",
+		},
+		{
+			name:        "неизвестное событие",
+			url:         "https://example.com/hook",
+			events:      []string{"chat.renamed"},
+			setupMock:   func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {},
+			expectError: true,
+			errorMsg:    "unknown event: chat.renamed",
+		},
+		{
+			name:   "чат для фильтра не существует",
+			url:    "https://example.com/hook",
+			chatID: &chatID,
+			setupMock: func(wr *mocks.MockWebhookRepository, cr *mocks.MockChatRepository) {
+				cr.EXPECT().Exists(gomock.Any(), int64(5)).Return(false, nil)
+			},
+			expectError: true,
+			errorMsg:    "chat not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+			mockDeliveryRepo := mocks.NewMockWebhookDeliveryRepository(ctrl)
+			mockChatRepo := mocks.NewMockChatRepository(ctrl)
+
+			tt.setupMock(mockWebhookRepo, mockChatRepo)
+
+			action := models.AuditWebhookCreate
+			if tt.expectError {
+				action = ""
+			}
+			service := NewWebhookService(mockWebhookRepo, mockDeliveryRepo, mockChatRepo, newTxManager(ctrl), newAudit(ctrl, action))
+
+			webhook, err := service.CreateWebhook(context.Background(), tt.url, tt.events, tt.chatID, tt.secret)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("ожидалась ошибка, но её не было")
+				}
+				if tt.errorMsg != "" && err.Error() != tt.errorMsg {
+					t.Errorf("ожидалась ошибка %q, получена %q", tt.errorMsg, err.Error())
+				}
+			} else {
+				if err != nil {
+					t.Errorf("неожиданная ошибка: %v", err)
+				}
+				if webhook == nil {
+					t.Error("вебхук не должен быть nil")
+				}
+			}
+		})
+	}
+}
+
+func TestListWebhooks_HidesSecrets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockWebhookRepo.EXPECT().
+		List(gomock.Any()).
+		Return([]models.Webhook{{ID: 1, URL: "https://example.com", Secret: "secret", Active: true}}, nil)
+
+	service := NewWebhookService(mockWebhookRepo, mocks.NewMockWebhookDeliveryRepository(ctrl), mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), newAudit(ctrl, ""))
+
+	webhooks, err := service.ListWebhooks(context.Background())
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(webhooks) != 1 || webhooks[0].Secret != "" {
+		t.Errorf("секрет не должен возвращаться в списке: %+v", webhooks)
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	stored := &models.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "secret", Active: true}
+
+	tests := []struct {
+		name      string
+		webhook   *models.Webhook
+		repoErr   error
+		expectErr string
+	}{
+		{name: "успешное удаление", webhook: stored},
+		{name: "вебхук не найден", expectErr: "webhook not found"},
+		{name: "удалён параллельно", webhook: stored, repoErr: repository.ErrWebhookNotFound, expectErr: "webhook not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+			mockWebhookRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(tt.webhook, nil)
+			if tt.webhook != nil {
+				mockWebhookRepo.EXPECT().Delete(gomock.Any(), int64(1)).Return(tt.repoErr)
+			}
+
+			mockAudit := mocks.NewMockAuditRepository(ctrl)
+			if tt.expectErr == "" {
+				mockAudit.EXPECT().
+					Add(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, entry *models.AuditEntry) error {
+						if entry.Action != models.AuditWebhookDelete || entry.TargetID != 1 {
+							t.Errorf("неверная запись аудита: %+v", entry)
+						}
+						if strings.Contains(string(entry.Before), "secret") || !strings.Contains(string(entry.Before), "example.com") {
+							t.Errorf("в снимке должна быть подписка без секрета: %s", entry.Before)
+						}
+						return nil
+					})
+			}
+
+			service := NewWebhookService(mockWebhookRepo, mocks.NewMockWebhookDeliveryRepository(ctrl), mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), mockAudit)
+
+			err := service.DeleteWebhook(context.Background(), 1)
+			if tt.expectErr == "" && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+			if tt.expectErr != "" && (err == nil || err.Error() != tt.expectErr) {
+				t.Errorf("ожидалась ошибка %q, получена %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestEnableWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockWebhookRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(&models.Webhook{ID: 1, ConsecutiveFailures: 10}, nil)
+	mockWebhookRepo.EXPECT().SetActive(gomock.Any(), int64(1), true).Return(nil)
+
+	service := NewWebhookService(mockWebhookRepo, mocks.NewMockWebhookDeliveryRepository(ctrl), mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), newAudit(ctrl, models.AuditWebhookUpdate))
+
+	if err := service.EnableWebhook(context.Background(), 1); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		webhook       *models.Webhook
+		expectedLimit int
+		expectError   bool
+	}{
+		{name: "limit по умолчанию", limit: 0, webhook: &models.Webhook{ID: 1}, expectedLimit: 20},
+		{name: "limit больше максимума", limit: 500, webhook: &models.Webhook{ID: 1}, expectedLimit: 100},
+		{name: "вебхук не найден", limit: 10, webhook: nil, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockWebhookRepo := mocks.NewMockWebhookRepository(ctrl)
+			mockDeliveryRepo := mocks.NewMockWebhookDeliveryRepository(ctrl)
+
+			mockWebhookRepo.EXPECT().GetByID(gomock.Any(), int64(1)).Return(tt.webhook, nil)
+			if !tt.expectError {
+				mockDeliveryRepo.EXPECT().
+					ListByWebhook(gomock.Any(), int64(1), tt.expectedLimit).
+					Return([]models.WebhookDelivery{}, nil)
+			}
+
+			service := NewWebhookService(mockWebhookRepo, mockDeliveryRepo, mocks.NewMockChatRepository(ctrl), newTxManager(ctrl), newAudit(ctrl, ""))
+
+			_, err := service.ListDeliveries(context.Background(), 1, tt.limit)
+			if tt.expectError && err == nil {
+				t.Error("ожидалась ошибка, но её не было")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Package thumbnail генерирует миниатюры картинок-вложений в фоновом пуле воркеров.
+//
+// Загрузка вложения не ждёт миниатюру: сервис ставит вложение в очередь, воркер
+// декодирует оригинал (JPEG, PNG, GIF), уменьшает его, кладёт миниатюру в то же
+// blob-хранилище и записывает размеры в базу. Очередь живёт в памяти, поэтому при
+// переполнении или перезапуске миниатюра может не появиться — оригинал при этом
+// остаётся доступным.
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // регистрирует GIF-декодер для image.Decode
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+const (
+	// DefaultSize — сторона квадрата, в который вписывается миниатюра
+	DefaultSize = 320
+	// maxPixels защищает от "бомб": маленький файл с огромными размерами картинки
+	maxPixels = 40_000_000
+	// jpegQuality — качество JPEG-миниатюр
+	jpegQuality = 80
+)
+
+var ErrTooLarge = errors.New("image is too large")
+
+type Generator struct {
+	attachmentRepo repository.AttachmentRepository
+	blobs          blob.Store
+
+	jobs    chan models.Attachment
+	workers int
+	size    int
+}
+
+func NewGenerator(attachmentRepo repository.AttachmentRepository, blobs blob.Store, workers, queueSize int) *Generator {
+	return &Generator{
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		jobs:           make(chan models.Attachment, queueSize),
+		workers:        workers,
+		size:           DefaultSize,
+	}
+}
+
+// Supported сообщает, умеет ли генератор делать миниатюры для contentType
+func Supported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Enqueue ставит вложение в очередь и никогда не блокируется.
+// Возвращает false, если тип не поддерживается или очередь переполнена.
+func (g *Generator) Enqueue(attachment models.Attachment) bool {
+	if !Supported(attachment.ContentType) {
+		return false
+	}
+
+	select {
+	case g.jobs <- attachment:
+		return true
+	default:
+		log.Printf("thumbnail: queue is full, skipping attachment %d", attachment.ID)
+		return false
+	}
+}
+
+// Run запускает воркеры и ждёт их завершения после отмены ctx
+func (g *Generator) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for range g.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case attachment := <-g.jobs:
+					if err := g.Process(ctx, attachment); err != nil && ctx.Err() == nil {
+						log.Printf("thumbnail: attachment %d: %v", attachment.ID, err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Process генерирует миниатюру одного вложения и сохраняет её
+func (g *Generator) Process(ctx context.Context, attachment models.Attachment) error {
+	img, err := g.decode(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	width, height := fit(bounds.Dx(), bounds.Dy(), g.size)
+	thumb := resize(img, width, height)
+
+	key := attachment.StorageKey + "-thumb"
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(encode(writer, thumb, attachment.ThumbnailContentType()))
+	}()
+	if _, err := g.blobs.Put(ctx, key, reader); err != nil {
+		reader.CloseWithError(err)
+		return fmt.Errorf("store thumbnail: %w", err)
+	}
+
+	attachment.Width = bounds.Dx()
+	attachment.Height = bounds.Dy()
+	attachment.ThumbnailKey = key
+	attachment.ThumbnailWidth = width
+	attachment.ThumbnailHeight = height
+
+	if err := g.attachmentRepo.UpdateThumbnail(ctx, &attachment); err != nil {
+		// Вложение удалили, пока мы работали — миниатюра больше не нужна
+		g.blobs.Delete(context.WithoutCancel(ctx), key)
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+// decode читает оригинал, предварительно проверив размеры по заголовку
+func (g *Generator) decode(ctx context.Context, key string) (image.Image, error) {
+	file, err := g.blobs.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// Для GIF берётся первый кадр
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	return img, nil
+}
+
+func encode(w io.Writer, img image.Image, contentType string) error {
+	if contentType == "image/jpeg" {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	}
+	return png.Encode(w, img)
+}
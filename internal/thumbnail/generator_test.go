@@ -0,0 +1,205 @@
+package thumbnail
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/blob"
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/memory"
+)
+
+type env struct {
+	blobs       *blob.LocalStore
+	attachments repository.AttachmentRepository
+	messages    repository.MessageRepository
+	message     *models.Message
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	blobs, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	store := memory.NewStore()
+	ctx := context.Background()
+	chat := &models.Chat{Title: "Images"}
+	require.NoError(t, memory.NewChatRepository(store).Create(ctx, chat))
+	messages := memory.NewMessageRepository(store)
+	message := &models.Message{ChatID: chat.ID, Text: "photo"}
+	require.NoError(t, messages.Create(ctx, message))
+
+	return &env{
+		blobs:       blobs,
+		attachments: memory.NewAttachmentRepository(store),
+		messages:    messages,
+		message:     message,
+	}
+}
+
+// upload кладёт файл в хранилище и создаёт вложение
+func (e *env) upload(t *testing.T, contentType string, data []byte) models.Attachment {
+	t.Helper()
+
+	attachment := models.Attachment{
+		ChatID:      e.message.ChatID,
+		MessageID:   e.message.ID,
+		FileName:    "image",
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  "chats/1/image",
+	}
+	_, err := e.blobs.Put(context.Background(), attachment.StorageKey, bytes.NewReader(data))
+	require.NoError(t, err)
+	require.NoError(t, e.attachments.Create(context.Background(), &attachment))
+	return attachment
+}
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeImage(t *testing.T, format string, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		require.NoError(t, png.Encode(&buf, img))
+	case "jpeg":
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	case "gif":
+		require.NoError(t, gif.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		format      string
+		contentType string
+		width       int
+		height      int
+		wantWidth   int
+		wantHeight  int
+		wantFormat  string
+	}{
+		{format: "png", contentType: "image/png", width: 800, height: 400, wantWidth: 320, wantHeight: 160, wantFormat: "png"},
+		{format: "jpeg", contentType: "image/jpeg", width: 300, height: 900, wantWidth: 106, wantHeight: 320, wantFormat: "jpeg"},
+		{format: "gif", contentType: "image/gif", width: 100, height: 50, wantWidth: 100, wantHeight: 50, wantFormat: "png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			e := newEnv(t)
+			attachment := e.upload(t, tt.contentType, encodeImage(t, tt.format, testImage(tt.width, tt.height)))
+
+			generator := NewGenerator(e.attachments, e.blobs, 1, 1)
+			require.NoError(t, generator.Process(context.Background(), attachment))
+
+			stored, err := e.attachments.GetByID(context.Background(), attachment.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.width, stored.Width)
+			assert.Equal(t, tt.height, stored.Height)
+			assert.Equal(t, tt.wantWidth, stored.ThumbnailWidth)
+			assert.Equal(t, tt.wantHeight, stored.ThumbnailHeight)
+			require.NotEmpty(t, stored.ThumbnailKey)
+
+			file, err := e.blobs.Open(context.Background(), stored.ThumbnailKey)
+			require.NoError(t, err)
+			defer file.Close()
+
+			config, format, err := image.DecodeConfig(file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, format)
+			assert.Equal(t, tt.wantWidth, config.Width)
+			assert.Equal(t, tt.wantHeight, config.Height)
+		})
+	}
+}
+
+func TestProcess_RejectsDecompressionBomb(t *testing.T) {
+	e := newEnv(t)
+
+	// Заголовок GIF с логическим экраном 65535×65535 без самих данных
+	bomb := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	attachment := e.upload(t, "image/gif", bomb)
+
+	generator := NewGenerator(e.attachments, e.blobs, 1, 1)
+	assert.ErrorIs(t, generator.Process(context.Background(), attachment), ErrTooLarge)
+}
+
+func TestProcess_AttachmentDeleted(t *testing.T) {
+	e := newEnv(t)
+	attachment := e.upload(t, "image/png", encodeImage(t, "png", testImage(10, 10)))
+	attachment.ID += 1000
+
+	generator := NewGenerator(e.attachments, e.blobs, 1, 1)
+	require.NoError(t, generator.Process(context.Background(), attachment))
+
+	_, err := e.blobs.Open(context.Background(), attachment.StorageKey+"-thumb")
+	assert.ErrorIs(t, err, blob.ErrNotFound, "миниатюра удалённого вложения не должна оставаться в хранилище")
+}
+
+func TestEnqueue(t *testing.T) {
+	e := newEnv(t)
+	attachment := e.upload(t, "image/png", encodeImage(t, "png", testImage(400, 400)))
+
+	generator := NewGenerator(e.attachments, e.blobs, 2, 1)
+	assert.False(t, generator.Enqueue(models.Attachment{ContentType: "application/pdf"}), "не картинки не ставятся в очередь")
+	assert.True(t, generator.Enqueue(attachment))
+	assert.False(t, generator.Enqueue(attachment), "переполненная очередь не должна блокировать")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		generator.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		stored, err := e.attachments.GetByID(context.Background(), attachment.ID)
+		return err == nil && stored.ThumbnailKey != ""
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 2; y++ {
+			if x < 2 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				src.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	dst := resize(src, 2, 1)
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, dst.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, dst.RGBAAt(1, 0))
+
+	dst = resize(src, 1, 1)
+	assert.Equal(t, color.RGBA{R: 127, B: 127, A: 255}, dst.RGBAAt(0, 0))
+}
@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/netutil"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/retry"
+)
+
+type Deliverer struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	txManager  repository.TxManager
+	client     *http.Client
+
+	interval     time.Duration
+	batchSize    int
+	workers      int
+	claimTTL     time.Duration
+	maxAttempts  int
+	disableAfter int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+}
+
+// NewDeliverer создаёт отправителя доставок. Без client используется клиент из
+// netutil.NewSafeClient: адреса вебхуков задают пользователи, и ходить по ним
+// во внутреннюю сеть нельзя.
+func NewDeliverer(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, txManager repository.TxManager, client *http.Client, interval time.Duration) *Deliverer {
+	if client == nil {
+		client = netutil.NewSafeClient(10 * time.Second)
+	}
+	return &Deliverer{
+		webhooks:     webhooks,
+		deliveries:   deliveries,
+		txManager:    txManager,
+		client:       client,
+		interval:     interval,
+		batchSize:    50,
+		workers:      8,
+		claimTTL:     5 * time.Minute,
+		maxAttempts:  8,
+		disableAfter: 20,
+		minBackoff:   10 * time.Second,
+		maxBackoff:   time.Hour,
+	}
+}
+
+// Run отправляет доставки, время которых наступило, пока не будет отменён ctx
+func (d *Deliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("webhook: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue выполняет одну попытку для каждой доставки, время которой наступило,
+// и возвращает число успешно доставленных. Доставки захватываются через ClaimDue
+// в короткой транзакции на claimTTL, поэтому несколько реплик не отправят одну
+// доставку дважды. Отправляют их параллельно не больше workers воркеров: медленный
+// или мёртвый адрес занимает один воркер, а не задерживает остальных подписчиков.
+func (d *Deliverer) DeliverDue(ctx context.Context) (int, error) {
+	var due []models.WebhookDelivery
+	err := d.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		var err error
+		due, err = d.deliveries.ClaimDue(ctx, now, now.Add(d.claimTTL), d.batchSize)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("claim due deliveries: %w", err)
+	}
+
+	var (
+		mu        sync.Mutex
+		delivered int
+		firstErr  error
+		wg        sync.WaitGroup
+	)
+	jobs := make(chan *models.WebhookDelivery)
+	for range min(d.workers, len(due)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range jobs {
+				ok, err := d.attempt(ctx, delivery)
+
+				mu.Lock()
+				if ok {
+					delivered++
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	// После отмены ctx невзятые доставки уйдут снова по истечении захвата
+	for i := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- &due[i]
+	}
+	close(jobs)
+	wg.Wait()
+
+	return delivered, firstErr
+}
+
+func (d *Deliverer) attempt(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	webhook, err := d.webhooks.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return false, err
+	}
+	if webhook == nil || !webhook.Active {
+		delivery.Status = models.DeliveryFailed
+		delivery.LastError = "webhook is disabled"
+		return false, d.deliveries.Update(ctx, delivery)
+	}
+
+	delivery.Attempts++
+	code, sendErr := d.send(ctx, webhook, delivery)
+	delivery.ResponseCode = code
+
+	if sendErr == nil {
+		now := time.Now()
+		delivery.Status = models.DeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		if err := d.deliveries.Update(ctx, delivery); err != nil {
+			return false, err
+		}
+		return true, d.webhooks.ResetFailures(ctx, webhook.ID)
+	}
+
+	delivery.LastError = sendErr.Error()
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.DeliveryFailed
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(retry.Backoff(delivery.Attempts, d.minBackoff, d.maxBackoff))
+	}
+	if err := d.deliveries.Update(ctx, delivery); err != nil {
+		return false, err
+	}
+
+	disabled, err := d.webhooks.RecordFailure(ctx, webhook.ID, d.disableAfter)
+	if err != nil {
+		return false, err
+	}
+	if disabled {
+		log.Printf("webhook: %d disabled after %d consecutive failures", webhook.ID, d.disableAfter)
+	}
+
+	return false, nil
+}
+
+// send отправляет доставку и возвращает код ответа (0, если ответа не было)
+func (d *Deliverer) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "chat-golang-webhooks/1.0")
+	req.Header.Set(HeaderWebhookID, strconv.FormatInt(webhook.ID, 10))
+	req.Header.Set(HeaderDeliveryID, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
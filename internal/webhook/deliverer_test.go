@@ -0,0 +1,320 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/outbox"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+	"github.com/GlebMoskalev/chat-golang/internal/repository/memory"
+)
+
+// receiver — тестовый получатель вебхуков, проверяющий подпись
+type receiver struct {
+	t      *testing.T
+	secret string
+	status int
+
+	mu       sync.Mutex
+	received []outbox.Envelope
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(rc.t, err)
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(rc.t, err)
+	assert.True(rc.t, Verify(rc.secret, timestamp, body, r.Header.Get(HeaderSignature)), "подпись не совпала")
+	assert.NotEmpty(rc.t, r.Header.Get(HeaderDeliveryID))
+
+	var envelope outbox.Envelope
+	require.NoError(rc.t, json.Unmarshal(body, &envelope))
+	assert.Equal(rc.t, envelope.Type, r.Header.Get(HeaderEventType))
+
+	rc.mu.Lock()
+	rc.received = append(rc.received, envelope)
+	status := rc.status
+	rc.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) setStatus(status int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.status = status
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.received)
+}
+
+type fixture struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	sink       *Sink
+	deliverer  *Deliverer
+}
+
+func newFixture(client *http.Client) *fixture {
+	store := memory.NewStore()
+	f := &fixture{
+		webhooks:   memory.NewWebhookRepository(store),
+		deliveries: memory.NewWebhookDeliveryRepository(store),
+	}
+	f.sink = NewSink(f.webhooks, f.deliveries)
+	f.deliverer = NewDeliverer(f.webhooks, f.deliveries, memory.NewTxManager(store), client, time.Second)
+	// Повторы в тестах должны наступать сразу
+	f.deliverer.minBackoff = 0
+	f.deliverer.maxBackoff = 0
+	return f
+}
+
+func (f *fixture) subscribe(t *testing.T, url, secret string, chatID *int64, events ...string) *models.Webhook {
+	t.Helper()
+
+	webhook := &models.Webhook{URL: url, Events: events, ChatID: chatID, Secret: secret, Active: true}
+	require.NoError(t, f.webhooks.Create(context.Background(), webhook))
+	return webhook
+}
+
+func event(id int64, eventType string, chatID int64) models.OutboxEvent {
+	return models.OutboxEvent{
+		ID:        id,
+		EventType: eventType,
+		ChatID:    chatID,
+		Payload:   `{"id":` + strconv.FormatInt(id, 10) + `}`,
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestDeliver_SignedPayload(t *testing.T) {
+	rc := &receiver{t: t, secret: "s3cret", status: http.StatusOK}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	f := newFixture(server.Client())
+	webhook := f.subscribe(t, server.URL, "s3cret", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventMessageCreated, 5)))
+	// Повторная публикация того же события (at-least-once из outbox) не создаёт вторую доставку
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventMessageCreated, 5)))
+
+	n, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Equal(t, 1, rc.count())
+	assert.Equal(t, int64(1), rc.received[0].ID)
+	assert.Equal(t, int64(5), rc.received[0].ChatID)
+	assert.JSONEq(t, `{"id":1}`, string(rc.received[0].Data))
+
+	log, err := f.deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, models.DeliveryDelivered, log[0].Status)
+	assert.Equal(t, http.StatusOK, log[0].ResponseCode)
+	assert.Equal(t, 1, log[0].Attempts)
+}
+
+func TestDeliver_Filters(t *testing.T) {
+	rc := &receiver{t: t, secret: "s", status: http.StatusOK}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	f := newFixture(server.Client())
+	chatID := int64(5)
+	f.subscribe(t, server.URL, "s", &chatID, models.EventMessageCreated)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventMessageCreated, 5)))
+	require.NoError(t, f.sink.Publish(ctx, event(2, models.EventMessageCreated, 6)))
+	require.NoError(t, f.sink.Publish(ctx, event(3, models.EventChatDeleted, 5)))
+
+	_, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, rc.count())
+	assert.Equal(t, int64(1), rc.received[0].ID)
+}
+
+func TestDeliver_BlocksInternalAddress(t *testing.T) {
+	rc := &receiver{t: t, secret: "s", status: http.StatusOK}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	// Клиент по умолчанию не ходит на loopback, даже если адрес уже сохранён в подписке
+	f := newFixture(nil)
+	webhook := f.subscribe(t, server.URL, "s", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventChatCreated, 1)))
+
+	n, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Zero(t, rc.count())
+
+	log, err := f.deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Contains(t, log[0].LastError, "address is not allowed")
+}
+
+func TestDeliver_RetriesThenFails(t *testing.T) {
+	rc := &receiver{t: t, secret: "s", status: http.StatusInternalServerError}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	f := newFixture(server.Client())
+	f.deliverer.maxAttempts = 3
+	webhook := f.subscribe(t, server.URL, "s", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventChatCreated, 1)))
+
+	for i := 0; i < 5; i++ {
+		n, err := f.deliverer.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	}
+	assert.Equal(t, 3, rc.count(), "после maxAttempts попыток доставка прекращается")
+
+	log, err := f.deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, log, 1)
+	assert.Equal(t, models.DeliveryFailed, log[0].Status)
+	assert.Equal(t, 3, log[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, log[0].ResponseCode)
+	assert.Contains(t, log[0].LastError, "500")
+}
+
+func TestDeliver_AutoDisable(t *testing.T) {
+	rc := &receiver{t: t, secret: "s", status: http.StatusBadGateway}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	f := newFixture(server.Client())
+	f.deliverer.disableAfter = 2
+	// Доставки идут по одной, чтобы выключение случилось ровно после второй ошибки
+	f.deliverer.workers = 1
+	webhook := f.subscribe(t, server.URL, "s", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventChatCreated, 1)))
+	require.NoError(t, f.sink.Publish(ctx, event(2, models.EventChatCreated, 2)))
+	require.NoError(t, f.sink.Publish(ctx, event(3, models.EventChatCreated, 3)))
+
+	_, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	found, err := f.webhooks.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.False(t, found.Active, "вебхук должен выключиться после двух ошибок подряд")
+	assert.NotNil(t, found.DisabledAt)
+	assert.Equal(t, 2, rc.count(), "после выключения запросы не отправляются")
+
+	// Оставшиеся в очереди доставки выключенного вебхука закрываются без запросов
+	_, err = f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, rc.count())
+
+	// Новые события выключенному вебхуку не ставятся в очередь
+	require.NoError(t, f.sink.Publish(ctx, event(4, models.EventChatCreated, 4)))
+	log, err := f.deliveries.ListByWebhook(ctx, webhook.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, log, 3)
+	for _, delivery := range log {
+		assert.Equal(t, models.DeliveryFailed, delivery.Status)
+	}
+}
+
+func TestDeliver_SuccessResetsFailures(t *testing.T) {
+	rc := &receiver{t: t, secret: "s", status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	f := newFixture(server.Client())
+	webhook := f.subscribe(t, server.URL, "s", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventChatCreated, 1)))
+	_, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+
+	found, err := f.webhooks.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.ConsecutiveFailures)
+
+	rc.setStatus(http.StatusNoContent)
+	n, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	found, err = f.webhooks.GetByID(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Zero(t, found.ConsecutiveFailures)
+}
+
+func TestDeliver_SlowEndpointDoesNotStall(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	rc := &receiver{t: t, secret: "s", status: http.StatusOK}
+	fast := httptest.NewServer(rc)
+	defer fast.Close()
+
+	f := newFixture(fast.Client())
+	f.subscribe(t, slow.URL, "s", nil)
+	f.subscribe(t, fast.URL, "s", nil)
+	ctx := context.Background()
+
+	require.NoError(t, f.sink.Publish(ctx, event(1, models.EventChatCreated, 1)))
+
+	done := make(chan int)
+	go func() {
+		n, err := f.deliverer.DeliverDue(ctx)
+		assert.NoError(t, err)
+		done <- n
+	}()
+
+	// Быстрый подписчик получает событие, пока медленный ещё не ответил
+	require.Eventually(t, func() bool { return rc.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// Обе доставки захвачены: второй проход, как и другая реплика, их не отправит
+	n, err := f.deliverer.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, 1, rc.count())
+
+	release <- struct{}{}
+	assert.Equal(t, 2, <-done)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", 1700000000, body)
+
+	assert.True(t, Verify("secret", 1700000000, body, signature))
+	assert.False(t, Verify("other", 1700000000, body, signature))
+	assert.False(t, Verify("secret", 1700000001, body, signature))
+	assert.False(t, Verify("secret", 1700000000, []byte(`{"id":2}`), signature))
+}
@@ -0,0 +1,38 @@
+// Package webhook доставляет доменные события подписчикам по HTTP.
+//
+// Sink получает события от outbox.Dispatcher и ставит в очередь доставку для каждой
+// подходящей подписки, а Deliverer в фоне отправляет их с подписью HMAC-SHA256,
+// повторяет неудачные попытки с экспоненциальной задержкой и выключает подписку
+// после серии ошибок подряд.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Заголовки, которые получает подписчик
+const (
+	HeaderWebhookID  = "X-Webhook-ID"
+	HeaderDeliveryID = "X-Webhook-Delivery"
+	HeaderEventType  = "X-Webhook-Event"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+// Sign вычисляет подпись "sha256=<hex>" для HMAC-SHA256(secret, "<timestamp>.<body>").
+// Timestamp входит в подпись, чтобы получатель мог отбрасывать старые повторы запросов.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись за постоянное время
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/GlebMoskalev/chat-golang/internal/models"
+	"github.com/GlebMoskalev/chat-golang/internal/outbox"
+	"github.com/GlebMoskalev/chat-golang/internal/repository"
+)
+
+// Sink — приёмник outbox, который превращает событие в доставки для подходящих подписок
+type Sink struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+}
+
+func NewSink(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) *Sink {
+	return &Sink{webhooks: webhooks, deliveries: deliveries}
+}
+
+// Publish ставит событие в очередь доставки каждой активной подписке, которая на него подписана.
+// Постановка идемпотентна, поэтому повтор события из outbox не приводит к дублям.
+func (s *Sink) Publish(ctx context.Context, event models.OutboxEvent) error {
+	webhooks, err := s.webhooks.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Matches(event.EventType, event.ChatID) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(outbox.NewEnvelope(event))
+			if err != nil {
+				return err
+			}
+		}
+
+		err := s.deliveries.Enqueue(ctx, &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.EventType,
+			Payload:       string(payload),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
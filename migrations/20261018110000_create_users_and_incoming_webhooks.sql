@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE users (
+    id BIGSERIAL PRIMARY KEY,
+    username VARCHAR(64) NOT NULL UNIQUE,
+    is_bot BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+ALTER TABLE chats ADD COLUMN owner_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN author_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE incoming_webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL CHECK (length(name) > 0),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    bot_user_id BIGINT NOT NULL REFERENCES users(id),
+    created_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_incoming_webhooks_chat ON incoming_webhooks(chat_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS incoming_webhooks;
+ALTER TABLE messages DROP COLUMN IF EXISTS author_id;
+ALTER TABLE chats DROP COLUMN IF EXISTS owner_id;
+DROP TABLE IF EXISTS users;
+-- +goose StatementEnd
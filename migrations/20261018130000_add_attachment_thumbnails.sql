@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE attachments
+    ADD COLUMN width INT NOT NULL DEFAULT 0,
+    ADD COLUMN height INT NOT NULL DEFAULT 0,
+    ADD COLUMN thumbnail_key VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN thumbnail_width INT NOT NULL DEFAULT 0,
+    ADD COLUMN thumbnail_height INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attachments
+    DROP COLUMN IF EXISTS thumbnail_height,
+    DROP COLUMN IF EXISTS thumbnail_width,
+    DROP COLUMN IF EXISTS thumbnail_key,
+    DROP COLUMN IF EXISTS height,
+    DROP COLUMN IF EXISTS width;
+-- +goose StatementEnd
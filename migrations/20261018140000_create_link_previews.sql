@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE link_previews (
+    id BIGSERIAL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL UNIQUE,
+    title VARCHAR(2000) NOT NULL DEFAULT '',
+    description VARCHAR(2000) NOT NULL DEFAULT '',
+    image_url VARCHAR(2048) NOT NULL DEFAULT '',
+    site_name VARCHAR(2000) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    fetched_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS link_previews;
+-- +goose StatementEnd
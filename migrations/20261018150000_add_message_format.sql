@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE messages
+    ADD COLUMN format VARCHAR(16) NOT NULL DEFAULT 'plain' CHECK (format IN ('plain', 'markdown'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE messages DROP COLUMN IF EXISTS format;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chats
+    ADD COLUMN type VARCHAR(16) NOT NULL DEFAULT 'room',
+    ADD COLUMN dm_key VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_chats_dm_key ON chats(dm_key) WHERE dm_key IS NOT NULL;
+
+-- У личных чатов нет названия
+ALTER TABLE chats DROP CONSTRAINT chats_title_check;
+ALTER TABLE chats ADD CONSTRAINT chats_title_check CHECK (type = 'dm' OR length(title) > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM chats WHERE type = 'dm';
+
+ALTER TABLE chats DROP CONSTRAINT chats_title_check;
+ALTER TABLE chats ADD CONSTRAINT chats_title_check CHECK (length(title) > 0);
+
+DROP INDEX IF EXISTS idx_chats_dm_key;
+
+ALTER TABLE chats
+    DROP COLUMN IF EXISTS dm_key,
+    DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chats ADD COLUMN import_key VARCHAR(255);
+ALTER TABLE messages ADD COLUMN import_key VARCHAR(255);
+
+CREATE UNIQUE INDEX idx_chats_import_key ON chats(import_key) WHERE import_key IS NOT NULL;
+CREATE UNIQUE INDEX idx_messages_import_key ON messages(import_key) WHERE import_key IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_messages_import_key;
+DROP INDEX IF EXISTS idx_chats_import_key;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS import_key;
+ALTER TABLE chats DROP COLUMN IF EXISTS import_key;
+-- +goose StatementEnd
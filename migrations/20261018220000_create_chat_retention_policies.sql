@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE chat_retention_policies (
+    chat_id BIGINT PRIMARY KEY REFERENCES chats(id) ON DELETE CASCADE,
+    max_age_seconds BIGINT CHECK (max_age_seconds >= 0),
+    max_messages BIGINT CHECK (max_messages >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS chat_retention_policies;
+-- +goose StatementEnd
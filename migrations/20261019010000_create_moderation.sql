@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE moderation_rules (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    pattern TEXT NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('reject', 'mask', 'flag')),
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_moderation_rules_chat_id ON moderation_rules(chat_id);
+
+CREATE TABLE moderation_flags (
+    message_id BIGINT PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_moderation_flags_chat_id ON moderation_flags(chat_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS moderation_flags;
+DROP TABLE IF EXISTS moderation_rules;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox_cursors (
+    sink VARCHAR(64) PRIMARY KEY,
+    last_event_id BIGINT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+-- Приёмники продолжают перед первым неопубликованным событием: все неопубликованные
+-- будут доставлены, а опубликованные после него повторятся (доставка at-least-once).
+-- Если неопубликованных нет, продолжают после последнего события.
+INSERT INTO outbox_cursors (sink, last_event_id)
+SELECT sink, COALESCE(
+    (SELECT MIN(id) - 1 FROM outbox WHERE published_at IS NULL),
+    (SELECT MAX(id) FROM outbox),
+    0
+)
+FROM (VALUES ('bus'), ('webhooks'), ('link_previews'), ('log'), ('http')) AS sinks(sink);
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+ALTER TABLE outbox DROP COLUMN published_at, DROP COLUMN attempts, DROP COLUMN last_error;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox
+    ADD COLUMN published_at TIMESTAMP,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+UPDATE outbox SET published_at = now()
+WHERE id <= (SELECT COALESCE(MIN(last_event_id), 0) FROM outbox_cursors);
+CREATE INDEX idx_outbox_pending ON outbox(id) WHERE published_at IS NULL;
+DROP TABLE IF EXISTS outbox_cursors;
+-- +goose StatementEnd
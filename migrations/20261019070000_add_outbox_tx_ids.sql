@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Каждое событие помечается ID записавшей его транзакции. Читаются только события транзакций
+-- старше самой старой незавершённой, поэтому курсор в порядке (tx_id, id) ничего не пропускает
+-- и не ждёт на откатах.
+ALTER TABLE outbox ADD COLUMN tx_id BIGINT NOT NULL DEFAULT pg_current_xact_id()::text::bigint;
+CREATE INDEX idx_outbox_position ON outbox(tx_id, id);
+
+-- Существующие события получили ID этой транзакции, поэтому курсоры остаются на тех же событиях
+ALTER TABLE outbox_cursors ADD COLUMN last_tx_id BIGINT NOT NULL DEFAULT 0;
+UPDATE outbox_cursors SET last_tx_id = pg_current_xact_id()::text::bigint;
+
+-- Шина держит курсор в памяти каждого процесса
+DELETE FROM outbox_cursors WHERE sink = 'bus';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox_cursors DROP COLUMN IF EXISTS last_tx_id;
+DROP INDEX IF EXISTS idx_outbox_position;
+ALTER TABLE outbox DROP COLUMN IF EXISTS tx_id;
+-- +goose StatementEnd